@@ -0,0 +1,263 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"math"
+
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	kclusterv1beta1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// ConvertTo converts this KindCluster to the Hub version (v1beta1).
+func (src *KindCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*kclusterv1beta1.KindCluster)
+
+	dst.ObjectMeta = src.ObjectMeta
+	Convert_v1alpha3_KindClusterSpec_To_v1beta1_KindClusterSpec(&src.Spec, &dst.Spec)
+	Convert_v1alpha3_KindClusterStatus_To_v1beta1_KindClusterStatus(&src.Status, &dst.Status)
+
+	// Restore the fields that cannot be represented in v1alpha3 from the
+	// annotation written by ConvertFrom.
+	restored := &kclusterv1beta1.KindCluster{}
+	ok, err := utilconversion.UnmarshalData(src, restored)
+	if err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.KubernetesVersion = restored.Spec.KubernetesVersion
+	dst.Spec.FailureDomains = restored.Spec.FailureDomains
+	dst.Spec.HostRef = restored.Spec.HostRef
+	dst.Spec.TTL = restored.Spec.TTL
+	dst.Spec.ExpiresAt = restored.Spec.ExpiresAt
+	dst.Spec.ExpirationPolicy = restored.Spec.ExpirationPolicy
+	dst.Spec.PoolRef = restored.Spec.PoolRef
+	dst.Spec.Suspended = restored.Spec.Suspended
+	dst.Spec.DeletionPolicy = restored.Spec.DeletionPolicy
+	dst.Spec.DeletionProtection = restored.Spec.DeletionProtection
+	dst.Status.FailureDomains = restored.Status.FailureDomains
+	dst.Status.HostRef = restored.Status.HostRef
+	dst.Status.ExpiresAt = restored.Status.ExpiresAt
+	dst.Status.ExpiryWarningTime = restored.Status.ExpiryWarningTime
+	dst.Status.Activity = restored.Status.Activity
+	dst.Status.CertificatesExpireAt = restored.Status.CertificatesExpireAt
+	if dst.Status.Remediation != nil && restored.Status.Remediation != nil {
+		dst.Status.Remediation.Exhausted = restored.Status.Remediation.Exhausted
+	}
+	if len(dst.Status.Nodes) == len(restored.Status.Nodes) {
+		for i := range dst.Status.Nodes {
+			dst.Status.Nodes[i].FailureDomain = restored.Status.Nodes[i].FailureDomain
+			dst.Status.Nodes[i].CreationTime = restored.Status.Nodes[i].CreationTime
+		}
+	}
+
+	// v1alpha3 cannot tell an empty failure message from an unset one.
+	if src.Status.FailureMessage == "" && restored.Status.FailureMessage != nil && *restored.Status.FailureMessage == "" {
+		dst.Status.FailureMessage = restored.Status.FailureMessage
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *KindCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*kclusterv1beta1.KindCluster)
+
+	dst.ObjectMeta = src.ObjectMeta
+	Convert_v1beta1_KindClusterSpec_To_v1alpha3_KindClusterSpec(&src.Spec, &dst.Spec)
+	Convert_v1beta1_KindClusterStatus_To_v1alpha3_KindClusterStatus(&src.Status, &dst.Status)
+
+	// Preserve the hub data on the spoke so that it survives a round trip.
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this KindClusterList to the Hub version (v1beta1).
+func (src *KindClusterList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*kclusterv1beta1.KindClusterList)
+
+	dst.ListMeta = src.ListMeta
+	dst.Items = make([]kclusterv1beta1.KindCluster, len(src.Items))
+	for i := range src.Items {
+		if err := src.Items[i].ConvertTo(&dst.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1beta1) to this version.
+func (dst *KindClusterList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*kclusterv1beta1.KindClusterList)
+
+	dst.ListMeta = src.ListMeta
+	dst.Items = make([]KindCluster, len(src.Items))
+	for i := range src.Items {
+		if err := dst.Items[i].ConvertFrom(&src.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func Convert_v1alpha3_KindClusterSpec_To_v1beta1_KindClusterSpec(in *KindClusterSpec, out *kclusterv1beta1.KindClusterSpec) {
+	out.Name = in.Name
+	out.ControlPlaneNodes = toInt32(in.ControlPlaneNodes)
+	out.WorkerNodes = toInt32(in.WorkerNodes)
+	out.ControlPlaneEndpoint.Host = in.ControlPlaneEndpoint.Host
+	out.ControlPlaneEndpoint.Port = toInt32(in.ControlPlaneEndpoint.Port)
+
+	out.Remediation = nil
+	if in.Remediation != nil {
+		out.Remediation = &kclusterv1beta1.RemediationPolicy{
+			MaxAttempts:        in.Remediation.MaxAttempts,
+			UnhealthyThreshold: in.Remediation.UnhealthyThreshold,
+			Cooldown:           in.Remediation.Cooldown,
+		}
+	}
+}
+
+func Convert_v1beta1_KindClusterSpec_To_v1alpha3_KindClusterSpec(in *kclusterv1beta1.KindClusterSpec, out *KindClusterSpec) {
+	out.Name = in.Name
+	out.ControlPlaneNodes = int(in.ControlPlaneNodes)
+	out.WorkerNodes = int(in.WorkerNodes)
+	out.ControlPlaneEndpoint.Host = in.ControlPlaneEndpoint.Host
+	out.ControlPlaneEndpoint.Port = int(in.ControlPlaneEndpoint.Port)
+
+	out.Remediation = nil
+	if in.Remediation != nil {
+		out.Remediation = &RemediationPolicy{
+			MaxAttempts:        in.Remediation.MaxAttempts,
+			UnhealthyThreshold: in.Remediation.UnhealthyThreshold,
+			Cooldown:           in.Remediation.Cooldown,
+		}
+	}
+}
+
+func Convert_v1alpha3_KindClusterStatus_To_v1beta1_KindClusterStatus(in *KindClusterStatus, out *kclusterv1beta1.KindClusterStatus) {
+	out.Ready = in.Ready
+	out.Phase = kclusterv1beta1.ClusterPhase(in.Phase)
+	out.FailureMessage = nil
+	if in.FailureMessage != "" {
+		failureMessage := in.FailureMessage
+		out.FailureMessage = &failureMessage
+	}
+	out.KubernetesVersion = in.KubernetesVersion
+	out.NodeCount = toInt32(in.NodeCount)
+	out.Conditions = in.Conditions
+
+	out.Nodes = nil
+	if in.Nodes != nil {
+		out.Nodes = make([]kclusterv1beta1.NodeStatus, len(in.Nodes))
+		for i, node := range in.Nodes {
+			out.Nodes[i] = kclusterv1beta1.NodeStatus{
+				Name:        node.Name,
+				Role:        node.Role,
+				ContainerID: node.ContainerID,
+				Image:       node.Image,
+				InternalIPs: node.InternalIPs,
+				State:       node.State,
+			}
+		}
+	}
+
+	out.Diagnostics = nil
+	if in.Diagnostics != nil {
+		out.Diagnostics = &kclusterv1beta1.DiagnosticsReference{
+			Storage:     kclusterv1beta1.DiagnosticsStorage(in.Diagnostics.Storage),
+			Name:        in.Diagnostics.Name,
+			Key:         in.Diagnostics.Key,
+			Path:        in.Diagnostics.Path,
+			Size:        in.Diagnostics.Size,
+			CollectedAt: in.Diagnostics.CollectedAt,
+		}
+	}
+
+	out.Remediation = nil
+	if in.Remediation != nil {
+		out.Remediation = &kclusterv1beta1.RemediationStatus{
+			Attempts:        in.Remediation.Attempts,
+			LastAttemptTime: in.Remediation.LastAttemptTime,
+			LastAction:      kclusterv1beta1.RemediationAction(in.Remediation.LastAction),
+		}
+	}
+}
+
+func Convert_v1beta1_KindClusterStatus_To_v1alpha3_KindClusterStatus(in *kclusterv1beta1.KindClusterStatus, out *KindClusterStatus) {
+	out.Ready = in.Ready
+	out.Phase = ClusterPhase(in.Phase)
+	out.FailureMessage = ""
+	if in.FailureMessage != nil {
+		out.FailureMessage = *in.FailureMessage
+	}
+	out.KubernetesVersion = in.KubernetesVersion
+	out.NodeCount = int(in.NodeCount)
+	out.Conditions = in.Conditions
+
+	out.Nodes = nil
+	if in.Nodes != nil {
+		out.Nodes = make([]NodeStatus, len(in.Nodes))
+		for i, node := range in.Nodes {
+			out.Nodes[i] = NodeStatus{
+				Name:        node.Name,
+				Role:        node.Role,
+				ContainerID: node.ContainerID,
+				Image:       node.Image,
+				InternalIPs: node.InternalIPs,
+				State:       node.State,
+			}
+		}
+	}
+
+	out.Diagnostics = nil
+	if in.Diagnostics != nil {
+		out.Diagnostics = &DiagnosticsReference{
+			Storage:     DiagnosticsStorage(in.Diagnostics.Storage),
+			Name:        in.Diagnostics.Name,
+			Key:         in.Diagnostics.Key,
+			Path:        in.Diagnostics.Path,
+			Size:        in.Diagnostics.Size,
+			CollectedAt: in.Diagnostics.CollectedAt,
+		}
+	}
+
+	out.Remediation = nil
+	if in.Remediation != nil {
+		out.Remediation = &RemediationStatus{
+			Attempts:        in.Remediation.Attempts,
+			LastAttemptTime: in.Remediation.LastAttemptTime,
+			LastAction:      RemediationAction(in.Remediation.LastAction),
+		}
+	}
+}
+
+// toInt32 converts the int of a v1alpha3 field to the int32 of the v1beta1
+// field, clamping it to the range of an int32. Node counts and ports outside
+// of it are not valid anyway, so they are not preserved.
+func toInt32(value int) int32 {
+	switch {
+	case value > math.MaxInt32:
+		return math.MaxInt32
+	case value < math.MinInt32:
+		return math.MinInt32
+	default:
+		return int32(value)
+	}
+}
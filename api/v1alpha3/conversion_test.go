@@ -0,0 +1,79 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+
+	kclusterv1beta1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+func TestFuzzyConversion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := kclusterv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("for KindCluster", utilconversion.FuzzTestFunc(utilconversion.FuzzTestFuncInput{
+		Scheme:      scheme,
+		Hub:         &kclusterv1beta1.KindCluster{},
+		Spoke:       &KindCluster{},
+		FuzzerFuncs: []fuzzer.FuzzerFuncs{fuzzFuncs},
+	}))
+}
+
+func fuzzFuncs(_ runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		spokeAPIEndpointFuzzer,
+		spokeKindClusterSpecFuzzer,
+		spokeKindClusterStatusFuzzer,
+	}
+}
+
+// spokeAPIEndpointFuzzer keeps the v1alpha3 port within the int32 range of
+// the hub, as larger ports are not valid anyway.
+func spokeAPIEndpointFuzzer(in *APIEndpoint, c fuzz.Continue) {
+	c.FuzzNoCustom(in)
+
+	in.Port = int(c.Int31())
+}
+
+// spokeKindClusterSpecFuzzer keeps the v1alpha3 node counts within the int32
+// range of the hub.
+func spokeKindClusterSpecFuzzer(in *KindClusterSpec, c fuzz.Continue) {
+	c.FuzzNoCustom(in)
+
+	in.ControlPlaneNodes = int(c.Int31())
+	in.WorkerNodes = int(c.Int31())
+}
+
+// spokeKindClusterStatusFuzzer keeps the v1alpha3 node count within the
+// int32 range of the hub.
+func spokeKindClusterStatusFuzzer(in *KindClusterStatus, c fuzz.Continue) {
+	c.FuzzNoCustom(in)
+
+	in.NodeCount = int(c.Int31())
+}
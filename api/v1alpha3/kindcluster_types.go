@@ -17,7 +17,10 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 type ClusterPhase string
@@ -52,6 +55,77 @@ type KindClusterSpec struct {
 	// reached the Created phase.
 	//+optional
 	ControlPlaneEndpoint APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// Remediation enables automatic remediation of the kind cluster when its
+	// node containers have exited or its API server stays unreachable. If not
+	// set the cluster is never remediated.
+	//+optional
+	Remediation *RemediationPolicy `json:"remediation,omitempty"`
+}
+
+type RemediationAction string
+
+const (
+	// RemediationActionRestartNodes starts the stopped node containers of the
+	// kind cluster.
+	RemediationActionRestartNodes RemediationAction = "RestartNodes"
+	// RemediationActionRecreate deletes the kind cluster and creates it again.
+	RemediationActionRecreate RemediationAction = "Recreate"
+)
+
+const (
+	DefaultRemediationMaxAttempts        = 3
+	DefaultRemediationUnhealthyThreshold = 5 * time.Minute
+	DefaultRemediationCooldown           = 10 * time.Minute
+)
+
+// RemediationPolicy describes when and how often an unhealthy kind cluster is
+// remediated. The first attempt restarts the stopped node containers, every
+// following attempt recreates the whole cluster.
+type RemediationPolicy struct {
+	// MaxAttempts is the number of remediation attempts after which the
+	// controller gives up until the cluster becomes healthy again.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=3
+	//+optional
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// UnhealthyThreshold is how long the cluster has to be unhealthy before
+	// it is remediated.
+	//+kubebuilder:default="5m"
+	//+optional
+	UnhealthyThreshold *metav1.Duration `json:"unhealthyThreshold,omitempty"`
+
+	// Cooldown is the minimum time between two remediation attempts. The
+	// attempt counter is reset once the cluster has been healthy for this
+	// long.
+	//+kubebuilder:default="10m"
+	//+optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// GetMaxAttempts returns MaxAttempts or its default if not set.
+func (p *RemediationPolicy) GetMaxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRemediationMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// GetUnhealthyThreshold returns UnhealthyThreshold or its default if not set.
+func (p *RemediationPolicy) GetUnhealthyThreshold() time.Duration {
+	if p.UnhealthyThreshold == nil {
+		return DefaultRemediationUnhealthyThreshold
+	}
+	return p.UnhealthyThreshold.Duration
+}
+
+// GetCooldown returns Cooldown or its default if not set.
+func (p *RemediationPolicy) GetCooldown() time.Duration {
+	if p.Cooldown == nil {
+		return DefaultRemediationCooldown
+	}
+	return p.Cooldown.Duration
 }
 
 type APIEndpoint struct {
@@ -76,12 +150,119 @@ type KindClusterStatus struct {
 	// FailureMessage indicates there is a fatal problem reconciling the provider's infrastructure
 	//+kubebuilder:validation:Optional
 	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// Nodes lists the nodes of the kind cluster as observed on the container
+	// runtime.
+	//+optional
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+
+	// NodeCount is the number of nodes of the kind cluster.
+	//+optional
+	NodeCount int `json:"nodeCount,omitempty"`
+
+	// KubernetesVersion is the version reported by the API server of the kind
+	// cluster.
+	//+optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Diagnostics references the logs collected from the nodes of the kind
+	// cluster the last time it failed to create.
+	//+optional
+	Diagnostics *DiagnosticsReference `json:"diagnostics,omitempty"`
+
+	// Remediation records the automatic remediation attempts of the kind
+	// cluster.
+	//+optional
+	Remediation *RemediationStatus `json:"remediation,omitempty"`
+
+	// Conditions defines current service state of the KindCluster.
+	//+optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// NodeStatus describes a single node container of a kind cluster
+type NodeStatus struct {
+	// Name is the name of the node container.
+	Name string `json:"name"`
+
+	// Role is the kind role of the node, e.g. control-plane or worker.
+	Role string `json:"role"`
+
+	// ContainerID is the ID of the node container.
+	//+optional
+	ContainerID string `json:"containerID,omitempty"`
+
+	// Image is the node image the container was created from.
+	//+optional
+	Image string `json:"image,omitempty"`
+
+	// InternalIPs are the addresses of the node on the kind network.
+	//+optional
+	InternalIPs []string `json:"internalIPs,omitempty"`
+
+	// State is the state of the node container, e.g. running or exited.
+	//+optional
+	State string `json:"state,omitempty"`
+}
+
+type DiagnosticsStorage string
+
+const (
+	DiagnosticsStorageSecret    DiagnosticsStorage = "Secret"
+	DiagnosticsStorageConfigMap DiagnosticsStorage = "ConfigMap"
+	DiagnosticsStorageHostPath  DiagnosticsStorage = "HostPath"
+)
+
+// DiagnosticsReference points to a gzipped tarball of the logs collected from
+// a kind cluster that failed to create
+type DiagnosticsReference struct {
+	// Storage is where the archive is stored.
+	//+kubebuilder:validation:Enum=Secret;ConfigMap;HostPath
+	Storage DiagnosticsStorage `json:"storage"`
+
+	// Name is the name of the Secret or ConfigMap in the namespace of the
+	// KindCluster holding the archive.
+	//+optional
+	Name string `json:"name,omitempty"`
+
+	// Key is the key of the archive in the Secret or ConfigMap.
+	//+optional
+	Key string `json:"key,omitempty"`
+
+	// Path is the path of the archive on the manager host.
+	//+optional
+	Path string `json:"path,omitempty"`
+
+	// Size is the size of the archive in bytes.
+	Size int `json:"size"`
+
+	// CollectedAt is when the logs were collected.
+	CollectedAt metav1.Time `json:"collectedAt"`
+}
+
+// RemediationStatus records the remediation attempts of a kind cluster
+type RemediationStatus struct {
+	// Attempts is the number of remediation attempts since the cluster was
+	// last healthy.
+	Attempts int `json:"attempts"`
+
+	// LastAttemptTime is when the cluster was last remediated.
+	//+optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// LastAction is the action taken by the last remediation attempt.
+	//+optional
+	LastAction RemediationAction `json:"lastAction,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
 //+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.kubernetesVersion`
+//+kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.nodeCount`
+//+kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.controlPlaneEndpoint.host`
+//+kubebuilder:printcolumn:name="Port",type=integer,JSONPath=`.spec.controlPlaneEndpoint.port`,priority=1
 
 // KindCluster is the Schema for the kindclusters API
 type KindCluster struct {
@@ -92,6 +273,16 @@ type KindCluster struct {
 	Status KindClusterStatus `json:"status,omitempty"`
 }
 
+// GetConditions returns the set of conditions for this object.
+func (c *KindCluster) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *KindCluster) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
 //+kubebuilder:object:root=true
 
 // KindClusterList contains a list of KindCluster
@@ -21,7 +21,9 @@ limitations under the License.
 package v1alpha3
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -39,13 +41,29 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsReference) DeepCopyInto(out *DiagnosticsReference) {
+	*out = *in
+	in.CollectedAt.DeepCopyInto(&out.CollectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsReference.
+func (in *DiagnosticsReference) DeepCopy() *DiagnosticsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindCluster) DeepCopyInto(out *KindCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindCluster.
@@ -102,6 +120,11 @@ func (in *KindClusterList) DeepCopyObject() runtime.Object {
 func (in *KindClusterSpec) DeepCopyInto(out *KindClusterSpec) {
 	*out = *in
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterSpec.
@@ -117,6 +140,30 @@ func (in *KindClusterSpec) DeepCopy() *KindClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KindClusterStatus) DeepCopyInto(out *KindClusterStatus) {
 	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(DiagnosticsReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterStatus.
@@ -128,3 +175,67 @@ func (in *KindClusterStatus) DeepCopy() *KindClusterStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+	*out = *in
+	if in.InternalIPs != nil {
+		in, out := &in.InternalIPs, &out.InternalIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
+func (in *NodeStatus) DeepCopy() *NodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicy) DeepCopyInto(out *RemediationPolicy) {
+	*out = *in
+	if in.UnhealthyThreshold != nil {
+		in, out := &in.UnhealthyThreshold, &out.UnhealthyThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicy.
+func (in *RemediationPolicy) DeepCopy() *RemediationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStatus) DeepCopyInto(out *RemediationStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStatus.
+func (in *RemediationStatus) DeepCopy() *RemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
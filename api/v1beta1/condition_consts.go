@@ -0,0 +1,96 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+const (
+	// WorkloadAPIReachableCondition reports whether the node containers of a
+	// Ready kind cluster are running and its API server answers /readyz.
+	WorkloadAPIReachableCondition clusterv1.ConditionType = "WorkloadAPIReachable"
+
+	// WorkloadAPIUnreachableReason is used when a node container is not
+	// running or the API server of the kind cluster is not ready.
+	WorkloadAPIUnreachableReason = "WorkloadAPIUnreachable"
+
+	// SuspendedReason is used while the node containers of the kind cluster
+	// are stopped because it is suspended.
+	SuspendedReason = "Suspended"
+
+	// ResumingReason is used while the API server of a resumed kind cluster
+	// is not ready yet.
+	ResumingReason = "Resuming"
+
+	// ControlPlaneComponentsHealthyCondition reports whether the control
+	// plane components of all control plane nodes are ready.
+	ControlPlaneComponentsHealthyCondition clusterv1.ConditionType = "ControlPlaneComponentsHealthy"
+
+	// ControlPlaneComponentsUnhealthyReason is used when a control plane
+	// component is missing or not ready.
+	ControlPlaneComponentsUnhealthyReason = "ControlPlaneComponentsUnhealthy"
+
+	// ResizedCondition reports whether the number of control plane or
+	// machine pool nodes matches the desired replicas.
+	ResizedCondition clusterv1.ConditionType = "Resized"
+
+	// ScalingUpReason is used while nodes are added.
+	ScalingUpReason = "ScalingUp"
+
+	// ScalingDownReason is used while nodes are removed.
+	ScalingDownReason = "ScalingDown"
+
+	// ReplacingOutdatedNodesReason is used while machine pool nodes running
+	// an outdated Kubernetes version are replaced.
+	ReplacingOutdatedNodesReason = "ReplacingOutdatedNodes"
+
+	// WaitingForKindClusterReason is used while the kind cluster of the
+	// Cluster is not ready.
+	WaitingForKindClusterReason = "WaitingForKindCluster"
+
+	// HostScheduledCondition reports whether a KindHost with enough capacity
+	// has been picked for a kind cluster without an explicit host.
+	HostScheduledCondition clusterv1.ConditionType = "HostScheduled"
+
+	// InsufficientHostCapacityReason is used while no ready KindHost has the
+	// capacity for the kind cluster.
+	InsufficientHostCapacityReason = "InsufficientHostCapacity"
+
+	// HostReachableCondition reports whether the docker daemon of a KindHost
+	// answers.
+	HostReachableCondition clusterv1.ConditionType = "HostReachable"
+
+	// HostUnreachableReason is used when the docker daemon of a KindHost can
+	// not be reached.
+	HostUnreachableReason = "HostUnreachable"
+
+	// WithinQuotaCondition reports whether a pending kind cluster fits within
+	// the limits of the manager and the KindClusterQuotas of its namespace.
+	WithinQuotaCondition clusterv1.ConditionType = "WithinQuota"
+
+	// QuotaExceededReason is used while creating the kind cluster would
+	// exceed a quota.
+	QuotaExceededReason = "QuotaExceeded"
+
+	// AccessGrantedCondition reports whether the ClusterRole of a
+	// KindClusterAccess is bound in the workload cluster and its kubeconfig
+	// Secret is up to date.
+	AccessGrantedCondition clusterv1.ConditionType = "AccessGranted"
+
+	// AccessGrantFailedReason is used when binding the ClusterRole or issuing
+	// the client certificate fails.
+	AccessGrantFailedReason = "AccessGrantFailed"
+)
@@ -0,0 +1,59 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KindClusterNameField is the field index of KindClusters by the name of
+// their kind cluster.
+const KindClusterNameField = "spec.name"
+
+// IndexKindClusterName registers the KindClusterNameField index, so that
+// KindClusters can be listed by kind cluster name from the cache.
+func IndexKindClusterName(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &KindCluster{}, KindClusterNameField, kindClusterName)
+}
+
+func kindClusterName(obj client.Object) []string {
+	kindCluster, ok := obj.(*KindCluster)
+	if !ok || kindCluster.Spec.Name == "" {
+		return nil
+	}
+	return []string{kindCluster.Spec.Name}
+}
+
+// KindClusterHostField is the field index of KindClusters by the name of the
+// KindHost they are created on.
+const KindClusterHostField = "hostName"
+
+// IndexKindClusterHost registers the KindClusterHostField index, so that
+// KindClusters can be listed by host from the cache.
+func IndexKindClusterHost(ctx context.Context, indexer client.FieldIndexer) error {
+	return indexer.IndexField(ctx, &KindCluster{}, KindClusterHostField, kindClusterHost)
+}
+
+func kindClusterHost(obj client.Object) []string {
+	kindCluster, ok := obj.(*KindCluster)
+	if !ok || kindCluster.GetHostName() == "" {
+		return nil
+	}
+	return []string{kindCluster.GetHostName()}
+}
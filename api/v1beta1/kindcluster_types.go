@@ -0,0 +1,560 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+type ClusterPhase string
+
+const (
+	ClusterPhasePending      ClusterPhase = "Pending"
+	ClusterPhaseProvisioning ClusterPhase = "Provisioning"
+	ClusterPhaseDeleting     ClusterPhase = "Deleting"
+	ClusterPhaseProvisioned  ClusterPhase = "Provisioned"
+	ClusterPhaseReady        ClusterPhase = "Ready"
+	ClusterPhaseSuspended    ClusterPhase = "Suspended"
+)
+
+// ErrKindClusterExists is wrapped by the errors creating a kind cluster
+// returns when a kind cluster with its name already exists, which the failed
+// create must not clean up, as it might not be the manager's.
+var ErrKindClusterExists = errors.New("kind cluster already exists")
+
+// ExtendLeaseAnnotation extends the lease of a KindCluster with a TTL or
+// expiry. Its value is a duration, e.g. 2h. The controller moves
+// spec.expiresAt to that long from now, unless it already is later, and then
+// removes the annotation.
+const ExtendLeaseAnnotation = "cluster.x-k8s.io/kind-extend-lease"
+
+const (
+	// IdleTimeoutAnnotation sets for how long the API server of a kind
+	// cluster may serve no mutating requests before the KindCluster is
+	// suspended. Its value is a duration, e.g. 2h, or 0 to never suspend the
+	// KindCluster. On a Namespace it sets the default of the KindClusters in
+	// it, which the annotation on a KindCluster overrides.
+	IdleTimeoutAnnotation = "cluster.x-k8s.io/kind-idle-timeout"
+
+	// IdleSuspendedAtAnnotation records when the controller last suspended
+	// the KindCluster because it was idle.
+	IdleSuspendedAtAnnotation = "cluster.x-k8s.io/kind-idle-suspended-at"
+
+	// IdleSuspendReasonAnnotation records why the controller last suspended
+	// the KindCluster because it was idle.
+	IdleSuspendReasonAnnotation = "cluster.x-k8s.io/kind-idle-suspend-reason"
+)
+
+// DeletionProtectionAnnotation protects a KindCluster from being deleted,
+// like spec.deletionProtection, when its value is true.
+const DeletionProtectionAnnotation = "cluster.x-k8s.io/kind-deletion-protection"
+
+// KindClusterSpec defines the desired state of KindCluster
+type KindClusterSpec struct {
+	// Name is the name with which the actual kind cluster will be created. If
+	// the name already exists the KindCluster will stay in the Pending phase
+	// until the cluster is removed. If not set the controller generates a
+	// unique name from the namespace, name and UID of the KindCluster. A name
+	// already used by another KindCluster is rejected.
+	//+optional
+	Name string `json:"name,omitempty"`
+
+	// HostRef references the KindHost the kind cluster is created on. If not
+	// set the kind cluster is created on the docker daemon of the manager, or
+	// on a KindHost picked by the controller if host scheduling is enabled.
+	// It can not be changed once the kind cluster is being created.
+	//+optional
+	HostRef *corev1.LocalObjectReference `json:"hostRef,omitempty"`
+
+	// ControlPlaneNodes specifies the number of control plane nodes for the
+	// kind cluster
+	//+optional
+	ControlPlaneNodes int32 `json:"controlPlaneNodes,omitempty"`
+
+	// WorkerNodes specifies the number of worker nodes for the kind cluster
+	//+optional
+	WorkerNodes int32 `json:"workerNodes,omitempty"`
+
+	// KubernetesVersion selects the kindest/node image of the nodes, e.g.
+	// v1.31.0. Defaults to the node image of the kind release. When the
+	// Cluster references a KindControlPlane its replicas and version are
+	// used instead of ControlPlaneNodes and KubernetesVersion.
+	//+kubebuilder:validation:Pattern=`^v\d+\.\d+\.\d+$`
+	//+optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// ControlPlaneEndpoint is the host and port at which the cluster is
+	// reachable. It will be set by the controller after the cluster has
+	// reached the Created phase.
+	//+optional
+	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint,omitempty"`
+
+	// Remediation enables automatic remediation of the kind cluster when its
+	// node containers have exited or its API server stays unreachable. If not
+	// set the cluster is never remediated.
+	//+optional
+	Remediation *RemediationPolicy `json:"remediation,omitempty"`
+
+	// FailureDomains are the failure domains the nodes of the kind cluster
+	// are spread across. Each failure domain is backed by its own docker
+	// network, which the nodes placed in it are attached to in addition to
+	// the kind network. The nodes are labelled with the failure domain as
+	// their topology.kubernetes.io/zone. Changing the failure domains does
+	// not move existing nodes.
+	//+listType=map
+	//+listMapKey=name
+	//+optional
+	FailureDomains []FailureDomain `json:"failureDomains,omitempty"`
+
+	// TTL is how long after its creation the KindCluster expires. Ignored if
+	// ExpiresAt is set.
+	//+optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// ExpiresAt is when the KindCluster expires. Once expired, the
+	// KindCluster or its Cluster is deleted, as set by ExpirationPolicy.
+	//+optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// ExpirationPolicy is what is deleted once the KindCluster expires.
+	// DeleteKindCluster deletes only the KindCluster and with it the kind
+	// cluster. DeleteCluster deletes the owning Cluster and with it all of
+	// its objects.
+	//+kubebuilder:validation:Enum=DeleteKindCluster;DeleteCluster
+	//+kubebuilder:default=DeleteKindCluster
+	//+optional
+	ExpirationPolicy ExpirationPolicy `json:"expirationPolicy,omitempty"`
+
+	// PoolRef references a KindClusterPool in the namespace of the
+	// KindCluster to claim an idle kind cluster from instead of creating
+	// one. A kind cluster is only claimed if the name is not set and the
+	// nodes, Kubernetes version, failure domains and host match the template
+	// of the pool. If none is idle the kind cluster is created as usual.
+	//+optional
+	PoolRef *corev1.LocalObjectReference `json:"poolRef,omitempty"`
+
+	// Suspended stops the node containers of the kind cluster, keeping them
+	// and their state, and moves the KindCluster to the Suspended phase.
+	// Setting it back to false starts the containers again and waits for
+	// the API server before the KindCluster is Ready again.
+	//+optional
+	Suspended bool `json:"suspended,omitempty"`
+
+	// DeletionPolicy is what happens to the kind cluster when the
+	// KindCluster is deleted. Delete deletes it. Retain keeps it and its
+	// node containers and leaves it to be inspected or deleted with kind.
+	// The owner recorded in /kind/owner.json in the node containers is
+	// overwritten with an empty one, as the file can not be removed while
+	// the containers are stopped, and the kind-owner-<name> volume is
+	// removed. The node containers keep their labels, as docker labels can
+	// not be changed after the container is created.
+	//+kubebuilder:validation:Enum=Delete;Retain
+	//+kubebuilder:default=Delete
+	//+optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// DeletionProtection rejects deletes of the KindCluster and keeps the
+	// controller from deleting its kind cluster, e.g. once it expires,
+	// until it is set back to false.
+	//+optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+}
+
+type ExpirationPolicy string
+
+const (
+	// ExpirationPolicyDeleteKindCluster deletes the expired KindCluster.
+	ExpirationPolicyDeleteKindCluster ExpirationPolicy = "DeleteKindCluster"
+	// ExpirationPolicyDeleteCluster deletes the Cluster owning the expired
+	// KindCluster.
+	ExpirationPolicyDeleteCluster ExpirationPolicy = "DeleteCluster"
+)
+
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the kind cluster with the KindCluster.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain keeps the kind cluster when the KindCluster is
+	// deleted.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// FailureDomain describes a failure domain of a kind cluster
+type FailureDomain struct {
+	// Name is the name of the failure domain and the zone label of its
+	// nodes.
+	//+kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	//+kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Network is the docker network backing the failure domain. It is
+	// created if it does not exist. Defaults to <kind cluster name>-<name>.
+	//+optional
+	Network string `json:"network,omitempty"`
+
+	// ControlPlane indicates whether control plane nodes can be placed in
+	// the failure domain.
+	//+optional
+	ControlPlane bool `json:"controlPlane,omitempty"`
+
+	// Attributes are published with the failure domain in the status.
+	//+optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// GetNetwork returns the Network of the failure domain or its default for
+// the kind cluster name if not set.
+func (d *FailureDomain) GetNetwork(clusterName string) string {
+	if d.Network == "" {
+		return fmt.Sprintf("%s-%s", clusterName, d.Name)
+	}
+	return d.Network
+}
+
+type RemediationAction string
+
+const (
+	// RemediationActionRestartNodes starts the stopped node containers of the
+	// kind cluster.
+	RemediationActionRestartNodes RemediationAction = "RestartNodes"
+	// RemediationActionRecreate deletes the kind cluster and creates it again.
+	RemediationActionRecreate RemediationAction = "Recreate"
+)
+
+const (
+	DefaultRemediationMaxAttempts        = 3
+	DefaultRemediationUnhealthyThreshold = 5 * time.Minute
+	DefaultRemediationCooldown           = 10 * time.Minute
+)
+
+// RemediationPolicy describes when and how often an unhealthy kind cluster is
+// remediated. The first attempt restarts the stopped node containers, every
+// following attempt recreates the whole cluster.
+type RemediationPolicy struct {
+	// MaxAttempts is the number of remediation attempts after which the
+	// controller gives up until the cluster becomes healthy again.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=3
+	//+optional
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// UnhealthyThreshold is how long the cluster has to be unhealthy before
+	// it is remediated.
+	//+kubebuilder:default="5m"
+	//+optional
+	UnhealthyThreshold *metav1.Duration `json:"unhealthyThreshold,omitempty"`
+
+	// Cooldown is the minimum time between two remediation attempts. The
+	// attempt counter is reset once the cluster has been healthy for this
+	// long.
+	//+kubebuilder:default="10m"
+	//+optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// GetMaxAttempts returns MaxAttempts or its default if not set.
+func (p *RemediationPolicy) GetMaxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRemediationMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// GetUnhealthyThreshold returns UnhealthyThreshold or its default if not set.
+func (p *RemediationPolicy) GetUnhealthyThreshold() time.Duration {
+	if p.UnhealthyThreshold == nil {
+		return DefaultRemediationUnhealthyThreshold
+	}
+	return p.UnhealthyThreshold.Duration
+}
+
+// GetCooldown returns Cooldown or its default if not set.
+func (p *RemediationPolicy) GetCooldown() time.Duration {
+	if p.Cooldown == nil {
+		return DefaultRemediationCooldown
+	}
+	return p.Cooldown.Duration
+}
+
+// KindClusterStatus defines the observed state of KindCluster
+type KindClusterStatus struct {
+	// Ready indicates if the cluster's control plane is running and ready to
+	// be used
+	//+kubebuilder:validation:Required
+	//+kubebuilder:default=false
+	Ready bool `json:"ready"`
+	// Phase indicates which phase the cluster creation is in
+	//+kubebuilder:validation:Required
+	//+kubebuilder:default=Pending
+	Phase ClusterPhase `json:"phase"`
+	// FailureMessage indicates there is a fatal problem reconciling the provider's infrastructure
+	//+optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Nodes lists the nodes of the kind cluster as observed on the container
+	// runtime.
+	//+optional
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+
+	// NodeCount is the number of nodes of the kind cluster.
+	//+optional
+	NodeCount int32 `json:"nodeCount,omitempty"`
+
+	// KubernetesVersion is the version reported by the API server of the kind
+	// cluster.
+	//+optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Diagnostics references the logs collected from the nodes of the kind
+	// cluster the last time it failed to create.
+	//+optional
+	Diagnostics *DiagnosticsReference `json:"diagnostics,omitempty"`
+
+	// Remediation records the automatic remediation attempts of the kind
+	// cluster.
+	//+optional
+	Remediation *RemediationStatus `json:"remediation,omitempty"`
+
+	// HostRef references the KindHost the controller picked for the kind
+	// cluster when it has no host in its spec.
+	//+optional
+	HostRef *corev1.LocalObjectReference `json:"hostRef,omitempty"`
+
+	// FailureDomains are the failure domains of the kind cluster that
+	// Machines can be placed in.
+	//+optional
+	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+
+	// ExpiresAt is when the KindCluster expires, from its TTL or expiry.
+	//+optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// ExpiryWarningTime is when the warning that the KindCluster is about to
+	// expire was last emitted.
+	//+optional
+	ExpiryWarningTime *metav1.Time `json:"expiryWarningTime,omitempty"`
+
+	// CertificatesExpireAt is when the first of the certificates kubeadm
+	// issued for the control plane of the kind cluster, including the admin
+	// client certificate of its kubeconfig, expires.
+	//+optional
+	CertificatesExpireAt *metav1.Time `json:"certificatesExpireAt,omitempty"`
+
+	// Activity records the requests served by the API server of the kind
+	// cluster, to tell for how long it has been idle. Only set while idle
+	// suspension is enabled for the KindCluster.
+	//+optional
+	Activity *ActivityStatus `json:"activity,omitempty"`
+
+	// Conditions defines current service state of the KindCluster.
+	//+optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// NodeStatus describes a single node container of a kind cluster
+type NodeStatus struct {
+	// Name is the name of the node container.
+	Name string `json:"name"`
+
+	// Role is the kind role of the node, e.g. control-plane or worker.
+	Role string `json:"role"`
+
+	// ContainerID is the ID of the node container.
+	//+optional
+	ContainerID string `json:"containerID,omitempty"`
+
+	// Image is the node image the container was created from.
+	//+optional
+	Image string `json:"image,omitempty"`
+
+	// InternalIPs are the addresses of the node on the kind network.
+	//+optional
+	InternalIPs []string `json:"internalIPs,omitempty"`
+
+	// State is the state of the node container, e.g. running or exited.
+	//+optional
+	State string `json:"state,omitempty"`
+
+	// FailureDomain is the failure domain the node is placed in.
+	//+optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+
+	// CreationTime is when the node container was created.
+	//+optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+}
+
+type DiagnosticsStorage string
+
+const (
+	DiagnosticsStorageSecret    DiagnosticsStorage = "Secret"
+	DiagnosticsStorageConfigMap DiagnosticsStorage = "ConfigMap"
+	DiagnosticsStorageHostPath  DiagnosticsStorage = "HostPath"
+)
+
+// DiagnosticsReference points to a gzipped tarball of the logs collected from
+// a kind cluster that failed to create
+type DiagnosticsReference struct {
+	// Storage is where the archive is stored.
+	//+kubebuilder:validation:Enum=Secret;ConfigMap;HostPath
+	Storage DiagnosticsStorage `json:"storage"`
+
+	// Name is the name of the Secret or ConfigMap in the namespace of the
+	// KindCluster holding the archive.
+	//+optional
+	Name string `json:"name,omitempty"`
+
+	// Key is the key of the archive in the Secret or ConfigMap.
+	//+optional
+	Key string `json:"key,omitempty"`
+
+	// Path is the path of the archive on the manager host.
+	//+optional
+	Path string `json:"path,omitempty"`
+
+	// Size is the size of the archive in bytes.
+	Size int `json:"size"`
+
+	// CollectedAt is when the logs were collected.
+	CollectedAt metav1.Time `json:"collectedAt"`
+}
+
+// RemediationStatus records the remediation attempts of a kind cluster
+type RemediationStatus struct {
+	// Attempts is the number of remediation attempts since the cluster was
+	// last healthy.
+	Attempts int `json:"attempts"`
+
+	// LastAttemptTime is when the cluster was last remediated.
+	//+optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// LastAction is the action taken by the last remediation attempt.
+	//+optional
+	LastAction RemediationAction `json:"lastAction,omitempty"`
+
+	// Exhausted is true once all remediation attempts were made and the
+	// cluster is still unhealthy, so that it is only reported once.
+	//+optional
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
+// ActivityStatus records the activity of the API server of a kind cluster
+type ActivityStatus struct {
+	// MutatingRequests is the number of mutating requests to workload
+	// resources the API server had served since it started when it was last
+	// checked.
+	MutatingRequests int64 `json:"mutatingRequests"`
+
+	// LastActiveTime is when the number of mutating requests was last seen
+	// changing, or when checking it started.
+	LastActiveTime metav1.Time `json:"lastActiveTime"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.kubernetesVersion`
+//+kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.nodeCount`
+//+kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.controlPlaneEndpoint.host`
+//+kubebuilder:printcolumn:name="Port",type=integer,JSONPath=`.spec.controlPlaneEndpoint.port`,priority=1
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`
+
+// KindCluster is the Schema for the kindclusters API
+type KindCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindClusterSpec   `json:"spec,omitempty"`
+	Status KindClusterStatus `json:"status,omitempty"`
+}
+
+// GetHostName returns the name of the KindHost the kind cluster is created
+// on, either from the spec or as picked by the controller, or an empty
+// string for the docker daemon of the manager.
+func (c *KindCluster) GetHostName() string {
+	if c.Spec.HostRef != nil {
+		return c.Spec.HostRef.Name
+	}
+	if c.Status.HostRef != nil {
+		return c.Status.HostRef.Name
+	}
+	return ""
+}
+
+// GetExpiresAt returns when the KindCluster expires, either its ExpiresAt or
+// its creation time plus its TTL, or nil if it does not expire.
+func (c *KindCluster) GetExpiresAt() *metav1.Time {
+	if c.Spec.ExpiresAt != nil {
+		return c.Spec.ExpiresAt
+	}
+	if c.Spec.TTL != nil {
+		return &metav1.Time{Time: c.CreationTimestamp.Add(c.Spec.TTL.Duration)}
+	}
+	return nil
+}
+
+// IsDeletionProtected returns whether the KindCluster is protected from
+// being deleted, either by its spec or by the DeletionProtectionAnnotation.
+func (c *KindCluster) IsDeletionProtected() bool {
+	if c.Spec.DeletionProtection {
+		return true
+	}
+	protected, _ := strconv.ParseBool(c.Annotations[DeletionProtectionAnnotation])
+	return protected
+}
+
+// GetRequestedNodes returns the number of nodes the kind cluster takes up.
+// This is the number of nodes kind creates, or the number of nodes it has
+// if more have been added since, e.g. by a KindMachinePool.
+func (c *KindCluster) GetRequestedNodes() int32 {
+	nodes := max(c.Spec.ControlPlaneNodes, 1) + c.Spec.WorkerNodes
+	return max(nodes, c.Status.NodeCount)
+}
+
+// GetConditions returns the set of conditions for this object.
+func (c *KindCluster) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *KindCluster) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KindClusterList contains a list of KindCluster
+type KindClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindCluster{}, &KindClusterList{})
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// kindNetwork is the docker network kind attaches all nodes to.
+const kindNetwork = "kind"
+
+//+kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-kindcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=kindclusters,verbs=create;update;delete,versions=v1beta1,name=validation.kindcluster.infrastructure.cluster.x-k8s.io,admissionReviewVersions=v1
+
+// SetupWebhookWithManager registers the webhooks for KindCluster, including
+// the conversion webhook for the older API versions. The validating webhook
+// enforces the limits and the KindClusterQuotas. It reads the other
+// KindClusters and the KindClusterQuotas with the API reader of the manager,
+// as the cache may not have seen a KindCluster created right before, which
+// would let two KindClusters take the same kind cluster name or exceed a
+// quota together.
+func (c *KindCluster) SetupWebhookWithManager(mgr ctrl.Manager, limits QuotaLimits) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(c).
+		WithValidator(NewKindClusterValidator(mgr.GetAPIReader(), limits)).
+		Complete()
+}
+
+//+kubebuilder:object:generate=false
+
+// KindClusterValidator rejects KindClusters using a kind cluster name that
+// is already used by another KindCluster, failure domains sharing a docker
+// network, an invalid TTL, lease extension, idle timeout or deletion
+// protection, or exceeding the limits or a KindClusterQuota. It also rejects
+// deletes of KindClusters protected from deletion.
+type KindClusterValidator struct {
+	reader client.Reader
+	limits QuotaLimits
+}
+
+var _ webhook.CustomValidator = &KindClusterValidator{}
+
+func NewKindClusterValidator(reader client.Reader, limits QuotaLimits) *KindClusterValidator {
+	return &KindClusterValidator{
+		reader: reader,
+		limits: limits,
+	}
+}
+
+// ValidateCreate checks that the kind cluster name is not already in use,
+// that the failure domains are valid and that the KindCluster fits within
+// the quotas.
+func (v *KindClusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	kindCluster, ok := obj.(*KindCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindCluster but got a %T", obj))
+	}
+
+	err := v.validate(ctx, kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, v.checkQuota(ctx, kindCluster)
+}
+
+// ValidateUpdate checks that the kind cluster name is not already in use,
+// that the failure domains are valid, that the host and kind cluster name are
+// not changed once the kind cluster is being created and that added nodes fit
+// within the quotas.
+func (v *KindClusterValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	kindCluster, ok := newObj.(*KindCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindCluster but got a %T", newObj))
+	}
+
+	oldKindCluster, ok := oldObj.(*KindCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindCluster but got a %T", oldObj))
+	}
+
+	phase := oldKindCluster.Status.Phase
+	if phase != "" && phase != ClusterPhasePending {
+		allErrs := field.ErrorList{}
+		if !equality.Semantic.DeepEqual(oldKindCluster.Spec.HostRef, kindCluster.Spec.HostRef) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "hostRef"),
+				"can not be changed once the kind cluster is being created"))
+		}
+		if oldKindCluster.Spec.Name != "" && oldKindCluster.Spec.Name != kindCluster.Spec.Name {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "name"),
+				"can not be changed once the kind cluster is being created"))
+		}
+		if len(allErrs) > 0 {
+			return nil, apierrors.NewInvalid(GroupVersion.WithKind("KindCluster").GroupKind(), kindCluster.Name, allErrs)
+		}
+	}
+
+	err := v.validate(ctx, kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if kindCluster.GetRequestedNodes() <= oldKindCluster.GetRequestedNodes() {
+		return nil, nil
+	}
+
+	return nil, v.checkQuota(ctx, kindCluster)
+}
+
+// ValidateDelete rejects deleting a KindCluster that is protected from
+// deletion, unless clusterctl move deletes it from the source management
+// cluster after moving it. That leaves the kind cluster alone, as the
+// KindCluster has no finalizer by then.
+func (v *KindClusterValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	kindCluster, ok := obj.(*KindCluster)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindCluster but got a %T", obj))
+	}
+
+	if !kindCluster.IsDeletionProtected() || deletedForMove(kindCluster) {
+		return nil, nil
+	}
+
+	return nil, apierrors.NewForbidden(GroupVersion.WithResource("kindclusters").GroupResource(), kindCluster.Name,
+		fmt.Errorf("KindCluster is protected from deletion, set spec.deletionProtection to false and remove the %s annotation first",
+			DeletionProtectionAnnotation))
+}
+
+func (v *KindClusterValidator) validate(ctx context.Context, kindCluster *KindCluster) error {
+	allErrs := validateFailureDomains(kindCluster)
+	allErrs = append(allErrs, validateExpiry(kindCluster)...)
+	allErrs = append(allErrs, validateIdleTimeout(kindCluster)...)
+	allErrs = append(allErrs, validateDeletionProtection(kindCluster)...)
+
+	if kindCluster.Spec.Name != "" {
+		// The API server can not filter by the kind cluster name, so all
+		// KindClusters are listed.
+		list := &KindClusterList{}
+		err := v.reader.List(ctx, list)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+
+		for _, other := range list.Items {
+			if other.Spec.Name != kindCluster.Spec.Name {
+				continue
+			}
+			if other.Namespace == kindCluster.Namespace && other.Name == kindCluster.Name {
+				continue
+			}
+
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "name"), kindCluster.Spec.Name,
+				fmt.Sprintf("kind cluster name is already used by KindCluster %s/%s", other.Namespace, other.Name)))
+			break
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("KindCluster").GroupKind(), kindCluster.Name, allErrs)
+}
+
+// checkQuota rejects the KindCluster if it exceeds the limits or a
+// KindClusterQuota, counting all other KindClusters whether or not their
+// kind cluster has been created yet.
+func (v *KindClusterValidator) checkQuota(ctx context.Context, kindCluster *KindCluster) error {
+	err := CheckQuota(ctx, v.reader, kindCluster, v.limits, func(*KindCluster) bool { return true })
+	if errors.Is(err, ErrQuotaExceeded) {
+		return apierrors.NewForbidden(GroupVersion.WithResource("kindclusters").GroupResource(), kindCluster.Name, err)
+	}
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+
+	return nil
+}
+
+// validateExpiry checks that the TTL and the lease extension are positive
+// durations.
+func validateExpiry(kindCluster *KindCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if kindCluster.Spec.TTL != nil && kindCluster.Spec.TTL.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "ttl"), kindCluster.Spec.TTL.Duration.String(),
+			"must be a positive duration"))
+	}
+
+	if value, ok := kindCluster.Annotations[ExtendLeaseAnnotation]; ok {
+		path := field.NewPath("metadata", "annotations").Key(ExtendLeaseAnnotation)
+		extension, err := time.ParseDuration(value)
+		if err != nil || extension <= 0 {
+			allErrs = append(allErrs, field.Invalid(path, value, "must be a positive duration, e.g. 2h"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateIdleTimeout checks that the idle timeout is a duration that is not
+// negative.
+func validateIdleTimeout(kindCluster *KindCluster) field.ErrorList {
+	value, ok := kindCluster.Annotations[IdleTimeoutAnnotation]
+	if !ok {
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		path := field.NewPath("metadata", "annotations").Key(IdleTimeoutAnnotation)
+		return field.ErrorList{field.Invalid(path, value, "must be a duration, e.g. 2h, or 0 to disable")}
+	}
+
+	return nil
+}
+
+// validateDeletionProtection checks that the deletion protection annotation
+// is a boolean.
+func validateDeletionProtection(kindCluster *KindCluster) field.ErrorList {
+	value, ok := kindCluster.Annotations[DeletionProtectionAnnotation]
+	if !ok {
+		return nil
+	}
+
+	if _, err := strconv.ParseBool(value); err != nil {
+		path := field.NewPath("metadata", "annotations").Key(DeletionProtectionAnnotation)
+		return field.ErrorList{field.Invalid(path, value, "must be true or false")}
+	}
+
+	return nil
+}
+
+// validateFailureDomains checks that every failure domain is backed by its
+// own docker network, other than the kind network shared by all nodes.
+func validateFailureDomains(kindCluster *KindCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	networks := map[string]bool{}
+	for i, failureDomain := range kindCluster.Spec.FailureDomains {
+		// The network defaults can only be compared once the name is known.
+		if failureDomain.Network == "" && kindCluster.Spec.Name == "" {
+			continue
+		}
+
+		path := field.NewPath("spec", "failureDomains").Index(i).Child("network")
+		network := failureDomain.GetNetwork(kindCluster.Spec.Name)
+		if network == kindNetwork {
+			allErrs = append(allErrs, field.Invalid(path, network, "the kind network can not back a failure domain"))
+			continue
+		}
+		if networks[network] {
+			allErrs = append(allErrs, field.Duplicate(path, network))
+			continue
+		}
+		networks[network] = true
+	}
+
+	return allErrs
+}
+
+// deletedForMove returns whether clusterctl move is deleting the KindCluster
+// from the source management cluster: right before the delete, it sets the
+// delete-for-move annotation and removes the finalizers. Pausing the
+// KindCluster or its Cluster alone does not allow deleting it.
+func deletedForMove(kindCluster *KindCluster) bool {
+	_, ok := kindCluster.Annotations[clusterctlv1.DeleteForMoveAnnotation]
+	return ok && len(kindCluster.Finalizers) == 0
+}
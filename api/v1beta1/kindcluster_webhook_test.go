@@ -0,0 +1,588 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKindClusterValidator(t *testing.T) {
+	existing := &KindCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+		Spec:       KindClusterSpec{Name: "the-kind-cluster-name"},
+	}
+
+	tests := []struct {
+		name        string
+		kindCluster *KindCluster
+		wantErr     bool
+	}{
+		{
+			name: "allows an unused kind cluster name",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "baz"},
+				Spec:       KindClusterSpec{Name: "another-kind-cluster-name"},
+			},
+		},
+		{
+			name: "allows an empty kind cluster name",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "baz"},
+			},
+		},
+		{
+			name:        "allows the KindCluster already using the name",
+			kindCluster: existing.DeepCopy(),
+		},
+		{
+			name: "rejects a kind cluster name used by another KindCluster",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "baz"},
+				Spec:       KindClusterSpec{Name: "the-kind-cluster-name"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(existing.DeepCopy()).
+				Build()
+			validator := NewKindClusterValidator(reader, QuotaLimits{})
+
+			_, createErr := validator.ValidateCreate(context.Background(), tt.kindCluster)
+			_, updateErr := validator.ValidateUpdate(context.Background(), tt.kindCluster, tt.kindCluster)
+			if tt.wantErr {
+				g.Expect(createErr).To(MatchError(ContainSubstring("already used by KindCluster bar/foo")))
+				g.Expect(updateErr).To(MatchError(ContainSubstring("already used by KindCluster bar/foo")))
+			} else {
+				g.Expect(createErr).NotTo(HaveOccurred())
+				g.Expect(updateErr).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterValidatorFailureDomains(t *testing.T) {
+	tests := []struct {
+		name           string
+		clusterName    string
+		failureDomains []FailureDomain
+		wantErr        string
+	}{
+		{
+			name:        "allows failure domains with distinct networks",
+			clusterName: "potato",
+			failureDomains: []FailureDomain{
+				{Name: "zone-a", ControlPlane: true},
+				{Name: "zone-b", Network: "zone-b-network"},
+			},
+		},
+		{
+			name: "allows defaulted networks before the kind cluster name is set",
+			failureDomains: []FailureDomain{
+				{Name: "zone-a"},
+				{Name: "zone-b"},
+			},
+		},
+		{
+			name:        "rejects failure domains sharing a network",
+			clusterName: "potato",
+			failureDomains: []FailureDomain{
+				{Name: "zone-a", Network: "shared"},
+				{Name: "zone-b", Network: "shared"},
+			},
+			wantErr: "spec.failureDomains[1].network: Duplicate value",
+		},
+		{
+			name:        "rejects a network clashing with a defaulted network",
+			clusterName: "potato",
+			failureDomains: []FailureDomain{
+				{Name: "zone-a"},
+				{Name: "zone-b", Network: "potato-zone-a"},
+			},
+			wantErr: "spec.failureDomains[1].network: Duplicate value",
+		},
+		{
+			name:        "rejects the kind network",
+			clusterName: "potato",
+			failureDomains: []FailureDomain{
+				{Name: "zone-a", Network: "kind"},
+			},
+			wantErr: "the kind network can not back a failure domain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				Build()
+			validator := NewKindClusterValidator(reader, QuotaLimits{})
+
+			kindCluster := &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Spec: KindClusterSpec{
+					Name:           tt.clusterName,
+					FailureDomains: tt.failureDomains,
+				},
+			}
+
+			_, err := validator.ValidateCreate(context.Background(), kindCluster)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterValidatorHostRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		phase   ClusterPhase
+		oldHost *corev1.LocalObjectReference
+		newHost *corev1.LocalObjectReference
+		wantErr bool
+	}{
+		{
+			name:    "allows setting the host of a pending kind cluster",
+			phase:   ClusterPhasePending,
+			newHost: &corev1.LocalObjectReference{Name: "remote"},
+		},
+		{
+			name:    "allows keeping the host of a created kind cluster",
+			phase:   ClusterPhaseReady,
+			oldHost: &corev1.LocalObjectReference{Name: "remote"},
+			newHost: &corev1.LocalObjectReference{Name: "remote"},
+		},
+		{
+			name:    "rejects changing the host of a created kind cluster",
+			phase:   ClusterPhaseReady,
+			oldHost: &corev1.LocalObjectReference{Name: "remote"},
+			newHost: &corev1.LocalObjectReference{Name: "another-remote"},
+			wantErr: true,
+		},
+		{
+			name:    "rejects removing the host of a provisioning kind cluster",
+			phase:   ClusterPhaseProvisioning,
+			oldHost: &corev1.LocalObjectReference{Name: "remote"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				Build()
+			validator := NewKindClusterValidator(reader, QuotaLimits{})
+
+			oldKindCluster := &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Spec:       KindClusterSpec{Name: "potato", HostRef: tt.oldHost},
+				Status:     KindClusterStatus{Phase: tt.phase},
+			}
+			newKindCluster := oldKindCluster.DeepCopy()
+			newKindCluster.Spec.HostRef = tt.newHost
+
+			_, err := validator.ValidateUpdate(context.Background(), oldKindCluster, newKindCluster)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ContainSubstring("spec.hostRef: Forbidden")))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterValidatorName(t *testing.T) {
+	tests := []struct {
+		name    string
+		phase   ClusterPhase
+		oldName string
+		newName string
+		wantErr bool
+	}{
+		{
+			name:    "allows changing the name of a pending kind cluster",
+			phase:   ClusterPhasePending,
+			oldName: "potato",
+			newName: "carrot",
+		},
+		{
+			name:    "allows setting the name of a created kind cluster without one",
+			phase:   ClusterPhaseReady,
+			newName: "carrot",
+		},
+		{
+			name:    "rejects changing the name of a created kind cluster",
+			phase:   ClusterPhaseReady,
+			oldName: "potato",
+			newName: "carrot",
+			wantErr: true,
+		},
+		{
+			name:    "rejects removing the name of a provisioning kind cluster",
+			phase:   ClusterPhaseProvisioning,
+			oldName: "potato",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				Build()
+			validator := NewKindClusterValidator(reader, QuotaLimits{})
+
+			oldKindCluster := &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Spec:       KindClusterSpec{Name: tt.oldName},
+				Status:     KindClusterStatus{Phase: tt.phase},
+			}
+			newKindCluster := oldKindCluster.DeepCopy()
+			newKindCluster.Spec.Name = tt.newName
+
+			_, err := validator.ValidateUpdate(context.Background(), oldKindCluster, newKindCluster)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ContainSubstring("spec.name: Forbidden")))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterValidatorQuota(t *testing.T) {
+	existing := &KindCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "bar"},
+		Spec:       KindClusterSpec{Name: "existing-kind-cluster-name", WorkerNodes: 1},
+	}
+	quota := &KindClusterQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "bar"},
+		Spec: KindClusterQuotaSpec{
+			MaxClusters: ptr.To[int32](2),
+			MaxNodes:    ptr.To[int32](4),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		kindCluster *KindCluster
+		limits      QuotaLimits
+		wantErr     string
+	}{
+		{
+			name: "allows a KindCluster within the quota",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			},
+		},
+		{
+			name: "rejects a KindCluster exceeding the nodes of the quota",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Spec:       KindClusterSpec{WorkerNodes: 2},
+			},
+			wantErr: "5 nodes exceed the maximum of 4 of KindClusterQuota quota",
+		},
+		{
+			name: "ignores the quotas of other namespaces",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "baz"},
+				Spec:       KindClusterSpec{WorkerNodes: 5},
+			},
+		},
+		{
+			name: "rejects a KindCluster exceeding the global cluster limit",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "baz"},
+			},
+			limits:  QuotaLimits{MaxClusters: 1},
+			wantErr: "2 KindClusters exceed the limit of 1",
+		},
+		{
+			name: "rejects a KindCluster exceeding the global node limit",
+			kindCluster: &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "baz"},
+				Spec:       KindClusterSpec{ControlPlaneNodes: 3},
+			},
+			limits:  QuotaLimits{MaxNodes: 4},
+			wantErr: "5 nodes exceed the limit of 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(existing.DeepCopy(), quota.DeepCopy()).
+				Build()
+			validator := NewKindClusterValidator(reader, tt.limits)
+
+			_, err := validator.ValidateCreate(context.Background(), tt.kindCluster)
+			if tt.wantErr != "" {
+				g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterValidatorQuotaUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&KindClusterQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "bar"},
+			Spec:       KindClusterQuotaSpec{MaxNodes: ptr.To[int32](2)},
+		}).
+		Build()
+	validator := NewKindClusterValidator(reader, QuotaLimits{})
+
+	oldKindCluster := &KindCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Labels: map[string]string{"a": "b"}},
+		Spec:       KindClusterSpec{WorkerNodes: 2},
+	}
+
+	// Updates not adding nodes are allowed even if the KindCluster already
+	// exceeds the quota, e.g. because the quota was lowered.
+	newKindCluster := oldKindCluster.DeepCopy()
+	newKindCluster.Labels = nil
+	_, err := validator.ValidateUpdate(context.Background(), oldKindCluster, newKindCluster)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newKindCluster.Spec.WorkerNodes = 3
+	_, err = validator.ValidateUpdate(context.Background(), oldKindCluster, newKindCluster)
+	g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+}
+
+func TestKindClusterValidatorLifecycle(t *testing.T) {
+	tests := []struct {
+		name        string
+		ttl         *metav1.Duration
+		annotations map[string]string
+		wantErr     string
+	}{
+		{
+			name: "allows a positive TTL",
+			ttl:  &metav1.Duration{Duration: time.Hour},
+		},
+		{
+			name:    "rejects a zero TTL",
+			ttl:     &metav1.Duration{},
+			wantErr: "spec.ttl",
+		},
+		{
+			name:        "allows a lease extension",
+			annotations: map[string]string{ExtendLeaseAnnotation: "2h"},
+		},
+		{
+			name:        "rejects a lease extension that is not a duration",
+			annotations: map[string]string{ExtendLeaseAnnotation: "tomorrow"},
+			wantErr:     ExtendLeaseAnnotation,
+		},
+		{
+			name:        "rejects a negative lease extension",
+			annotations: map[string]string{ExtendLeaseAnnotation: "-1h"},
+			wantErr:     ExtendLeaseAnnotation,
+		},
+		{
+			name:        "allows an idle timeout",
+			annotations: map[string]string{IdleTimeoutAnnotation: "30m"},
+		},
+		{
+			name:        "allows disabling idle suspension",
+			annotations: map[string]string{IdleTimeoutAnnotation: "0"},
+		},
+		{
+			name:        "rejects an idle timeout that is not a duration",
+			annotations: map[string]string{IdleTimeoutAnnotation: "never"},
+			wantErr:     IdleTimeoutAnnotation,
+		},
+		{
+			name:        "rejects a negative idle timeout",
+			annotations: map[string]string{IdleTimeoutAnnotation: "-1h"},
+			wantErr:     IdleTimeoutAnnotation,
+		},
+		{
+			name:        "allows deletion protection",
+			annotations: map[string]string{DeletionProtectionAnnotation: "true"},
+		},
+		{
+			name:        "rejects deletion protection that is not a boolean",
+			annotations: map[string]string{DeletionProtectionAnnotation: "yes please"},
+			wantErr:     DeletionProtectionAnnotation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(AddToScheme(scheme)).To(Succeed())
+			reader := fake.NewClientBuilder().
+				WithScheme(scheme).
+				Build()
+			validator := NewKindClusterValidator(reader, QuotaLimits{})
+
+			kindCluster := &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Annotations: tt.annotations},
+				Spec:       KindClusterSpec{TTL: tt.ttl},
+			}
+
+			_, err := validator.ValidateCreate(context.Background(), kindCluster)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterValidatorDelete(t *testing.T) {
+	tests := []struct {
+		name               string
+		annotations        map[string]string
+		finalizers         []string
+		deletionProtection bool
+		clusterPaused      bool
+		wantErr            bool
+	}{
+		{
+			name: "allows deleting an unprotected KindCluster",
+		},
+		{
+			name:               "rejects deleting a KindCluster protected by its spec",
+			deletionProtection: true,
+			wantErr:            true,
+		},
+		{
+			name:        "rejects deleting a KindCluster protected by the annotation",
+			annotations: map[string]string{DeletionProtectionAnnotation: "true"},
+			wantErr:     true,
+		},
+		{
+			name:        "allows deleting a KindCluster with the protection turned off",
+			annotations: map[string]string{DeletionProtectionAnnotation: "false"},
+		},
+		{
+			name:               "rejects deleting a protected KindCluster that is paused but not being moved",
+			annotations:        map[string]string{clusterv1.PausedAnnotation: ""},
+			deletionProtection: true,
+			wantErr:            true,
+		},
+		{
+			name:               "rejects deleting a protected KindCluster whose Cluster is paused but not being moved",
+			deletionProtection: true,
+			clusterPaused:      true,
+			wantErr:            true,
+		},
+		{
+			name:               "allows deleting a protected KindCluster that clusterctl move deletes",
+			annotations:        map[string]string{clusterctlv1.DeleteForMoveAnnotation: ""},
+			deletionProtection: true,
+			clusterPaused:      true,
+		},
+		{
+			name:               "rejects deleting a protected KindCluster annotated for move that still has its finalizer",
+			annotations:        map[string]string{clusterctlv1.DeleteForMoveAnnotation: ""},
+			finalizers:         []string{"kindcluster.infrastructure.cluster.x-k8s.io"},
+			deletionProtection: true,
+			clusterPaused:      true,
+			wantErr:            true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+				Spec:       clusterv1.ClusterSpec{Paused: tt.clusterPaused},
+			}
+			validator := NewKindClusterValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build(), QuotaLimits{})
+			kindCluster := &KindCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "foo",
+					Namespace:   "bar",
+					Annotations: tt.annotations,
+					Finalizers:  tt.finalizers,
+					OwnerReferences: []metav1.OwnerReference{
+						{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "foo", UID: "cluster-uid"},
+					},
+				},
+				Spec: KindClusterSpec{DeletionProtection: tt.deletionProtection},
+			}
+
+			_, err := validator.ValidateDelete(context.Background(), kindCluster)
+			if tt.wantErr {
+				g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+				g.Expect(err).To(MatchError(ContainSubstring("protected from deletion")))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
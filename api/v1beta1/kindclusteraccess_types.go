@@ -0,0 +1,162 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// KindClusterAccessSpec defines the desired state of KindClusterAccess
+type KindClusterAccessSpec struct {
+	// KindClusterRef references the KindCluster in the same namespace whose
+	// kind cluster access is granted to. It can not be changed.
+	//+kubebuilder:validation:XValidation:rule="self == oldSelf",message="kindClusterRef is immutable"
+	KindClusterRef corev1.LocalObjectReference `json:"kindClusterRef"`
+
+	// User is the user name of the client certificate, and the user the
+	// ClusterRole is bound to. Users starting with system: are reserved for
+	// Kubernetes.
+	//+kubebuilder:validation:MinLength=1
+	//+kubebuilder:validation:XValidation:rule="!self.startsWith('system:')",message="users starting with system: are reserved"
+	User string `json:"user"`
+
+	// Groups are the groups of the client certificate, which the ClusterRole
+	// is bound to as well. Groups starting with system: are reserved for
+	// Kubernetes.
+	//+kubebuilder:validation:XValidation:rule="self.all(g, !g.startsWith('system:'))",message="groups starting with system: are reserved"
+	//+optional
+	Groups []string `json:"groups,omitempty"`
+
+	// ClusterRole is the name of the ClusterRole in the workload cluster that
+	// is bound to the user and groups, e.g. view or edit.
+	//+kubebuilder:validation:MinLength=1
+	ClusterRole string `json:"clusterRole"`
+
+	// Namespace restricts the access to a namespace of the workload cluster
+	// by binding the ClusterRole with a RoleBinding in it. The namespace has
+	// to exist. If not set the ClusterRole is bound cluster wide.
+	//+optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Expiry is for how long the client certificate is valid. It is rotated
+	// once two thirds of it have passed.
+	//+kubebuilder:default="24h"
+	//+optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+}
+
+// KindClusterAccessStatus defines the observed state of KindClusterAccess
+type KindClusterAccessStatus struct {
+	// Ready is true when the ClusterRole is bound in the workload cluster and
+	// the kubeconfig Secret holds a valid client certificate.
+	//+optional
+	Ready bool `json:"ready"`
+
+	// SecretName is the name of the Secret holding the kubeconfig in the
+	// value key.
+	//+optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// IssuedAt is when the current client certificate was issued.
+	//+optional
+	IssuedAt *metav1.Time `json:"issuedAt,omitempty"`
+
+	// ExpiresAt is when the current client certificate expires.
+	//+optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// CACertHash is the hash of the CA certificate of the workload cluster
+	// the current client certificate was signed with, so that it is issued
+	// again if the kind cluster is created again.
+	//+optional
+	CACertHash string `json:"caCertHash,omitempty"`
+
+	// ObservedGeneration is the generation of the KindClusterAccess the
+	// current client certificate was issued for.
+	//+optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current service state of the KindClusterAccess.
+	//+optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="KindCluster",type=string,JSONPath=`.spec.kindClusterRef.name`
+//+kubebuilder:printcolumn:name="User",type=string,JSONPath=`.spec.user`
+//+kubebuilder:printcolumn:name="ClusterRole",type=string,JSONPath=`.spec.clusterRole`
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`
+
+// KindClusterAccess is the Schema for the kindclusteraccesses API. It grants
+// a user access to the kind cluster of a KindCluster with a ClusterRole, and
+// keeps a kubeconfig with a short-lived client certificate for the user in
+// a Secret.
+type KindClusterAccess struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindClusterAccessSpec   `json:"spec,omitempty"`
+	Status KindClusterAccessStatus `json:"status,omitempty"`
+}
+
+// DefaultAccessExpiry is for how long the client certificate of a
+// KindClusterAccess without an expiry is valid.
+const DefaultAccessExpiry = 24 * time.Hour
+
+// GetExpiry returns for how long the client certificate is valid.
+func (a *KindClusterAccess) GetExpiry() time.Duration {
+	if a.Spec.Expiry == nil || a.Spec.Expiry.Duration <= 0 {
+		return DefaultAccessExpiry
+	}
+	return a.Spec.Expiry.Duration
+}
+
+// GetSecretName returns the name of the Secret holding the kubeconfig. It
+// does not end in -kubeconfig alone so that it can not collide with the
+// kubeconfig Secret of a Cluster.
+func (a *KindClusterAccess) GetSecretName() string {
+	return a.Name + "-access-kubeconfig"
+}
+
+// GetConditions returns the set of conditions for this object.
+func (a *KindClusterAccess) GetConditions() clusterv1.Conditions {
+	return a.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (a *KindClusterAccess) SetConditions(conditions clusterv1.Conditions) {
+	a.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KindClusterAccessList contains a list of KindClusterAccess
+type KindClusterAccessList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindClusterAccess `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindClusterAccess{}, &KindClusterAccessList{})
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PooledClusterPhase string
+
+const (
+	// PooledClusterPhaseProvisioning is the phase of a pooled kind cluster
+	// that is being created.
+	PooledClusterPhaseProvisioning PooledClusterPhase = "Provisioning"
+	// PooledClusterPhaseIdle is the phase of a pooled kind cluster that is
+	// ready to be claimed.
+	PooledClusterPhaseIdle PooledClusterPhase = "Idle"
+	// PooledClusterPhaseClaimed is the phase of a pooled kind cluster that
+	// has been claimed by a KindCluster, but not yet handed over to it.
+	PooledClusterPhaseClaimed PooledClusterPhase = "Claimed"
+	// PooledClusterPhaseDeleting is the phase of a pooled kind cluster that
+	// is being deleted and can no longer be claimed.
+	PooledClusterPhaseDeleting PooledClusterPhase = "Deleting"
+)
+
+// KindClusterPoolSpec defines the desired state of KindClusterPool
+type KindClusterPoolSpec struct {
+	// Size is the number of kind clusters kept ready to be claimed. Claimed
+	// kind clusters are replaced in the background.
+	//+kubebuilder:validation:Minimum=0
+	Size int32 `json:"size"`
+
+	// Template is the spec of the kind clusters of the pool. Only the nodes,
+	// Kubernetes version, failure domains and host are used. Idle kind
+	// clusters created from an older template are replaced.
+	Template KindClusterSpec `json:"template"`
+}
+
+// KindClusterPoolStatus defines the observed state of KindClusterPool
+type KindClusterPoolStatus struct {
+	// Ready is the number of idle kind clusters that can be claimed.
+	//+optional
+	Ready int32 `json:"ready"`
+
+	// Clusters are the kind clusters of the pool that have not been handed
+	// over to a KindCluster yet.
+	//+listType=map
+	//+listMapKey=name
+	//+optional
+	Clusters []PooledCluster `json:"clusters,omitempty"`
+}
+
+// PooledCluster describes a kind cluster of a KindClusterPool
+type PooledCluster struct {
+	// Name is the name of the kind cluster.
+	Name string `json:"name"`
+
+	// Phase is the phase of the kind cluster in the pool.
+	//+kubebuilder:validation:Enum=Provisioning;Idle;Claimed;Deleting
+	Phase PooledClusterPhase `json:"phase"`
+
+	// TemplateHash is the hash of the template the kind cluster was created
+	// from.
+	TemplateHash string `json:"templateHash"`
+
+	// ClaimedBy references the KindCluster that claimed the kind cluster.
+	//+optional
+	ClaimedBy *corev1.ObjectReference `json:"claimedBy,omitempty"`
+
+	// ClaimedAt is when the kind cluster was claimed.
+	//+optional
+	ClaimedAt *metav1.Time `json:"claimedAt,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Size",type=integer,JSONPath=`.spec.size`
+//+kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.ready`
+
+// KindClusterPool is the Schema for the kindclusterpools API. It keeps kind
+// clusters of the same spec created ahead of time, so that KindClusters
+// referencing the pool can claim one instead of waiting for kind to create
+// theirs.
+type KindClusterPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindClusterPoolSpec   `json:"spec,omitempty"`
+	Status KindClusterPoolStatus `json:"status,omitempty"`
+}
+
+// Matches returns true if the kind clusters of the pool can be claimed by the
+// KindCluster, as it asks for the same nodes, Kubernetes version, failure
+// domains and host as the template of the pool.
+func (p *KindClusterPool) Matches(kindCluster *KindCluster) bool {
+	return equality.Semantic.DeepEqual(poolShape(&p.Spec.Template), poolShape(&kindCluster.Spec))
+}
+
+// TemplateHash returns a hash of the parts of the template that shape the
+// kind clusters of the pool.
+func (p *KindClusterPool) TemplateHash() string {
+	// The spec only holds plain fields, so marshalling it can not fail.
+	data, _ := json.Marshal(poolShape(&p.Spec.Template))
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])[:10]
+}
+
+// poolShape returns the parts of the spec that have to match for a KindCluster
+// to claim a kind cluster of a pool.
+func poolShape(spec *KindClusterSpec) KindClusterSpec {
+	return KindClusterSpec{
+		HostRef:           spec.HostRef,
+		ControlPlaneNodes: max(spec.ControlPlaneNodes, 1),
+		WorkerNodes:       spec.WorkerNodes,
+		KubernetesVersion: spec.KubernetesVersion,
+		FailureDomains:    spec.FailureDomains,
+	}
+}
+
+//+kubebuilder:object:root=true
+
+// KindClusterPoolList contains a list of KindClusterPool
+type KindClusterPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindClusterPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindClusterPool{}, &KindClusterPoolList{})
+}
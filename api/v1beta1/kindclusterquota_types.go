@@ -0,0 +1,62 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KindClusterQuotaSpec defines the desired state of KindClusterQuota
+type KindClusterQuotaSpec struct {
+	// MaxClusters is the maximum number of KindClusters in the namespace.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxClusters *int32 `json:"maxClusters,omitempty"`
+
+	// MaxNodes is the maximum number of nodes of all KindClusters in the
+	// namespace.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxNodes *int32 `json:"maxNodes,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Max Clusters",type=integer,JSONPath=`.spec.maxClusters`
+//+kubebuilder:printcolumn:name="Max Nodes",type=integer,JSONPath=`.spec.maxNodes`
+
+// KindClusterQuota is the Schema for the kindclusterquotas API. It limits
+// the KindClusters and nodes in its namespace. If a namespace has several
+// quotas, all of them apply.
+type KindClusterQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KindClusterQuotaSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KindClusterQuotaList contains a list of KindClusterQuota
+type KindClusterQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindClusterQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindClusterQuota{}, &KindClusterQuotaList{})
+}
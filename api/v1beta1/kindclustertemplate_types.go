@@ -0,0 +1,64 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// KindClusterTemplateSpec defines the desired state of KindClusterTemplate
+type KindClusterTemplateSpec struct {
+	Template KindClusterTemplateResource `json:"template"`
+}
+
+// KindClusterTemplateResource describes the data needed to create a
+// KindCluster from a template
+type KindClusterTemplateResource struct {
+	// ObjectMeta is the metadata applied to the KindClusters created from
+	// the template.
+	//+optional
+	ObjectMeta clusterv1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the spec of the KindClusters created from the template. The
+	// kind cluster name must be left empty, as every KindCluster created
+	// from the template needs its own. It is generated by the controller.
+	Spec KindClusterSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// KindClusterTemplate is the Schema for the kindclustertemplates API
+type KindClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec KindClusterTemplateSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// KindClusterTemplateList contains a list of KindClusterTemplate
+type KindClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindClusterTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindClusterTemplate{}, &KindClusterTemplateList{})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta1-kindclustertemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=kindclustertemplates,verbs=create;update,versions=v1beta1,name=validation.kindclustertemplate.infrastructure.cluster.x-k8s.io,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &KindClusterTemplate{}
+
+// SetupWebhookWithManager registers the validating webhook for
+// KindClusterTemplate.
+func (t *KindClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(t).
+		WithValidator(t).
+		Complete()
+}
+
+// ValidateCreate rejects templates that carry a kind cluster name or a
+// control plane endpoint, as both are unique to a single KindCluster.
+func (t *KindClusterTemplate) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	template, ok := obj.(*KindClusterTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindClusterTemplate but got a %T", obj))
+	}
+
+	return nil, template.validate(nil)
+}
+
+// ValidateUpdate rejects changes to the template spec. Templates are
+// immutable, ClusterClasses are rebased onto a new template instead.
+func (t *KindClusterTemplate) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldTemplate, ok := oldObj.(*KindClusterTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindClusterTemplate but got a %T", oldObj))
+	}
+	newTemplate, ok := newObj.(*KindClusterTemplate)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a KindClusterTemplate but got a %T", newObj))
+	}
+
+	return nil, newTemplate.validate(oldTemplate)
+}
+
+// ValidateDelete allows all deletes.
+func (t *KindClusterTemplate) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (t *KindClusterTemplate) validate(old *KindClusterTemplate) error {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec", "template", "spec")
+	spec := t.Spec.Template.Spec
+
+	if spec.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("name"),
+			"must be empty, the kind cluster name is generated for every KindCluster created from the template"))
+	}
+
+	if spec.ControlPlaneEndpoint != (clusterv1.APIEndpoint{}) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("controlPlaneEndpoint"),
+			"must be empty, it is set by the controller"))
+	}
+
+	if spec.ControlPlaneNodes < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("controlPlaneNodes"), spec.ControlPlaneNodes, "must not be negative"))
+	}
+
+	if spec.WorkerNodes < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("workerNodes"), spec.WorkerNodes, "must not be negative"))
+	}
+
+	if old != nil && !reflect.DeepEqual(old.Spec.Template.Spec, spec) {
+		allErrs = append(allErrs, field.Forbidden(specPath, "is immutable"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("KindClusterTemplate").GroupKind(), t.Name, allErrs)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestKindClusterTemplateValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    KindClusterSpec
+		wantErr bool
+	}{
+		{
+			name: "allows a spec without a kind cluster name",
+			spec: KindClusterSpec{ControlPlaneNodes: 1, WorkerNodes: 2},
+		},
+		{
+			name:    "rejects a kind cluster name",
+			spec:    KindClusterSpec{Name: "foo"},
+			wantErr: true,
+		},
+		{
+			name:    "rejects a control plane endpoint",
+			spec:    KindClusterSpec{ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 6443}},
+			wantErr: true,
+		},
+		{
+			name:    "rejects negative node counts",
+			spec:    KindClusterSpec{WorkerNodes: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template := &KindClusterTemplate{
+				Spec: KindClusterTemplateSpec{
+					Template: KindClusterTemplateResource{Spec: tt.spec},
+				},
+			}
+
+			_, err := template.ValidateCreate(context.Background(), template)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestKindClusterTemplateValidateUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	oldTemplate := &KindClusterTemplate{
+		Spec: KindClusterTemplateSpec{
+			Template: KindClusterTemplateResource{Spec: KindClusterSpec{WorkerNodes: 1}},
+		},
+	}
+
+	newTemplate := oldTemplate.DeepCopy()
+	newTemplate.Spec.Template.ObjectMeta.Labels = map[string]string{"foo": "bar"}
+	_, err := newTemplate.ValidateUpdate(context.Background(), oldTemplate, newTemplate)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	newTemplate.Spec.Template.Spec.WorkerNodes = 2
+	_, err = newTemplate.ValidateUpdate(context.Background(), oldTemplate, newTemplate)
+	g.Expect(err).To(MatchError(ContainSubstring("is immutable")))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ControlPlaneComponents are the static pods every kind control plane node
+// runs.
+var ControlPlaneComponents = []string{"etcd", "kube-apiserver", "kube-controller-manager", "kube-scheduler"}
+
+// KindControlPlaneSpec defines the desired state of KindControlPlane
+type KindControlPlaneSpec struct {
+	// Replicas is the number of control plane nodes of the kind cluster.
+	// The kind cluster is created with this many control plane nodes, later
+	// changes add or remove nodes. The first control plane node is never
+	// removed.
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=1
+	//+optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Version is the Kubernetes version of the control plane, e.g. v1.31.0.
+	// It selects the kindest/node image of the nodes. Changing it only
+	// affects nodes added afterwards.
+	//+kubebuilder:validation:Pattern=`^v\d+\.\d+\.\d+$`
+	Version string `json:"version"`
+}
+
+// KindControlPlaneStatus defines the observed state of KindControlPlane
+type KindControlPlaneStatus struct {
+	// Selector is the label selector of the control plane nodes in string
+	// form, used by the scale subresource.
+	//+optional
+	Selector string `json:"selector,omitempty"`
+
+	// Replicas is the number of control plane node containers.
+	//+optional
+	Replicas int32 `json:"replicas"`
+
+	// UpdatedReplicas is the number of control plane nodes running the
+	// image of the desired version.
+	//+optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// ReadyReplicas is the number of control plane nodes whose components
+	// are all ready.
+	//+optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// UnavailableReplicas is the number of control plane nodes that are not
+	// ready.
+	//+optional
+	UnavailableReplicas int32 `json:"unavailableReplicas"`
+
+	// Version is the Kubernetes version reported by the API server.
+	//+optional
+	Version *string `json:"version,omitempty"`
+
+	// Initialized is true once the API server of the kind cluster has been
+	// reachable.
+	//+optional
+	Initialized bool `json:"initialized"`
+
+	// Ready is true while at least one control plane node is ready.
+	//+optional
+	Ready bool `json:"ready"`
+
+	// Components lists the control plane components of every control plane
+	// node.
+	//+optional
+	Components []ControlPlaneComponent `json:"components,omitempty"`
+
+	// FailureMessage indicates there is a fatal problem reconciling the
+	// control plane.
+	//+optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the KindControlPlane.
+	//+optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ControlPlaneComponent is a control plane component static pod running on
+// a control plane node
+type ControlPlaneComponent struct {
+	// Node is the name of the control plane node.
+	Node string `json:"node"`
+
+	// Name is the name of the component, e.g. kube-apiserver.
+	Name string `json:"name"`
+
+	// Ready indicates whether the pod of the component is ready.
+	Ready bool `json:"ready"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+//+kubebuilder:printcolumn:name="Initialized",type=boolean,JSONPath=`.status.initialized`
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+//+kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
+//+kubebuilder:printcolumn:name="Ready Replicas",type=integer,JSONPath=`.status.readyReplicas`
+
+// KindControlPlane is the Schema for the kindcontrolplanes API
+type KindControlPlane struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindControlPlaneSpec   `json:"spec,omitempty"`
+	Status KindControlPlaneStatus `json:"status,omitempty"`
+}
+
+// GetReplicas returns Replicas or its default if not set.
+func (c *KindControlPlane) GetReplicas() int32 {
+	if c.Spec.Replicas == nil {
+		return 1
+	}
+	return *c.Spec.Replicas
+}
+
+// GetConditions returns the set of conditions for this object.
+func (c *KindControlPlane) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *KindControlPlane) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KindControlPlaneList contains a list of KindControlPlane
+type KindControlPlaneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindControlPlane `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindControlPlane{}, &KindControlPlaneList{})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// HostTLSCAKey is the key of the CA certificate of the docker daemon in
+	// the TLS Secret of a KindHost.
+	HostTLSCAKey = "ca.crt"
+	// HostTLSCertKey is the key of the client certificate in the TLS Secret
+	// of a KindHost.
+	HostTLSCertKey = corev1.TLSCertKey
+	// HostTLSKeyKey is the key of the client key in the TLS Secret of a
+	// KindHost.
+	HostTLSKeyKey = corev1.TLSPrivateKeyKey
+)
+
+// KindHostSpec defines the desired state of KindHost
+type KindHostSpec struct {
+	// Endpoint is the DOCKER_HOST URL of the docker daemon of the host, e.g.
+	// tcp://10.0.0.5:2376.
+	//+kubebuilder:validation:Pattern=`^(tcp|ssh)://.+`
+	Endpoint string `json:"endpoint"`
+
+	// TLSSecretRef references a Secret with the CA certificate of the docker
+	// daemon in ca.crt and the client certificate and key in tls.crt and
+	// tls.key. If set, the daemon is reached with TLS verification.
+	//+optional
+	TLSSecretRef *corev1.SecretReference `json:"tlsSecretRef,omitempty"`
+
+	// APIServerAddress is the address the API servers of the kind clusters on
+	// the host listen on and are reached at. It has to be reachable from the
+	// manager. Defaults to the address of the endpoint.
+	//+optional
+	APIServerAddress string `json:"apiServerAddress,omitempty"`
+
+	// Capacity limits the kind clusters placed on the host.
+	//+optional
+	Capacity *HostCapacity `json:"capacity,omitempty"`
+}
+
+// HostCapacity limits the kind clusters placed on a KindHost
+type HostCapacity struct {
+	// MaxClusters is the maximum number of kind clusters on the host.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxClusters *int32 `json:"maxClusters,omitempty"`
+
+	// MaxNodes is the maximum number of node containers of all kind clusters
+	// on the host.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxNodes *int32 `json:"maxNodes,omitempty"`
+}
+
+// KindHostStatus defines the observed state of KindHost
+type KindHostStatus struct {
+	// Ready is true when the docker daemon of the host can be reached.
+	//+optional
+	Ready bool `json:"ready"`
+
+	// Clusters is the number of kind clusters on the host.
+	//+optional
+	Clusters int32 `json:"clusters"`
+
+	// Nodes is the number of kind node containers on the host.
+	//+optional
+	Nodes int32 `json:"nodes"`
+
+	// CPUs is the number of CPUs the docker daemon reports.
+	//+optional
+	CPUs int32 `json:"cpus,omitempty"`
+
+	// Memory is the total memory the docker daemon reports.
+	//+optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// Conditions defines current service state of the KindHost.
+	//+optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.spec.endpoint`
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.clusters`
+//+kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=`.status.nodes`
+
+// KindHost is the Schema for the kindhosts API. It describes a remote docker
+// daemon kind clusters can be created on.
+type KindHost struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindHostSpec   `json:"spec,omitempty"`
+	Status KindHostStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (h *KindHost) GetConditions() clusterv1.Conditions {
+	return h.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (h *KindHost) SetConditions(conditions clusterv1.Conditions) {
+	h.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KindHostList contains a list of KindHost
+type KindHostList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindHost `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindHost{}, &KindHostList{})
+}
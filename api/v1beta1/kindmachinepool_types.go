@@ -0,0 +1,166 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MachinePoolDeletePolicy selects which worker nodes are removed first when
+// a KindMachinePool scales down.
+// +kubebuilder:validation:Enum=Newest;Oldest
+type MachinePoolDeletePolicy string
+
+const (
+	// MachinePoolDeletePolicyNewest removes the most recently created nodes
+	// first.
+	MachinePoolDeletePolicyNewest MachinePoolDeletePolicy = "Newest"
+
+	// MachinePoolDeletePolicyOldest removes the least recently created nodes
+	// first.
+	MachinePoolDeletePolicyOldest MachinePoolDeletePolicy = "Oldest"
+)
+
+// KindMachinePoolSpec defines the desired state of KindMachinePool
+type KindMachinePoolSpec struct {
+	// ProviderIDList are the provider IDs of the worker nodes of the pool. It
+	// is set by the controller.
+	//+optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Strategy configures how worker nodes are replaced and removed.
+	//+optional
+	Strategy *MachinePoolStrategy `json:"strategy,omitempty"`
+}
+
+// MachinePoolStrategy configures how the worker nodes of a KindMachinePool
+// are replaced and removed
+type MachinePoolStrategy struct {
+	// MaxSurge is the number of worker nodes that can be added above the
+	// desired replicas while nodes running an outdated Kubernetes version are
+	// replaced. With zero an outdated node is removed before its replacement
+	// is added. Defaults to 1.
+	//+kubebuilder:validation:Minimum=0
+	//+optional
+	MaxSurge *int32 `json:"maxSurge,omitempty"`
+
+	// DeletePolicy selects which nodes are removed first when scaling down.
+	// Nodes running an outdated Kubernetes version are always removed before
+	// up to date ones. Defaults to Newest.
+	//+optional
+	DeletePolicy MachinePoolDeletePolicy `json:"deletePolicy,omitempty"`
+}
+
+// KindMachinePoolStatus defines the observed state of KindMachinePool
+type KindMachinePoolStatus struct {
+	// Ready is true when the pool has the desired number of worker nodes and
+	// all of them run the desired Kubernetes version.
+	//+optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the number of worker node containers of the pool.
+	//+optional
+	Replicas int32 `json:"replicas"`
+
+	// Nodes are the worker nodes of the pool.
+	//+optional
+	Nodes []MachinePoolNode `json:"nodes,omitempty"`
+
+	// FailureMessage indicates there is a problem adding or removing worker
+	// nodes.
+	//+optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the KindMachinePool.
+	//+optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// MachinePoolNode is a worker node container of a KindMachinePool
+type MachinePoolNode struct {
+	// Name is the name of the node container and the Kubernetes node.
+	Name string `json:"name"`
+
+	// ProviderID is the provider ID the node is registered with.
+	ProviderID string `json:"providerID"`
+
+	// Image is the kindest/node image the node runs.
+	Image string `json:"image"`
+
+	// CreationTime is when the node container was created.
+	CreationTime metav1.Time `json:"creationTime"`
+
+	// FailureDomain is the failure domain the node is placed in.
+	//+optional
+	FailureDomain string `json:"failureDomain,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+//+kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.replicas`
+
+// KindMachinePool is the Schema for the kindmachinepools API
+type KindMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KindMachinePoolSpec   `json:"spec,omitempty"`
+	Status KindMachinePoolStatus `json:"status,omitempty"`
+}
+
+// GetMaxSurge returns the MaxSurge of the strategy or its default if not
+// set.
+func (p *KindMachinePool) GetMaxSurge() int {
+	if p.Spec.Strategy == nil || p.Spec.Strategy.MaxSurge == nil {
+		return 1
+	}
+	return int(*p.Spec.Strategy.MaxSurge)
+}
+
+// GetDeletePolicy returns the DeletePolicy of the strategy or its default if
+// not set.
+func (p *KindMachinePool) GetDeletePolicy() MachinePoolDeletePolicy {
+	if p.Spec.Strategy == nil || p.Spec.Strategy.DeletePolicy == "" {
+		return MachinePoolDeletePolicyNewest
+	}
+	return p.Spec.Strategy.DeletePolicy
+}
+
+// GetConditions returns the set of conditions for this object.
+func (p *KindMachinePool) GetConditions() clusterv1.Conditions {
+	return p.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (p *KindMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	p.Status.Conditions = conditions
+}
+
+//+kubebuilder:object:root=true
+
+// KindMachinePoolList contains a list of KindMachinePool
+type KindMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KindMachinePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KindMachinePool{}, &KindMachinePoolList{})
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeImageRepository is the repository of the node images of a Kubernetes
+// version.
+const NodeImageRepository = "kindest/node"
+
+// NodeImage returns the node image of the Kubernetes version.
+func NodeImage(version string) string {
+	return fmt.Sprintf("%s:%s", NodeImageRepository, version)
+}
+
+// IsNodeImage returns whether the image is the node image of the Kubernetes
+// version, optionally pinned to a digest. The tag has to match exactly, so
+// that e.g. v1.29.1 does not match v1.29.10.
+func IsNodeImage(image, version string) bool {
+	tagged, digest, pinned := strings.Cut(image, "@")
+	if pinned && !strings.HasPrefix(digest, "sha256:") {
+		return false
+	}
+	return tagged == NodeImage(version)
+}
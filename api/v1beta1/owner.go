@@ -0,0 +1,48 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+//+kubebuilder:object:generate=false
+
+// OwnedKindCluster is a kind cluster whose owner is recorded in its node
+// containers.
+type OwnedKindCluster struct {
+	// Name is the name of the kind cluster.
+	Name string
+	// HostName is the name of the KindHost the kind cluster runs on, or
+	// empty for the docker daemon of the manager.
+	HostName string
+	// Owner references the KindCluster or KindClusterPool owning the kind
+	// cluster.
+	Owner corev1.ObjectReference
+}
+
+// KindCluster returns a KindCluster referring to the kind cluster, to pass
+// to the operations of the provider.
+func (c OwnedKindCluster) KindCluster() *KindCluster {
+	kindCluster := &KindCluster{
+		Spec: KindClusterSpec{Name: c.Name},
+	}
+	if c.HostName != "" {
+		kindCluster.Spec.HostRef = &corev1.LocalObjectReference{Name: c.HostName}
+	}
+	return kindCluster
+}
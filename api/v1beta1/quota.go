@@ -0,0 +1,102 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrQuotaExceeded is wrapped by the errors CheckQuota returns when a kind
+// cluster does not fit within the limits or a KindClusterQuota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+//+kubebuilder:object:generate=false
+
+// QuotaLimits are the limits on the KindClusters of all namespaces. Zero
+// means no limit.
+type QuotaLimits struct {
+	// MaxClusters is the maximum number of KindClusters.
+	MaxClusters int32
+	// MaxNodes is the maximum number of nodes of all KindClusters.
+	MaxNodes int32
+}
+
+// CheckQuota returns an error wrapping ErrQuotaExceeded if adding the kind
+// cluster exceeds the limits or a KindClusterQuota of its namespace. Of the
+// other KindClusters only those counts returns true for are counted, so that
+// the webhook can count all KindClusters and the controller only those
+// holding a kind cluster.
+func CheckQuota(ctx context.Context, reader client.Reader, kindCluster *KindCluster, limits QuotaLimits, counts func(*KindCluster) bool) error {
+	quotas := &KindClusterQuotaList{}
+	err := reader.List(ctx, quotas, client.InNamespace(kindCluster.Namespace))
+	if err != nil {
+		return err
+	}
+
+	if len(quotas.Items) == 0 && limits.MaxClusters == 0 && limits.MaxNodes == 0 {
+		return nil
+	}
+
+	kindClusters := &KindClusterList{}
+	err = reader.List(ctx, kindClusters)
+	if err != nil {
+		return err
+	}
+
+	var clusters, nodes, namespaceClusters, namespaceNodes int32
+	for i := range kindClusters.Items {
+		other := &kindClusters.Items[i]
+		if other.Namespace == kindCluster.Namespace && other.Name == kindCluster.Name {
+			continue
+		}
+		if !other.DeletionTimestamp.IsZero() || !counts(other) {
+			continue
+		}
+
+		clusters++
+		nodes += other.GetRequestedNodes()
+		if other.Namespace == kindCluster.Namespace {
+			namespaceClusters++
+			namespaceNodes += other.GetRequestedNodes()
+		}
+	}
+
+	requested := kindCluster.GetRequestedNodes()
+	if limits.MaxClusters > 0 && clusters+1 > limits.MaxClusters {
+		return fmt.Errorf("%w: %d KindClusters exceed the limit of %d", ErrQuotaExceeded, clusters+1, limits.MaxClusters)
+	}
+	if limits.MaxNodes > 0 && nodes+requested > limits.MaxNodes {
+		return fmt.Errorf("%w: %d nodes exceed the limit of %d", ErrQuotaExceeded, nodes+requested, limits.MaxNodes)
+	}
+
+	for _, quota := range quotas.Items {
+		if quota.Spec.MaxClusters != nil && namespaceClusters+1 > *quota.Spec.MaxClusters {
+			return fmt.Errorf("%w: %d KindClusters exceed the maximum of %d of KindClusterQuota %s",
+				ErrQuotaExceeded, namespaceClusters+1, *quota.Spec.MaxClusters, quota.Name)
+		}
+		if quota.Spec.MaxNodes != nil && namespaceNodes+requested > *quota.Spec.MaxNodes {
+			return fmt.Errorf("%w: %d nodes exceed the maximum of %d of KindClusterQuota %s",
+				ErrQuotaExceeded, namespaceNodes+requested, *quota.Spec.MaxNodes, quota.Name)
+		}
+	}
+
+	return nil
+}
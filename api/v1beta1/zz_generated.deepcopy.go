@@ -0,0 +1,1160 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityStatus) DeepCopyInto(out *ActivityStatus) {
+	*out = *in
+	in.LastActiveTime.DeepCopyInto(&out.LastActiveTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityStatus.
+func (in *ActivityStatus) DeepCopy() *ActivityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneComponent) DeepCopyInto(out *ControlPlaneComponent) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneComponent.
+func (in *ControlPlaneComponent) DeepCopy() *ControlPlaneComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsReference) DeepCopyInto(out *DiagnosticsReference) {
+	*out = *in
+	in.CollectedAt.DeepCopyInto(&out.CollectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsReference.
+func (in *DiagnosticsReference) DeepCopy() *DiagnosticsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailureDomain) DeepCopyInto(out *FailureDomain) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailureDomain.
+func (in *FailureDomain) DeepCopy() *FailureDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(FailureDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostCapacity) DeepCopyInto(out *HostCapacity) {
+	*out = *in
+	if in.MaxClusters != nil {
+		in, out := &in.MaxClusters, &out.MaxClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCapacity.
+func (in *HostCapacity) DeepCopy() *HostCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(HostCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindCluster) DeepCopyInto(out *KindCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindCluster.
+func (in *KindCluster) DeepCopy() *KindCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KindCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterAccess) DeepCopyInto(out *KindClusterAccess) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterAccess.
+func (in *KindClusterAccess) DeepCopy() *KindClusterAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterAccess) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterAccessList) DeepCopyInto(out *KindClusterAccessList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindClusterAccess, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterAccessList.
+func (in *KindClusterAccessList) DeepCopy() *KindClusterAccessList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterAccessList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterAccessList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterAccessSpec) DeepCopyInto(out *KindClusterAccessSpec) {
+	*out = *in
+	out.KindClusterRef = in.KindClusterRef
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterAccessSpec.
+func (in *KindClusterAccessSpec) DeepCopy() *KindClusterAccessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterAccessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterAccessStatus) DeepCopyInto(out *KindClusterAccessStatus) {
+	*out = *in
+	if in.IssuedAt != nil {
+		in, out := &in.IssuedAt, &out.IssuedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterAccessStatus.
+func (in *KindClusterAccessStatus) DeepCopy() *KindClusterAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterList) DeepCopyInto(out *KindClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterList.
+func (in *KindClusterList) DeepCopy() *KindClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterPool) DeepCopyInto(out *KindClusterPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterPool.
+func (in *KindClusterPool) DeepCopy() *KindClusterPool {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterPoolList) DeepCopyInto(out *KindClusterPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindClusterPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterPoolList.
+func (in *KindClusterPoolList) DeepCopy() *KindClusterPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterPoolSpec) DeepCopyInto(out *KindClusterPoolSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterPoolSpec.
+func (in *KindClusterPoolSpec) DeepCopy() *KindClusterPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterPoolStatus) DeepCopyInto(out *KindClusterPoolStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]PooledCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterPoolStatus.
+func (in *KindClusterPoolStatus) DeepCopy() *KindClusterPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterQuota) DeepCopyInto(out *KindClusterQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterQuota.
+func (in *KindClusterQuota) DeepCopy() *KindClusterQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterQuotaList) DeepCopyInto(out *KindClusterQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindClusterQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterQuotaList.
+func (in *KindClusterQuotaList) DeepCopy() *KindClusterQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterQuotaSpec) DeepCopyInto(out *KindClusterQuotaSpec) {
+	*out = *in
+	if in.MaxClusters != nil {
+		in, out := &in.MaxClusters, &out.MaxClusters
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxNodes != nil {
+		in, out := &in.MaxNodes, &out.MaxNodes
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterQuotaSpec.
+func (in *KindClusterQuotaSpec) DeepCopy() *KindClusterQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterSpec) DeepCopyInto(out *KindClusterSpec) {
+	*out = *in
+	if in.HostRef != nil {
+		in, out := &in.HostRef, &out.HostRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]FailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.PoolRef != nil {
+		in, out := &in.PoolRef, &out.PoolRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterSpec.
+func (in *KindClusterSpec) DeepCopy() *KindClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterStatus) DeepCopyInto(out *KindClusterStatus) {
+	*out = *in
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(DiagnosticsReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(RemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostRef != nil {
+		in, out := &in.HostRef, &out.HostRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make(apiv1beta1.FailureDomains, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiryWarningTime != nil {
+		in, out := &in.ExpiryWarningTime, &out.ExpiryWarningTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificatesExpireAt != nil {
+		in, out := &in.CertificatesExpireAt, &out.CertificatesExpireAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Activity != nil {
+		in, out := &in.Activity, &out.Activity
+		*out = new(ActivityStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterStatus.
+func (in *KindClusterStatus) DeepCopy() *KindClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterTemplate) DeepCopyInto(out *KindClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterTemplate.
+func (in *KindClusterTemplate) DeepCopy() *KindClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterTemplateList) DeepCopyInto(out *KindClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterTemplateList.
+func (in *KindClusterTemplateList) DeepCopy() *KindClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterTemplateResource) DeepCopyInto(out *KindClusterTemplateResource) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterTemplateResource.
+func (in *KindClusterTemplateResource) DeepCopy() *KindClusterTemplateResource {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterTemplateResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindClusterTemplateSpec) DeepCopyInto(out *KindClusterTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindClusterTemplateSpec.
+func (in *KindClusterTemplateSpec) DeepCopy() *KindClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindControlPlane) DeepCopyInto(out *KindControlPlane) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindControlPlane.
+func (in *KindControlPlane) DeepCopy() *KindControlPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(KindControlPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindControlPlane) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindControlPlaneList) DeepCopyInto(out *KindControlPlaneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindControlPlane, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindControlPlaneList.
+func (in *KindControlPlaneList) DeepCopy() *KindControlPlaneList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindControlPlaneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindControlPlaneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindControlPlaneSpec) DeepCopyInto(out *KindControlPlaneSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindControlPlaneSpec.
+func (in *KindControlPlaneSpec) DeepCopy() *KindControlPlaneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindControlPlaneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindControlPlaneStatus) DeepCopyInto(out *KindControlPlaneStatus) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ControlPlaneComponent, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindControlPlaneStatus.
+func (in *KindControlPlaneStatus) DeepCopy() *KindControlPlaneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindControlPlaneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHost) DeepCopyInto(out *KindHost) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHost.
+func (in *KindHost) DeepCopy() *KindHost {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindHost) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostList) DeepCopyInto(out *KindHostList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostList.
+func (in *KindHostList) DeepCopy() *KindHostList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindHostList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostSpec) DeepCopyInto(out *KindHostSpec) {
+	*out = *in
+	if in.TLSSecretRef != nil {
+		in, out := &in.TLSSecretRef, &out.TLSSecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(HostCapacity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostSpec.
+func (in *KindHostSpec) DeepCopy() *KindHostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindHostStatus) DeepCopyInto(out *KindHostStatus) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindHostStatus.
+func (in *KindHostStatus) DeepCopy() *KindHostStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindHostStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindMachinePool) DeepCopyInto(out *KindMachinePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindMachinePool.
+func (in *KindMachinePool) DeepCopy() *KindMachinePool {
+	if in == nil {
+		return nil
+	}
+	out := new(KindMachinePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindMachinePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindMachinePoolList) DeepCopyInto(out *KindMachinePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KindMachinePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindMachinePoolList.
+func (in *KindMachinePoolList) DeepCopy() *KindMachinePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(KindMachinePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KindMachinePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindMachinePoolSpec) DeepCopyInto(out *KindMachinePoolSpec) {
+	*out = *in
+	if in.ProviderIDList != nil {
+		in, out := &in.ProviderIDList, &out.ProviderIDList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(MachinePoolStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindMachinePoolSpec.
+func (in *KindMachinePoolSpec) DeepCopy() *KindMachinePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KindMachinePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KindMachinePoolStatus) DeepCopyInto(out *KindMachinePoolStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]MachinePoolNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(apiv1beta1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KindMachinePoolStatus.
+func (in *KindMachinePoolStatus) DeepCopy() *KindMachinePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KindMachinePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolNode) DeepCopyInto(out *MachinePoolNode) {
+	*out = *in
+	in.CreationTime.DeepCopyInto(&out.CreationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolNode.
+func (in *MachinePoolNode) DeepCopy() *MachinePoolNode {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolStrategy) DeepCopyInto(out *MachinePoolStrategy) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolStrategy.
+func (in *MachinePoolStrategy) DeepCopy() *MachinePoolStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
+	*out = *in
+	if in.InternalIPs != nil {
+		in, out := &in.InternalIPs, &out.InternalIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeStatus.
+func (in *NodeStatus) DeepCopy() *NodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PooledCluster) DeepCopyInto(out *PooledCluster) {
+	*out = *in
+	if in.ClaimedBy != nil {
+		in, out := &in.ClaimedBy, &out.ClaimedBy
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.ClaimedAt != nil {
+		in, out := &in.ClaimedAt, &out.ClaimedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PooledCluster.
+func (in *PooledCluster) DeepCopy() *PooledCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(PooledCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationPolicy) DeepCopyInto(out *RemediationPolicy) {
+	*out = *in
+	if in.UnhealthyThreshold != nil {
+		in, out := &in.UnhealthyThreshold, &out.UnhealthyThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationPolicy.
+func (in *RemediationPolicy) DeepCopy() *RemediationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemediationStatus) DeepCopyInto(out *RemediationStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemediationStatus.
+func (in *RemediationStatus) DeepCopy() *RemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
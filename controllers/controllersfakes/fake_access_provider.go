@@ -0,0 +1,356 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeAccessProvider struct {
+	GetCACertHashStub        func(*v1beta1.KindCluster) (string, error)
+	getCACertHashMutex       sync.RWMutex
+	getCACertHashArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	getCACertHashReturns struct {
+		result1 string
+		result2 error
+	}
+	getCACertHashReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GrantAccessStub        func(*v1beta1.KindCluster, *v1beta1.KindClusterAccess) error
+	grantAccessMutex       sync.RWMutex
+	grantAccessArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 *v1beta1.KindClusterAccess
+	}
+	grantAccessReturns struct {
+		result1 error
+	}
+	grantAccessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	IssueKubeconfigStub        func(*v1beta1.KindCluster, *v1beta1.KindClusterAccess) ([]byte, time.Time, error)
+	issueKubeconfigMutex       sync.RWMutex
+	issueKubeconfigArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 *v1beta1.KindClusterAccess
+	}
+	issueKubeconfigReturns struct {
+		result1 []byte
+		result2 time.Time
+		result3 error
+	}
+	issueKubeconfigReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 time.Time
+		result3 error
+	}
+	RevokeAccessStub        func(*v1beta1.KindCluster, *v1beta1.KindClusterAccess) error
+	revokeAccessMutex       sync.RWMutex
+	revokeAccessArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 *v1beta1.KindClusterAccess
+	}
+	revokeAccessReturns struct {
+		result1 error
+	}
+	revokeAccessReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAccessProvider) GetCACertHash(arg1 *v1beta1.KindCluster) (string, error) {
+	fake.getCACertHashMutex.Lock()
+	ret, specificReturn := fake.getCACertHashReturnsOnCall[len(fake.getCACertHashArgsForCall)]
+	fake.getCACertHashArgsForCall = append(fake.getCACertHashArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.GetCACertHashStub
+	fakeReturns := fake.getCACertHashReturns
+	fake.recordInvocation("GetCACertHash", []interface{}{arg1})
+	fake.getCACertHashMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessProvider) GetCACertHashCallCount() int {
+	fake.getCACertHashMutex.RLock()
+	defer fake.getCACertHashMutex.RUnlock()
+	return len(fake.getCACertHashArgsForCall)
+}
+
+func (fake *FakeAccessProvider) GetCACertHashCalls(stub func(*v1beta1.KindCluster) (string, error)) {
+	fake.getCACertHashMutex.Lock()
+	defer fake.getCACertHashMutex.Unlock()
+	fake.GetCACertHashStub = stub
+}
+
+func (fake *FakeAccessProvider) GetCACertHashArgsForCall(i int) *v1beta1.KindCluster {
+	fake.getCACertHashMutex.RLock()
+	defer fake.getCACertHashMutex.RUnlock()
+	argsForCall := fake.getCACertHashArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAccessProvider) GetCACertHashReturns(result1 string, result2 error) {
+	fake.getCACertHashMutex.Lock()
+	defer fake.getCACertHashMutex.Unlock()
+	fake.GetCACertHashStub = nil
+	fake.getCACertHashReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessProvider) GetCACertHashReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getCACertHashMutex.Lock()
+	defer fake.getCACertHashMutex.Unlock()
+	fake.GetCACertHashStub = nil
+	if fake.getCACertHashReturnsOnCall == nil {
+		fake.getCACertHashReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getCACertHashReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessProvider) GrantAccess(arg1 *v1beta1.KindCluster, arg2 *v1beta1.KindClusterAccess) error {
+	fake.grantAccessMutex.Lock()
+	ret, specificReturn := fake.grantAccessReturnsOnCall[len(fake.grantAccessArgsForCall)]
+	fake.grantAccessArgsForCall = append(fake.grantAccessArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 *v1beta1.KindClusterAccess
+	}{arg1, arg2})
+	stub := fake.GrantAccessStub
+	fakeReturns := fake.grantAccessReturns
+	fake.recordInvocation("GrantAccess", []interface{}{arg1, arg2})
+	fake.grantAccessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAccessProvider) GrantAccessCallCount() int {
+	fake.grantAccessMutex.RLock()
+	defer fake.grantAccessMutex.RUnlock()
+	return len(fake.grantAccessArgsForCall)
+}
+
+func (fake *FakeAccessProvider) GrantAccessCalls(stub func(*v1beta1.KindCluster, *v1beta1.KindClusterAccess) error) {
+	fake.grantAccessMutex.Lock()
+	defer fake.grantAccessMutex.Unlock()
+	fake.GrantAccessStub = stub
+}
+
+func (fake *FakeAccessProvider) GrantAccessArgsForCall(i int) (*v1beta1.KindCluster, *v1beta1.KindClusterAccess) {
+	fake.grantAccessMutex.RLock()
+	defer fake.grantAccessMutex.RUnlock()
+	argsForCall := fake.grantAccessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessProvider) GrantAccessReturns(result1 error) {
+	fake.grantAccessMutex.Lock()
+	defer fake.grantAccessMutex.Unlock()
+	fake.GrantAccessStub = nil
+	fake.grantAccessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessProvider) GrantAccessReturnsOnCall(i int, result1 error) {
+	fake.grantAccessMutex.Lock()
+	defer fake.grantAccessMutex.Unlock()
+	fake.GrantAccessStub = nil
+	if fake.grantAccessReturnsOnCall == nil {
+		fake.grantAccessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.grantAccessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessProvider) IssueKubeconfig(arg1 *v1beta1.KindCluster, arg2 *v1beta1.KindClusterAccess) ([]byte, time.Time, error) {
+	fake.issueKubeconfigMutex.Lock()
+	ret, specificReturn := fake.issueKubeconfigReturnsOnCall[len(fake.issueKubeconfigArgsForCall)]
+	fake.issueKubeconfigArgsForCall = append(fake.issueKubeconfigArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 *v1beta1.KindClusterAccess
+	}{arg1, arg2})
+	stub := fake.IssueKubeconfigStub
+	fakeReturns := fake.issueKubeconfigReturns
+	fake.recordInvocation("IssueKubeconfig", []interface{}{arg1, arg2})
+	fake.issueKubeconfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeAccessProvider) IssueKubeconfigCallCount() int {
+	fake.issueKubeconfigMutex.RLock()
+	defer fake.issueKubeconfigMutex.RUnlock()
+	return len(fake.issueKubeconfigArgsForCall)
+}
+
+func (fake *FakeAccessProvider) IssueKubeconfigCalls(stub func(*v1beta1.KindCluster, *v1beta1.KindClusterAccess) ([]byte, time.Time, error)) {
+	fake.issueKubeconfigMutex.Lock()
+	defer fake.issueKubeconfigMutex.Unlock()
+	fake.IssueKubeconfigStub = stub
+}
+
+func (fake *FakeAccessProvider) IssueKubeconfigArgsForCall(i int) (*v1beta1.KindCluster, *v1beta1.KindClusterAccess) {
+	fake.issueKubeconfigMutex.RLock()
+	defer fake.issueKubeconfigMutex.RUnlock()
+	argsForCall := fake.issueKubeconfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessProvider) IssueKubeconfigReturns(result1 []byte, result2 time.Time, result3 error) {
+	fake.issueKubeconfigMutex.Lock()
+	defer fake.issueKubeconfigMutex.Unlock()
+	fake.IssueKubeconfigStub = nil
+	fake.issueKubeconfigReturns = struct {
+		result1 []byte
+		result2 time.Time
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAccessProvider) IssueKubeconfigReturnsOnCall(i int, result1 []byte, result2 time.Time, result3 error) {
+	fake.issueKubeconfigMutex.Lock()
+	defer fake.issueKubeconfigMutex.Unlock()
+	fake.IssueKubeconfigStub = nil
+	if fake.issueKubeconfigReturnsOnCall == nil {
+		fake.issueKubeconfigReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 time.Time
+			result3 error
+		})
+	}
+	fake.issueKubeconfigReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 time.Time
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeAccessProvider) RevokeAccess(arg1 *v1beta1.KindCluster, arg2 *v1beta1.KindClusterAccess) error {
+	fake.revokeAccessMutex.Lock()
+	ret, specificReturn := fake.revokeAccessReturnsOnCall[len(fake.revokeAccessArgsForCall)]
+	fake.revokeAccessArgsForCall = append(fake.revokeAccessArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 *v1beta1.KindClusterAccess
+	}{arg1, arg2})
+	stub := fake.RevokeAccessStub
+	fakeReturns := fake.revokeAccessReturns
+	fake.recordInvocation("RevokeAccess", []interface{}{arg1, arg2})
+	fake.revokeAccessMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeAccessProvider) RevokeAccessCallCount() int {
+	fake.revokeAccessMutex.RLock()
+	defer fake.revokeAccessMutex.RUnlock()
+	return len(fake.revokeAccessArgsForCall)
+}
+
+func (fake *FakeAccessProvider) RevokeAccessCalls(stub func(*v1beta1.KindCluster, *v1beta1.KindClusterAccess) error) {
+	fake.revokeAccessMutex.Lock()
+	defer fake.revokeAccessMutex.Unlock()
+	fake.RevokeAccessStub = stub
+}
+
+func (fake *FakeAccessProvider) RevokeAccessArgsForCall(i int) (*v1beta1.KindCluster, *v1beta1.KindClusterAccess) {
+	fake.revokeAccessMutex.RLock()
+	defer fake.revokeAccessMutex.RUnlock()
+	argsForCall := fake.revokeAccessArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessProvider) RevokeAccessReturns(result1 error) {
+	fake.revokeAccessMutex.Lock()
+	defer fake.revokeAccessMutex.Unlock()
+	fake.RevokeAccessStub = nil
+	fake.revokeAccessReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessProvider) RevokeAccessReturnsOnCall(i int, result1 error) {
+	fake.revokeAccessMutex.Lock()
+	defer fake.revokeAccessMutex.Unlock()
+	fake.RevokeAccessStub = nil
+	if fake.revokeAccessReturnsOnCall == nil {
+		fake.revokeAccessReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeAccessReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getCACertHashMutex.RLock()
+	defer fake.getCACertHashMutex.RUnlock()
+	fake.grantAccessMutex.RLock()
+	defer fake.grantAccessMutex.RUnlock()
+	fake.issueKubeconfigMutex.RLock()
+	defer fake.issueKubeconfigMutex.RUnlock()
+	fake.revokeAccessMutex.RLock()
+	defer fake.revokeAccessMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeAccessProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.AccessProvider = new(FakeAccessProvider)
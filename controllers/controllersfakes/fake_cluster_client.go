@@ -5,17 +5,29 @@ import (
 	"context"
 	"sync"
 
-	"github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	v1beta1a "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/controllers"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 type FakeClusterClient struct {
-	GetStub        func(context.Context, *v1alpha3.KindCluster) (*v1beta1.Cluster, error)
+	DeleteStub        func(context.Context, *v1beta1.Cluster) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStub        func(context.Context, *v1beta1a.KindCluster) (*v1beta1.Cluster, error)
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
 		arg1 context.Context
-		arg2 *v1alpha3.KindCluster
+		arg2 *v1beta1a.KindCluster
 	}
 	getReturns struct {
 		result1 *v1beta1.Cluster
@@ -25,16 +37,92 @@ type FakeClusterClient struct {
 		result1 *v1beta1.Cluster
 		result2 error
 	}
+	GetControlPlaneStub        func(context.Context, *v1beta1.Cluster) (*v1beta1a.KindControlPlane, error)
+	getControlPlaneMutex       sync.RWMutex
+	getControlPlaneArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}
+	getControlPlaneReturns struct {
+		result1 *v1beta1a.KindControlPlane
+		result2 error
+	}
+	getControlPlaneReturnsOnCall map[int]struct {
+		result1 *v1beta1a.KindControlPlane
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeClusterClient) Get(arg1 context.Context, arg2 *v1alpha3.KindCluster) (*v1beta1.Cluster, error) {
+func (fake *FakeClusterClient) Delete(arg1 context.Context, arg2 *v1beta1.Cluster) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterClient) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeClusterClient) DeleteCalls(stub func(context.Context, *v1beta1.Cluster) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeClusterClient) DeleteArgsForCall(i int) (context.Context, *v1beta1.Cluster) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClusterClient) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterClient) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterClient) Get(arg1 context.Context, arg2 *v1beta1a.KindCluster) (*v1beta1.Cluster, error) {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
 		arg1 context.Context
-		arg2 *v1alpha3.KindCluster
+		arg2 *v1beta1a.KindCluster
 	}{arg1, arg2})
 	stub := fake.GetStub
 	fakeReturns := fake.getReturns
@@ -55,13 +143,13 @@ func (fake *FakeClusterClient) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakeClusterClient) GetCalls(stub func(context.Context, *v1alpha3.KindCluster) (*v1beta1.Cluster, error)) {
+func (fake *FakeClusterClient) GetCalls(stub func(context.Context, *v1beta1a.KindCluster) (*v1beta1.Cluster, error)) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
 }
 
-func (fake *FakeClusterClient) GetArgsForCall(i int) (context.Context, *v1alpha3.KindCluster) {
+func (fake *FakeClusterClient) GetArgsForCall(i int) (context.Context, *v1beta1a.KindCluster) {
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
 	argsForCall := fake.getArgsForCall[i]
@@ -94,11 +182,80 @@ func (fake *FakeClusterClient) GetReturnsOnCall(i int, result1 *v1beta1.Cluster,
 	}{result1, result2}
 }
 
+func (fake *FakeClusterClient) GetControlPlane(arg1 context.Context, arg2 *v1beta1.Cluster) (*v1beta1a.KindControlPlane, error) {
+	fake.getControlPlaneMutex.Lock()
+	ret, specificReturn := fake.getControlPlaneReturnsOnCall[len(fake.getControlPlaneArgsForCall)]
+	fake.getControlPlaneArgsForCall = append(fake.getControlPlaneArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}{arg1, arg2})
+	stub := fake.GetControlPlaneStub
+	fakeReturns := fake.getControlPlaneReturns
+	fake.recordInvocation("GetControlPlane", []interface{}{arg1, arg2})
+	fake.getControlPlaneMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterClient) GetControlPlaneCallCount() int {
+	fake.getControlPlaneMutex.RLock()
+	defer fake.getControlPlaneMutex.RUnlock()
+	return len(fake.getControlPlaneArgsForCall)
+}
+
+func (fake *FakeClusterClient) GetControlPlaneCalls(stub func(context.Context, *v1beta1.Cluster) (*v1beta1a.KindControlPlane, error)) {
+	fake.getControlPlaneMutex.Lock()
+	defer fake.getControlPlaneMutex.Unlock()
+	fake.GetControlPlaneStub = stub
+}
+
+func (fake *FakeClusterClient) GetControlPlaneArgsForCall(i int) (context.Context, *v1beta1.Cluster) {
+	fake.getControlPlaneMutex.RLock()
+	defer fake.getControlPlaneMutex.RUnlock()
+	argsForCall := fake.getControlPlaneArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClusterClient) GetControlPlaneReturns(result1 *v1beta1a.KindControlPlane, result2 error) {
+	fake.getControlPlaneMutex.Lock()
+	defer fake.getControlPlaneMutex.Unlock()
+	fake.GetControlPlaneStub = nil
+	fake.getControlPlaneReturns = struct {
+		result1 *v1beta1a.KindControlPlane
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterClient) GetControlPlaneReturnsOnCall(i int, result1 *v1beta1a.KindControlPlane, result2 error) {
+	fake.getControlPlaneMutex.Lock()
+	defer fake.getControlPlaneMutex.Unlock()
+	fake.GetControlPlaneStub = nil
+	if fake.getControlPlaneReturnsOnCall == nil {
+		fake.getControlPlaneReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1a.KindControlPlane
+			result2 error
+		})
+	}
+	fake.getControlPlaneReturnsOnCall[i] = struct {
+		result1 *v1beta1a.KindControlPlane
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClusterClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.getControlPlaneMutex.RLock()
+	defer fake.getControlPlaneMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
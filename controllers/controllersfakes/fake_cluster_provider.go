@@ -3,16 +3,46 @@ package controllersfakes
 
 import (
 	"sync"
+	"time"
 
-	"github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	v1 "k8s.io/api/core/v1"
 )
 
 type FakeClusterProvider struct {
-	CreateStub        func(*v1alpha3.KindCluster) error
+	CheckHealthStub        func(*v1beta1.KindCluster) ([]byte, error)
+	checkHealthMutex       sync.RWMutex
+	checkHealthArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	checkHealthReturns struct {
+		result1 []byte
+		result2 error
+	}
+	checkHealthReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	CollectDiagnosticsStub        func(*v1beta1.KindCluster, int) ([]byte, error)
+	collectDiagnosticsMutex       sync.RWMutex
+	collectDiagnosticsArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 int
+	}
+	collectDiagnosticsReturns struct {
+		result1 []byte
+		result2 error
+	}
+	collectDiagnosticsReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	CreateStub        func(*v1beta1.KindCluster, v1.ObjectReference) error
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
-		arg1 *v1alpha3.KindCluster
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
 	}
 	createReturns struct {
 		result1 error
@@ -20,10 +50,10 @@ type FakeClusterProvider struct {
 	createReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func(*v1alpha3.KindCluster) error
+	DeleteStub        func(*v1beta1.KindCluster) error
 	deleteMutex       sync.RWMutex
 	deleteArgsForCall []struct {
-		arg1 *v1alpha3.KindCluster
+		arg1 *v1beta1.KindCluster
 	}
 	deleteReturns struct {
 		result1 error
@@ -31,10 +61,10 @@ type FakeClusterProvider struct {
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ExistsStub        func(*v1alpha3.KindCluster) (bool, error)
+	ExistsStub        func(*v1beta1.KindCluster) (bool, error)
 	existsMutex       sync.RWMutex
 	existsArgsForCall []struct {
-		arg1 *v1alpha3.KindCluster
+		arg1 *v1beta1.KindCluster
 	}
 	existsReturns struct {
 		result1 bool
@@ -44,37 +74,301 @@ type FakeClusterProvider struct {
 		result1 bool
 		result2 error
 	}
-	GetControlPlaneEndpointStub        func(*v1alpha3.KindCluster) (string, int, error)
+	ExportKubeconfigStub        func(*v1beta1.KindCluster, []byte) error
+	exportKubeconfigMutex       sync.RWMutex
+	exportKubeconfigArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 []byte
+	}
+	exportKubeconfigReturns struct {
+		result1 error
+	}
+	exportKubeconfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetCertificateExpiryStub        func(*v1beta1.KindCluster, []byte) (time.Time, error)
+	getCertificateExpiryMutex       sync.RWMutex
+	getCertificateExpiryArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 []byte
+	}
+	getCertificateExpiryReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	getCertificateExpiryReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	GetControlPlaneEndpointStub        func([]byte) (string, int32, error)
 	getControlPlaneEndpointMutex       sync.RWMutex
 	getControlPlaneEndpointArgsForCall []struct {
-		arg1 *v1alpha3.KindCluster
+		arg1 []byte
 	}
 	getControlPlaneEndpointReturns struct {
 		result1 string
-		result2 int
+		result2 int32
 		result3 error
 	}
 	getControlPlaneEndpointReturnsOnCall map[int]struct {
 		result1 string
-		result2 int
+		result2 int32
 		result3 error
 	}
+	GetKubeconfigStub        func(*v1beta1.KindCluster) ([]byte, error)
+	getKubeconfigMutex       sync.RWMutex
+	getKubeconfigArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	getKubeconfigReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getKubeconfigReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetKubernetesVersionStub        func([]byte) (string, error)
+	getKubernetesVersionMutex       sync.RWMutex
+	getKubernetesVersionArgsForCall []struct {
+		arg1 []byte
+	}
+	getKubernetesVersionReturns struct {
+		result1 string
+		result2 error
+	}
+	getKubernetesVersionReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetMutatingRequestsStub        func([]byte) (int64, error)
+	getMutatingRequestsMutex       sync.RWMutex
+	getMutatingRequestsArgsForCall []struct {
+		arg1 []byte
+	}
+	getMutatingRequestsReturns struct {
+		result1 int64
+		result2 error
+	}
+	getMutatingRequestsReturnsOnCall map[int]struct {
+		result1 int64
+		result2 error
+	}
+	GetNodesStub        func(*v1beta1.KindCluster) ([]v1beta1.NodeStatus, error)
+	getNodesMutex       sync.RWMutex
+	getNodesArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	getNodesReturns struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}
+	getNodesReturnsOnCall map[int]struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}
+	ReleaseOwnerStub        func(*v1beta1.KindCluster) error
+	releaseOwnerMutex       sync.RWMutex
+	releaseOwnerArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	releaseOwnerReturns struct {
+		result1 error
+	}
+	releaseOwnerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenewCertificatesStub        func(*v1beta1.KindCluster) error
+	renewCertificatesMutex       sync.RWMutex
+	renewCertificatesArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	renewCertificatesReturns struct {
+		result1 error
+	}
+	renewCertificatesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RestartNodesStub        func(*v1beta1.KindCluster) error
+	restartNodesMutex       sync.RWMutex
+	restartNodesArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	restartNodesReturns struct {
+		result1 error
+	}
+	restartNodesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetOwnerStub        func(*v1beta1.KindCluster, v1.ObjectReference) error
+	setOwnerMutex       sync.RWMutex
+	setOwnerArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}
+	setOwnerReturns struct {
+		result1 error
+	}
+	setOwnerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StopNodesStub        func(*v1beta1.KindCluster) error
+	stopNodesMutex       sync.RWMutex
+	stopNodesArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	stopNodesReturns struct {
+		result1 error
+	}
+	stopNodesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeClusterProvider) Create(arg1 *v1alpha3.KindCluster) error {
+func (fake *FakeClusterProvider) CheckHealth(arg1 *v1beta1.KindCluster) ([]byte, error) {
+	fake.checkHealthMutex.Lock()
+	ret, specificReturn := fake.checkHealthReturnsOnCall[len(fake.checkHealthArgsForCall)]
+	fake.checkHealthArgsForCall = append(fake.checkHealthArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.CheckHealthStub
+	fakeReturns := fake.checkHealthReturns
+	fake.recordInvocation("CheckHealth", []interface{}{arg1})
+	fake.checkHealthMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) CheckHealthCallCount() int {
+	fake.checkHealthMutex.RLock()
+	defer fake.checkHealthMutex.RUnlock()
+	return len(fake.checkHealthArgsForCall)
+}
+
+func (fake *FakeClusterProvider) CheckHealthCalls(stub func(*v1beta1.KindCluster) ([]byte, error)) {
+	fake.checkHealthMutex.Lock()
+	defer fake.checkHealthMutex.Unlock()
+	fake.CheckHealthStub = stub
+}
+
+func (fake *FakeClusterProvider) CheckHealthArgsForCall(i int) *v1beta1.KindCluster {
+	fake.checkHealthMutex.RLock()
+	defer fake.checkHealthMutex.RUnlock()
+	argsForCall := fake.checkHealthArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) CheckHealthReturns(result1 []byte, result2 error) {
+	fake.checkHealthMutex.Lock()
+	defer fake.checkHealthMutex.Unlock()
+	fake.CheckHealthStub = nil
+	fake.checkHealthReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) CheckHealthReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.checkHealthMutex.Lock()
+	defer fake.checkHealthMutex.Unlock()
+	fake.CheckHealthStub = nil
+	if fake.checkHealthReturnsOnCall == nil {
+		fake.checkHealthReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.checkHealthReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) CollectDiagnostics(arg1 *v1beta1.KindCluster, arg2 int) ([]byte, error) {
+	fake.collectDiagnosticsMutex.Lock()
+	ret, specificReturn := fake.collectDiagnosticsReturnsOnCall[len(fake.collectDiagnosticsArgsForCall)]
+	fake.collectDiagnosticsArgsForCall = append(fake.collectDiagnosticsArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.CollectDiagnosticsStub
+	fakeReturns := fake.collectDiagnosticsReturns
+	fake.recordInvocation("CollectDiagnostics", []interface{}{arg1, arg2})
+	fake.collectDiagnosticsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) CollectDiagnosticsCallCount() int {
+	fake.collectDiagnosticsMutex.RLock()
+	defer fake.collectDiagnosticsMutex.RUnlock()
+	return len(fake.collectDiagnosticsArgsForCall)
+}
+
+func (fake *FakeClusterProvider) CollectDiagnosticsCalls(stub func(*v1beta1.KindCluster, int) ([]byte, error)) {
+	fake.collectDiagnosticsMutex.Lock()
+	defer fake.collectDiagnosticsMutex.Unlock()
+	fake.CollectDiagnosticsStub = stub
+}
+
+func (fake *FakeClusterProvider) CollectDiagnosticsArgsForCall(i int) (*v1beta1.KindCluster, int) {
+	fake.collectDiagnosticsMutex.RLock()
+	defer fake.collectDiagnosticsMutex.RUnlock()
+	argsForCall := fake.collectDiagnosticsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClusterProvider) CollectDiagnosticsReturns(result1 []byte, result2 error) {
+	fake.collectDiagnosticsMutex.Lock()
+	defer fake.collectDiagnosticsMutex.Unlock()
+	fake.CollectDiagnosticsStub = nil
+	fake.collectDiagnosticsReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) CollectDiagnosticsReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.collectDiagnosticsMutex.Lock()
+	defer fake.collectDiagnosticsMutex.Unlock()
+	fake.CollectDiagnosticsStub = nil
+	if fake.collectDiagnosticsReturnsOnCall == nil {
+		fake.collectDiagnosticsReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.collectDiagnosticsReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) Create(arg1 *v1beta1.KindCluster, arg2 v1.ObjectReference) error {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
-		arg1 *v1alpha3.KindCluster
-	}{arg1})
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}{arg1, arg2})
 	stub := fake.CreateStub
 	fakeReturns := fake.createReturns
-	fake.recordInvocation("Create", []interface{}{arg1})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2})
 	fake.createMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
@@ -88,17 +382,17 @@ func (fake *FakeClusterProvider) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeClusterProvider) CreateCalls(stub func(*v1alpha3.KindCluster) error) {
+func (fake *FakeClusterProvider) CreateCalls(stub func(*v1beta1.KindCluster, v1.ObjectReference) error) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *FakeClusterProvider) CreateArgsForCall(i int) *v1alpha3.KindCluster {
+func (fake *FakeClusterProvider) CreateArgsForCall(i int) (*v1beta1.KindCluster, v1.ObjectReference) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeClusterProvider) CreateReturns(result1 error) {
@@ -124,11 +418,11 @@ func (fake *FakeClusterProvider) CreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeClusterProvider) Delete(arg1 *v1alpha3.KindCluster) error {
+func (fake *FakeClusterProvider) Delete(arg1 *v1beta1.KindCluster) error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
 	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
-		arg1 *v1alpha3.KindCluster
+		arg1 *v1beta1.KindCluster
 	}{arg1})
 	stub := fake.DeleteStub
 	fakeReturns := fake.deleteReturns
@@ -149,13 +443,13 @@ func (fake *FakeClusterProvider) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
-func (fake *FakeClusterProvider) DeleteCalls(stub func(*v1alpha3.KindCluster) error) {
+func (fake *FakeClusterProvider) DeleteCalls(stub func(*v1beta1.KindCluster) error) {
 	fake.deleteMutex.Lock()
 	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = stub
 }
 
-func (fake *FakeClusterProvider) DeleteArgsForCall(i int) *v1alpha3.KindCluster {
+func (fake *FakeClusterProvider) DeleteArgsForCall(i int) *v1beta1.KindCluster {
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	argsForCall := fake.deleteArgsForCall[i]
@@ -185,11 +479,11 @@ func (fake *FakeClusterProvider) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *FakeClusterProvider) Exists(arg1 *v1alpha3.KindCluster) (bool, error) {
+func (fake *FakeClusterProvider) Exists(arg1 *v1beta1.KindCluster) (bool, error) {
 	fake.existsMutex.Lock()
 	ret, specificReturn := fake.existsReturnsOnCall[len(fake.existsArgsForCall)]
 	fake.existsArgsForCall = append(fake.existsArgsForCall, struct {
-		arg1 *v1alpha3.KindCluster
+		arg1 *v1beta1.KindCluster
 	}{arg1})
 	stub := fake.ExistsStub
 	fakeReturns := fake.existsReturns
@@ -210,13 +504,13 @@ func (fake *FakeClusterProvider) ExistsCallCount() int {
 	return len(fake.existsArgsForCall)
 }
 
-func (fake *FakeClusterProvider) ExistsCalls(stub func(*v1alpha3.KindCluster) (bool, error)) {
+func (fake *FakeClusterProvider) ExistsCalls(stub func(*v1beta1.KindCluster) (bool, error)) {
 	fake.existsMutex.Lock()
 	defer fake.existsMutex.Unlock()
 	fake.ExistsStub = stub
 }
 
-func (fake *FakeClusterProvider) ExistsArgsForCall(i int) *v1alpha3.KindCluster {
+func (fake *FakeClusterProvider) ExistsArgsForCall(i int) *v1beta1.KindCluster {
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
 	argsForCall := fake.existsArgsForCall[i]
@@ -249,15 +543,157 @@ func (fake *FakeClusterProvider) ExistsReturnsOnCall(i int, result1 bool, result
 	}{result1, result2}
 }
 
-func (fake *FakeClusterProvider) GetControlPlaneEndpoint(arg1 *v1alpha3.KindCluster) (string, int, error) {
+func (fake *FakeClusterProvider) ExportKubeconfig(arg1 *v1beta1.KindCluster, arg2 []byte) error {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.exportKubeconfigMutex.Lock()
+	ret, specificReturn := fake.exportKubeconfigReturnsOnCall[len(fake.exportKubeconfigArgsForCall)]
+	fake.exportKubeconfigArgsForCall = append(fake.exportKubeconfigArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 []byte
+	}{arg1, arg2Copy})
+	stub := fake.ExportKubeconfigStub
+	fakeReturns := fake.exportKubeconfigReturns
+	fake.recordInvocation("ExportKubeconfig", []interface{}{arg1, arg2Copy})
+	fake.exportKubeconfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterProvider) ExportKubeconfigCallCount() int {
+	fake.exportKubeconfigMutex.RLock()
+	defer fake.exportKubeconfigMutex.RUnlock()
+	return len(fake.exportKubeconfigArgsForCall)
+}
+
+func (fake *FakeClusterProvider) ExportKubeconfigCalls(stub func(*v1beta1.KindCluster, []byte) error) {
+	fake.exportKubeconfigMutex.Lock()
+	defer fake.exportKubeconfigMutex.Unlock()
+	fake.ExportKubeconfigStub = stub
+}
+
+func (fake *FakeClusterProvider) ExportKubeconfigArgsForCall(i int) (*v1beta1.KindCluster, []byte) {
+	fake.exportKubeconfigMutex.RLock()
+	defer fake.exportKubeconfigMutex.RUnlock()
+	argsForCall := fake.exportKubeconfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClusterProvider) ExportKubeconfigReturns(result1 error) {
+	fake.exportKubeconfigMutex.Lock()
+	defer fake.exportKubeconfigMutex.Unlock()
+	fake.ExportKubeconfigStub = nil
+	fake.exportKubeconfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) ExportKubeconfigReturnsOnCall(i int, result1 error) {
+	fake.exportKubeconfigMutex.Lock()
+	defer fake.exportKubeconfigMutex.Unlock()
+	fake.ExportKubeconfigStub = nil
+	if fake.exportKubeconfigReturnsOnCall == nil {
+		fake.exportKubeconfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.exportKubeconfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) GetCertificateExpiry(arg1 *v1beta1.KindCluster, arg2 []byte) (time.Time, error) {
+	var arg2Copy []byte
+	if arg2 != nil {
+		arg2Copy = make([]byte, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.getCertificateExpiryMutex.Lock()
+	ret, specificReturn := fake.getCertificateExpiryReturnsOnCall[len(fake.getCertificateExpiryArgsForCall)]
+	fake.getCertificateExpiryArgsForCall = append(fake.getCertificateExpiryArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 []byte
+	}{arg1, arg2Copy})
+	stub := fake.GetCertificateExpiryStub
+	fakeReturns := fake.getCertificateExpiryReturns
+	fake.recordInvocation("GetCertificateExpiry", []interface{}{arg1, arg2Copy})
+	fake.getCertificateExpiryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) GetCertificateExpiryCallCount() int {
+	fake.getCertificateExpiryMutex.RLock()
+	defer fake.getCertificateExpiryMutex.RUnlock()
+	return len(fake.getCertificateExpiryArgsForCall)
+}
+
+func (fake *FakeClusterProvider) GetCertificateExpiryCalls(stub func(*v1beta1.KindCluster, []byte) (time.Time, error)) {
+	fake.getCertificateExpiryMutex.Lock()
+	defer fake.getCertificateExpiryMutex.Unlock()
+	fake.GetCertificateExpiryStub = stub
+}
+
+func (fake *FakeClusterProvider) GetCertificateExpiryArgsForCall(i int) (*v1beta1.KindCluster, []byte) {
+	fake.getCertificateExpiryMutex.RLock()
+	defer fake.getCertificateExpiryMutex.RUnlock()
+	argsForCall := fake.getCertificateExpiryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClusterProvider) GetCertificateExpiryReturns(result1 time.Time, result2 error) {
+	fake.getCertificateExpiryMutex.Lock()
+	defer fake.getCertificateExpiryMutex.Unlock()
+	fake.GetCertificateExpiryStub = nil
+	fake.getCertificateExpiryReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetCertificateExpiryReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.getCertificateExpiryMutex.Lock()
+	defer fake.getCertificateExpiryMutex.Unlock()
+	fake.GetCertificateExpiryStub = nil
+	if fake.getCertificateExpiryReturnsOnCall == nil {
+		fake.getCertificateExpiryReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.getCertificateExpiryReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetControlPlaneEndpoint(arg1 []byte) (string, int32, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
 	fake.getControlPlaneEndpointMutex.Lock()
 	ret, specificReturn := fake.getControlPlaneEndpointReturnsOnCall[len(fake.getControlPlaneEndpointArgsForCall)]
 	fake.getControlPlaneEndpointArgsForCall = append(fake.getControlPlaneEndpointArgsForCall, struct {
-		arg1 *v1alpha3.KindCluster
-	}{arg1})
+		arg1 []byte
+	}{arg1Copy})
 	stub := fake.GetControlPlaneEndpointStub
 	fakeReturns := fake.getControlPlaneEndpointReturns
-	fake.recordInvocation("GetControlPlaneEndpoint", []interface{}{arg1})
+	fake.recordInvocation("GetControlPlaneEndpoint", []interface{}{arg1Copy})
 	fake.getControlPlaneEndpointMutex.Unlock()
 	if stub != nil {
 		return stub(arg1)
@@ -274,59 +710,657 @@ func (fake *FakeClusterProvider) GetControlPlaneEndpointCallCount() int {
 	return len(fake.getControlPlaneEndpointArgsForCall)
 }
 
-func (fake *FakeClusterProvider) GetControlPlaneEndpointCalls(stub func(*v1alpha3.KindCluster) (string, int, error)) {
+func (fake *FakeClusterProvider) GetControlPlaneEndpointCalls(stub func([]byte) (string, int32, error)) {
 	fake.getControlPlaneEndpointMutex.Lock()
 	defer fake.getControlPlaneEndpointMutex.Unlock()
 	fake.GetControlPlaneEndpointStub = stub
 }
 
-func (fake *FakeClusterProvider) GetControlPlaneEndpointArgsForCall(i int) *v1alpha3.KindCluster {
+func (fake *FakeClusterProvider) GetControlPlaneEndpointArgsForCall(i int) []byte {
 	fake.getControlPlaneEndpointMutex.RLock()
 	defer fake.getControlPlaneEndpointMutex.RUnlock()
 	argsForCall := fake.getControlPlaneEndpointArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *FakeClusterProvider) GetControlPlaneEndpointReturns(result1 string, result2 int, result3 error) {
+func (fake *FakeClusterProvider) GetControlPlaneEndpointReturns(result1 string, result2 int32, result3 error) {
 	fake.getControlPlaneEndpointMutex.Lock()
 	defer fake.getControlPlaneEndpointMutex.Unlock()
 	fake.GetControlPlaneEndpointStub = nil
 	fake.getControlPlaneEndpointReturns = struct {
 		result1 string
-		result2 int
+		result2 int32
 		result3 error
 	}{result1, result2, result3}
 }
 
-func (fake *FakeClusterProvider) GetControlPlaneEndpointReturnsOnCall(i int, result1 string, result2 int, result3 error) {
+func (fake *FakeClusterProvider) GetControlPlaneEndpointReturnsOnCall(i int, result1 string, result2 int32, result3 error) {
 	fake.getControlPlaneEndpointMutex.Lock()
 	defer fake.getControlPlaneEndpointMutex.Unlock()
 	fake.GetControlPlaneEndpointStub = nil
 	if fake.getControlPlaneEndpointReturnsOnCall == nil {
 		fake.getControlPlaneEndpointReturnsOnCall = make(map[int]struct {
 			result1 string
-			result2 int
+			result2 int32
 			result3 error
 		})
 	}
 	fake.getControlPlaneEndpointReturnsOnCall[i] = struct {
 		result1 string
-		result2 int
+		result2 int32
 		result3 error
 	}{result1, result2, result3}
 }
 
+func (fake *FakeClusterProvider) GetKubeconfig(arg1 *v1beta1.KindCluster) ([]byte, error) {
+	fake.getKubeconfigMutex.Lock()
+	ret, specificReturn := fake.getKubeconfigReturnsOnCall[len(fake.getKubeconfigArgsForCall)]
+	fake.getKubeconfigArgsForCall = append(fake.getKubeconfigArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.GetKubeconfigStub
+	fakeReturns := fake.getKubeconfigReturns
+	fake.recordInvocation("GetKubeconfig", []interface{}{arg1})
+	fake.getKubeconfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) GetKubeconfigCallCount() int {
+	fake.getKubeconfigMutex.RLock()
+	defer fake.getKubeconfigMutex.RUnlock()
+	return len(fake.getKubeconfigArgsForCall)
+}
+
+func (fake *FakeClusterProvider) GetKubeconfigCalls(stub func(*v1beta1.KindCluster) ([]byte, error)) {
+	fake.getKubeconfigMutex.Lock()
+	defer fake.getKubeconfigMutex.Unlock()
+	fake.GetKubeconfigStub = stub
+}
+
+func (fake *FakeClusterProvider) GetKubeconfigArgsForCall(i int) *v1beta1.KindCluster {
+	fake.getKubeconfigMutex.RLock()
+	defer fake.getKubeconfigMutex.RUnlock()
+	argsForCall := fake.getKubeconfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) GetKubeconfigReturns(result1 []byte, result2 error) {
+	fake.getKubeconfigMutex.Lock()
+	defer fake.getKubeconfigMutex.Unlock()
+	fake.GetKubeconfigStub = nil
+	fake.getKubeconfigReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetKubeconfigReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getKubeconfigMutex.Lock()
+	defer fake.getKubeconfigMutex.Unlock()
+	fake.GetKubeconfigStub = nil
+	if fake.getKubeconfigReturnsOnCall == nil {
+		fake.getKubeconfigReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getKubeconfigReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetKubernetesVersion(arg1 []byte) (string, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.getKubernetesVersionMutex.Lock()
+	ret, specificReturn := fake.getKubernetesVersionReturnsOnCall[len(fake.getKubernetesVersionArgsForCall)]
+	fake.getKubernetesVersionArgsForCall = append(fake.getKubernetesVersionArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	stub := fake.GetKubernetesVersionStub
+	fakeReturns := fake.getKubernetesVersionReturns
+	fake.recordInvocation("GetKubernetesVersion", []interface{}{arg1Copy})
+	fake.getKubernetesVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) GetKubernetesVersionCallCount() int {
+	fake.getKubernetesVersionMutex.RLock()
+	defer fake.getKubernetesVersionMutex.RUnlock()
+	return len(fake.getKubernetesVersionArgsForCall)
+}
+
+func (fake *FakeClusterProvider) GetKubernetesVersionCalls(stub func([]byte) (string, error)) {
+	fake.getKubernetesVersionMutex.Lock()
+	defer fake.getKubernetesVersionMutex.Unlock()
+	fake.GetKubernetesVersionStub = stub
+}
+
+func (fake *FakeClusterProvider) GetKubernetesVersionArgsForCall(i int) []byte {
+	fake.getKubernetesVersionMutex.RLock()
+	defer fake.getKubernetesVersionMutex.RUnlock()
+	argsForCall := fake.getKubernetesVersionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) GetKubernetesVersionReturns(result1 string, result2 error) {
+	fake.getKubernetesVersionMutex.Lock()
+	defer fake.getKubernetesVersionMutex.Unlock()
+	fake.GetKubernetesVersionStub = nil
+	fake.getKubernetesVersionReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetKubernetesVersionReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getKubernetesVersionMutex.Lock()
+	defer fake.getKubernetesVersionMutex.Unlock()
+	fake.GetKubernetesVersionStub = nil
+	if fake.getKubernetesVersionReturnsOnCall == nil {
+		fake.getKubernetesVersionReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getKubernetesVersionReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetMutatingRequests(arg1 []byte) (int64, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.getMutatingRequestsMutex.Lock()
+	ret, specificReturn := fake.getMutatingRequestsReturnsOnCall[len(fake.getMutatingRequestsArgsForCall)]
+	fake.getMutatingRequestsArgsForCall = append(fake.getMutatingRequestsArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	stub := fake.GetMutatingRequestsStub
+	fakeReturns := fake.getMutatingRequestsReturns
+	fake.recordInvocation("GetMutatingRequests", []interface{}{arg1Copy})
+	fake.getMutatingRequestsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) GetMutatingRequestsCallCount() int {
+	fake.getMutatingRequestsMutex.RLock()
+	defer fake.getMutatingRequestsMutex.RUnlock()
+	return len(fake.getMutatingRequestsArgsForCall)
+}
+
+func (fake *FakeClusterProvider) GetMutatingRequestsCalls(stub func([]byte) (int64, error)) {
+	fake.getMutatingRequestsMutex.Lock()
+	defer fake.getMutatingRequestsMutex.Unlock()
+	fake.GetMutatingRequestsStub = stub
+}
+
+func (fake *FakeClusterProvider) GetMutatingRequestsArgsForCall(i int) []byte {
+	fake.getMutatingRequestsMutex.RLock()
+	defer fake.getMutatingRequestsMutex.RUnlock()
+	argsForCall := fake.getMutatingRequestsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) GetMutatingRequestsReturns(result1 int64, result2 error) {
+	fake.getMutatingRequestsMutex.Lock()
+	defer fake.getMutatingRequestsMutex.Unlock()
+	fake.GetMutatingRequestsStub = nil
+	fake.getMutatingRequestsReturns = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetMutatingRequestsReturnsOnCall(i int, result1 int64, result2 error) {
+	fake.getMutatingRequestsMutex.Lock()
+	defer fake.getMutatingRequestsMutex.Unlock()
+	fake.GetMutatingRequestsStub = nil
+	if fake.getMutatingRequestsReturnsOnCall == nil {
+		fake.getMutatingRequestsReturnsOnCall = make(map[int]struct {
+			result1 int64
+			result2 error
+		})
+	}
+	fake.getMutatingRequestsReturnsOnCall[i] = struct {
+		result1 int64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetNodes(arg1 *v1beta1.KindCluster) ([]v1beta1.NodeStatus, error) {
+	fake.getNodesMutex.Lock()
+	ret, specificReturn := fake.getNodesReturnsOnCall[len(fake.getNodesArgsForCall)]
+	fake.getNodesArgsForCall = append(fake.getNodesArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.GetNodesStub
+	fakeReturns := fake.getNodesReturns
+	fake.recordInvocation("GetNodes", []interface{}{arg1})
+	fake.getNodesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClusterProvider) GetNodesCallCount() int {
+	fake.getNodesMutex.RLock()
+	defer fake.getNodesMutex.RUnlock()
+	return len(fake.getNodesArgsForCall)
+}
+
+func (fake *FakeClusterProvider) GetNodesCalls(stub func(*v1beta1.KindCluster) ([]v1beta1.NodeStatus, error)) {
+	fake.getNodesMutex.Lock()
+	defer fake.getNodesMutex.Unlock()
+	fake.GetNodesStub = stub
+}
+
+func (fake *FakeClusterProvider) GetNodesArgsForCall(i int) *v1beta1.KindCluster {
+	fake.getNodesMutex.RLock()
+	defer fake.getNodesMutex.RUnlock()
+	argsForCall := fake.getNodesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) GetNodesReturns(result1 []v1beta1.NodeStatus, result2 error) {
+	fake.getNodesMutex.Lock()
+	defer fake.getNodesMutex.Unlock()
+	fake.GetNodesStub = nil
+	fake.getNodesReturns = struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) GetNodesReturnsOnCall(i int, result1 []v1beta1.NodeStatus, result2 error) {
+	fake.getNodesMutex.Lock()
+	defer fake.getNodesMutex.Unlock()
+	fake.GetNodesStub = nil
+	if fake.getNodesReturnsOnCall == nil {
+		fake.getNodesReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.NodeStatus
+			result2 error
+		})
+	}
+	fake.getNodesReturnsOnCall[i] = struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClusterProvider) ReleaseOwner(arg1 *v1beta1.KindCluster) error {
+	fake.releaseOwnerMutex.Lock()
+	ret, specificReturn := fake.releaseOwnerReturnsOnCall[len(fake.releaseOwnerArgsForCall)]
+	fake.releaseOwnerArgsForCall = append(fake.releaseOwnerArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.ReleaseOwnerStub
+	fakeReturns := fake.releaseOwnerReturns
+	fake.recordInvocation("ReleaseOwner", []interface{}{arg1})
+	fake.releaseOwnerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterProvider) ReleaseOwnerCallCount() int {
+	fake.releaseOwnerMutex.RLock()
+	defer fake.releaseOwnerMutex.RUnlock()
+	return len(fake.releaseOwnerArgsForCall)
+}
+
+func (fake *FakeClusterProvider) ReleaseOwnerCalls(stub func(*v1beta1.KindCluster) error) {
+	fake.releaseOwnerMutex.Lock()
+	defer fake.releaseOwnerMutex.Unlock()
+	fake.ReleaseOwnerStub = stub
+}
+
+func (fake *FakeClusterProvider) ReleaseOwnerArgsForCall(i int) *v1beta1.KindCluster {
+	fake.releaseOwnerMutex.RLock()
+	defer fake.releaseOwnerMutex.RUnlock()
+	argsForCall := fake.releaseOwnerArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) ReleaseOwnerReturns(result1 error) {
+	fake.releaseOwnerMutex.Lock()
+	defer fake.releaseOwnerMutex.Unlock()
+	fake.ReleaseOwnerStub = nil
+	fake.releaseOwnerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) ReleaseOwnerReturnsOnCall(i int, result1 error) {
+	fake.releaseOwnerMutex.Lock()
+	defer fake.releaseOwnerMutex.Unlock()
+	fake.ReleaseOwnerStub = nil
+	if fake.releaseOwnerReturnsOnCall == nil {
+		fake.releaseOwnerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseOwnerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) RenewCertificates(arg1 *v1beta1.KindCluster) error {
+	fake.renewCertificatesMutex.Lock()
+	ret, specificReturn := fake.renewCertificatesReturnsOnCall[len(fake.renewCertificatesArgsForCall)]
+	fake.renewCertificatesArgsForCall = append(fake.renewCertificatesArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.RenewCertificatesStub
+	fakeReturns := fake.renewCertificatesReturns
+	fake.recordInvocation("RenewCertificates", []interface{}{arg1})
+	fake.renewCertificatesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterProvider) RenewCertificatesCallCount() int {
+	fake.renewCertificatesMutex.RLock()
+	defer fake.renewCertificatesMutex.RUnlock()
+	return len(fake.renewCertificatesArgsForCall)
+}
+
+func (fake *FakeClusterProvider) RenewCertificatesCalls(stub func(*v1beta1.KindCluster) error) {
+	fake.renewCertificatesMutex.Lock()
+	defer fake.renewCertificatesMutex.Unlock()
+	fake.RenewCertificatesStub = stub
+}
+
+func (fake *FakeClusterProvider) RenewCertificatesArgsForCall(i int) *v1beta1.KindCluster {
+	fake.renewCertificatesMutex.RLock()
+	defer fake.renewCertificatesMutex.RUnlock()
+	argsForCall := fake.renewCertificatesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) RenewCertificatesReturns(result1 error) {
+	fake.renewCertificatesMutex.Lock()
+	defer fake.renewCertificatesMutex.Unlock()
+	fake.RenewCertificatesStub = nil
+	fake.renewCertificatesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) RenewCertificatesReturnsOnCall(i int, result1 error) {
+	fake.renewCertificatesMutex.Lock()
+	defer fake.renewCertificatesMutex.Unlock()
+	fake.RenewCertificatesStub = nil
+	if fake.renewCertificatesReturnsOnCall == nil {
+		fake.renewCertificatesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renewCertificatesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) RestartNodes(arg1 *v1beta1.KindCluster) error {
+	fake.restartNodesMutex.Lock()
+	ret, specificReturn := fake.restartNodesReturnsOnCall[len(fake.restartNodesArgsForCall)]
+	fake.restartNodesArgsForCall = append(fake.restartNodesArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.RestartNodesStub
+	fakeReturns := fake.restartNodesReturns
+	fake.recordInvocation("RestartNodes", []interface{}{arg1})
+	fake.restartNodesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterProvider) RestartNodesCallCount() int {
+	fake.restartNodesMutex.RLock()
+	defer fake.restartNodesMutex.RUnlock()
+	return len(fake.restartNodesArgsForCall)
+}
+
+func (fake *FakeClusterProvider) RestartNodesCalls(stub func(*v1beta1.KindCluster) error) {
+	fake.restartNodesMutex.Lock()
+	defer fake.restartNodesMutex.Unlock()
+	fake.RestartNodesStub = stub
+}
+
+func (fake *FakeClusterProvider) RestartNodesArgsForCall(i int) *v1beta1.KindCluster {
+	fake.restartNodesMutex.RLock()
+	defer fake.restartNodesMutex.RUnlock()
+	argsForCall := fake.restartNodesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) RestartNodesReturns(result1 error) {
+	fake.restartNodesMutex.Lock()
+	defer fake.restartNodesMutex.Unlock()
+	fake.RestartNodesStub = nil
+	fake.restartNodesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) RestartNodesReturnsOnCall(i int, result1 error) {
+	fake.restartNodesMutex.Lock()
+	defer fake.restartNodesMutex.Unlock()
+	fake.RestartNodesStub = nil
+	if fake.restartNodesReturnsOnCall == nil {
+		fake.restartNodesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restartNodesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) SetOwner(arg1 *v1beta1.KindCluster, arg2 v1.ObjectReference) error {
+	fake.setOwnerMutex.Lock()
+	ret, specificReturn := fake.setOwnerReturnsOnCall[len(fake.setOwnerArgsForCall)]
+	fake.setOwnerArgsForCall = append(fake.setOwnerArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}{arg1, arg2})
+	stub := fake.SetOwnerStub
+	fakeReturns := fake.setOwnerReturns
+	fake.recordInvocation("SetOwner", []interface{}{arg1, arg2})
+	fake.setOwnerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterProvider) SetOwnerCallCount() int {
+	fake.setOwnerMutex.RLock()
+	defer fake.setOwnerMutex.RUnlock()
+	return len(fake.setOwnerArgsForCall)
+}
+
+func (fake *FakeClusterProvider) SetOwnerCalls(stub func(*v1beta1.KindCluster, v1.ObjectReference) error) {
+	fake.setOwnerMutex.Lock()
+	defer fake.setOwnerMutex.Unlock()
+	fake.SetOwnerStub = stub
+}
+
+func (fake *FakeClusterProvider) SetOwnerArgsForCall(i int) (*v1beta1.KindCluster, v1.ObjectReference) {
+	fake.setOwnerMutex.RLock()
+	defer fake.setOwnerMutex.RUnlock()
+	argsForCall := fake.setOwnerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClusterProvider) SetOwnerReturns(result1 error) {
+	fake.setOwnerMutex.Lock()
+	defer fake.setOwnerMutex.Unlock()
+	fake.SetOwnerStub = nil
+	fake.setOwnerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) SetOwnerReturnsOnCall(i int, result1 error) {
+	fake.setOwnerMutex.Lock()
+	defer fake.setOwnerMutex.Unlock()
+	fake.SetOwnerStub = nil
+	if fake.setOwnerReturnsOnCall == nil {
+		fake.setOwnerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setOwnerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) StopNodes(arg1 *v1beta1.KindCluster) error {
+	fake.stopNodesMutex.Lock()
+	ret, specificReturn := fake.stopNodesReturnsOnCall[len(fake.stopNodesArgsForCall)]
+	fake.stopNodesArgsForCall = append(fake.stopNodesArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.StopNodesStub
+	fakeReturns := fake.stopNodesReturns
+	fake.recordInvocation("StopNodes", []interface{}{arg1})
+	fake.stopNodesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClusterProvider) StopNodesCallCount() int {
+	fake.stopNodesMutex.RLock()
+	defer fake.stopNodesMutex.RUnlock()
+	return len(fake.stopNodesArgsForCall)
+}
+
+func (fake *FakeClusterProvider) StopNodesCalls(stub func(*v1beta1.KindCluster) error) {
+	fake.stopNodesMutex.Lock()
+	defer fake.stopNodesMutex.Unlock()
+	fake.StopNodesStub = stub
+}
+
+func (fake *FakeClusterProvider) StopNodesArgsForCall(i int) *v1beta1.KindCluster {
+	fake.stopNodesMutex.RLock()
+	defer fake.stopNodesMutex.RUnlock()
+	argsForCall := fake.stopNodesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClusterProvider) StopNodesReturns(result1 error) {
+	fake.stopNodesMutex.Lock()
+	defer fake.stopNodesMutex.Unlock()
+	fake.StopNodesStub = nil
+	fake.stopNodesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClusterProvider) StopNodesReturnsOnCall(i int, result1 error) {
+	fake.stopNodesMutex.Lock()
+	defer fake.stopNodesMutex.Unlock()
+	fake.StopNodesStub = nil
+	if fake.stopNodesReturnsOnCall == nil {
+		fake.stopNodesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.stopNodesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClusterProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.checkHealthMutex.RLock()
+	defer fake.checkHealthMutex.RUnlock()
+	fake.collectDiagnosticsMutex.RLock()
+	defer fake.collectDiagnosticsMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
 	fake.existsMutex.RLock()
 	defer fake.existsMutex.RUnlock()
+	fake.exportKubeconfigMutex.RLock()
+	defer fake.exportKubeconfigMutex.RUnlock()
+	fake.getCertificateExpiryMutex.RLock()
+	defer fake.getCertificateExpiryMutex.RUnlock()
 	fake.getControlPlaneEndpointMutex.RLock()
 	defer fake.getControlPlaneEndpointMutex.RUnlock()
+	fake.getKubeconfigMutex.RLock()
+	defer fake.getKubeconfigMutex.RUnlock()
+	fake.getKubernetesVersionMutex.RLock()
+	defer fake.getKubernetesVersionMutex.RUnlock()
+	fake.getMutatingRequestsMutex.RLock()
+	defer fake.getMutatingRequestsMutex.RUnlock()
+	fake.getNodesMutex.RLock()
+	defer fake.getNodesMutex.RUnlock()
+	fake.releaseOwnerMutex.RLock()
+	defer fake.releaseOwnerMutex.RUnlock()
+	fake.renewCertificatesMutex.RLock()
+	defer fake.renewCertificatesMutex.RUnlock()
+	fake.restartNodesMutex.RLock()
+	defer fake.restartNodesMutex.RUnlock()
+	fake.setOwnerMutex.RLock()
+	defer fake.setOwnerMutex.RUnlock()
+	fake.stopNodesMutex.RLock()
+	defer fake.stopNodesMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,202 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	v1beta1a "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+type FakeControlPlaneClusterClient struct {
+	GetForControlPlaneStub        func(context.Context, *v1beta1a.KindControlPlane) (*v1beta1.Cluster, error)
+	getForControlPlaneMutex       sync.RWMutex
+	getForControlPlaneArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1a.KindControlPlane
+	}
+	getForControlPlaneReturns struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}
+	getForControlPlaneReturnsOnCall map[int]struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}
+	GetKindClusterStub        func(context.Context, *v1beta1.Cluster) (*v1beta1a.KindCluster, error)
+	getKindClusterMutex       sync.RWMutex
+	getKindClusterArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}
+	getKindClusterReturns struct {
+		result1 *v1beta1a.KindCluster
+		result2 error
+	}
+	getKindClusterReturnsOnCall map[int]struct {
+		result1 *v1beta1a.KindCluster
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeControlPlaneClusterClient) GetForControlPlane(arg1 context.Context, arg2 *v1beta1a.KindControlPlane) (*v1beta1.Cluster, error) {
+	fake.getForControlPlaneMutex.Lock()
+	ret, specificReturn := fake.getForControlPlaneReturnsOnCall[len(fake.getForControlPlaneArgsForCall)]
+	fake.getForControlPlaneArgsForCall = append(fake.getForControlPlaneArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1a.KindControlPlane
+	}{arg1, arg2})
+	stub := fake.GetForControlPlaneStub
+	fakeReturns := fake.getForControlPlaneReturns
+	fake.recordInvocation("GetForControlPlane", []interface{}{arg1, arg2})
+	fake.getForControlPlaneMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneClusterClient) GetForControlPlaneCallCount() int {
+	fake.getForControlPlaneMutex.RLock()
+	defer fake.getForControlPlaneMutex.RUnlock()
+	return len(fake.getForControlPlaneArgsForCall)
+}
+
+func (fake *FakeControlPlaneClusterClient) GetForControlPlaneCalls(stub func(context.Context, *v1beta1a.KindControlPlane) (*v1beta1.Cluster, error)) {
+	fake.getForControlPlaneMutex.Lock()
+	defer fake.getForControlPlaneMutex.Unlock()
+	fake.GetForControlPlaneStub = stub
+}
+
+func (fake *FakeControlPlaneClusterClient) GetForControlPlaneArgsForCall(i int) (context.Context, *v1beta1a.KindControlPlane) {
+	fake.getForControlPlaneMutex.RLock()
+	defer fake.getForControlPlaneMutex.RUnlock()
+	argsForCall := fake.getForControlPlaneArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeControlPlaneClusterClient) GetForControlPlaneReturns(result1 *v1beta1.Cluster, result2 error) {
+	fake.getForControlPlaneMutex.Lock()
+	defer fake.getForControlPlaneMutex.Unlock()
+	fake.GetForControlPlaneStub = nil
+	fake.getForControlPlaneReturns = struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneClusterClient) GetForControlPlaneReturnsOnCall(i int, result1 *v1beta1.Cluster, result2 error) {
+	fake.getForControlPlaneMutex.Lock()
+	defer fake.getForControlPlaneMutex.Unlock()
+	fake.GetForControlPlaneStub = nil
+	if fake.getForControlPlaneReturnsOnCall == nil {
+		fake.getForControlPlaneReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.Cluster
+			result2 error
+		})
+	}
+	fake.getForControlPlaneReturnsOnCall[i] = struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneClusterClient) GetKindCluster(arg1 context.Context, arg2 *v1beta1.Cluster) (*v1beta1a.KindCluster, error) {
+	fake.getKindClusterMutex.Lock()
+	ret, specificReturn := fake.getKindClusterReturnsOnCall[len(fake.getKindClusterArgsForCall)]
+	fake.getKindClusterArgsForCall = append(fake.getKindClusterArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}{arg1, arg2})
+	stub := fake.GetKindClusterStub
+	fakeReturns := fake.getKindClusterReturns
+	fake.recordInvocation("GetKindCluster", []interface{}{arg1, arg2})
+	fake.getKindClusterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneClusterClient) GetKindClusterCallCount() int {
+	fake.getKindClusterMutex.RLock()
+	defer fake.getKindClusterMutex.RUnlock()
+	return len(fake.getKindClusterArgsForCall)
+}
+
+func (fake *FakeControlPlaneClusterClient) GetKindClusterCalls(stub func(context.Context, *v1beta1.Cluster) (*v1beta1a.KindCluster, error)) {
+	fake.getKindClusterMutex.Lock()
+	defer fake.getKindClusterMutex.Unlock()
+	fake.GetKindClusterStub = stub
+}
+
+func (fake *FakeControlPlaneClusterClient) GetKindClusterArgsForCall(i int) (context.Context, *v1beta1.Cluster) {
+	fake.getKindClusterMutex.RLock()
+	defer fake.getKindClusterMutex.RUnlock()
+	argsForCall := fake.getKindClusterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeControlPlaneClusterClient) GetKindClusterReturns(result1 *v1beta1a.KindCluster, result2 error) {
+	fake.getKindClusterMutex.Lock()
+	defer fake.getKindClusterMutex.Unlock()
+	fake.GetKindClusterStub = nil
+	fake.getKindClusterReturns = struct {
+		result1 *v1beta1a.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneClusterClient) GetKindClusterReturnsOnCall(i int, result1 *v1beta1a.KindCluster, result2 error) {
+	fake.getKindClusterMutex.Lock()
+	defer fake.getKindClusterMutex.Unlock()
+	fake.GetKindClusterStub = nil
+	if fake.getKindClusterReturnsOnCall == nil {
+		fake.getKindClusterReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1a.KindCluster
+			result2 error
+		})
+	}
+	fake.getKindClusterReturnsOnCall[i] = struct {
+		result1 *v1beta1a.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneClusterClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getForControlPlaneMutex.RLock()
+	defer fake.getForControlPlaneMutex.RUnlock()
+	fake.getKindClusterMutex.RLock()
+	defer fake.getKindClusterMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeControlPlaneClusterClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.ControlPlaneClusterClient = new(FakeControlPlaneClusterClient)
@@ -0,0 +1,516 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeControlPlaneProvider struct {
+	AddControlPlaneNodeStub        func(*v1beta1.KindCluster, string) (string, error)
+	addControlPlaneNodeMutex       sync.RWMutex
+	addControlPlaneNodeArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}
+	addControlPlaneNodeReturns struct {
+		result1 string
+		result2 error
+	}
+	addControlPlaneNodeReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetControlPlaneComponentsStub        func(*v1beta1.KindCluster) ([]v1beta1.ControlPlaneComponent, error)
+	getControlPlaneComponentsMutex       sync.RWMutex
+	getControlPlaneComponentsArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	getControlPlaneComponentsReturns struct {
+		result1 []v1beta1.ControlPlaneComponent
+		result2 error
+	}
+	getControlPlaneComponentsReturnsOnCall map[int]struct {
+		result1 []v1beta1.ControlPlaneComponent
+		result2 error
+	}
+	GetKubeconfigStub        func(*v1beta1.KindCluster) ([]byte, error)
+	getKubeconfigMutex       sync.RWMutex
+	getKubeconfigArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	getKubeconfigReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getKubeconfigReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetKubernetesVersionStub        func([]byte) (string, error)
+	getKubernetesVersionMutex       sync.RWMutex
+	getKubernetesVersionArgsForCall []struct {
+		arg1 []byte
+	}
+	getKubernetesVersionReturns struct {
+		result1 string
+		result2 error
+	}
+	getKubernetesVersionReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetNodesStub        func(*v1beta1.KindCluster) ([]v1beta1.NodeStatus, error)
+	getNodesMutex       sync.RWMutex
+	getNodesArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	getNodesReturns struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}
+	getNodesReturnsOnCall map[int]struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}
+	RemoveControlPlaneNodeStub        func(*v1beta1.KindCluster, string) error
+	removeControlPlaneNodeMutex       sync.RWMutex
+	removeControlPlaneNodeArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}
+	removeControlPlaneNodeReturns struct {
+		result1 error
+	}
+	removeControlPlaneNodeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeControlPlaneProvider) AddControlPlaneNode(arg1 *v1beta1.KindCluster, arg2 string) (string, error) {
+	fake.addControlPlaneNodeMutex.Lock()
+	ret, specificReturn := fake.addControlPlaneNodeReturnsOnCall[len(fake.addControlPlaneNodeArgsForCall)]
+	fake.addControlPlaneNodeArgsForCall = append(fake.addControlPlaneNodeArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AddControlPlaneNodeStub
+	fakeReturns := fake.addControlPlaneNodeReturns
+	fake.recordInvocation("AddControlPlaneNode", []interface{}{arg1, arg2})
+	fake.addControlPlaneNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneProvider) AddControlPlaneNodeCallCount() int {
+	fake.addControlPlaneNodeMutex.RLock()
+	defer fake.addControlPlaneNodeMutex.RUnlock()
+	return len(fake.addControlPlaneNodeArgsForCall)
+}
+
+func (fake *FakeControlPlaneProvider) AddControlPlaneNodeCalls(stub func(*v1beta1.KindCluster, string) (string, error)) {
+	fake.addControlPlaneNodeMutex.Lock()
+	defer fake.addControlPlaneNodeMutex.Unlock()
+	fake.AddControlPlaneNodeStub = stub
+}
+
+func (fake *FakeControlPlaneProvider) AddControlPlaneNodeArgsForCall(i int) (*v1beta1.KindCluster, string) {
+	fake.addControlPlaneNodeMutex.RLock()
+	defer fake.addControlPlaneNodeMutex.RUnlock()
+	argsForCall := fake.addControlPlaneNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeControlPlaneProvider) AddControlPlaneNodeReturns(result1 string, result2 error) {
+	fake.addControlPlaneNodeMutex.Lock()
+	defer fake.addControlPlaneNodeMutex.Unlock()
+	fake.AddControlPlaneNodeStub = nil
+	fake.addControlPlaneNodeReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) AddControlPlaneNodeReturnsOnCall(i int, result1 string, result2 error) {
+	fake.addControlPlaneNodeMutex.Lock()
+	defer fake.addControlPlaneNodeMutex.Unlock()
+	fake.AddControlPlaneNodeStub = nil
+	if fake.addControlPlaneNodeReturnsOnCall == nil {
+		fake.addControlPlaneNodeReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.addControlPlaneNodeReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetControlPlaneComponents(arg1 *v1beta1.KindCluster) ([]v1beta1.ControlPlaneComponent, error) {
+	fake.getControlPlaneComponentsMutex.Lock()
+	ret, specificReturn := fake.getControlPlaneComponentsReturnsOnCall[len(fake.getControlPlaneComponentsArgsForCall)]
+	fake.getControlPlaneComponentsArgsForCall = append(fake.getControlPlaneComponentsArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.GetControlPlaneComponentsStub
+	fakeReturns := fake.getControlPlaneComponentsReturns
+	fake.recordInvocation("GetControlPlaneComponents", []interface{}{arg1})
+	fake.getControlPlaneComponentsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneProvider) GetControlPlaneComponentsCallCount() int {
+	fake.getControlPlaneComponentsMutex.RLock()
+	defer fake.getControlPlaneComponentsMutex.RUnlock()
+	return len(fake.getControlPlaneComponentsArgsForCall)
+}
+
+func (fake *FakeControlPlaneProvider) GetControlPlaneComponentsCalls(stub func(*v1beta1.KindCluster) ([]v1beta1.ControlPlaneComponent, error)) {
+	fake.getControlPlaneComponentsMutex.Lock()
+	defer fake.getControlPlaneComponentsMutex.Unlock()
+	fake.GetControlPlaneComponentsStub = stub
+}
+
+func (fake *FakeControlPlaneProvider) GetControlPlaneComponentsArgsForCall(i int) *v1beta1.KindCluster {
+	fake.getControlPlaneComponentsMutex.RLock()
+	defer fake.getControlPlaneComponentsMutex.RUnlock()
+	argsForCall := fake.getControlPlaneComponentsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeControlPlaneProvider) GetControlPlaneComponentsReturns(result1 []v1beta1.ControlPlaneComponent, result2 error) {
+	fake.getControlPlaneComponentsMutex.Lock()
+	defer fake.getControlPlaneComponentsMutex.Unlock()
+	fake.GetControlPlaneComponentsStub = nil
+	fake.getControlPlaneComponentsReturns = struct {
+		result1 []v1beta1.ControlPlaneComponent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetControlPlaneComponentsReturnsOnCall(i int, result1 []v1beta1.ControlPlaneComponent, result2 error) {
+	fake.getControlPlaneComponentsMutex.Lock()
+	defer fake.getControlPlaneComponentsMutex.Unlock()
+	fake.GetControlPlaneComponentsStub = nil
+	if fake.getControlPlaneComponentsReturnsOnCall == nil {
+		fake.getControlPlaneComponentsReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.ControlPlaneComponent
+			result2 error
+		})
+	}
+	fake.getControlPlaneComponentsReturnsOnCall[i] = struct {
+		result1 []v1beta1.ControlPlaneComponent
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetKubeconfig(arg1 *v1beta1.KindCluster) ([]byte, error) {
+	fake.getKubeconfigMutex.Lock()
+	ret, specificReturn := fake.getKubeconfigReturnsOnCall[len(fake.getKubeconfigArgsForCall)]
+	fake.getKubeconfigArgsForCall = append(fake.getKubeconfigArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.GetKubeconfigStub
+	fakeReturns := fake.getKubeconfigReturns
+	fake.recordInvocation("GetKubeconfig", []interface{}{arg1})
+	fake.getKubeconfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneProvider) GetKubeconfigCallCount() int {
+	fake.getKubeconfigMutex.RLock()
+	defer fake.getKubeconfigMutex.RUnlock()
+	return len(fake.getKubeconfigArgsForCall)
+}
+
+func (fake *FakeControlPlaneProvider) GetKubeconfigCalls(stub func(*v1beta1.KindCluster) ([]byte, error)) {
+	fake.getKubeconfigMutex.Lock()
+	defer fake.getKubeconfigMutex.Unlock()
+	fake.GetKubeconfigStub = stub
+}
+
+func (fake *FakeControlPlaneProvider) GetKubeconfigArgsForCall(i int) *v1beta1.KindCluster {
+	fake.getKubeconfigMutex.RLock()
+	defer fake.getKubeconfigMutex.RUnlock()
+	argsForCall := fake.getKubeconfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeControlPlaneProvider) GetKubeconfigReturns(result1 []byte, result2 error) {
+	fake.getKubeconfigMutex.Lock()
+	defer fake.getKubeconfigMutex.Unlock()
+	fake.GetKubeconfigStub = nil
+	fake.getKubeconfigReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetKubeconfigReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getKubeconfigMutex.Lock()
+	defer fake.getKubeconfigMutex.Unlock()
+	fake.GetKubeconfigStub = nil
+	if fake.getKubeconfigReturnsOnCall == nil {
+		fake.getKubeconfigReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getKubeconfigReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetKubernetesVersion(arg1 []byte) (string, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.getKubernetesVersionMutex.Lock()
+	ret, specificReturn := fake.getKubernetesVersionReturnsOnCall[len(fake.getKubernetesVersionArgsForCall)]
+	fake.getKubernetesVersionArgsForCall = append(fake.getKubernetesVersionArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	stub := fake.GetKubernetesVersionStub
+	fakeReturns := fake.getKubernetesVersionReturns
+	fake.recordInvocation("GetKubernetesVersion", []interface{}{arg1Copy})
+	fake.getKubernetesVersionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneProvider) GetKubernetesVersionCallCount() int {
+	fake.getKubernetesVersionMutex.RLock()
+	defer fake.getKubernetesVersionMutex.RUnlock()
+	return len(fake.getKubernetesVersionArgsForCall)
+}
+
+func (fake *FakeControlPlaneProvider) GetKubernetesVersionCalls(stub func([]byte) (string, error)) {
+	fake.getKubernetesVersionMutex.Lock()
+	defer fake.getKubernetesVersionMutex.Unlock()
+	fake.GetKubernetesVersionStub = stub
+}
+
+func (fake *FakeControlPlaneProvider) GetKubernetesVersionArgsForCall(i int) []byte {
+	fake.getKubernetesVersionMutex.RLock()
+	defer fake.getKubernetesVersionMutex.RUnlock()
+	argsForCall := fake.getKubernetesVersionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeControlPlaneProvider) GetKubernetesVersionReturns(result1 string, result2 error) {
+	fake.getKubernetesVersionMutex.Lock()
+	defer fake.getKubernetesVersionMutex.Unlock()
+	fake.GetKubernetesVersionStub = nil
+	fake.getKubernetesVersionReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetKubernetesVersionReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getKubernetesVersionMutex.Lock()
+	defer fake.getKubernetesVersionMutex.Unlock()
+	fake.GetKubernetesVersionStub = nil
+	if fake.getKubernetesVersionReturnsOnCall == nil {
+		fake.getKubernetesVersionReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getKubernetesVersionReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetNodes(arg1 *v1beta1.KindCluster) ([]v1beta1.NodeStatus, error) {
+	fake.getNodesMutex.Lock()
+	ret, specificReturn := fake.getNodesReturnsOnCall[len(fake.getNodesArgsForCall)]
+	fake.getNodesArgsForCall = append(fake.getNodesArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.GetNodesStub
+	fakeReturns := fake.getNodesReturns
+	fake.recordInvocation("GetNodes", []interface{}{arg1})
+	fake.getNodesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeControlPlaneProvider) GetNodesCallCount() int {
+	fake.getNodesMutex.RLock()
+	defer fake.getNodesMutex.RUnlock()
+	return len(fake.getNodesArgsForCall)
+}
+
+func (fake *FakeControlPlaneProvider) GetNodesCalls(stub func(*v1beta1.KindCluster) ([]v1beta1.NodeStatus, error)) {
+	fake.getNodesMutex.Lock()
+	defer fake.getNodesMutex.Unlock()
+	fake.GetNodesStub = stub
+}
+
+func (fake *FakeControlPlaneProvider) GetNodesArgsForCall(i int) *v1beta1.KindCluster {
+	fake.getNodesMutex.RLock()
+	defer fake.getNodesMutex.RUnlock()
+	argsForCall := fake.getNodesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeControlPlaneProvider) GetNodesReturns(result1 []v1beta1.NodeStatus, result2 error) {
+	fake.getNodesMutex.Lock()
+	defer fake.getNodesMutex.Unlock()
+	fake.GetNodesStub = nil
+	fake.getNodesReturns = struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) GetNodesReturnsOnCall(i int, result1 []v1beta1.NodeStatus, result2 error) {
+	fake.getNodesMutex.Lock()
+	defer fake.getNodesMutex.Unlock()
+	fake.GetNodesStub = nil
+	if fake.getNodesReturnsOnCall == nil {
+		fake.getNodesReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.NodeStatus
+			result2 error
+		})
+	}
+	fake.getNodesReturnsOnCall[i] = struct {
+		result1 []v1beta1.NodeStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeControlPlaneProvider) RemoveControlPlaneNode(arg1 *v1beta1.KindCluster, arg2 string) error {
+	fake.removeControlPlaneNodeMutex.Lock()
+	ret, specificReturn := fake.removeControlPlaneNodeReturnsOnCall[len(fake.removeControlPlaneNodeArgsForCall)]
+	fake.removeControlPlaneNodeArgsForCall = append(fake.removeControlPlaneNodeArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RemoveControlPlaneNodeStub
+	fakeReturns := fake.removeControlPlaneNodeReturns
+	fake.recordInvocation("RemoveControlPlaneNode", []interface{}{arg1, arg2})
+	fake.removeControlPlaneNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeControlPlaneProvider) RemoveControlPlaneNodeCallCount() int {
+	fake.removeControlPlaneNodeMutex.RLock()
+	defer fake.removeControlPlaneNodeMutex.RUnlock()
+	return len(fake.removeControlPlaneNodeArgsForCall)
+}
+
+func (fake *FakeControlPlaneProvider) RemoveControlPlaneNodeCalls(stub func(*v1beta1.KindCluster, string) error) {
+	fake.removeControlPlaneNodeMutex.Lock()
+	defer fake.removeControlPlaneNodeMutex.Unlock()
+	fake.RemoveControlPlaneNodeStub = stub
+}
+
+func (fake *FakeControlPlaneProvider) RemoveControlPlaneNodeArgsForCall(i int) (*v1beta1.KindCluster, string) {
+	fake.removeControlPlaneNodeMutex.RLock()
+	defer fake.removeControlPlaneNodeMutex.RUnlock()
+	argsForCall := fake.removeControlPlaneNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeControlPlaneProvider) RemoveControlPlaneNodeReturns(result1 error) {
+	fake.removeControlPlaneNodeMutex.Lock()
+	defer fake.removeControlPlaneNodeMutex.Unlock()
+	fake.RemoveControlPlaneNodeStub = nil
+	fake.removeControlPlaneNodeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeControlPlaneProvider) RemoveControlPlaneNodeReturnsOnCall(i int, result1 error) {
+	fake.removeControlPlaneNodeMutex.Lock()
+	defer fake.removeControlPlaneNodeMutex.Unlock()
+	fake.RemoveControlPlaneNodeStub = nil
+	if fake.removeControlPlaneNodeReturnsOnCall == nil {
+		fake.removeControlPlaneNodeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeControlPlaneNodeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeControlPlaneProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addControlPlaneNodeMutex.RLock()
+	defer fake.addControlPlaneNodeMutex.RUnlock()
+	fake.getControlPlaneComponentsMutex.RLock()
+	defer fake.getControlPlaneComponentsMutex.RUnlock()
+	fake.getKubeconfigMutex.RLock()
+	defer fake.getKubeconfigMutex.RUnlock()
+	fake.getKubernetesVersionMutex.RLock()
+	defer fake.getKubernetesVersionMutex.RUnlock()
+	fake.getNodesMutex.RLock()
+	defer fake.getNodesMutex.RUnlock()
+	fake.removeControlPlaneNodeMutex.RLock()
+	defer fake.removeControlPlaneNodeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeControlPlaneProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.ControlPlaneProvider = new(FakeControlPlaneProvider)
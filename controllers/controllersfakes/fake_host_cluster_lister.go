@@ -0,0 +1,120 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeHostClusterLister struct {
+	ListByHostStub        func(context.Context, string) ([]v1beta1.KindCluster, error)
+	listByHostMutex       sync.RWMutex
+	listByHostArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listByHostReturns struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}
+	listByHostReturnsOnCall map[int]struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeHostClusterLister) ListByHost(arg1 context.Context, arg2 string) ([]v1beta1.KindCluster, error) {
+	fake.listByHostMutex.Lock()
+	ret, specificReturn := fake.listByHostReturnsOnCall[len(fake.listByHostArgsForCall)]
+	fake.listByHostArgsForCall = append(fake.listByHostArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListByHostStub
+	fakeReturns := fake.listByHostReturns
+	fake.recordInvocation("ListByHost", []interface{}{arg1, arg2})
+	fake.listByHostMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeHostClusterLister) ListByHostCallCount() int {
+	fake.listByHostMutex.RLock()
+	defer fake.listByHostMutex.RUnlock()
+	return len(fake.listByHostArgsForCall)
+}
+
+func (fake *FakeHostClusterLister) ListByHostCalls(stub func(context.Context, string) ([]v1beta1.KindCluster, error)) {
+	fake.listByHostMutex.Lock()
+	defer fake.listByHostMutex.Unlock()
+	fake.ListByHostStub = stub
+}
+
+func (fake *FakeHostClusterLister) ListByHostArgsForCall(i int) (context.Context, string) {
+	fake.listByHostMutex.RLock()
+	defer fake.listByHostMutex.RUnlock()
+	argsForCall := fake.listByHostArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeHostClusterLister) ListByHostReturns(result1 []v1beta1.KindCluster, result2 error) {
+	fake.listByHostMutex.Lock()
+	defer fake.listByHostMutex.Unlock()
+	fake.ListByHostStub = nil
+	fake.listByHostReturns = struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHostClusterLister) ListByHostReturnsOnCall(i int, result1 []v1beta1.KindCluster, result2 error) {
+	fake.listByHostMutex.Lock()
+	defer fake.listByHostMutex.Unlock()
+	fake.ListByHostStub = nil
+	if fake.listByHostReturnsOnCall == nil {
+		fake.listByHostReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.KindCluster
+			result2 error
+		})
+	}
+	fake.listByHostReturnsOnCall[i] = struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHostClusterLister) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listByHostMutex.RLock()
+	defer fake.listByHostMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeHostClusterLister) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.HostClusterLister = new(FakeHostClusterLister)
@@ -0,0 +1,156 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeHostProvider struct {
+	ForgetHostStub        func(string)
+	forgetHostMutex       sync.RWMutex
+	forgetHostArgsForCall []struct {
+		arg1 string
+	}
+	GetHostStatusStub        func(*v1beta1.KindHost) (v1beta1.KindHostStatus, error)
+	getHostStatusMutex       sync.RWMutex
+	getHostStatusArgsForCall []struct {
+		arg1 *v1beta1.KindHost
+	}
+	getHostStatusReturns struct {
+		result1 v1beta1.KindHostStatus
+		result2 error
+	}
+	getHostStatusReturnsOnCall map[int]struct {
+		result1 v1beta1.KindHostStatus
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeHostProvider) ForgetHost(arg1 string) {
+	fake.forgetHostMutex.Lock()
+	fake.forgetHostArgsForCall = append(fake.forgetHostArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ForgetHostStub
+	fake.recordInvocation("ForgetHost", []interface{}{arg1})
+	fake.forgetHostMutex.Unlock()
+	if stub != nil {
+		fake.ForgetHostStub(arg1)
+	}
+}
+
+func (fake *FakeHostProvider) ForgetHostCallCount() int {
+	fake.forgetHostMutex.RLock()
+	defer fake.forgetHostMutex.RUnlock()
+	return len(fake.forgetHostArgsForCall)
+}
+
+func (fake *FakeHostProvider) ForgetHostCalls(stub func(string)) {
+	fake.forgetHostMutex.Lock()
+	defer fake.forgetHostMutex.Unlock()
+	fake.ForgetHostStub = stub
+}
+
+func (fake *FakeHostProvider) ForgetHostArgsForCall(i int) string {
+	fake.forgetHostMutex.RLock()
+	defer fake.forgetHostMutex.RUnlock()
+	argsForCall := fake.forgetHostArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeHostProvider) GetHostStatus(arg1 *v1beta1.KindHost) (v1beta1.KindHostStatus, error) {
+	fake.getHostStatusMutex.Lock()
+	ret, specificReturn := fake.getHostStatusReturnsOnCall[len(fake.getHostStatusArgsForCall)]
+	fake.getHostStatusArgsForCall = append(fake.getHostStatusArgsForCall, struct {
+		arg1 *v1beta1.KindHost
+	}{arg1})
+	stub := fake.GetHostStatusStub
+	fakeReturns := fake.getHostStatusReturns
+	fake.recordInvocation("GetHostStatus", []interface{}{arg1})
+	fake.getHostStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeHostProvider) GetHostStatusCallCount() int {
+	fake.getHostStatusMutex.RLock()
+	defer fake.getHostStatusMutex.RUnlock()
+	return len(fake.getHostStatusArgsForCall)
+}
+
+func (fake *FakeHostProvider) GetHostStatusCalls(stub func(*v1beta1.KindHost) (v1beta1.KindHostStatus, error)) {
+	fake.getHostStatusMutex.Lock()
+	defer fake.getHostStatusMutex.Unlock()
+	fake.GetHostStatusStub = stub
+}
+
+func (fake *FakeHostProvider) GetHostStatusArgsForCall(i int) *v1beta1.KindHost {
+	fake.getHostStatusMutex.RLock()
+	defer fake.getHostStatusMutex.RUnlock()
+	argsForCall := fake.getHostStatusArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeHostProvider) GetHostStatusReturns(result1 v1beta1.KindHostStatus, result2 error) {
+	fake.getHostStatusMutex.Lock()
+	defer fake.getHostStatusMutex.Unlock()
+	fake.GetHostStatusStub = nil
+	fake.getHostStatusReturns = struct {
+		result1 v1beta1.KindHostStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHostProvider) GetHostStatusReturnsOnCall(i int, result1 v1beta1.KindHostStatus, result2 error) {
+	fake.getHostStatusMutex.Lock()
+	defer fake.getHostStatusMutex.Unlock()
+	fake.GetHostStatusStub = nil
+	if fake.getHostStatusReturnsOnCall == nil {
+		fake.getHostStatusReturnsOnCall = make(map[int]struct {
+			result1 v1beta1.KindHostStatus
+			result2 error
+		})
+	}
+	fake.getHostStatusReturnsOnCall[i] = struct {
+		result1 v1beta1.KindHostStatus
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHostProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.forgetHostMutex.RLock()
+	defer fake.forgetHostMutex.RUnlock()
+	fake.getHostStatusMutex.RLock()
+	defer fake.getHostStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeHostProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.HostProvider = new(FakeHostProvider)
@@ -0,0 +1,120 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeHostScheduler struct {
+	ScheduleStub        func(context.Context, *v1beta1.KindCluster) (*v1beta1.KindHost, error)
+	scheduleMutex       sync.RWMutex
+	scheduleArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindCluster
+	}
+	scheduleReturns struct {
+		result1 *v1beta1.KindHost
+		result2 error
+	}
+	scheduleReturnsOnCall map[int]struct {
+		result1 *v1beta1.KindHost
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeHostScheduler) Schedule(arg1 context.Context, arg2 *v1beta1.KindCluster) (*v1beta1.KindHost, error) {
+	fake.scheduleMutex.Lock()
+	ret, specificReturn := fake.scheduleReturnsOnCall[len(fake.scheduleArgsForCall)]
+	fake.scheduleArgsForCall = append(fake.scheduleArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindCluster
+	}{arg1, arg2})
+	stub := fake.ScheduleStub
+	fakeReturns := fake.scheduleReturns
+	fake.recordInvocation("Schedule", []interface{}{arg1, arg2})
+	fake.scheduleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeHostScheduler) ScheduleCallCount() int {
+	fake.scheduleMutex.RLock()
+	defer fake.scheduleMutex.RUnlock()
+	return len(fake.scheduleArgsForCall)
+}
+
+func (fake *FakeHostScheduler) ScheduleCalls(stub func(context.Context, *v1beta1.KindCluster) (*v1beta1.KindHost, error)) {
+	fake.scheduleMutex.Lock()
+	defer fake.scheduleMutex.Unlock()
+	fake.ScheduleStub = stub
+}
+
+func (fake *FakeHostScheduler) ScheduleArgsForCall(i int) (context.Context, *v1beta1.KindCluster) {
+	fake.scheduleMutex.RLock()
+	defer fake.scheduleMutex.RUnlock()
+	argsForCall := fake.scheduleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeHostScheduler) ScheduleReturns(result1 *v1beta1.KindHost, result2 error) {
+	fake.scheduleMutex.Lock()
+	defer fake.scheduleMutex.Unlock()
+	fake.ScheduleStub = nil
+	fake.scheduleReturns = struct {
+		result1 *v1beta1.KindHost
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHostScheduler) ScheduleReturnsOnCall(i int, result1 *v1beta1.KindHost, result2 error) {
+	fake.scheduleMutex.Lock()
+	defer fake.scheduleMutex.Unlock()
+	fake.ScheduleStub = nil
+	if fake.scheduleReturnsOnCall == nil {
+		fake.scheduleReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.KindHost
+			result2 error
+		})
+	}
+	fake.scheduleReturnsOnCall[i] = struct {
+		result1 *v1beta1.KindHost
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHostScheduler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.scheduleMutex.RLock()
+	defer fake.scheduleMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeHostScheduler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.HostScheduler = new(FakeHostScheduler)
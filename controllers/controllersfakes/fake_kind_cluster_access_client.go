@@ -0,0 +1,596 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type FakeKindClusterAccessClient struct {
+	AddFinalizerStub        func(context.Context, *v1beta1.KindClusterAccess) error
+	addFinalizerMutex       sync.RWMutex
+	addFinalizerArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+	}
+	addFinalizerReturns struct {
+		result1 error
+	}
+	addFinalizerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStub        func(context.Context, types.NamespacedName) (*v1beta1.KindClusterAccess, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}
+	getReturns struct {
+		result1 *v1beta1.KindClusterAccess
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 *v1beta1.KindClusterAccess
+		result2 error
+	}
+	KubeconfigExistsStub        func(context.Context, *v1beta1.KindClusterAccess) (bool, error)
+	kubeconfigExistsMutex       sync.RWMutex
+	kubeconfigExistsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+	}
+	kubeconfigExistsReturns struct {
+		result1 bool
+		result2 error
+	}
+	kubeconfigExistsReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	ListForKindClusterStub        func(context.Context, *v1beta1.KindCluster) ([]v1beta1.KindClusterAccess, error)
+	listForKindClusterMutex       sync.RWMutex
+	listForKindClusterArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindCluster
+	}
+	listForKindClusterReturns struct {
+		result1 []v1beta1.KindClusterAccess
+		result2 error
+	}
+	listForKindClusterReturnsOnCall map[int]struct {
+		result1 []v1beta1.KindClusterAccess
+		result2 error
+	}
+	RemoveFinalizerStub        func(context.Context, *v1beta1.KindClusterAccess) error
+	removeFinalizerMutex       sync.RWMutex
+	removeFinalizerArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+	}
+	removeFinalizerReturns struct {
+		result1 error
+	}
+	removeFinalizerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StoreKubeconfigStub        func(context.Context, *v1beta1.KindClusterAccess, []byte) error
+	storeKubeconfigMutex       sync.RWMutex
+	storeKubeconfigArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+		arg3 []byte
+	}
+	storeKubeconfigReturns struct {
+		result1 error
+	}
+	storeKubeconfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStatusStub        func(context.Context, v1beta1.KindClusterAccessStatus, *v1beta1.KindClusterAccess) error
+	updateStatusMutex       sync.RWMutex
+	updateStatusArgsForCall []struct {
+		arg1 context.Context
+		arg2 v1beta1.KindClusterAccessStatus
+		arg3 *v1beta1.KindClusterAccess
+	}
+	updateStatusReturns struct {
+		result1 error
+	}
+	updateStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKindClusterAccessClient) AddFinalizer(arg1 context.Context, arg2 *v1beta1.KindClusterAccess) error {
+	fake.addFinalizerMutex.Lock()
+	ret, specificReturn := fake.addFinalizerReturnsOnCall[len(fake.addFinalizerArgsForCall)]
+	fake.addFinalizerArgsForCall = append(fake.addFinalizerArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+	}{arg1, arg2})
+	stub := fake.AddFinalizerStub
+	fakeReturns := fake.addFinalizerReturns
+	fake.recordInvocation("AddFinalizer", []interface{}{arg1, arg2})
+	fake.addFinalizerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterAccessClient) AddFinalizerCallCount() int {
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	return len(fake.addFinalizerArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) AddFinalizerCalls(stub func(context.Context, *v1beta1.KindClusterAccess) error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) AddFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindClusterAccess) {
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	argsForCall := fake.addFinalizerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterAccessClient) AddFinalizerReturns(result1 error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = nil
+	fake.addFinalizerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) AddFinalizerReturnsOnCall(i int, result1 error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = nil
+	if fake.addFinalizerReturnsOnCall == nil {
+		fake.addFinalizerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addFinalizerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) Get(arg1 context.Context, arg2 types.NamespacedName) (*v1beta1.KindClusterAccess, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}{arg1, arg2})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindClusterAccessClient) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) GetCalls(stub func(context.Context, types.NamespacedName) (*v1beta1.KindClusterAccess, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) GetArgsForCall(i int) (context.Context, types.NamespacedName) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterAccessClient) GetReturns(result1 *v1beta1.KindClusterAccess, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *v1beta1.KindClusterAccess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterAccessClient) GetReturnsOnCall(i int, result1 *v1beta1.KindClusterAccess, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.KindClusterAccess
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 *v1beta1.KindClusterAccess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterAccessClient) KubeconfigExists(arg1 context.Context, arg2 *v1beta1.KindClusterAccess) (bool, error) {
+	fake.kubeconfigExistsMutex.Lock()
+	ret, specificReturn := fake.kubeconfigExistsReturnsOnCall[len(fake.kubeconfigExistsArgsForCall)]
+	fake.kubeconfigExistsArgsForCall = append(fake.kubeconfigExistsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+	}{arg1, arg2})
+	stub := fake.KubeconfigExistsStub
+	fakeReturns := fake.kubeconfigExistsReturns
+	fake.recordInvocation("KubeconfigExists", []interface{}{arg1, arg2})
+	fake.kubeconfigExistsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindClusterAccessClient) KubeconfigExistsCallCount() int {
+	fake.kubeconfigExistsMutex.RLock()
+	defer fake.kubeconfigExistsMutex.RUnlock()
+	return len(fake.kubeconfigExistsArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) KubeconfigExistsCalls(stub func(context.Context, *v1beta1.KindClusterAccess) (bool, error)) {
+	fake.kubeconfigExistsMutex.Lock()
+	defer fake.kubeconfigExistsMutex.Unlock()
+	fake.KubeconfigExistsStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) KubeconfigExistsArgsForCall(i int) (context.Context, *v1beta1.KindClusterAccess) {
+	fake.kubeconfigExistsMutex.RLock()
+	defer fake.kubeconfigExistsMutex.RUnlock()
+	argsForCall := fake.kubeconfigExistsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterAccessClient) KubeconfigExistsReturns(result1 bool, result2 error) {
+	fake.kubeconfigExistsMutex.Lock()
+	defer fake.kubeconfigExistsMutex.Unlock()
+	fake.KubeconfigExistsStub = nil
+	fake.kubeconfigExistsReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterAccessClient) KubeconfigExistsReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.kubeconfigExistsMutex.Lock()
+	defer fake.kubeconfigExistsMutex.Unlock()
+	fake.KubeconfigExistsStub = nil
+	if fake.kubeconfigExistsReturnsOnCall == nil {
+		fake.kubeconfigExistsReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.kubeconfigExistsReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterAccessClient) ListForKindCluster(arg1 context.Context, arg2 *v1beta1.KindCluster) ([]v1beta1.KindClusterAccess, error) {
+	fake.listForKindClusterMutex.Lock()
+	ret, specificReturn := fake.listForKindClusterReturnsOnCall[len(fake.listForKindClusterArgsForCall)]
+	fake.listForKindClusterArgsForCall = append(fake.listForKindClusterArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindCluster
+	}{arg1, arg2})
+	stub := fake.ListForKindClusterStub
+	fakeReturns := fake.listForKindClusterReturns
+	fake.recordInvocation("ListForKindCluster", []interface{}{arg1, arg2})
+	fake.listForKindClusterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindClusterAccessClient) ListForKindClusterCallCount() int {
+	fake.listForKindClusterMutex.RLock()
+	defer fake.listForKindClusterMutex.RUnlock()
+	return len(fake.listForKindClusterArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) ListForKindClusterCalls(stub func(context.Context, *v1beta1.KindCluster) ([]v1beta1.KindClusterAccess, error)) {
+	fake.listForKindClusterMutex.Lock()
+	defer fake.listForKindClusterMutex.Unlock()
+	fake.ListForKindClusterStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) ListForKindClusterArgsForCall(i int) (context.Context, *v1beta1.KindCluster) {
+	fake.listForKindClusterMutex.RLock()
+	defer fake.listForKindClusterMutex.RUnlock()
+	argsForCall := fake.listForKindClusterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterAccessClient) ListForKindClusterReturns(result1 []v1beta1.KindClusterAccess, result2 error) {
+	fake.listForKindClusterMutex.Lock()
+	defer fake.listForKindClusterMutex.Unlock()
+	fake.ListForKindClusterStub = nil
+	fake.listForKindClusterReturns = struct {
+		result1 []v1beta1.KindClusterAccess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterAccessClient) ListForKindClusterReturnsOnCall(i int, result1 []v1beta1.KindClusterAccess, result2 error) {
+	fake.listForKindClusterMutex.Lock()
+	defer fake.listForKindClusterMutex.Unlock()
+	fake.ListForKindClusterStub = nil
+	if fake.listForKindClusterReturnsOnCall == nil {
+		fake.listForKindClusterReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.KindClusterAccess
+			result2 error
+		})
+	}
+	fake.listForKindClusterReturnsOnCall[i] = struct {
+		result1 []v1beta1.KindClusterAccess
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterAccessClient) RemoveFinalizer(arg1 context.Context, arg2 *v1beta1.KindClusterAccess) error {
+	fake.removeFinalizerMutex.Lock()
+	ret, specificReturn := fake.removeFinalizerReturnsOnCall[len(fake.removeFinalizerArgsForCall)]
+	fake.removeFinalizerArgsForCall = append(fake.removeFinalizerArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+	}{arg1, arg2})
+	stub := fake.RemoveFinalizerStub
+	fakeReturns := fake.removeFinalizerReturns
+	fake.recordInvocation("RemoveFinalizer", []interface{}{arg1, arg2})
+	fake.removeFinalizerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterAccessClient) RemoveFinalizerCallCount() int {
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	return len(fake.removeFinalizerArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) RemoveFinalizerCalls(stub func(context.Context, *v1beta1.KindClusterAccess) error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) RemoveFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindClusterAccess) {
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	argsForCall := fake.removeFinalizerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterAccessClient) RemoveFinalizerReturns(result1 error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = nil
+	fake.removeFinalizerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) RemoveFinalizerReturnsOnCall(i int, result1 error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = nil
+	if fake.removeFinalizerReturnsOnCall == nil {
+		fake.removeFinalizerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeFinalizerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) StoreKubeconfig(arg1 context.Context, arg2 *v1beta1.KindClusterAccess, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.storeKubeconfigMutex.Lock()
+	ret, specificReturn := fake.storeKubeconfigReturnsOnCall[len(fake.storeKubeconfigArgsForCall)]
+	fake.storeKubeconfigArgsForCall = append(fake.storeKubeconfigArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterAccess
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.StoreKubeconfigStub
+	fakeReturns := fake.storeKubeconfigReturns
+	fake.recordInvocation("StoreKubeconfig", []interface{}{arg1, arg2, arg3Copy})
+	fake.storeKubeconfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterAccessClient) StoreKubeconfigCallCount() int {
+	fake.storeKubeconfigMutex.RLock()
+	defer fake.storeKubeconfigMutex.RUnlock()
+	return len(fake.storeKubeconfigArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) StoreKubeconfigCalls(stub func(context.Context, *v1beta1.KindClusterAccess, []byte) error) {
+	fake.storeKubeconfigMutex.Lock()
+	defer fake.storeKubeconfigMutex.Unlock()
+	fake.StoreKubeconfigStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) StoreKubeconfigArgsForCall(i int) (context.Context, *v1beta1.KindClusterAccess, []byte) {
+	fake.storeKubeconfigMutex.RLock()
+	defer fake.storeKubeconfigMutex.RUnlock()
+	argsForCall := fake.storeKubeconfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterAccessClient) StoreKubeconfigReturns(result1 error) {
+	fake.storeKubeconfigMutex.Lock()
+	defer fake.storeKubeconfigMutex.Unlock()
+	fake.StoreKubeconfigStub = nil
+	fake.storeKubeconfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) StoreKubeconfigReturnsOnCall(i int, result1 error) {
+	fake.storeKubeconfigMutex.Lock()
+	defer fake.storeKubeconfigMutex.Unlock()
+	fake.StoreKubeconfigStub = nil
+	if fake.storeKubeconfigReturnsOnCall == nil {
+		fake.storeKubeconfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeKubeconfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) UpdateStatus(arg1 context.Context, arg2 v1beta1.KindClusterAccessStatus, arg3 *v1beta1.KindClusterAccess) error {
+	fake.updateStatusMutex.Lock()
+	ret, specificReturn := fake.updateStatusReturnsOnCall[len(fake.updateStatusArgsForCall)]
+	fake.updateStatusArgsForCall = append(fake.updateStatusArgsForCall, struct {
+		arg1 context.Context
+		arg2 v1beta1.KindClusterAccessStatus
+		arg3 *v1beta1.KindClusterAccess
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStatusStub
+	fakeReturns := fake.updateStatusReturns
+	fake.recordInvocation("UpdateStatus", []interface{}{arg1, arg2, arg3})
+	fake.updateStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterAccessClient) UpdateStatusCallCount() int {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	return len(fake.updateStatusArgsForCall)
+}
+
+func (fake *FakeKindClusterAccessClient) UpdateStatusCalls(stub func(context.Context, v1beta1.KindClusterAccessStatus, *v1beta1.KindClusterAccess) error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = stub
+}
+
+func (fake *FakeKindClusterAccessClient) UpdateStatusArgsForCall(i int) (context.Context, v1beta1.KindClusterAccessStatus, *v1beta1.KindClusterAccess) {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	argsForCall := fake.updateStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterAccessClient) UpdateStatusReturns(result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	fake.updateStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) UpdateStatusReturnsOnCall(i int, result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	if fake.updateStatusReturnsOnCall == nil {
+		fake.updateStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterAccessClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.kubeconfigExistsMutex.RLock()
+	defer fake.kubeconfigExistsMutex.RUnlock()
+	fake.listForKindClusterMutex.RLock()
+	defer fake.listForKindClusterMutex.RUnlock()
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	fake.storeKubeconfigMutex.RLock()
+	defer fake.storeKubeconfigMutex.RUnlock()
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKindClusterAccessClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.KindClusterAccessClient = new(FakeKindClusterAccessClient)
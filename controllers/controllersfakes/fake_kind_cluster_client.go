@@ -5,17 +5,19 @@ import (
 	"context"
 	"sync"
 
-	"github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	v1beta1a "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 type FakeKindClusterClient struct {
-	AddFinalizerStub        func(context.Context, *v1alpha3.KindCluster) error
+	AddFinalizerStub        func(context.Context, *v1beta1.KindCluster) error
 	addFinalizerMutex       sync.RWMutex
 	addFinalizerArgsForCall []struct {
 		arg1 context.Context
-		arg2 *v1alpha3.KindCluster
+		arg2 *v1beta1.KindCluster
 	}
 	addFinalizerReturns struct {
 		result1 error
@@ -23,25 +25,64 @@ type FakeKindClusterClient struct {
 	addFinalizerReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetStub        func(context.Context, types.NamespacedName) (*v1alpha3.KindCluster, error)
+	DeleteStub        func(context.Context, *v1beta1.KindCluster) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindCluster
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExtendLeaseStub        func(context.Context, v1.Time, *v1beta1.KindCluster) error
+	extendLeaseMutex       sync.RWMutex
+	extendLeaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 v1.Time
+		arg3 *v1beta1.KindCluster
+	}
+	extendLeaseReturns struct {
+		result1 error
+	}
+	extendLeaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStub        func(context.Context, types.NamespacedName) (*v1beta1.KindCluster, error)
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
 		arg1 context.Context
 		arg2 types.NamespacedName
 	}
 	getReturns struct {
-		result1 *v1alpha3.KindCluster
+		result1 *v1beta1.KindCluster
 		result2 error
 	}
 	getReturnsOnCall map[int]struct {
-		result1 *v1alpha3.KindCluster
+		result1 *v1beta1.KindCluster
+		result2 error
+	}
+	ListByNameStub        func(context.Context, string) ([]v1beta1.KindCluster, error)
+	listByNameMutex       sync.RWMutex
+	listByNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listByNameReturns struct {
+		result1 []v1beta1.KindCluster
 		result2 error
 	}
-	RemoveFinalizerStub        func(context.Context, *v1alpha3.KindCluster) error
+	listByNameReturnsOnCall map[int]struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}
+	RemoveFinalizerStub        func(context.Context, *v1beta1.KindCluster) error
 	removeFinalizerMutex       sync.RWMutex
 	removeFinalizerArgsForCall []struct {
 		arg1 context.Context
-		arg2 *v1alpha3.KindCluster
+		arg2 *v1beta1.KindCluster
 	}
 	removeFinalizerReturns struct {
 		result1 error
@@ -49,12 +90,12 @@ type FakeKindClusterClient struct {
 	removeFinalizerReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SetControlPlaneEndpointStub        func(context.Context, v1alpha3.APIEndpoint, *v1alpha3.KindCluster) error
+	SetControlPlaneEndpointStub        func(context.Context, v1beta1a.APIEndpoint, *v1beta1.KindCluster) error
 	setControlPlaneEndpointMutex       sync.RWMutex
 	setControlPlaneEndpointArgsForCall []struct {
 		arg1 context.Context
-		arg2 v1alpha3.APIEndpoint
-		arg3 *v1alpha3.KindCluster
+		arg2 v1beta1a.APIEndpoint
+		arg3 *v1beta1.KindCluster
 	}
 	setControlPlaneEndpointReturns struct {
 		result1 error
@@ -62,12 +103,38 @@ type FakeKindClusterClient struct {
 	setControlPlaneEndpointReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UpdateStatusStub        func(context.Context, v1alpha3.KindClusterStatus, *v1alpha3.KindCluster) error
+	SetNameStub        func(context.Context, string, *v1beta1.KindCluster) error
+	setNameMutex       sync.RWMutex
+	setNameArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindCluster
+	}
+	setNameReturns struct {
+		result1 error
+	}
+	setNameReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SuspendIdleStub        func(context.Context, string, *v1beta1.KindCluster) error
+	suspendIdleMutex       sync.RWMutex
+	suspendIdleArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindCluster
+	}
+	suspendIdleReturns struct {
+		result1 error
+	}
+	suspendIdleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStatusStub        func(context.Context, v1beta1.KindClusterStatus, *v1beta1.KindCluster) error
 	updateStatusMutex       sync.RWMutex
 	updateStatusArgsForCall []struct {
 		arg1 context.Context
-		arg2 v1alpha3.KindClusterStatus
-		arg3 *v1alpha3.KindCluster
+		arg2 v1beta1.KindClusterStatus
+		arg3 *v1beta1.KindCluster
 	}
 	updateStatusReturns struct {
 		result1 error
@@ -79,12 +146,12 @@ type FakeKindClusterClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeKindClusterClient) AddFinalizer(arg1 context.Context, arg2 *v1alpha3.KindCluster) error {
+func (fake *FakeKindClusterClient) AddFinalizer(arg1 context.Context, arg2 *v1beta1.KindCluster) error {
 	fake.addFinalizerMutex.Lock()
 	ret, specificReturn := fake.addFinalizerReturnsOnCall[len(fake.addFinalizerArgsForCall)]
 	fake.addFinalizerArgsForCall = append(fake.addFinalizerArgsForCall, struct {
 		arg1 context.Context
-		arg2 *v1alpha3.KindCluster
+		arg2 *v1beta1.KindCluster
 	}{arg1, arg2})
 	stub := fake.AddFinalizerStub
 	fakeReturns := fake.addFinalizerReturns
@@ -105,13 +172,13 @@ func (fake *FakeKindClusterClient) AddFinalizerCallCount() int {
 	return len(fake.addFinalizerArgsForCall)
 }
 
-func (fake *FakeKindClusterClient) AddFinalizerCalls(stub func(context.Context, *v1alpha3.KindCluster) error) {
+func (fake *FakeKindClusterClient) AddFinalizerCalls(stub func(context.Context, *v1beta1.KindCluster) error) {
 	fake.addFinalizerMutex.Lock()
 	defer fake.addFinalizerMutex.Unlock()
 	fake.AddFinalizerStub = stub
 }
 
-func (fake *FakeKindClusterClient) AddFinalizerArgsForCall(i int) (context.Context, *v1alpha3.KindCluster) {
+func (fake *FakeKindClusterClient) AddFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindCluster) {
 	fake.addFinalizerMutex.RLock()
 	defer fake.addFinalizerMutex.RUnlock()
 	argsForCall := fake.addFinalizerArgsForCall[i]
@@ -141,7 +208,132 @@ func (fake *FakeKindClusterClient) AddFinalizerReturnsOnCall(i int, result1 erro
 	}{result1}
 }
 
-func (fake *FakeKindClusterClient) Get(arg1 context.Context, arg2 types.NamespacedName) (*v1alpha3.KindCluster, error) {
+func (fake *FakeKindClusterClient) Delete(arg1 context.Context, arg2 *v1beta1.KindCluster) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindCluster
+	}{arg1, arg2})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterClient) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeKindClusterClient) DeleteCalls(stub func(context.Context, *v1beta1.KindCluster) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeKindClusterClient) DeleteArgsForCall(i int) (context.Context, *v1beta1.KindCluster) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterClient) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) ExtendLease(arg1 context.Context, arg2 v1.Time, arg3 *v1beta1.KindCluster) error {
+	fake.extendLeaseMutex.Lock()
+	ret, specificReturn := fake.extendLeaseReturnsOnCall[len(fake.extendLeaseArgsForCall)]
+	fake.extendLeaseArgsForCall = append(fake.extendLeaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 v1.Time
+		arg3 *v1beta1.KindCluster
+	}{arg1, arg2, arg3})
+	stub := fake.ExtendLeaseStub
+	fakeReturns := fake.extendLeaseReturns
+	fake.recordInvocation("ExtendLease", []interface{}{arg1, arg2, arg3})
+	fake.extendLeaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterClient) ExtendLeaseCallCount() int {
+	fake.extendLeaseMutex.RLock()
+	defer fake.extendLeaseMutex.RUnlock()
+	return len(fake.extendLeaseArgsForCall)
+}
+
+func (fake *FakeKindClusterClient) ExtendLeaseCalls(stub func(context.Context, v1.Time, *v1beta1.KindCluster) error) {
+	fake.extendLeaseMutex.Lock()
+	defer fake.extendLeaseMutex.Unlock()
+	fake.ExtendLeaseStub = stub
+}
+
+func (fake *FakeKindClusterClient) ExtendLeaseArgsForCall(i int) (context.Context, v1.Time, *v1beta1.KindCluster) {
+	fake.extendLeaseMutex.RLock()
+	defer fake.extendLeaseMutex.RUnlock()
+	argsForCall := fake.extendLeaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterClient) ExtendLeaseReturns(result1 error) {
+	fake.extendLeaseMutex.Lock()
+	defer fake.extendLeaseMutex.Unlock()
+	fake.ExtendLeaseStub = nil
+	fake.extendLeaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) ExtendLeaseReturnsOnCall(i int, result1 error) {
+	fake.extendLeaseMutex.Lock()
+	defer fake.extendLeaseMutex.Unlock()
+	fake.ExtendLeaseStub = nil
+	if fake.extendLeaseReturnsOnCall == nil {
+		fake.extendLeaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.extendLeaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) Get(arg1 context.Context, arg2 types.NamespacedName) (*v1beta1.KindCluster, error) {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
@@ -167,7 +359,7 @@ func (fake *FakeKindClusterClient) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakeKindClusterClient) GetCalls(stub func(context.Context, types.NamespacedName) (*v1alpha3.KindCluster, error)) {
+func (fake *FakeKindClusterClient) GetCalls(stub func(context.Context, types.NamespacedName) (*v1beta1.KindCluster, error)) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
@@ -180,38 +372,103 @@ func (fake *FakeKindClusterClient) GetArgsForCall(i int) (context.Context, types
 	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeKindClusterClient) GetReturns(result1 *v1alpha3.KindCluster, result2 error) {
+func (fake *FakeKindClusterClient) GetReturns(result1 *v1beta1.KindCluster, result2 error) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = nil
 	fake.getReturns = struct {
-		result1 *v1alpha3.KindCluster
+		result1 *v1beta1.KindCluster
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeKindClusterClient) GetReturnsOnCall(i int, result1 *v1alpha3.KindCluster, result2 error) {
+func (fake *FakeKindClusterClient) GetReturnsOnCall(i int, result1 *v1beta1.KindCluster, result2 error) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = nil
 	if fake.getReturnsOnCall == nil {
 		fake.getReturnsOnCall = make(map[int]struct {
-			result1 *v1alpha3.KindCluster
+			result1 *v1beta1.KindCluster
 			result2 error
 		})
 	}
 	fake.getReturnsOnCall[i] = struct {
-		result1 *v1alpha3.KindCluster
+		result1 *v1beta1.KindCluster
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeKindClusterClient) RemoveFinalizer(arg1 context.Context, arg2 *v1alpha3.KindCluster) error {
+func (fake *FakeKindClusterClient) ListByName(arg1 context.Context, arg2 string) ([]v1beta1.KindCluster, error) {
+	fake.listByNameMutex.Lock()
+	ret, specificReturn := fake.listByNameReturnsOnCall[len(fake.listByNameArgsForCall)]
+	fake.listByNameArgsForCall = append(fake.listByNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListByNameStub
+	fakeReturns := fake.listByNameReturns
+	fake.recordInvocation("ListByName", []interface{}{arg1, arg2})
+	fake.listByNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindClusterClient) ListByNameCallCount() int {
+	fake.listByNameMutex.RLock()
+	defer fake.listByNameMutex.RUnlock()
+	return len(fake.listByNameArgsForCall)
+}
+
+func (fake *FakeKindClusterClient) ListByNameCalls(stub func(context.Context, string) ([]v1beta1.KindCluster, error)) {
+	fake.listByNameMutex.Lock()
+	defer fake.listByNameMutex.Unlock()
+	fake.ListByNameStub = stub
+}
+
+func (fake *FakeKindClusterClient) ListByNameArgsForCall(i int) (context.Context, string) {
+	fake.listByNameMutex.RLock()
+	defer fake.listByNameMutex.RUnlock()
+	argsForCall := fake.listByNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterClient) ListByNameReturns(result1 []v1beta1.KindCluster, result2 error) {
+	fake.listByNameMutex.Lock()
+	defer fake.listByNameMutex.Unlock()
+	fake.ListByNameStub = nil
+	fake.listByNameReturns = struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterClient) ListByNameReturnsOnCall(i int, result1 []v1beta1.KindCluster, result2 error) {
+	fake.listByNameMutex.Lock()
+	defer fake.listByNameMutex.Unlock()
+	fake.ListByNameStub = nil
+	if fake.listByNameReturnsOnCall == nil {
+		fake.listByNameReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.KindCluster
+			result2 error
+		})
+	}
+	fake.listByNameReturnsOnCall[i] = struct {
+		result1 []v1beta1.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterClient) RemoveFinalizer(arg1 context.Context, arg2 *v1beta1.KindCluster) error {
 	fake.removeFinalizerMutex.Lock()
 	ret, specificReturn := fake.removeFinalizerReturnsOnCall[len(fake.removeFinalizerArgsForCall)]
 	fake.removeFinalizerArgsForCall = append(fake.removeFinalizerArgsForCall, struct {
 		arg1 context.Context
-		arg2 *v1alpha3.KindCluster
+		arg2 *v1beta1.KindCluster
 	}{arg1, arg2})
 	stub := fake.RemoveFinalizerStub
 	fakeReturns := fake.removeFinalizerReturns
@@ -232,13 +489,13 @@ func (fake *FakeKindClusterClient) RemoveFinalizerCallCount() int {
 	return len(fake.removeFinalizerArgsForCall)
 }
 
-func (fake *FakeKindClusterClient) RemoveFinalizerCalls(stub func(context.Context, *v1alpha3.KindCluster) error) {
+func (fake *FakeKindClusterClient) RemoveFinalizerCalls(stub func(context.Context, *v1beta1.KindCluster) error) {
 	fake.removeFinalizerMutex.Lock()
 	defer fake.removeFinalizerMutex.Unlock()
 	fake.RemoveFinalizerStub = stub
 }
 
-func (fake *FakeKindClusterClient) RemoveFinalizerArgsForCall(i int) (context.Context, *v1alpha3.KindCluster) {
+func (fake *FakeKindClusterClient) RemoveFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindCluster) {
 	fake.removeFinalizerMutex.RLock()
 	defer fake.removeFinalizerMutex.RUnlock()
 	argsForCall := fake.removeFinalizerArgsForCall[i]
@@ -268,13 +525,13 @@ func (fake *FakeKindClusterClient) RemoveFinalizerReturnsOnCall(i int, result1 e
 	}{result1}
 }
 
-func (fake *FakeKindClusterClient) SetControlPlaneEndpoint(arg1 context.Context, arg2 v1alpha3.APIEndpoint, arg3 *v1alpha3.KindCluster) error {
+func (fake *FakeKindClusterClient) SetControlPlaneEndpoint(arg1 context.Context, arg2 v1beta1a.APIEndpoint, arg3 *v1beta1.KindCluster) error {
 	fake.setControlPlaneEndpointMutex.Lock()
 	ret, specificReturn := fake.setControlPlaneEndpointReturnsOnCall[len(fake.setControlPlaneEndpointArgsForCall)]
 	fake.setControlPlaneEndpointArgsForCall = append(fake.setControlPlaneEndpointArgsForCall, struct {
 		arg1 context.Context
-		arg2 v1alpha3.APIEndpoint
-		arg3 *v1alpha3.KindCluster
+		arg2 v1beta1a.APIEndpoint
+		arg3 *v1beta1.KindCluster
 	}{arg1, arg2, arg3})
 	stub := fake.SetControlPlaneEndpointStub
 	fakeReturns := fake.setControlPlaneEndpointReturns
@@ -295,13 +552,13 @@ func (fake *FakeKindClusterClient) SetControlPlaneEndpointCallCount() int {
 	return len(fake.setControlPlaneEndpointArgsForCall)
 }
 
-func (fake *FakeKindClusterClient) SetControlPlaneEndpointCalls(stub func(context.Context, v1alpha3.APIEndpoint, *v1alpha3.KindCluster) error) {
+func (fake *FakeKindClusterClient) SetControlPlaneEndpointCalls(stub func(context.Context, v1beta1a.APIEndpoint, *v1beta1.KindCluster) error) {
 	fake.setControlPlaneEndpointMutex.Lock()
 	defer fake.setControlPlaneEndpointMutex.Unlock()
 	fake.SetControlPlaneEndpointStub = stub
 }
 
-func (fake *FakeKindClusterClient) SetControlPlaneEndpointArgsForCall(i int) (context.Context, v1alpha3.APIEndpoint, *v1alpha3.KindCluster) {
+func (fake *FakeKindClusterClient) SetControlPlaneEndpointArgsForCall(i int) (context.Context, v1beta1a.APIEndpoint, *v1beta1.KindCluster) {
 	fake.setControlPlaneEndpointMutex.RLock()
 	defer fake.setControlPlaneEndpointMutex.RUnlock()
 	argsForCall := fake.setControlPlaneEndpointArgsForCall[i]
@@ -331,13 +588,139 @@ func (fake *FakeKindClusterClient) SetControlPlaneEndpointReturnsOnCall(i int, r
 	}{result1}
 }
 
-func (fake *FakeKindClusterClient) UpdateStatus(arg1 context.Context, arg2 v1alpha3.KindClusterStatus, arg3 *v1alpha3.KindCluster) error {
+func (fake *FakeKindClusterClient) SetName(arg1 context.Context, arg2 string, arg3 *v1beta1.KindCluster) error {
+	fake.setNameMutex.Lock()
+	ret, specificReturn := fake.setNameReturnsOnCall[len(fake.setNameArgsForCall)]
+	fake.setNameArgsForCall = append(fake.setNameArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindCluster
+	}{arg1, arg2, arg3})
+	stub := fake.SetNameStub
+	fakeReturns := fake.setNameReturns
+	fake.recordInvocation("SetName", []interface{}{arg1, arg2, arg3})
+	fake.setNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterClient) SetNameCallCount() int {
+	fake.setNameMutex.RLock()
+	defer fake.setNameMutex.RUnlock()
+	return len(fake.setNameArgsForCall)
+}
+
+func (fake *FakeKindClusterClient) SetNameCalls(stub func(context.Context, string, *v1beta1.KindCluster) error) {
+	fake.setNameMutex.Lock()
+	defer fake.setNameMutex.Unlock()
+	fake.SetNameStub = stub
+}
+
+func (fake *FakeKindClusterClient) SetNameArgsForCall(i int) (context.Context, string, *v1beta1.KindCluster) {
+	fake.setNameMutex.RLock()
+	defer fake.setNameMutex.RUnlock()
+	argsForCall := fake.setNameArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterClient) SetNameReturns(result1 error) {
+	fake.setNameMutex.Lock()
+	defer fake.setNameMutex.Unlock()
+	fake.SetNameStub = nil
+	fake.setNameReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) SetNameReturnsOnCall(i int, result1 error) {
+	fake.setNameMutex.Lock()
+	defer fake.setNameMutex.Unlock()
+	fake.SetNameStub = nil
+	if fake.setNameReturnsOnCall == nil {
+		fake.setNameReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setNameReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) SuspendIdle(arg1 context.Context, arg2 string, arg3 *v1beta1.KindCluster) error {
+	fake.suspendIdleMutex.Lock()
+	ret, specificReturn := fake.suspendIdleReturnsOnCall[len(fake.suspendIdleArgsForCall)]
+	fake.suspendIdleArgsForCall = append(fake.suspendIdleArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindCluster
+	}{arg1, arg2, arg3})
+	stub := fake.SuspendIdleStub
+	fakeReturns := fake.suspendIdleReturns
+	fake.recordInvocation("SuspendIdle", []interface{}{arg1, arg2, arg3})
+	fake.suspendIdleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterClient) SuspendIdleCallCount() int {
+	fake.suspendIdleMutex.RLock()
+	defer fake.suspendIdleMutex.RUnlock()
+	return len(fake.suspendIdleArgsForCall)
+}
+
+func (fake *FakeKindClusterClient) SuspendIdleCalls(stub func(context.Context, string, *v1beta1.KindCluster) error) {
+	fake.suspendIdleMutex.Lock()
+	defer fake.suspendIdleMutex.Unlock()
+	fake.SuspendIdleStub = stub
+}
+
+func (fake *FakeKindClusterClient) SuspendIdleArgsForCall(i int) (context.Context, string, *v1beta1.KindCluster) {
+	fake.suspendIdleMutex.RLock()
+	defer fake.suspendIdleMutex.RUnlock()
+	argsForCall := fake.suspendIdleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterClient) SuspendIdleReturns(result1 error) {
+	fake.suspendIdleMutex.Lock()
+	defer fake.suspendIdleMutex.Unlock()
+	fake.SuspendIdleStub = nil
+	fake.suspendIdleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) SuspendIdleReturnsOnCall(i int, result1 error) {
+	fake.suspendIdleMutex.Lock()
+	defer fake.suspendIdleMutex.Unlock()
+	fake.SuspendIdleStub = nil
+	if fake.suspendIdleReturnsOnCall == nil {
+		fake.suspendIdleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.suspendIdleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterClient) UpdateStatus(arg1 context.Context, arg2 v1beta1.KindClusterStatus, arg3 *v1beta1.KindCluster) error {
 	fake.updateStatusMutex.Lock()
 	ret, specificReturn := fake.updateStatusReturnsOnCall[len(fake.updateStatusArgsForCall)]
 	fake.updateStatusArgsForCall = append(fake.updateStatusArgsForCall, struct {
 		arg1 context.Context
-		arg2 v1alpha3.KindClusterStatus
-		arg3 *v1alpha3.KindCluster
+		arg2 v1beta1.KindClusterStatus
+		arg3 *v1beta1.KindCluster
 	}{arg1, arg2, arg3})
 	stub := fake.UpdateStatusStub
 	fakeReturns := fake.updateStatusReturns
@@ -358,13 +741,13 @@ func (fake *FakeKindClusterClient) UpdateStatusCallCount() int {
 	return len(fake.updateStatusArgsForCall)
 }
 
-func (fake *FakeKindClusterClient) UpdateStatusCalls(stub func(context.Context, v1alpha3.KindClusterStatus, *v1alpha3.KindCluster) error) {
+func (fake *FakeKindClusterClient) UpdateStatusCalls(stub func(context.Context, v1beta1.KindClusterStatus, *v1beta1.KindCluster) error) {
 	fake.updateStatusMutex.Lock()
 	defer fake.updateStatusMutex.Unlock()
 	fake.UpdateStatusStub = stub
 }
 
-func (fake *FakeKindClusterClient) UpdateStatusArgsForCall(i int) (context.Context, v1alpha3.KindClusterStatus, *v1alpha3.KindCluster) {
+func (fake *FakeKindClusterClient) UpdateStatusArgsForCall(i int) (context.Context, v1beta1.KindClusterStatus, *v1beta1.KindCluster) {
 	fake.updateStatusMutex.RLock()
 	defer fake.updateStatusMutex.RUnlock()
 	argsForCall := fake.updateStatusArgsForCall[i]
@@ -399,12 +782,22 @@ func (fake *FakeKindClusterClient) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.addFinalizerMutex.RLock()
 	defer fake.addFinalizerMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.extendLeaseMutex.RLock()
+	defer fake.extendLeaseMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.listByNameMutex.RLock()
+	defer fake.listByNameMutex.RUnlock()
 	fake.removeFinalizerMutex.RLock()
 	defer fake.removeFinalizerMutex.RUnlock()
 	fake.setControlPlaneEndpointMutex.RLock()
 	defer fake.setControlPlaneEndpointMutex.RUnlock()
+	fake.setNameMutex.RLock()
+	defer fake.setNameMutex.RUnlock()
+	fake.suspendIdleMutex.RLock()
+	defer fake.suspendIdleMutex.RUnlock()
 	fake.updateStatusMutex.RLock()
 	defer fake.updateStatusMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,509 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type FakeKindClusterPoolClient struct {
+	AddFinalizerStub        func(context.Context, *v1beta1.KindClusterPool) error
+	addFinalizerMutex       sync.RWMutex
+	addFinalizerArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterPool
+	}
+	addFinalizerReturns struct {
+		result1 error
+	}
+	addFinalizerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ClaimStub        func(context.Context, string, *v1beta1.KindCluster, *v1beta1.KindClusterPool) error
+	claimMutex       sync.RWMutex
+	claimArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindCluster
+		arg4 *v1beta1.KindClusterPool
+	}
+	claimReturns struct {
+		result1 error
+	}
+	claimReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStub        func(context.Context, types.NamespacedName) (*v1beta1.KindClusterPool, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}
+	getReturns struct {
+		result1 *v1beta1.KindClusterPool
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 *v1beta1.KindClusterPool
+		result2 error
+	}
+	MarkDeletingStub        func(context.Context, string, *v1beta1.KindClusterPool) error
+	markDeletingMutex       sync.RWMutex
+	markDeletingArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindClusterPool
+	}
+	markDeletingReturns struct {
+		result1 error
+	}
+	markDeletingReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RemoveFinalizerStub        func(context.Context, *v1beta1.KindClusterPool) error
+	removeFinalizerMutex       sync.RWMutex
+	removeFinalizerArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterPool
+	}
+	removeFinalizerReturns struct {
+		result1 error
+	}
+	removeFinalizerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStatusStub        func(context.Context, v1beta1.KindClusterPoolStatus, *v1beta1.KindClusterPool) error
+	updateStatusMutex       sync.RWMutex
+	updateStatusArgsForCall []struct {
+		arg1 context.Context
+		arg2 v1beta1.KindClusterPoolStatus
+		arg3 *v1beta1.KindClusterPool
+	}
+	updateStatusReturns struct {
+		result1 error
+	}
+	updateStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKindClusterPoolClient) AddFinalizer(arg1 context.Context, arg2 *v1beta1.KindClusterPool) error {
+	fake.addFinalizerMutex.Lock()
+	ret, specificReturn := fake.addFinalizerReturnsOnCall[len(fake.addFinalizerArgsForCall)]
+	fake.addFinalizerArgsForCall = append(fake.addFinalizerArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterPool
+	}{arg1, arg2})
+	stub := fake.AddFinalizerStub
+	fakeReturns := fake.addFinalizerReturns
+	fake.recordInvocation("AddFinalizer", []interface{}{arg1, arg2})
+	fake.addFinalizerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterPoolClient) AddFinalizerCallCount() int {
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	return len(fake.addFinalizerArgsForCall)
+}
+
+func (fake *FakeKindClusterPoolClient) AddFinalizerCalls(stub func(context.Context, *v1beta1.KindClusterPool) error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = stub
+}
+
+func (fake *FakeKindClusterPoolClient) AddFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindClusterPool) {
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	argsForCall := fake.addFinalizerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterPoolClient) AddFinalizerReturns(result1 error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = nil
+	fake.addFinalizerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) AddFinalizerReturnsOnCall(i int, result1 error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = nil
+	if fake.addFinalizerReturnsOnCall == nil {
+		fake.addFinalizerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addFinalizerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) Claim(arg1 context.Context, arg2 string, arg3 *v1beta1.KindCluster, arg4 *v1beta1.KindClusterPool) error {
+	fake.claimMutex.Lock()
+	ret, specificReturn := fake.claimReturnsOnCall[len(fake.claimArgsForCall)]
+	fake.claimArgsForCall = append(fake.claimArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindCluster
+		arg4 *v1beta1.KindClusterPool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ClaimStub
+	fakeReturns := fake.claimReturns
+	fake.recordInvocation("Claim", []interface{}{arg1, arg2, arg3, arg4})
+	fake.claimMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterPoolClient) ClaimCallCount() int {
+	fake.claimMutex.RLock()
+	defer fake.claimMutex.RUnlock()
+	return len(fake.claimArgsForCall)
+}
+
+func (fake *FakeKindClusterPoolClient) ClaimCalls(stub func(context.Context, string, *v1beta1.KindCluster, *v1beta1.KindClusterPool) error) {
+	fake.claimMutex.Lock()
+	defer fake.claimMutex.Unlock()
+	fake.ClaimStub = stub
+}
+
+func (fake *FakeKindClusterPoolClient) ClaimArgsForCall(i int) (context.Context, string, *v1beta1.KindCluster, *v1beta1.KindClusterPool) {
+	fake.claimMutex.RLock()
+	defer fake.claimMutex.RUnlock()
+	argsForCall := fake.claimArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeKindClusterPoolClient) ClaimReturns(result1 error) {
+	fake.claimMutex.Lock()
+	defer fake.claimMutex.Unlock()
+	fake.ClaimStub = nil
+	fake.claimReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) ClaimReturnsOnCall(i int, result1 error) {
+	fake.claimMutex.Lock()
+	defer fake.claimMutex.Unlock()
+	fake.ClaimStub = nil
+	if fake.claimReturnsOnCall == nil {
+		fake.claimReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.claimReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) Get(arg1 context.Context, arg2 types.NamespacedName) (*v1beta1.KindClusterPool, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}{arg1, arg2})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindClusterPoolClient) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeKindClusterPoolClient) GetCalls(stub func(context.Context, types.NamespacedName) (*v1beta1.KindClusterPool, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeKindClusterPoolClient) GetArgsForCall(i int) (context.Context, types.NamespacedName) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterPoolClient) GetReturns(result1 *v1beta1.KindClusterPool, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *v1beta1.KindClusterPool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterPoolClient) GetReturnsOnCall(i int, result1 *v1beta1.KindClusterPool, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.KindClusterPool
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 *v1beta1.KindClusterPool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindClusterPoolClient) MarkDeleting(arg1 context.Context, arg2 string, arg3 *v1beta1.KindClusterPool) error {
+	fake.markDeletingMutex.Lock()
+	ret, specificReturn := fake.markDeletingReturnsOnCall[len(fake.markDeletingArgsForCall)]
+	fake.markDeletingArgsForCall = append(fake.markDeletingArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 *v1beta1.KindClusterPool
+	}{arg1, arg2, arg3})
+	stub := fake.MarkDeletingStub
+	fakeReturns := fake.markDeletingReturns
+	fake.recordInvocation("MarkDeleting", []interface{}{arg1, arg2, arg3})
+	fake.markDeletingMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterPoolClient) MarkDeletingCallCount() int {
+	fake.markDeletingMutex.RLock()
+	defer fake.markDeletingMutex.RUnlock()
+	return len(fake.markDeletingArgsForCall)
+}
+
+func (fake *FakeKindClusterPoolClient) MarkDeletingCalls(stub func(context.Context, string, *v1beta1.KindClusterPool) error) {
+	fake.markDeletingMutex.Lock()
+	defer fake.markDeletingMutex.Unlock()
+	fake.MarkDeletingStub = stub
+}
+
+func (fake *FakeKindClusterPoolClient) MarkDeletingArgsForCall(i int) (context.Context, string, *v1beta1.KindClusterPool) {
+	fake.markDeletingMutex.RLock()
+	defer fake.markDeletingMutex.RUnlock()
+	argsForCall := fake.markDeletingArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterPoolClient) MarkDeletingReturns(result1 error) {
+	fake.markDeletingMutex.Lock()
+	defer fake.markDeletingMutex.Unlock()
+	fake.MarkDeletingStub = nil
+	fake.markDeletingReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) MarkDeletingReturnsOnCall(i int, result1 error) {
+	fake.markDeletingMutex.Lock()
+	defer fake.markDeletingMutex.Unlock()
+	fake.MarkDeletingStub = nil
+	if fake.markDeletingReturnsOnCall == nil {
+		fake.markDeletingReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.markDeletingReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) RemoveFinalizer(arg1 context.Context, arg2 *v1beta1.KindClusterPool) error {
+	fake.removeFinalizerMutex.Lock()
+	ret, specificReturn := fake.removeFinalizerReturnsOnCall[len(fake.removeFinalizerArgsForCall)]
+	fake.removeFinalizerArgsForCall = append(fake.removeFinalizerArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindClusterPool
+	}{arg1, arg2})
+	stub := fake.RemoveFinalizerStub
+	fakeReturns := fake.removeFinalizerReturns
+	fake.recordInvocation("RemoveFinalizer", []interface{}{arg1, arg2})
+	fake.removeFinalizerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterPoolClient) RemoveFinalizerCallCount() int {
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	return len(fake.removeFinalizerArgsForCall)
+}
+
+func (fake *FakeKindClusterPoolClient) RemoveFinalizerCalls(stub func(context.Context, *v1beta1.KindClusterPool) error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = stub
+}
+
+func (fake *FakeKindClusterPoolClient) RemoveFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindClusterPool) {
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	argsForCall := fake.removeFinalizerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindClusterPoolClient) RemoveFinalizerReturns(result1 error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = nil
+	fake.removeFinalizerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) RemoveFinalizerReturnsOnCall(i int, result1 error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = nil
+	if fake.removeFinalizerReturnsOnCall == nil {
+		fake.removeFinalizerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeFinalizerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) UpdateStatus(arg1 context.Context, arg2 v1beta1.KindClusterPoolStatus, arg3 *v1beta1.KindClusterPool) error {
+	fake.updateStatusMutex.Lock()
+	ret, specificReturn := fake.updateStatusReturnsOnCall[len(fake.updateStatusArgsForCall)]
+	fake.updateStatusArgsForCall = append(fake.updateStatusArgsForCall, struct {
+		arg1 context.Context
+		arg2 v1beta1.KindClusterPoolStatus
+		arg3 *v1beta1.KindClusterPool
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStatusStub
+	fakeReturns := fake.updateStatusReturns
+	fake.recordInvocation("UpdateStatus", []interface{}{arg1, arg2, arg3})
+	fake.updateStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindClusterPoolClient) UpdateStatusCallCount() int {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	return len(fake.updateStatusArgsForCall)
+}
+
+func (fake *FakeKindClusterPoolClient) UpdateStatusCalls(stub func(context.Context, v1beta1.KindClusterPoolStatus, *v1beta1.KindClusterPool) error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = stub
+}
+
+func (fake *FakeKindClusterPoolClient) UpdateStatusArgsForCall(i int) (context.Context, v1beta1.KindClusterPoolStatus, *v1beta1.KindClusterPool) {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	argsForCall := fake.updateStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindClusterPoolClient) UpdateStatusReturns(result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	fake.updateStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) UpdateStatusReturnsOnCall(i int, result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	if fake.updateStatusReturnsOnCall == nil {
+		fake.updateStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindClusterPoolClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	fake.claimMutex.RLock()
+	defer fake.claimMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.markDeletingMutex.RLock()
+	defer fake.markDeletingMutex.RUnlock()
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKindClusterPoolClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.KindClusterPoolClient = new(FakeKindClusterPoolClient)
@@ -0,0 +1,199 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type FakeKindControlPlaneClient struct {
+	GetStub        func(context.Context, types.NamespacedName) (*v1beta1.KindControlPlane, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}
+	getReturns struct {
+		result1 *v1beta1.KindControlPlane
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 *v1beta1.KindControlPlane
+		result2 error
+	}
+	UpdateStatusStub        func(context.Context, v1beta1.KindControlPlaneStatus, *v1beta1.KindControlPlane) error
+	updateStatusMutex       sync.RWMutex
+	updateStatusArgsForCall []struct {
+		arg1 context.Context
+		arg2 v1beta1.KindControlPlaneStatus
+		arg3 *v1beta1.KindControlPlane
+	}
+	updateStatusReturns struct {
+		result1 error
+	}
+	updateStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKindControlPlaneClient) Get(arg1 context.Context, arg2 types.NamespacedName) (*v1beta1.KindControlPlane, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}{arg1, arg2})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindControlPlaneClient) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeKindControlPlaneClient) GetCalls(stub func(context.Context, types.NamespacedName) (*v1beta1.KindControlPlane, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeKindControlPlaneClient) GetArgsForCall(i int) (context.Context, types.NamespacedName) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindControlPlaneClient) GetReturns(result1 *v1beta1.KindControlPlane, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *v1beta1.KindControlPlane
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindControlPlaneClient) GetReturnsOnCall(i int, result1 *v1beta1.KindControlPlane, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.KindControlPlane
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 *v1beta1.KindControlPlane
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindControlPlaneClient) UpdateStatus(arg1 context.Context, arg2 v1beta1.KindControlPlaneStatus, arg3 *v1beta1.KindControlPlane) error {
+	fake.updateStatusMutex.Lock()
+	ret, specificReturn := fake.updateStatusReturnsOnCall[len(fake.updateStatusArgsForCall)]
+	fake.updateStatusArgsForCall = append(fake.updateStatusArgsForCall, struct {
+		arg1 context.Context
+		arg2 v1beta1.KindControlPlaneStatus
+		arg3 *v1beta1.KindControlPlane
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStatusStub
+	fakeReturns := fake.updateStatusReturns
+	fake.recordInvocation("UpdateStatus", []interface{}{arg1, arg2, arg3})
+	fake.updateStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindControlPlaneClient) UpdateStatusCallCount() int {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	return len(fake.updateStatusArgsForCall)
+}
+
+func (fake *FakeKindControlPlaneClient) UpdateStatusCalls(stub func(context.Context, v1beta1.KindControlPlaneStatus, *v1beta1.KindControlPlane) error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = stub
+}
+
+func (fake *FakeKindControlPlaneClient) UpdateStatusArgsForCall(i int) (context.Context, v1beta1.KindControlPlaneStatus, *v1beta1.KindControlPlane) {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	argsForCall := fake.updateStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindControlPlaneClient) UpdateStatusReturns(result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	fake.updateStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindControlPlaneClient) UpdateStatusReturnsOnCall(i int, result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	if fake.updateStatusReturnsOnCall == nil {
+		fake.updateStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindControlPlaneClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKindControlPlaneClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.KindControlPlaneClient = new(FakeKindControlPlaneClient)
@@ -0,0 +1,434 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type FakeKindMachinePoolClient struct {
+	AddFinalizerStub        func(context.Context, *v1beta1.KindMachinePool) error
+	addFinalizerMutex       sync.RWMutex
+	addFinalizerArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindMachinePool
+	}
+	addFinalizerReturns struct {
+		result1 error
+	}
+	addFinalizerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetStub        func(context.Context, types.NamespacedName) (*v1beta1.KindMachinePool, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}
+	getReturns struct {
+		result1 *v1beta1.KindMachinePool
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 *v1beta1.KindMachinePool
+		result2 error
+	}
+	RemoveFinalizerStub        func(context.Context, *v1beta1.KindMachinePool) error
+	removeFinalizerMutex       sync.RWMutex
+	removeFinalizerArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindMachinePool
+	}
+	removeFinalizerReturns struct {
+		result1 error
+	}
+	removeFinalizerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetProviderIDListStub        func(context.Context, []string, *v1beta1.KindMachinePool) error
+	setProviderIDListMutex       sync.RWMutex
+	setProviderIDListArgsForCall []struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 *v1beta1.KindMachinePool
+	}
+	setProviderIDListReturns struct {
+		result1 error
+	}
+	setProviderIDListReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UpdateStatusStub        func(context.Context, v1beta1.KindMachinePoolStatus, *v1beta1.KindMachinePool) error
+	updateStatusMutex       sync.RWMutex
+	updateStatusArgsForCall []struct {
+		arg1 context.Context
+		arg2 v1beta1.KindMachinePoolStatus
+		arg3 *v1beta1.KindMachinePool
+	}
+	updateStatusReturns struct {
+		result1 error
+	}
+	updateStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKindMachinePoolClient) AddFinalizer(arg1 context.Context, arg2 *v1beta1.KindMachinePool) error {
+	fake.addFinalizerMutex.Lock()
+	ret, specificReturn := fake.addFinalizerReturnsOnCall[len(fake.addFinalizerArgsForCall)]
+	fake.addFinalizerArgsForCall = append(fake.addFinalizerArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindMachinePool
+	}{arg1, arg2})
+	stub := fake.AddFinalizerStub
+	fakeReturns := fake.addFinalizerReturns
+	fake.recordInvocation("AddFinalizer", []interface{}{arg1, arg2})
+	fake.addFinalizerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindMachinePoolClient) AddFinalizerCallCount() int {
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	return len(fake.addFinalizerArgsForCall)
+}
+
+func (fake *FakeKindMachinePoolClient) AddFinalizerCalls(stub func(context.Context, *v1beta1.KindMachinePool) error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = stub
+}
+
+func (fake *FakeKindMachinePoolClient) AddFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindMachinePool) {
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	argsForCall := fake.addFinalizerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindMachinePoolClient) AddFinalizerReturns(result1 error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = nil
+	fake.addFinalizerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) AddFinalizerReturnsOnCall(i int, result1 error) {
+	fake.addFinalizerMutex.Lock()
+	defer fake.addFinalizerMutex.Unlock()
+	fake.AddFinalizerStub = nil
+	if fake.addFinalizerReturnsOnCall == nil {
+		fake.addFinalizerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addFinalizerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) Get(arg1 context.Context, arg2 types.NamespacedName) (*v1beta1.KindMachinePool, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 types.NamespacedName
+	}{arg1, arg2})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{arg1, arg2})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeKindMachinePoolClient) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeKindMachinePoolClient) GetCalls(stub func(context.Context, types.NamespacedName) (*v1beta1.KindMachinePool, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeKindMachinePoolClient) GetArgsForCall(i int) (context.Context, types.NamespacedName) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindMachinePoolClient) GetReturns(result1 *v1beta1.KindMachinePool, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 *v1beta1.KindMachinePool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindMachinePoolClient) GetReturnsOnCall(i int, result1 *v1beta1.KindMachinePool, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.KindMachinePool
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 *v1beta1.KindMachinePool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeKindMachinePoolClient) RemoveFinalizer(arg1 context.Context, arg2 *v1beta1.KindMachinePool) error {
+	fake.removeFinalizerMutex.Lock()
+	ret, specificReturn := fake.removeFinalizerReturnsOnCall[len(fake.removeFinalizerArgsForCall)]
+	fake.removeFinalizerArgsForCall = append(fake.removeFinalizerArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.KindMachinePool
+	}{arg1, arg2})
+	stub := fake.RemoveFinalizerStub
+	fakeReturns := fake.removeFinalizerReturns
+	fake.recordInvocation("RemoveFinalizer", []interface{}{arg1, arg2})
+	fake.removeFinalizerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindMachinePoolClient) RemoveFinalizerCallCount() int {
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	return len(fake.removeFinalizerArgsForCall)
+}
+
+func (fake *FakeKindMachinePoolClient) RemoveFinalizerCalls(stub func(context.Context, *v1beta1.KindMachinePool) error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = stub
+}
+
+func (fake *FakeKindMachinePoolClient) RemoveFinalizerArgsForCall(i int) (context.Context, *v1beta1.KindMachinePool) {
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	argsForCall := fake.removeFinalizerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeKindMachinePoolClient) RemoveFinalizerReturns(result1 error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = nil
+	fake.removeFinalizerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) RemoveFinalizerReturnsOnCall(i int, result1 error) {
+	fake.removeFinalizerMutex.Lock()
+	defer fake.removeFinalizerMutex.Unlock()
+	fake.RemoveFinalizerStub = nil
+	if fake.removeFinalizerReturnsOnCall == nil {
+		fake.removeFinalizerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeFinalizerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) SetProviderIDList(arg1 context.Context, arg2 []string, arg3 *v1beta1.KindMachinePool) error {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.setProviderIDListMutex.Lock()
+	ret, specificReturn := fake.setProviderIDListReturnsOnCall[len(fake.setProviderIDListArgsForCall)]
+	fake.setProviderIDListArgsForCall = append(fake.setProviderIDListArgsForCall, struct {
+		arg1 context.Context
+		arg2 []string
+		arg3 *v1beta1.KindMachinePool
+	}{arg1, arg2Copy, arg3})
+	stub := fake.SetProviderIDListStub
+	fakeReturns := fake.setProviderIDListReturns
+	fake.recordInvocation("SetProviderIDList", []interface{}{arg1, arg2Copy, arg3})
+	fake.setProviderIDListMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindMachinePoolClient) SetProviderIDListCallCount() int {
+	fake.setProviderIDListMutex.RLock()
+	defer fake.setProviderIDListMutex.RUnlock()
+	return len(fake.setProviderIDListArgsForCall)
+}
+
+func (fake *FakeKindMachinePoolClient) SetProviderIDListCalls(stub func(context.Context, []string, *v1beta1.KindMachinePool) error) {
+	fake.setProviderIDListMutex.Lock()
+	defer fake.setProviderIDListMutex.Unlock()
+	fake.SetProviderIDListStub = stub
+}
+
+func (fake *FakeKindMachinePoolClient) SetProviderIDListArgsForCall(i int) (context.Context, []string, *v1beta1.KindMachinePool) {
+	fake.setProviderIDListMutex.RLock()
+	defer fake.setProviderIDListMutex.RUnlock()
+	argsForCall := fake.setProviderIDListArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindMachinePoolClient) SetProviderIDListReturns(result1 error) {
+	fake.setProviderIDListMutex.Lock()
+	defer fake.setProviderIDListMutex.Unlock()
+	fake.SetProviderIDListStub = nil
+	fake.setProviderIDListReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) SetProviderIDListReturnsOnCall(i int, result1 error) {
+	fake.setProviderIDListMutex.Lock()
+	defer fake.setProviderIDListMutex.Unlock()
+	fake.SetProviderIDListStub = nil
+	if fake.setProviderIDListReturnsOnCall == nil {
+		fake.setProviderIDListReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setProviderIDListReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) UpdateStatus(arg1 context.Context, arg2 v1beta1.KindMachinePoolStatus, arg3 *v1beta1.KindMachinePool) error {
+	fake.updateStatusMutex.Lock()
+	ret, specificReturn := fake.updateStatusReturnsOnCall[len(fake.updateStatusArgsForCall)]
+	fake.updateStatusArgsForCall = append(fake.updateStatusArgsForCall, struct {
+		arg1 context.Context
+		arg2 v1beta1.KindMachinePoolStatus
+		arg3 *v1beta1.KindMachinePool
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStatusStub
+	fakeReturns := fake.updateStatusReturns
+	fake.recordInvocation("UpdateStatus", []interface{}{arg1, arg2, arg3})
+	fake.updateStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKindMachinePoolClient) UpdateStatusCallCount() int {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	return len(fake.updateStatusArgsForCall)
+}
+
+func (fake *FakeKindMachinePoolClient) UpdateStatusCalls(stub func(context.Context, v1beta1.KindMachinePoolStatus, *v1beta1.KindMachinePool) error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = stub
+}
+
+func (fake *FakeKindMachinePoolClient) UpdateStatusArgsForCall(i int) (context.Context, v1beta1.KindMachinePoolStatus, *v1beta1.KindMachinePool) {
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	argsForCall := fake.updateStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKindMachinePoolClient) UpdateStatusReturns(result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	fake.updateStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) UpdateStatusReturnsOnCall(i int, result1 error) {
+	fake.updateStatusMutex.Lock()
+	defer fake.updateStatusMutex.Unlock()
+	fake.UpdateStatusStub = nil
+	if fake.updateStatusReturnsOnCall == nil {
+		fake.updateStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKindMachinePoolClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addFinalizerMutex.RLock()
+	defer fake.addFinalizerMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.removeFinalizerMutex.RLock()
+	defer fake.removeFinalizerMutex.RUnlock()
+	fake.setProviderIDListMutex.RLock()
+	defer fake.setProviderIDListMutex.RUnlock()
+	fake.updateStatusMutex.RLock()
+	defer fake.updateStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKindMachinePoolClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.KindMachinePoolClient = new(FakeKindMachinePoolClient)
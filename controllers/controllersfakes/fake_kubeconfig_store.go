@@ -0,0 +1,122 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+type FakeKubeconfigStore struct {
+	StoreStub        func(context.Context, *v1beta1.Cluster, []byte) error
+	storeMutex       sync.RWMutex
+	storeArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+		arg3 []byte
+	}
+	storeReturns struct {
+		result1 error
+	}
+	storeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeKubeconfigStore) Store(arg1 context.Context, arg2 *v1beta1.Cluster, arg3 []byte) error {
+	var arg3Copy []byte
+	if arg3 != nil {
+		arg3Copy = make([]byte, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.storeMutex.Lock()
+	ret, specificReturn := fake.storeReturnsOnCall[len(fake.storeArgsForCall)]
+	fake.storeArgsForCall = append(fake.storeArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+		arg3 []byte
+	}{arg1, arg2, arg3Copy})
+	stub := fake.StoreStub
+	fakeReturns := fake.storeReturns
+	fake.recordInvocation("Store", []interface{}{arg1, arg2, arg3Copy})
+	fake.storeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeKubeconfigStore) StoreCallCount() int {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	return len(fake.storeArgsForCall)
+}
+
+func (fake *FakeKubeconfigStore) StoreCalls(stub func(context.Context, *v1beta1.Cluster, []byte) error) {
+	fake.storeMutex.Lock()
+	defer fake.storeMutex.Unlock()
+	fake.StoreStub = stub
+}
+
+func (fake *FakeKubeconfigStore) StoreArgsForCall(i int) (context.Context, *v1beta1.Cluster, []byte) {
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	argsForCall := fake.storeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeKubeconfigStore) StoreReturns(result1 error) {
+	fake.storeMutex.Lock()
+	defer fake.storeMutex.Unlock()
+	fake.StoreStub = nil
+	fake.storeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKubeconfigStore) StoreReturnsOnCall(i int, result1 error) {
+	fake.storeMutex.Lock()
+	defer fake.storeMutex.Unlock()
+	fake.StoreStub = nil
+	if fake.storeReturnsOnCall == nil {
+		fake.storeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeKubeconfigStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.storeMutex.RLock()
+	defer fake.storeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeKubeconfigStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.KubeconfigStore = new(FakeKubeconfigStore)
@@ -0,0 +1,284 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"context"
+	"sync"
+
+	v1beta1b "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"sigs.k8s.io/cluster-api/api/v1beta1"
+	v1beta1a "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+type FakeMachinePoolClusterClient struct {
+	GetForMachinePoolStub        func(context.Context, *v1beta1a.MachinePool) (*v1beta1.Cluster, error)
+	getForMachinePoolMutex       sync.RWMutex
+	getForMachinePoolArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1a.MachinePool
+	}
+	getForMachinePoolReturns struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}
+	getForMachinePoolReturnsOnCall map[int]struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}
+	GetKindClusterStub        func(context.Context, *v1beta1.Cluster) (*v1beta1b.KindCluster, error)
+	getKindClusterMutex       sync.RWMutex
+	getKindClusterArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}
+	getKindClusterReturns struct {
+		result1 *v1beta1b.KindCluster
+		result2 error
+	}
+	getKindClusterReturnsOnCall map[int]struct {
+		result1 *v1beta1b.KindCluster
+		result2 error
+	}
+	GetMachinePoolStub        func(context.Context, *v1beta1b.KindMachinePool) (*v1beta1a.MachinePool, error)
+	getMachinePoolMutex       sync.RWMutex
+	getMachinePoolArgsForCall []struct {
+		arg1 context.Context
+		arg2 *v1beta1b.KindMachinePool
+	}
+	getMachinePoolReturns struct {
+		result1 *v1beta1a.MachinePool
+		result2 error
+	}
+	getMachinePoolReturnsOnCall map[int]struct {
+		result1 *v1beta1a.MachinePool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeMachinePoolClusterClient) GetForMachinePool(arg1 context.Context, arg2 *v1beta1a.MachinePool) (*v1beta1.Cluster, error) {
+	fake.getForMachinePoolMutex.Lock()
+	ret, specificReturn := fake.getForMachinePoolReturnsOnCall[len(fake.getForMachinePoolArgsForCall)]
+	fake.getForMachinePoolArgsForCall = append(fake.getForMachinePoolArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1a.MachinePool
+	}{arg1, arg2})
+	stub := fake.GetForMachinePoolStub
+	fakeReturns := fake.getForMachinePoolReturns
+	fake.recordInvocation("GetForMachinePool", []interface{}{arg1, arg2})
+	fake.getForMachinePoolMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMachinePoolClusterClient) GetForMachinePoolCallCount() int {
+	fake.getForMachinePoolMutex.RLock()
+	defer fake.getForMachinePoolMutex.RUnlock()
+	return len(fake.getForMachinePoolArgsForCall)
+}
+
+func (fake *FakeMachinePoolClusterClient) GetForMachinePoolCalls(stub func(context.Context, *v1beta1a.MachinePool) (*v1beta1.Cluster, error)) {
+	fake.getForMachinePoolMutex.Lock()
+	defer fake.getForMachinePoolMutex.Unlock()
+	fake.GetForMachinePoolStub = stub
+}
+
+func (fake *FakeMachinePoolClusterClient) GetForMachinePoolArgsForCall(i int) (context.Context, *v1beta1a.MachinePool) {
+	fake.getForMachinePoolMutex.RLock()
+	defer fake.getForMachinePoolMutex.RUnlock()
+	argsForCall := fake.getForMachinePoolArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMachinePoolClusterClient) GetForMachinePoolReturns(result1 *v1beta1.Cluster, result2 error) {
+	fake.getForMachinePoolMutex.Lock()
+	defer fake.getForMachinePoolMutex.Unlock()
+	fake.GetForMachinePoolStub = nil
+	fake.getForMachinePoolReturns = struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolClusterClient) GetForMachinePoolReturnsOnCall(i int, result1 *v1beta1.Cluster, result2 error) {
+	fake.getForMachinePoolMutex.Lock()
+	defer fake.getForMachinePoolMutex.Unlock()
+	fake.GetForMachinePoolStub = nil
+	if fake.getForMachinePoolReturnsOnCall == nil {
+		fake.getForMachinePoolReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1.Cluster
+			result2 error
+		})
+	}
+	fake.getForMachinePoolReturnsOnCall[i] = struct {
+		result1 *v1beta1.Cluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolClusterClient) GetKindCluster(arg1 context.Context, arg2 *v1beta1.Cluster) (*v1beta1b.KindCluster, error) {
+	fake.getKindClusterMutex.Lock()
+	ret, specificReturn := fake.getKindClusterReturnsOnCall[len(fake.getKindClusterArgsForCall)]
+	fake.getKindClusterArgsForCall = append(fake.getKindClusterArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1.Cluster
+	}{arg1, arg2})
+	stub := fake.GetKindClusterStub
+	fakeReturns := fake.getKindClusterReturns
+	fake.recordInvocation("GetKindCluster", []interface{}{arg1, arg2})
+	fake.getKindClusterMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMachinePoolClusterClient) GetKindClusterCallCount() int {
+	fake.getKindClusterMutex.RLock()
+	defer fake.getKindClusterMutex.RUnlock()
+	return len(fake.getKindClusterArgsForCall)
+}
+
+func (fake *FakeMachinePoolClusterClient) GetKindClusterCalls(stub func(context.Context, *v1beta1.Cluster) (*v1beta1b.KindCluster, error)) {
+	fake.getKindClusterMutex.Lock()
+	defer fake.getKindClusterMutex.Unlock()
+	fake.GetKindClusterStub = stub
+}
+
+func (fake *FakeMachinePoolClusterClient) GetKindClusterArgsForCall(i int) (context.Context, *v1beta1.Cluster) {
+	fake.getKindClusterMutex.RLock()
+	defer fake.getKindClusterMutex.RUnlock()
+	argsForCall := fake.getKindClusterArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMachinePoolClusterClient) GetKindClusterReturns(result1 *v1beta1b.KindCluster, result2 error) {
+	fake.getKindClusterMutex.Lock()
+	defer fake.getKindClusterMutex.Unlock()
+	fake.GetKindClusterStub = nil
+	fake.getKindClusterReturns = struct {
+		result1 *v1beta1b.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolClusterClient) GetKindClusterReturnsOnCall(i int, result1 *v1beta1b.KindCluster, result2 error) {
+	fake.getKindClusterMutex.Lock()
+	defer fake.getKindClusterMutex.Unlock()
+	fake.GetKindClusterStub = nil
+	if fake.getKindClusterReturnsOnCall == nil {
+		fake.getKindClusterReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1b.KindCluster
+			result2 error
+		})
+	}
+	fake.getKindClusterReturnsOnCall[i] = struct {
+		result1 *v1beta1b.KindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolClusterClient) GetMachinePool(arg1 context.Context, arg2 *v1beta1b.KindMachinePool) (*v1beta1a.MachinePool, error) {
+	fake.getMachinePoolMutex.Lock()
+	ret, specificReturn := fake.getMachinePoolReturnsOnCall[len(fake.getMachinePoolArgsForCall)]
+	fake.getMachinePoolArgsForCall = append(fake.getMachinePoolArgsForCall, struct {
+		arg1 context.Context
+		arg2 *v1beta1b.KindMachinePool
+	}{arg1, arg2})
+	stub := fake.GetMachinePoolStub
+	fakeReturns := fake.getMachinePoolReturns
+	fake.recordInvocation("GetMachinePool", []interface{}{arg1, arg2})
+	fake.getMachinePoolMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMachinePoolClusterClient) GetMachinePoolCallCount() int {
+	fake.getMachinePoolMutex.RLock()
+	defer fake.getMachinePoolMutex.RUnlock()
+	return len(fake.getMachinePoolArgsForCall)
+}
+
+func (fake *FakeMachinePoolClusterClient) GetMachinePoolCalls(stub func(context.Context, *v1beta1b.KindMachinePool) (*v1beta1a.MachinePool, error)) {
+	fake.getMachinePoolMutex.Lock()
+	defer fake.getMachinePoolMutex.Unlock()
+	fake.GetMachinePoolStub = stub
+}
+
+func (fake *FakeMachinePoolClusterClient) GetMachinePoolArgsForCall(i int) (context.Context, *v1beta1b.KindMachinePool) {
+	fake.getMachinePoolMutex.RLock()
+	defer fake.getMachinePoolMutex.RUnlock()
+	argsForCall := fake.getMachinePoolArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMachinePoolClusterClient) GetMachinePoolReturns(result1 *v1beta1a.MachinePool, result2 error) {
+	fake.getMachinePoolMutex.Lock()
+	defer fake.getMachinePoolMutex.Unlock()
+	fake.GetMachinePoolStub = nil
+	fake.getMachinePoolReturns = struct {
+		result1 *v1beta1a.MachinePool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolClusterClient) GetMachinePoolReturnsOnCall(i int, result1 *v1beta1a.MachinePool, result2 error) {
+	fake.getMachinePoolMutex.Lock()
+	defer fake.getMachinePoolMutex.Unlock()
+	fake.GetMachinePoolStub = nil
+	if fake.getMachinePoolReturnsOnCall == nil {
+		fake.getMachinePoolReturnsOnCall = make(map[int]struct {
+			result1 *v1beta1a.MachinePool
+			result2 error
+		})
+	}
+	fake.getMachinePoolReturnsOnCall[i] = struct {
+		result1 *v1beta1a.MachinePool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolClusterClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getForMachinePoolMutex.RLock()
+	defer fake.getForMachinePoolMutex.RUnlock()
+	fake.getKindClusterMutex.RLock()
+	defer fake.getKindClusterMutex.RUnlock()
+	fake.getMachinePoolMutex.RLock()
+	defer fake.getMachinePoolMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeMachinePoolClusterClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.MachinePoolClusterClient = new(FakeMachinePoolClusterClient)
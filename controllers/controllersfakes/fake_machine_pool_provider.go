@@ -0,0 +1,285 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeMachinePoolProvider struct {
+	AddWorkerNodeStub        func(*v1beta1.KindCluster, string, string, []string) (string, error)
+	addWorkerNodeMutex       sync.RWMutex
+	addWorkerNodeArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+		arg3 string
+		arg4 []string
+	}
+	addWorkerNodeReturns struct {
+		result1 string
+		result2 error
+	}
+	addWorkerNodeReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	GetMachinePoolNodesStub        func(*v1beta1.KindCluster, string) ([]v1beta1.MachinePoolNode, error)
+	getMachinePoolNodesMutex       sync.RWMutex
+	getMachinePoolNodesArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}
+	getMachinePoolNodesReturns struct {
+		result1 []v1beta1.MachinePoolNode
+		result2 error
+	}
+	getMachinePoolNodesReturnsOnCall map[int]struct {
+		result1 []v1beta1.MachinePoolNode
+		result2 error
+	}
+	RemoveWorkerNodeStub        func(*v1beta1.KindCluster, string) error
+	removeWorkerNodeMutex       sync.RWMutex
+	removeWorkerNodeArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}
+	removeWorkerNodeReturns struct {
+		result1 error
+	}
+	removeWorkerNodeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeMachinePoolProvider) AddWorkerNode(arg1 *v1beta1.KindCluster, arg2 string, arg3 string, arg4 []string) (string, error) {
+	var arg4Copy []string
+	if arg4 != nil {
+		arg4Copy = make([]string, len(arg4))
+		copy(arg4Copy, arg4)
+	}
+	fake.addWorkerNodeMutex.Lock()
+	ret, specificReturn := fake.addWorkerNodeReturnsOnCall[len(fake.addWorkerNodeArgsForCall)]
+	fake.addWorkerNodeArgsForCall = append(fake.addWorkerNodeArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+		arg3 string
+		arg4 []string
+	}{arg1, arg2, arg3, arg4Copy})
+	stub := fake.AddWorkerNodeStub
+	fakeReturns := fake.addWorkerNodeReturns
+	fake.recordInvocation("AddWorkerNode", []interface{}{arg1, arg2, arg3, arg4Copy})
+	fake.addWorkerNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMachinePoolProvider) AddWorkerNodeCallCount() int {
+	fake.addWorkerNodeMutex.RLock()
+	defer fake.addWorkerNodeMutex.RUnlock()
+	return len(fake.addWorkerNodeArgsForCall)
+}
+
+func (fake *FakeMachinePoolProvider) AddWorkerNodeCalls(stub func(*v1beta1.KindCluster, string, string, []string) (string, error)) {
+	fake.addWorkerNodeMutex.Lock()
+	defer fake.addWorkerNodeMutex.Unlock()
+	fake.AddWorkerNodeStub = stub
+}
+
+func (fake *FakeMachinePoolProvider) AddWorkerNodeArgsForCall(i int) (*v1beta1.KindCluster, string, string, []string) {
+	fake.addWorkerNodeMutex.RLock()
+	defer fake.addWorkerNodeMutex.RUnlock()
+	argsForCall := fake.addWorkerNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeMachinePoolProvider) AddWorkerNodeReturns(result1 string, result2 error) {
+	fake.addWorkerNodeMutex.Lock()
+	defer fake.addWorkerNodeMutex.Unlock()
+	fake.AddWorkerNodeStub = nil
+	fake.addWorkerNodeReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolProvider) AddWorkerNodeReturnsOnCall(i int, result1 string, result2 error) {
+	fake.addWorkerNodeMutex.Lock()
+	defer fake.addWorkerNodeMutex.Unlock()
+	fake.AddWorkerNodeStub = nil
+	if fake.addWorkerNodeReturnsOnCall == nil {
+		fake.addWorkerNodeReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.addWorkerNodeReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolProvider) GetMachinePoolNodes(arg1 *v1beta1.KindCluster, arg2 string) ([]v1beta1.MachinePoolNode, error) {
+	fake.getMachinePoolNodesMutex.Lock()
+	ret, specificReturn := fake.getMachinePoolNodesReturnsOnCall[len(fake.getMachinePoolNodesArgsForCall)]
+	fake.getMachinePoolNodesArgsForCall = append(fake.getMachinePoolNodesArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetMachinePoolNodesStub
+	fakeReturns := fake.getMachinePoolNodesReturns
+	fake.recordInvocation("GetMachinePoolNodes", []interface{}{arg1, arg2})
+	fake.getMachinePoolNodesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMachinePoolProvider) GetMachinePoolNodesCallCount() int {
+	fake.getMachinePoolNodesMutex.RLock()
+	defer fake.getMachinePoolNodesMutex.RUnlock()
+	return len(fake.getMachinePoolNodesArgsForCall)
+}
+
+func (fake *FakeMachinePoolProvider) GetMachinePoolNodesCalls(stub func(*v1beta1.KindCluster, string) ([]v1beta1.MachinePoolNode, error)) {
+	fake.getMachinePoolNodesMutex.Lock()
+	defer fake.getMachinePoolNodesMutex.Unlock()
+	fake.GetMachinePoolNodesStub = stub
+}
+
+func (fake *FakeMachinePoolProvider) GetMachinePoolNodesArgsForCall(i int) (*v1beta1.KindCluster, string) {
+	fake.getMachinePoolNodesMutex.RLock()
+	defer fake.getMachinePoolNodesMutex.RUnlock()
+	argsForCall := fake.getMachinePoolNodesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMachinePoolProvider) GetMachinePoolNodesReturns(result1 []v1beta1.MachinePoolNode, result2 error) {
+	fake.getMachinePoolNodesMutex.Lock()
+	defer fake.getMachinePoolNodesMutex.Unlock()
+	fake.GetMachinePoolNodesStub = nil
+	fake.getMachinePoolNodesReturns = struct {
+		result1 []v1beta1.MachinePoolNode
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolProvider) GetMachinePoolNodesReturnsOnCall(i int, result1 []v1beta1.MachinePoolNode, result2 error) {
+	fake.getMachinePoolNodesMutex.Lock()
+	defer fake.getMachinePoolNodesMutex.Unlock()
+	fake.GetMachinePoolNodesStub = nil
+	if fake.getMachinePoolNodesReturnsOnCall == nil {
+		fake.getMachinePoolNodesReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.MachinePoolNode
+			result2 error
+		})
+	}
+	fake.getMachinePoolNodesReturnsOnCall[i] = struct {
+		result1 []v1beta1.MachinePoolNode
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMachinePoolProvider) RemoveWorkerNode(arg1 *v1beta1.KindCluster, arg2 string) error {
+	fake.removeWorkerNodeMutex.Lock()
+	ret, specificReturn := fake.removeWorkerNodeReturnsOnCall[len(fake.removeWorkerNodeArgsForCall)]
+	fake.removeWorkerNodeArgsForCall = append(fake.removeWorkerNodeArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RemoveWorkerNodeStub
+	fakeReturns := fake.removeWorkerNodeReturns
+	fake.recordInvocation("RemoveWorkerNode", []interface{}{arg1, arg2})
+	fake.removeWorkerNodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMachinePoolProvider) RemoveWorkerNodeCallCount() int {
+	fake.removeWorkerNodeMutex.RLock()
+	defer fake.removeWorkerNodeMutex.RUnlock()
+	return len(fake.removeWorkerNodeArgsForCall)
+}
+
+func (fake *FakeMachinePoolProvider) RemoveWorkerNodeCalls(stub func(*v1beta1.KindCluster, string) error) {
+	fake.removeWorkerNodeMutex.Lock()
+	defer fake.removeWorkerNodeMutex.Unlock()
+	fake.RemoveWorkerNodeStub = stub
+}
+
+func (fake *FakeMachinePoolProvider) RemoveWorkerNodeArgsForCall(i int) (*v1beta1.KindCluster, string) {
+	fake.removeWorkerNodeMutex.RLock()
+	defer fake.removeWorkerNodeMutex.RUnlock()
+	argsForCall := fake.removeWorkerNodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMachinePoolProvider) RemoveWorkerNodeReturns(result1 error) {
+	fake.removeWorkerNodeMutex.Lock()
+	defer fake.removeWorkerNodeMutex.Unlock()
+	fake.RemoveWorkerNodeStub = nil
+	fake.removeWorkerNodeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMachinePoolProvider) RemoveWorkerNodeReturnsOnCall(i int, result1 error) {
+	fake.removeWorkerNodeMutex.Lock()
+	defer fake.removeWorkerNodeMutex.Unlock()
+	fake.RemoveWorkerNodeStub = nil
+	if fake.removeWorkerNodeReturnsOnCall == nil {
+		fake.removeWorkerNodeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.removeWorkerNodeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMachinePoolProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addWorkerNodeMutex.RLock()
+	defer fake.addWorkerNodeMutex.RUnlock()
+	fake.getMachinePoolNodesMutex.RLock()
+	defer fake.getMachinePoolNodesMutex.RUnlock()
+	fake.removeWorkerNodeMutex.RLock()
+	defer fake.removeWorkerNodeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeMachinePoolProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.MachinePoolProvider = new(FakeMachinePoolProvider)
@@ -0,0 +1,191 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+)
+
+type FakeOrphanProvider struct {
+	DeleteStub        func(*v1beta1.KindCluster) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ListOwnedClustersStub        func(string) ([]v1beta1.OwnedKindCluster, error)
+	listOwnedClustersMutex       sync.RWMutex
+	listOwnedClustersArgsForCall []struct {
+		arg1 string
+	}
+	listOwnedClustersReturns struct {
+		result1 []v1beta1.OwnedKindCluster
+		result2 error
+	}
+	listOwnedClustersReturnsOnCall map[int]struct {
+		result1 []v1beta1.OwnedKindCluster
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeOrphanProvider) Delete(arg1 *v1beta1.KindCluster) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeOrphanProvider) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeOrphanProvider) DeleteCalls(stub func(*v1beta1.KindCluster) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeOrphanProvider) DeleteArgsForCall(i int) *v1beta1.KindCluster {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOrphanProvider) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrphanProvider) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOrphanProvider) ListOwnedClusters(arg1 string) ([]v1beta1.OwnedKindCluster, error) {
+	fake.listOwnedClustersMutex.Lock()
+	ret, specificReturn := fake.listOwnedClustersReturnsOnCall[len(fake.listOwnedClustersArgsForCall)]
+	fake.listOwnedClustersArgsForCall = append(fake.listOwnedClustersArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ListOwnedClustersStub
+	fakeReturns := fake.listOwnedClustersReturns
+	fake.recordInvocation("ListOwnedClusters", []interface{}{arg1})
+	fake.listOwnedClustersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeOrphanProvider) ListOwnedClustersCallCount() int {
+	fake.listOwnedClustersMutex.RLock()
+	defer fake.listOwnedClustersMutex.RUnlock()
+	return len(fake.listOwnedClustersArgsForCall)
+}
+
+func (fake *FakeOrphanProvider) ListOwnedClustersCalls(stub func(string) ([]v1beta1.OwnedKindCluster, error)) {
+	fake.listOwnedClustersMutex.Lock()
+	defer fake.listOwnedClustersMutex.Unlock()
+	fake.ListOwnedClustersStub = stub
+}
+
+func (fake *FakeOrphanProvider) ListOwnedClustersArgsForCall(i int) string {
+	fake.listOwnedClustersMutex.RLock()
+	defer fake.listOwnedClustersMutex.RUnlock()
+	argsForCall := fake.listOwnedClustersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeOrphanProvider) ListOwnedClustersReturns(result1 []v1beta1.OwnedKindCluster, result2 error) {
+	fake.listOwnedClustersMutex.Lock()
+	defer fake.listOwnedClustersMutex.Unlock()
+	fake.ListOwnedClustersStub = nil
+	fake.listOwnedClustersReturns = struct {
+		result1 []v1beta1.OwnedKindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrphanProvider) ListOwnedClustersReturnsOnCall(i int, result1 []v1beta1.OwnedKindCluster, result2 error) {
+	fake.listOwnedClustersMutex.Lock()
+	defer fake.listOwnedClustersMutex.Unlock()
+	fake.ListOwnedClustersStub = nil
+	if fake.listOwnedClustersReturnsOnCall == nil {
+		fake.listOwnedClustersReturnsOnCall = make(map[int]struct {
+			result1 []v1beta1.OwnedKindCluster
+			result2 error
+		})
+	}
+	fake.listOwnedClustersReturnsOnCall[i] = struct {
+		result1 []v1beta1.OwnedKindCluster
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeOrphanProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.listOwnedClustersMutex.RLock()
+	defer fake.listOwnedClustersMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeOrphanProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.OrphanProvider = new(FakeOrphanProvider)
@@ -0,0 +1,344 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package controllersfakes
+
+import (
+	"sync"
+
+	"github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	v1 "k8s.io/api/core/v1"
+)
+
+type FakePoolProvider struct {
+	CheckHealthStub        func(*v1beta1.KindCluster) ([]byte, error)
+	checkHealthMutex       sync.RWMutex
+	checkHealthArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	checkHealthReturns struct {
+		result1 []byte
+		result2 error
+	}
+	checkHealthReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	CreateStub        func(*v1beta1.KindCluster, v1.ObjectReference) error
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}
+	createReturns struct {
+		result1 error
+	}
+	createReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteStub        func(*v1beta1.KindCluster) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SetOwnerStub        func(*v1beta1.KindCluster, v1.ObjectReference) error
+	setOwnerMutex       sync.RWMutex
+	setOwnerArgsForCall []struct {
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}
+	setOwnerReturns struct {
+		result1 error
+	}
+	setOwnerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePoolProvider) CheckHealth(arg1 *v1beta1.KindCluster) ([]byte, error) {
+	fake.checkHealthMutex.Lock()
+	ret, specificReturn := fake.checkHealthReturnsOnCall[len(fake.checkHealthArgsForCall)]
+	fake.checkHealthArgsForCall = append(fake.checkHealthArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.CheckHealthStub
+	fakeReturns := fake.checkHealthReturns
+	fake.recordInvocation("CheckHealth", []interface{}{arg1})
+	fake.checkHealthMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePoolProvider) CheckHealthCallCount() int {
+	fake.checkHealthMutex.RLock()
+	defer fake.checkHealthMutex.RUnlock()
+	return len(fake.checkHealthArgsForCall)
+}
+
+func (fake *FakePoolProvider) CheckHealthCalls(stub func(*v1beta1.KindCluster) ([]byte, error)) {
+	fake.checkHealthMutex.Lock()
+	defer fake.checkHealthMutex.Unlock()
+	fake.CheckHealthStub = stub
+}
+
+func (fake *FakePoolProvider) CheckHealthArgsForCall(i int) *v1beta1.KindCluster {
+	fake.checkHealthMutex.RLock()
+	defer fake.checkHealthMutex.RUnlock()
+	argsForCall := fake.checkHealthArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePoolProvider) CheckHealthReturns(result1 []byte, result2 error) {
+	fake.checkHealthMutex.Lock()
+	defer fake.checkHealthMutex.Unlock()
+	fake.CheckHealthStub = nil
+	fake.checkHealthReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePoolProvider) CheckHealthReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.checkHealthMutex.Lock()
+	defer fake.checkHealthMutex.Unlock()
+	fake.CheckHealthStub = nil
+	if fake.checkHealthReturnsOnCall == nil {
+		fake.checkHealthReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.checkHealthReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePoolProvider) Create(arg1 *v1beta1.KindCluster, arg2 v1.ObjectReference) error {
+	fake.createMutex.Lock()
+	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}{arg1, arg2})
+	stub := fake.CreateStub
+	fakeReturns := fake.createReturns
+	fake.recordInvocation("Create", []interface{}{arg1, arg2})
+	fake.createMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePoolProvider) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+func (fake *FakePoolProvider) CreateCalls(stub func(*v1beta1.KindCluster, v1.ObjectReference) error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = stub
+}
+
+func (fake *FakePoolProvider) CreateArgsForCall(i int) (*v1beta1.KindCluster, v1.ObjectReference) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	argsForCall := fake.createArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePoolProvider) CreateReturns(result1 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePoolProvider) CreateReturnsOnCall(i int, result1 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	if fake.createReturnsOnCall == nil {
+		fake.createReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePoolProvider) Delete(arg1 *v1beta1.KindCluster) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+	}{arg1})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{arg1})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePoolProvider) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakePoolProvider) DeleteCalls(stub func(*v1beta1.KindCluster) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakePoolProvider) DeleteArgsForCall(i int) *v1beta1.KindCluster {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePoolProvider) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePoolProvider) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePoolProvider) SetOwner(arg1 *v1beta1.KindCluster, arg2 v1.ObjectReference) error {
+	fake.setOwnerMutex.Lock()
+	ret, specificReturn := fake.setOwnerReturnsOnCall[len(fake.setOwnerArgsForCall)]
+	fake.setOwnerArgsForCall = append(fake.setOwnerArgsForCall, struct {
+		arg1 *v1beta1.KindCluster
+		arg2 v1.ObjectReference
+	}{arg1, arg2})
+	stub := fake.SetOwnerStub
+	fakeReturns := fake.setOwnerReturns
+	fake.recordInvocation("SetOwner", []interface{}{arg1, arg2})
+	fake.setOwnerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePoolProvider) SetOwnerCallCount() int {
+	fake.setOwnerMutex.RLock()
+	defer fake.setOwnerMutex.RUnlock()
+	return len(fake.setOwnerArgsForCall)
+}
+
+func (fake *FakePoolProvider) SetOwnerCalls(stub func(*v1beta1.KindCluster, v1.ObjectReference) error) {
+	fake.setOwnerMutex.Lock()
+	defer fake.setOwnerMutex.Unlock()
+	fake.SetOwnerStub = stub
+}
+
+func (fake *FakePoolProvider) SetOwnerArgsForCall(i int) (*v1beta1.KindCluster, v1.ObjectReference) {
+	fake.setOwnerMutex.RLock()
+	defer fake.setOwnerMutex.RUnlock()
+	argsForCall := fake.setOwnerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePoolProvider) SetOwnerReturns(result1 error) {
+	fake.setOwnerMutex.Lock()
+	defer fake.setOwnerMutex.Unlock()
+	fake.SetOwnerStub = nil
+	fake.setOwnerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePoolProvider) SetOwnerReturnsOnCall(i int, result1 error) {
+	fake.setOwnerMutex.Lock()
+	defer fake.setOwnerMutex.Unlock()
+	fake.SetOwnerStub = nil
+	if fake.setOwnerReturnsOnCall == nil {
+		fake.setOwnerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setOwnerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePoolProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.checkHealthMutex.RLock()
+	defer fake.checkHealthMutex.RUnlock()
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.setOwnerMutex.RLock()
+	defer fake.setOwnerMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePoolProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ controllers.PoolProvider = new(FakePoolProvider)
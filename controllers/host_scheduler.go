@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+//counterfeiter:generate . HostScheduler
+//counterfeiter:generate . HostLister
+//counterfeiter:generate . HostClusterLister
+
+// HostScheduler picks the KindHost a kind cluster without an explicit host
+// is created on.
+type HostScheduler interface {
+	Schedule(context.Context, *kclusterv1.KindCluster) (*kclusterv1.KindHost, error)
+}
+
+type HostLister interface {
+	List(context.Context) ([]kclusterv1.KindHost, error)
+}
+
+type HostClusterLister interface {
+	ListByHost(context.Context, string) ([]kclusterv1.KindCluster, error)
+}
+
+// ErrInsufficientHostCapacity is returned by Schedule when no ready KindHost
+// has the capacity for the kind cluster.
+var ErrInsufficientHostCapacity = errors.New("no ready host has the capacity for the kind cluster")
+
+const (
+	// LeastLoadedPolicy spreads kind clusters across the hosts by picking
+	// the least utilized one.
+	LeastLoadedPolicy = "LeastLoaded"
+	// BinPackPolicy fills up hosts one after the other by picking the most
+	// utilized one the kind cluster still fits on.
+	BinPackPolicy = "BinPack"
+)
+
+// A kind node is assumed to use about one CPU and 1Gi of memory when
+// weighing the resources a docker daemon reports.
+var (
+	nodeCPUs   = int64(1)
+	nodeMemory = resource.MustParse("1Gi")
+)
+
+// HostSchedulingPolicy scores the hosts a kind cluster fits on. The host
+// with the highest score is picked.
+type HostSchedulingPolicy interface {
+	Score(HostLoad) float64
+}
+
+// HostSchedulingPolicyFunc adapts a function to a HostSchedulingPolicy.
+type HostSchedulingPolicyFunc func(HostLoad) float64
+
+func (f HostSchedulingPolicyFunc) Score(load HostLoad) float64 {
+	return f(load)
+}
+
+// NewHostSchedulingPolicy returns the policy with the given name.
+func NewHostSchedulingPolicy(name string) (HostSchedulingPolicy, error) {
+	switch name {
+	case LeastLoadedPolicy:
+		return HostSchedulingPolicyFunc(func(load HostLoad) float64 {
+			return -load.Utilization()
+		}), nil
+	case BinPackPolicy:
+		return HostSchedulingPolicyFunc(func(load HostLoad) float64 {
+			return load.Utilization()
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown host scheduling policy %q", name)
+	}
+}
+
+// HostLoad is the load of a KindHost with the kind cluster being scheduled
+// placed on it.
+type HostLoad struct {
+	Host     *kclusterv1.KindHost
+	Clusters int32
+	Nodes    int32
+}
+
+// Fits returns true if the clusters and nodes stay within the capacity of
+// the host.
+func (l HostLoad) Fits() bool {
+	capacity := l.Host.Spec.Capacity
+	if capacity == nil {
+		return true
+	}
+	if capacity.MaxClusters != nil && l.Clusters > *capacity.MaxClusters {
+		return false
+	}
+	if capacity.MaxNodes != nil && l.Nodes > *capacity.MaxNodes {
+		return false
+	}
+	return true
+}
+
+// Utilization returns the utilization of the most used resource of the host,
+// out of its capacity and the CPUs and memory of its docker daemon.
+func (l HostLoad) Utilization() float64 {
+	var utilization float64
+	use := func(used, available float64) {
+		if available > 0 && used/available > utilization {
+			utilization = used / available
+		}
+	}
+
+	if capacity := l.Host.Spec.Capacity; capacity != nil {
+		if capacity.MaxClusters != nil {
+			use(float64(l.Clusters), float64(*capacity.MaxClusters))
+		}
+		if capacity.MaxNodes != nil {
+			use(float64(l.Nodes), float64(*capacity.MaxNodes))
+		}
+	}
+
+	use(float64(int64(l.Nodes)*nodeCPUs), float64(l.Host.Status.CPUs))
+	if l.Host.Status.Memory != nil {
+		use(float64(l.Nodes)*nodeMemory.AsApproximateFloat64(), l.Host.Status.Memory.AsApproximateFloat64())
+	}
+
+	return utilization
+}
+
+// KindHostScheduler picks the ready KindHost with the best score of the
+// policy out of those the kind cluster fits on.
+type KindHostScheduler struct {
+	hosts        HostLister
+	kindClusters HostClusterLister
+	policy       HostSchedulingPolicy
+}
+
+func NewKindHostScheduler(hosts HostLister, kindClusters HostClusterLister, policy HostSchedulingPolicy) *KindHostScheduler {
+	return &KindHostScheduler{
+		hosts:        hosts,
+		kindClusters: kindClusters,
+		policy:       policy,
+	}
+}
+
+// Schedule returns the host for the kind cluster or
+// ErrInsufficientHostCapacity if it fits on none.
+func (s *KindHostScheduler) Schedule(ctx context.Context, kindCluster *kclusterv1.KindCluster) (*kclusterv1.KindHost, error) {
+	hosts, err := s.hosts.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].Name < hosts[j].Name
+	})
+
+	var selected *kclusterv1.KindHost
+	var selectedScore float64
+	for i := range hosts {
+		host := &hosts[i]
+		if !host.Status.Ready || !host.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		load, err := s.load(ctx, host, kindCluster)
+		if err != nil {
+			return nil, err
+		}
+
+		if !load.Fits() {
+			continue
+		}
+
+		score := s.policy.Score(load)
+		if selected == nil || score > selectedScore {
+			selected = host
+			selectedScore = score
+		}
+	}
+
+	if selected == nil {
+		return nil, ErrInsufficientHostCapacity
+	}
+
+	return selected, nil
+}
+
+// load returns the load of the host with the kind cluster placed on it. The
+// KindClusters already placed on the host count even before their nodes
+// exist, so that clusters scheduled at the same time do not overcommit it.
+func (s *KindHostScheduler) load(ctx context.Context, host *kclusterv1.KindHost, kindCluster *kclusterv1.KindCluster) (HostLoad, error) {
+	kindClusters, err := s.kindClusters.ListByHost(ctx, host.Name)
+	if err != nil {
+		return HostLoad{}, err
+	}
+
+	var clusters, nodes int32
+	for i := range kindClusters {
+		if kindClusters[i].Namespace == kindCluster.Namespace && kindClusters[i].Name == kindCluster.Name {
+			continue
+		}
+		clusters++
+		nodes += kindClusters[i].GetRequestedNodes()
+	}
+
+	return HostLoad{
+		Host:     host,
+		Clusters: max(clusters, host.Status.Clusters) + 1,
+		Nodes:    max(nodes, host.Status.Nodes) + kindCluster.GetRequestedNodes(),
+	}, nil
+}
@@ -0,0 +1,235 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+)
+
+var _ = Describe("KindHostScheduler", func() {
+	var (
+		scheduler    *controllers.KindHostScheduler
+		hostLister   *controllersfakes.FakeHostLister
+		kindClusters *controllersfakes.FakeHostClusterLister
+		policy       string
+		hosts        []kclusterv1.KindHost
+		placed       map[string][]kclusterv1.KindCluster
+		kindCluster  *kclusterv1.KindCluster
+		scheduled    *kclusterv1.KindHost
+		scheduleErr  error
+	)
+
+	newHost := func(name string, maxClusters, maxNodes int32) kclusterv1.KindHost {
+		return kclusterv1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kclusterv1.KindHostSpec{
+				Endpoint: "tcp://" + name + ":2376",
+				Capacity: &kclusterv1.HostCapacity{
+					MaxClusters: ptr.To(maxClusters),
+					MaxNodes:    ptr.To(maxNodes),
+				},
+			},
+			Status: kclusterv1.KindHostStatus{
+				Ready: true,
+			},
+		}
+	}
+
+	newKindCluster := func(name string, controlPlaneNodes, workerNodes int32) kclusterv1.KindCluster {
+		return kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "bar"},
+			Spec: kclusterv1.KindClusterSpec{
+				ControlPlaneNodes: controlPlaneNodes,
+				WorkerNodes:       workerNodes,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		hostLister = new(controllersfakes.FakeHostLister)
+		kindClusters = new(controllersfakes.FakeHostClusterLister)
+		policy = controllers.LeastLoadedPolicy
+
+		hosts = []kclusterv1.KindHost{
+			newHost("host-b", 4, 10),
+			newHost("host-a", 4, 10),
+		}
+		placed = map[string][]kclusterv1.KindCluster{
+			"host-a": {newKindCluster("one", 1, 1)},
+			"host-b": {newKindCluster("two", 1, 2), newKindCluster("three", 1, 2)},
+		}
+
+		c := newKindCluster("foo", 1, 1)
+		kindCluster = &c
+	})
+
+	JustBeforeEach(func() {
+		hostLister.ListReturns(hosts, nil)
+		kindClusters.ListByHostCalls(func(_ context.Context, host string) ([]kclusterv1.KindCluster, error) {
+			return placed[host], nil
+		})
+
+		schedulingPolicy, err := controllers.NewHostSchedulingPolicy(policy)
+		Expect(err).NotTo(HaveOccurred())
+		scheduler = controllers.NewKindHostScheduler(hostLister, kindClusters, schedulingPolicy)
+
+		scheduled, scheduleErr = scheduler.Schedule(context.Background(), kindCluster)
+	})
+
+	It("picks the least loaded host", func() {
+		Expect(scheduleErr).NotTo(HaveOccurred())
+		Expect(scheduled.Name).To(Equal("host-a"))
+	})
+
+	When("the policy is BinPack", func() {
+		BeforeEach(func() {
+			policy = controllers.BinPackPolicy
+		})
+
+		It("picks the most loaded host the cluster fits on", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-b"))
+		})
+
+		When("the cluster does not fit on the most loaded host", func() {
+			BeforeEach(func() {
+				c := newKindCluster("foo", 3, 2)
+				kindCluster = &c
+			})
+
+			It("picks the next host", func() {
+				Expect(scheduleErr).NotTo(HaveOccurred())
+				Expect(scheduled.Name).To(Equal("host-a"))
+			})
+		})
+	})
+
+	When("the hosts are equally loaded", func() {
+		BeforeEach(func() {
+			placed = map[string][]kclusterv1.KindCluster{}
+		})
+
+		It("picks the first host by name", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-a"))
+		})
+	})
+
+	When("a host is not ready", func() {
+		BeforeEach(func() {
+			hosts[1].Status.Ready = false
+		})
+
+		It("is skipped", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-b"))
+		})
+	})
+
+	When("the status of a host reports more usage than the KindClusters placed on it", func() {
+		BeforeEach(func() {
+			hosts[1].Status.Clusters = 3
+			hosts[1].Status.Nodes = 9
+		})
+
+		It("uses the usage from the status", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-b"))
+		})
+	})
+
+	When("the resources of the docker daemon are the most utilized", func() {
+		BeforeEach(func() {
+			hosts[1].Spec.Capacity = nil
+			hosts[1].Status.CPUs = 4
+			hosts[1].Status.Memory = resource.NewQuantity(64<<30, resource.BinarySI)
+		})
+
+		It("weighs the CPUs of the daemon", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-b"))
+		})
+	})
+
+	When("the cluster is already placed on a host", func() {
+		BeforeEach(func() {
+			placed["host-a"] = append(placed["host-a"], newKindCluster("foo", 1, 1), newKindCluster("other", 1, 2))
+		})
+
+		It("does not count the cluster twice", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-a"))
+		})
+	})
+
+	When("no host has the capacity for the cluster", func() {
+		BeforeEach(func() {
+			c := newKindCluster("foo", 3, 6)
+			kindCluster = &c
+		})
+
+		It("returns ErrInsufficientHostCapacity", func() {
+			Expect(scheduleErr).To(MatchError(controllers.ErrInsufficientHostCapacity))
+		})
+	})
+
+	When("a host has reached its maximum clusters", func() {
+		BeforeEach(func() {
+			hosts[1].Spec.Capacity.MaxClusters = ptr.To[int32](1)
+		})
+
+		It("is skipped", func() {
+			Expect(scheduleErr).NotTo(HaveOccurred())
+			Expect(scheduled.Name).To(Equal("host-b"))
+		})
+	})
+
+	When("there are no hosts", func() {
+		BeforeEach(func() {
+			hosts = nil
+		})
+
+		It("returns ErrInsufficientHostCapacity", func() {
+			Expect(scheduleErr).To(MatchError(controllers.ErrInsufficientHostCapacity))
+		})
+	})
+
+	When("listing the hosts fails", func() {
+		JustBeforeEach(func() {
+			hostLister.ListReturns(nil, errors.New("boom"))
+			scheduled, scheduleErr = scheduler.Schedule(context.Background(), kindCluster)
+		})
+
+		It("returns an error", func() {
+			Expect(scheduleErr).To(MatchError("boom"))
+		})
+	})
+
+	When("listing the KindClusters of a host fails", func() {
+		JustBeforeEach(func() {
+			kindClusters.ListByHostReturns(nil, errors.New("boom"))
+			kindClusters.ListByHostCalls(nil)
+			scheduled, scheduleErr = scheduler.Schedule(context.Background(), kindCluster)
+		})
+
+		It("returns an error", func() {
+			Expect(scheduleErr).To(MatchError("boom"))
+		})
+	})
+})
+
+var _ = Describe("NewHostSchedulingPolicy", func() {
+	It("rejects unknown policies", func() {
+		_, err := controllers.NewHostSchedulingPolicy("Random")
+		Expect(err).To(MatchError(ContainSubstring(`unknown host scheduling policy "Random"`)))
+	})
+})
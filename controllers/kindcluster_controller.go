@@ -18,48 +18,196 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/k8s"
 )
 
 //counterfeiter:generate . ClusterProvider
 //counterfeiter:generate . ClusterClient
 //counterfeiter:generate . KindClusterClient
+//counterfeiter:generate . DiagnosticsStore
+//counterfeiter:generate . KubeconfigStore
+//counterfeiter:generate . QuotaChecker
+//counterfeiter:generate . NamespaceClient
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusters/finalizers,verbs=update
-//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclustertemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindhosts,verbs=get;list;watch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusterquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;create;update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 type ClusterProvider interface {
-	Create(*kclusterv1.KindCluster) error
+	Create(*kclusterv1.KindCluster, corev1.ObjectReference) error
 	Exists(*kclusterv1.KindCluster) (bool, error)
 	Delete(*kclusterv1.KindCluster) error
-	GetControlPlaneEndpoint(*kclusterv1.KindCluster) (string, int, error)
+	GetKubeconfig(*kclusterv1.KindCluster) ([]byte, error)
+	GetControlPlaneEndpoint([]byte) (string, int32, error)
+	CheckHealth(*kclusterv1.KindCluster) ([]byte, error)
+	RestartNodes(*kclusterv1.KindCluster) error
+	GetNodes(*kclusterv1.KindCluster) ([]kclusterv1.NodeStatus, error)
+	GetKubernetesVersion([]byte) (string, error)
+	CollectDiagnostics(*kclusterv1.KindCluster, int) ([]byte, error)
+	ExportKubeconfig(*kclusterv1.KindCluster, []byte) error
+	SetOwner(*kclusterv1.KindCluster, corev1.ObjectReference) error
+	ReleaseOwner(*kclusterv1.KindCluster) error
+	StopNodes(*kclusterv1.KindCluster) error
+	GetMutatingRequests([]byte) (int64, error)
+	GetCertificateExpiry(*kclusterv1.KindCluster, []byte) (time.Time, error)
+	RenewCertificates(*kclusterv1.KindCluster) error
 }
 
 type KindClusterClient interface {
 	Get(context.Context, types.NamespacedName) (*kclusterv1.KindCluster, error)
+	ListByName(context.Context, string) ([]kclusterv1.KindCluster, error)
+	Delete(context.Context, *kclusterv1.KindCluster) error
 	AddFinalizer(context.Context, *kclusterv1.KindCluster) error
 	RemoveFinalizer(context.Context, *kclusterv1.KindCluster) error
-	SetControlPlaneEndpoint(context.Context, kclusterv1.APIEndpoint, *kclusterv1.KindCluster) error
+	SetName(context.Context, string, *kclusterv1.KindCluster) error
+	ExtendLease(context.Context, metav1.Time, *kclusterv1.KindCluster) error
+	SetControlPlaneEndpoint(context.Context, clusterv1.APIEndpoint, *kclusterv1.KindCluster) error
 	UpdateStatus(context.Context, kclusterv1.KindClusterStatus, *kclusterv1.KindCluster) error
+	SuspendIdle(context.Context, string, *kclusterv1.KindCluster) error
 }
 
 type ClusterClient interface {
 	Get(context.Context, *kclusterv1.KindCluster) (*clusterv1.Cluster, error)
+	Delete(context.Context, *clusterv1.Cluster) error
+	GetControlPlane(context.Context, *clusterv1.Cluster) (*kclusterv1.KindControlPlane, error)
+}
+
+type DiagnosticsStore interface {
+	Store(context.Context, *kclusterv1.KindCluster, []byte) (*kclusterv1.DiagnosticsReference, error)
+}
+
+type KubeconfigStore interface {
+	Store(context.Context, *clusterv1.Cluster, []byte) error
+}
+
+type QuotaChecker interface {
+	Check(context.Context, *kclusterv1.KindCluster) error
+}
+
+type NamespaceClient interface {
+	Get(context.Context, string) (*corev1.Namespace, error)
+}
+
+const (
+	// maxKindClusterNameLength is the length above which kind warns that the
+	// names of the node containers might be too long.
+	maxKindClusterNameLength = 50
+	generatedNameHashLength  = 8
+
+	// hostSchedulingRetryInterval is how often scheduling a kind cluster is
+	// retried while no KindHost has the capacity for it.
+	hostSchedulingRetryInterval = 30 * time.Second
+
+	// quotaRetryInterval is how often a kind cluster exceeding a quota is
+	// checked again.
+	quotaRetryInterval = 30 * time.Second
+
+	// expiryWarningPeriod is how long before a KindCluster expires a warning
+	// event is emitted.
+	expiryWarningPeriod = 10 * time.Minute
+
+	// resumeRetryInterval is how often the API server of a resumed kind
+	// cluster is checked until it is ready.
+	resumeRetryInterval = 5 * time.Second
+
+	// certificateRenewalCheckInterval is how often a kind cluster whose
+	// certificates are being renewed in the background is checked until the
+	// renewal finishes.
+	certificateRenewalCheckInterval = 10 * time.Second
+)
+
+// Options configures the behaviour of the KindClusterReconciler
+type Options struct {
+	// HealthCheckInterval is how often a Ready kind cluster is checked for
+	// running node containers and a ready API server. Zero disables the
+	// periodic check.
+	HealthCheckInterval time.Duration
+
+	// ClusterEvents is an optional stream of container runtime events. Each
+	// event object carries the affected kind cluster name in Spec.Name and
+	// triggers a reconcile of the KindClusters using that name.
+	ClusterEvents <-chan event.GenericEvent
+
+	// DiagnosticsMaxSize is the maximum size in bytes of the diagnostics
+	// archive collected when a kind cluster fails to create.
+	DiagnosticsMaxSize int
+
+	// HostScheduler picks the KindHost for kind clusters without an
+	// explicit host. Nil creates them on the docker daemon of the manager.
+	HostScheduler HostScheduler
+
+	// Quota keeps kind clusters exceeding a quota Pending. Nil disables the
+	// check.
+	Quota QuotaChecker
+
+	// Pools lets KindClusters referencing a KindClusterPool claim an idle
+	// kind cluster from it. Nil ignores the pool references.
+	Pools KindClusterPoolClient
+
+	// IdleTimeout is for how long the API server of a Ready kind cluster may
+	// serve no mutating requests before the KindCluster is suspended, unless
+	// the KindCluster or its namespace set their own with the
+	// kclusterv1.IdleTimeoutAnnotation. Zero only suspends the KindClusters
+	// that do.
+	IdleTimeout time.Duration
+
+	// Namespaces reads the idle timeout annotation of the namespaces of
+	// KindClusters. Nil ignores it.
+	Namespaces NamespaceClient
+
+	// OrphanCollectionInterval is how often the OrphanCollector looks for
+	// kind clusters whose owner no longer exists.
+	OrphanCollectionInterval time.Duration
+
+	// OrphanGracePeriod is for how long a kind cluster has to be orphaned
+	// before the OrphanCollector deletes it.
+	OrphanGracePeriod time.Duration
+
+	// OrphanDryRun makes the OrphanCollector only log the orphaned kind
+	// clusters it would delete.
+	OrphanDryRun bool
+
+	// CertificateRenewBefore is how long before they expire the certificates
+	// of the control plane of a Ready kind cluster are renewed. Zero disables
+	// the renewal.
+	CertificateRenewBefore time.Duration
 }
 
 // KindClusterReconciler reconciles a KindCluster object
@@ -67,21 +215,86 @@ type KindClusterReconciler struct {
 	clusters        ClusterClient
 	kindClusters    KindClusterClient
 	clusterProvider ClusterProvider
+	kubeconfigs     KubeconfigStore
+	diagnostics     DiagnosticsStore
+	recorder        record.EventRecorder
+	options         Options
+
+	mu sync.Mutex
+	// renewing are the KindClusters whose certificates are being renewed.
+	renewing map[types.NamespacedName]struct{}
 }
 
-func NewKindClusterReconciler(clusters ClusterClient, kindClusters KindClusterClient, clusterProvider ClusterProvider) *KindClusterReconciler {
+// NewKindClusterReconciler creates a KindClusterReconciler. If diagnostics is
+// nil no logs are collected when a kind cluster fails to create.
+func NewKindClusterReconciler(
+	clusters ClusterClient,
+	kindClusters KindClusterClient,
+	clusterProvider ClusterProvider,
+	kubeconfigs KubeconfigStore,
+	diagnostics DiagnosticsStore,
+	recorder record.EventRecorder,
+	options Options,
+) *KindClusterReconciler {
 	return &KindClusterReconciler{
 		clusters:        clusters,
 		kindClusters:    kindClusters,
 		clusterProvider: clusterProvider,
+		kubeconfigs:     kubeconfigs,
+		diagnostics:     diagnostics,
+		recorder:        recorder,
+		options:         options,
+		renewing:        map[types.NamespacedName]struct{}{},
 	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *KindClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&kclusterv1.KindCluster{}).
-		Complete(r)
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&kclusterv1.KindCluster{})
+
+	if r.options.ClusterEvents != nil {
+		builder = builder.WatchesRawSource(source.Channel(
+			r.options.ClusterEvents,
+			handler.EnqueueRequestsFromMapFunc(r.KindClustersForEvent),
+		))
+	}
+
+	return builder.Complete(r)
+}
+
+// KindClustersForEvent maps a container runtime event for a kind cluster to
+// reconcile requests for the KindClusters using that kind cluster name on
+// the same KindHost, or on the docker daemon of the manager.
+func (r *KindClusterReconciler) KindClustersForEvent(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	eventCluster, ok := obj.(*kclusterv1.KindCluster)
+	if !ok {
+		return nil
+	}
+
+	kindClusters, err := r.kindClusters.ListByName(ctx, eventCluster.Spec.Name)
+	if err != nil {
+		logger.Error(err, "failed to list KindClusters for container event", "cluster-name", eventCluster.Spec.Name)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, kindCluster := range kindClusters {
+		if kindCluster.GetHostName() != eventCluster.GetHostName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      kindCluster.Name,
+				Namespace: kindCluster.Namespace,
+			},
+		})
+	}
+
+	return requests
 }
 
 func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -108,6 +321,33 @@ func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	if annotations.IsPaused(cluster, kindCluster) {
+		logger.Info("reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
+	if kindCluster.Spec.Name == "" && kindCluster.Spec.PoolRef != nil && r.options.Pools != nil &&
+		kindCluster.DeletionTimestamp.IsZero() {
+		claimed, err := r.claimFromPool(ctx, cluster, kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to claim kind cluster from pool")
+			return ctrl.Result{}, err
+		}
+		if claimed {
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
+	if kindCluster.Spec.Name == "" {
+		name := generateName(kindCluster)
+		logger.Info("generating kind cluster name", "generated-name", name)
+		err = r.kindClusters.SetName(ctx, name, kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to set kind cluster name")
+			return ctrl.Result{}, err
+		}
+	}
+
 	logger = logger.WithValues("cluster-name", kindCluster.Spec.Name)
 	ctx = log.IntoContext(ctx, logger)
 
@@ -115,11 +355,238 @@ func (r *KindClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return r.reconcileDeletion(ctx, kindCluster)
 	}
 
+	if _, ok := kindCluster.Annotations[kclusterv1.ExtendLeaseAnnotation]; ok {
+		err = r.extendLease(ctx, kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to extend lease")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if expiresAt := kindCluster.GetExpiresAt(); expiresAt != nil && !time.Now().Before(expiresAt.Time) {
+		if !kindCluster.IsDeletionProtected() {
+			return r.expire(ctx, cluster, kindCluster)
+		}
+		logger.Info("KindCluster expired but is protected from deletion", "expires-at", expiresAt.UTC().Format(time.RFC3339))
+	}
+
 	if kindCluster.Status.Phase == kclusterv1.ClusterPhaseProvisioning {
 		logger.Info("cluster still creating - skipping event")
 		return ctrl.Result{Requeue: true}, nil
 	}
-	return r.reconcileNormal(ctx, kindCluster)
+
+	result, err := r.reconcileNormal(ctx, cluster, kindCluster)
+	if err != nil {
+		return result, err
+	}
+
+	return requeueAtExpiry(result, kindCluster), nil
+}
+
+// claimFromPool claims an idle kind cluster from the KindClusterPool the
+// KindCluster references and records the KindCluster as its owner. The kind
+// cluster is then adopted like an existing one. A kind cluster the
+// KindCluster already claimed, e.g. before failing to record its name, is
+// taken over again instead of claiming another one. It returns false if the
+// pool has no matching idle kind cluster, so that one is created as usual.
+func (r *KindClusterReconciler) claimFromPool(ctx context.Context, cluster *clusterv1.Cluster, kindCluster *kclusterv1.KindCluster) (bool, error) {
+	logger := log.FromContext(ctx).WithValues("pool", kindCluster.Spec.PoolRef.Name)
+
+	pool, err := r.options.Pools.Get(ctx, types.NamespacedName{
+		Namespace: kindCluster.Namespace,
+		Name:      kindCluster.Spec.PoolRef.Name,
+	})
+	if k8serrors.IsNotFound(err) {
+		logger.Info("KindClusterPool does not exist, creating kind cluster")
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, pooled := range pool.Status.Clusters {
+		if pooled.Phase == kclusterv1.PooledClusterPhaseClaimed && pooled.ClaimedBy != nil &&
+			pooled.ClaimedBy.UID == kindCluster.UID {
+			logger.Info("kind cluster already claimed from pool", "cluster-name", pooled.Name)
+			return true, r.takeOverClaim(ctx, kindCluster, pool, pooled.Name)
+		}
+	}
+
+	controlPlane, err := r.clusters.GetControlPlane(ctx, cluster)
+	if err != nil {
+		return false, err
+	}
+
+	desired := withControlPlane(kindCluster, controlPlane)
+	if !pool.Matches(desired) {
+		logger.Info("KindCluster does not match the template of the pool, creating kind cluster")
+		r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "PoolMismatch",
+			"Not claiming from KindClusterPool %s, as its template does not match the KindCluster", pool.Name)
+		return false, nil
+	}
+
+	if r.options.Quota != nil {
+		err = r.options.Quota.Check(ctx, desired)
+		if errors.Is(err, kclusterv1.ErrQuotaExceeded) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	hash := pool.TemplateHash()
+	name := ""
+	for _, pooled := range pool.Status.Clusters {
+		if pooled.Phase == kclusterv1.PooledClusterPhaseIdle && pooled.TemplateHash == hash {
+			name = pooled.Name
+			break
+		}
+	}
+	if name == "" {
+		logger.Info("KindClusterPool has no idle kind cluster, creating kind cluster")
+		return false, nil
+	}
+
+	err = r.options.Pools.Claim(ctx, name, kindCluster, pool)
+	if err != nil {
+		return false, err
+	}
+
+	logger.Info("claimed kind cluster from pool", "cluster-name", name)
+	return true, r.takeOverClaim(ctx, kindCluster, pool, name)
+}
+
+// takeOverClaim records the name of the kind cluster claimed from the pool
+// in the KindCluster and the KindCluster as its owner.
+func (r *KindClusterReconciler) takeOverClaim(ctx context.Context, kindCluster *kclusterv1.KindCluster, pool *kclusterv1.KindClusterPool, name string) error {
+	err := r.kindClusters.SetName(ctx, name, kindCluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.kindClusters.AddFinalizer(ctx, kindCluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.clusterProvider.SetOwner(kindCluster, kindClusterOwner(kindCluster))
+	if err != nil {
+		return err
+	}
+
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "ClaimedFromPool",
+		"Claimed kind cluster %s from KindClusterPool %s", name, pool.Name)
+	return nil
+}
+
+// extendLease moves the expiry of the KindCluster to the duration of the
+// kclusterv1.ExtendLeaseAnnotation from now, unless it already is later. The
+// annotation is ignored on KindClusters that do not expire.
+func (r *KindClusterReconciler) extendLease(ctx context.Context, kindCluster *kclusterv1.KindCluster) error {
+	logger := log.FromContext(ctx)
+
+	current := kindCluster.GetExpiresAt()
+	if current == nil {
+		logger.Info("ignoring lease extension of KindCluster without expiry")
+		return nil
+	}
+
+	value := kindCluster.Annotations[kclusterv1.ExtendLeaseAnnotation]
+	extension, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s annotation %q: %w", kclusterv1.ExtendLeaseAnnotation, value, err)
+	}
+
+	expiresAt := metav1.NewTime(time.Now().Add(extension))
+	if current.After(expiresAt.Time) {
+		expiresAt = *current
+	}
+
+	err = r.kindClusters.ExtendLease(ctx, expiresAt, kindCluster)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("extended lease", "expires-at", expiresAt)
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "LeaseExtended",
+		"Extended lease until %s", expiresAt.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// expire deletes the expired KindCluster, or its Cluster if the expiration
+// policy says so.
+func (r *KindClusterReconciler) expire(ctx context.Context, cluster *clusterv1.Cluster, kindCluster *kclusterv1.KindCluster) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	expiresAt := kindCluster.GetExpiresAt().UTC().Format(time.RFC3339)
+
+	if kindCluster.Spec.ExpirationPolicy == kclusterv1.ExpirationPolicyDeleteCluster {
+		logger.Info("KindCluster expired, deleting Cluster", "expires-at", expiresAt)
+		r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "Expired",
+			"KindCluster expired at %s, deleting Cluster %s", expiresAt, cluster.Name)
+		err := r.clusters.Delete(ctx, cluster)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Error(err, "failed to delete expired Cluster")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("KindCluster expired, deleting it", "expires-at", expiresAt)
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "Expired",
+		"KindCluster expired at %s, deleting it", expiresAt)
+	err := r.kindClusters.Delete(ctx, kindCluster)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "failed to delete expired KindCluster")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// warnBeforeExpiry emits a warning event once the KindCluster is about to
+// expire. The warning is emitted again if the lease is extended past the
+// time it was last emitted at.
+func (r *KindClusterReconciler) warnBeforeExpiry(kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) {
+	if status.ExpiresAt == nil {
+		return
+	}
+
+	now := time.Now()
+	warnAt := status.ExpiresAt.Add(-expiryWarningPeriod)
+	if now.Before(warnAt) {
+		return
+	}
+
+	if status.ExpiryWarningTime != nil && !status.ExpiryWarningTime.Time.Before(warnAt) {
+		return
+	}
+
+	r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "ExpiringSoon",
+		"KindCluster expires at %s, annotate it with %s to extend the lease",
+		status.ExpiresAt.UTC().Format(time.RFC3339), kclusterv1.ExtendLeaseAnnotation)
+	status.ExpiryWarningTime = &metav1.Time{Time: now}
+}
+
+// requeueAtExpiry makes sure the KindCluster is reconciled again when the
+// expiry warning is due and when it expires.
+func requeueAtExpiry(result ctrl.Result, kindCluster *kclusterv1.KindCluster) ctrl.Result {
+	expiresAt := kindCluster.GetExpiresAt()
+	if expiresAt == nil || (result.Requeue && result.RequeueAfter == 0) {
+		return result
+	}
+
+	next := time.Until(expiresAt.Time)
+	if untilWarning := next - expiryWarningPeriod; untilWarning > 0 {
+		next = untilWarning
+	}
+	if next <= 0 {
+		return result
+	}
+
+	if result.RequeueAfter == 0 || next < result.RequeueAfter {
+		result.RequeueAfter = next
+	}
+	return result
 }
 
 func (r *KindClusterReconciler) reconcileDeletion(ctx context.Context, kindCluster *kclusterv1.KindCluster) (ctrl.Result, error) {
@@ -133,19 +600,38 @@ func (r *KindClusterReconciler) reconcileDeletion(ctx context.Context, kindClust
 		return ctrl.Result{}, nil
 	}
 
+	// The webhook rejects deleting a protected KindCluster, but it can be
+	// bypassed, e.g. while it is not running. Turning the protection off
+	// reconciles the KindCluster again and the delete goes ahead.
+	if kindCluster.IsDeletionProtected() {
+		logger.Info("KindCluster is protected from deletion, keeping kind cluster")
+		r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "DeletionProtected",
+			"KindCluster is protected from deletion, set spec.deletionProtection to false and remove the %s annotation to delete kind cluster %q",
+			kclusterv1.DeletionProtectionAnnotation, kindCluster.Spec.Name)
+		return ctrl.Result{}, nil
+	}
+
 	status := &kclusterv1.KindClusterStatus{
-		Ready: false,
-		Phase: kclusterv1.ClusterPhaseDeleting,
+		Ready:   false,
+		Phase:   kclusterv1.ClusterPhaseDeleting,
+		HostRef: kindCluster.Status.HostRef,
 	}
 	r.updateStatus(logger, status, kindCluster)
 
-	err := r.clusterProvider.Delete(kindCluster)
-	if err != nil {
-		logger.Error(err, "failed to delete kind cluster")
-		return ctrl.Result{}, err
+	if kindCluster.Spec.DeletionPolicy == kclusterv1.DeletionPolicyRetain {
+		err := r.retainCluster(ctx, kindCluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else {
+		err := r.clusterProvider.Delete(kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to delete kind cluster")
+			return ctrl.Result{}, err
+		}
 	}
 
-	err = r.kindClusters.RemoveFinalizer(ctx, kindCluster)
+	err := r.kindClusters.RemoveFinalizer(ctx, kindCluster)
 	if err != nil {
 		logger.Error(err, "failed to remove finalizer")
 		return ctrl.Result{}, err
@@ -154,7 +640,25 @@ func (r *KindClusterReconciler) reconcileDeletion(ctx context.Context, kindClust
 	return ctrl.Result{}, nil
 }
 
-func (r *KindClusterReconciler) reconcileNormal(ctx context.Context, kindCluster *kclusterv1.KindCluster) (ctrl.Result, error) {
+// retainCluster releases the kind cluster of a KindCluster being deleted
+// instead of deleting it, so that it can still be inspected.
+func (r *KindClusterReconciler) retainCluster(ctx context.Context, kindCluster *kclusterv1.KindCluster) error {
+	logger := log.FromContext(ctx)
+
+	logger.Info("retaining kind cluster")
+	err := r.clusterProvider.ReleaseOwner(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to release kind cluster")
+		return err
+	}
+
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "Retained",
+		"Retained kind cluster %q, delete it with kind delete cluster --name %s",
+		kindCluster.Spec.Name, kindCluster.Spec.Name)
+	return nil
+}
+
+func (r *KindClusterReconciler) reconcileNormal(ctx context.Context, cluster *clusterv1.Cluster, kindCluster *kclusterv1.KindCluster) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	logger.Info("reconciling create")
@@ -164,17 +668,21 @@ func (r *KindClusterReconciler) reconcileNormal(ctx context.Context, kindCluster
 	// By default do not change the status - this is so we don't change the
 	// status in the event of an error. In this case we should requeue the
 	// event and try again.
-	status := &kclusterv1.KindClusterStatus{
-		Ready:          kindCluster.Status.Ready,
-		Phase:          kindCluster.Status.Phase,
-		FailureMessage: kindCluster.Status.FailureMessage,
-	}
+	status := kindCluster.Status.DeepCopy()
 	defer r.updateStatus(logger, status, kindCluster)
 
+	status.FailureDomains = failureDomains(kindCluster)
+	status.ExpiresAt = kindCluster.GetExpiresAt()
+	r.warnBeforeExpiry(kindCluster, status)
+
 	if kindCluster.Status.Phase == "" {
 		status.Ready = false
 		status.Phase = kclusterv1.ClusterPhasePending
 
+		if kindCluster.Spec.ControlPlaneEndpoint.IsValid() || kindCluster.Spec.PoolRef != nil {
+			return r.adoptCluster(ctx, kindCluster, status)
+		}
+
 		return ctrl.Result{}, nil
 	}
 
@@ -195,8 +703,8 @@ func (r *KindClusterReconciler) reconcileNormal(ctx context.Context, kindCluster
 		// there. This is because of a docker bug.
 		// See https://github.com/kubernetes-sigs/kind/issues/2530 and
 		// https://github.com/moby/moby/issues/40835
-		if status.FailureMessage == "" {
-			status.FailureMessage = existsErr.Error()
+		if status.FailureMessage == nil {
+			status.FailureMessage = ptr.To(existsErr.Error())
 		}
 
 		return ctrl.Result{}, existsErr
@@ -209,54 +717,541 @@ func (r *KindClusterReconciler) reconcileNormal(ctx context.Context, kindCluster
 		return ctrl.Result{}, nil
 	}
 
+	if kindCluster.Status.Phase == kclusterv1.ClusterPhaseSuspended {
+		if kindCluster.Spec.Suspended {
+			return ctrl.Result{}, nil
+		}
+		return r.resume(ctx, kindCluster, status)
+	}
+
+	if kindCluster.Spec.Suspended && createdCluster(kindCluster.Status.Phase) {
+		return r.suspend(ctx, kindCluster, status)
+	}
+
 	if kindCluster.Status.Phase == kclusterv1.ClusterPhaseProvisioned {
+		// The owner is recorded again after an adoption, as the UID of a
+		// KindCluster moved by clusterctl move changes.
+		err = r.clusterProvider.SetOwner(kindCluster, kindClusterOwner(kindCluster))
+		if err != nil {
+			logger.Error(err, "failed to record owner of kind cluster")
+			return ctrl.Result{}, err
+		}
+
+		kubeconfig, err := r.clusterProvider.GetKubeconfig(kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to get kubeconfig")
+			return ctrl.Result{}, err
+		}
+
 		logger.Info("setting control plane endpoint")
-		err = r.setControlPlaneEndpoint(ctx, logger, kindCluster)
+		endpoint, err := r.getControlPlaneEndpoint(kubeconfig)
+		if err != nil {
+			logger.Error(err, "failed to get control plane endpoint")
+			return ctrl.Result{}, err
+		}
+
+		err = r.setControlPlaneEndpoint(ctx, logger, kindCluster, endpoint)
 		if err != nil {
 			logger.Error(err, "failed to set control plane endpoint")
 			return ctrl.Result{}, err
 		}
 
+		err = r.storeKubeconfig(ctx, cluster, kindCluster, kubeconfig)
+		if err != nil {
+			logger.Error(err, "failed to store kubeconfig")
+			return ctrl.Result{}, err
+		}
+
 		status.Ready = true
 		status.Phase = kclusterv1.ClusterPhaseReady
+		setCondition(status, conditions.TrueCondition(kclusterv1.WorkloadAPIReachableCondition))
+		r.refreshClusterDetails(logger, kindCluster, status, kubeconfig)
 
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+	}
+
+	if kindCluster.Status.Phase == kclusterv1.ClusterPhaseReady {
+		// The control plane restarts while its certificates are renewed, so
+		// the kind cluster is not checked until the renewal finishes.
+		if r.isRenewing(kindCluster) {
+			logger.Info("certificates still renewing - skipping health check")
+			return ctrl.Result{RequeueAfter: certificateRenewalCheckInterval}, nil
+		}
+
+		// The kubeconfig is read from the control plane node once per
+		// health check and passed to everything reading from the API server.
+		kubeconfig := r.checkHealth(logger, kindCluster, status)
+		r.refreshClusterDetails(logger, kindCluster, status, kubeconfig)
+		if !status.Ready && kindCluster.Spec.Remediation != nil {
+			return r.remediate(ctx, kindCluster, status)
+		}
+		result := ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}
+		if !status.Ready {
+			return result, nil
+		}
+
+		if r.renewCertificates(logger, kindCluster, status, kubeconfig) {
+			return ctrl.Result{RequeueAfter: certificateRenewalCheckInterval}, nil
+		}
+
+		err = r.refreshKubeconfig(ctx, logger, cluster, kindCluster, kubeconfig)
+		if err != nil {
+			logger.Error(err, "failed to refresh kubeconfig")
+			return ctrl.Result{}, err
+		}
+
+		return r.suspendIfIdle(ctx, kindCluster, status, kubeconfig, result)
 	}
 
 	if kindCluster.Status.Phase == kclusterv1.ClusterPhasePending {
-		err := r.kindClusters.AddFinalizer(ctx, kindCluster)
+		controlPlane, err := r.clusters.GetControlPlane(ctx, cluster)
+		if err != nil {
+			logger.Error(err, "failed to get control plane")
+			return ctrl.Result{}, err
+		}
+
+		err = r.kindClusters.AddFinalizer(ctx, kindCluster)
 		if err != nil {
 			logger.Error(err, "failed to add finalizer")
 			return ctrl.Result{}, err
 		}
 
+		desired := withControlPlane(kindCluster, controlPlane)
+		if r.options.Quota != nil {
+			err = r.options.Quota.Check(ctx, desired)
+			if errors.Is(err, kclusterv1.ErrQuotaExceeded) {
+				logger.Info("kind cluster exceeds quota", "reason", err.Error())
+				setCondition(status, conditions.FalseCondition(kclusterv1.WithinQuotaCondition,
+					kclusterv1.QuotaExceededReason, clusterv1.ConditionSeverityWarning, "%v", err))
+				r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, kclusterv1.QuotaExceededReason,
+					"Not creating kind cluster: %v", err)
+				return ctrl.Result{RequeueAfter: quotaRetryInterval}, nil
+			}
+			if err != nil {
+				logger.Error(err, "failed to check quota")
+				return ctrl.Result{}, err
+			}
+			setCondition(status, conditions.TrueCondition(kclusterv1.WithinQuotaCondition))
+		}
+
+		if r.options.HostScheduler != nil && kindCluster.GetHostName() == "" {
+			host, err := r.options.HostScheduler.Schedule(ctx, desired)
+			if errors.Is(err, ErrInsufficientHostCapacity) {
+				logger.Info("no host has the capacity for the kind cluster")
+				setCondition(status, conditions.FalseCondition(kclusterv1.HostScheduledCondition,
+					kclusterv1.InsufficientHostCapacityReason, clusterv1.ConditionSeverityWarning, "%v", err))
+				r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, kclusterv1.InsufficientHostCapacityReason,
+					"Failed to schedule kind cluster: %v", err)
+				return ctrl.Result{RequeueAfter: hostSchedulingRetryInterval}, nil
+			}
+			if err != nil {
+				logger.Error(err, "failed to schedule kind cluster")
+				return ctrl.Result{}, err
+			}
+
+			logger.Info("scheduled kind cluster", "host", host.Name)
+			status.HostRef = &corev1.LocalObjectReference{Name: host.Name}
+			setCondition(status, conditions.TrueCondition(kclusterv1.HostScheduledCondition))
+			r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "HostScheduled",
+				"Scheduled kind cluster on host %s", host.Name)
+
+			desired = desired.DeepCopy()
+			desired.Status.HostRef = status.HostRef
+		}
+
 		status.Ready = false
 		status.Phase = kclusterv1.ClusterPhaseProvisioning
 
-		go r.createCluster(logger, kindCluster)
+		go r.createCluster(logger, kindCluster, desired)
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *KindClusterReconciler) createCluster(logger logr.Logger, kindCluster *kclusterv1.KindCluster) {
+// suspend stops the node containers of the kind cluster, keeping them so
+// that it can be resumed.
+func (r *KindClusterReconciler) suspend(ctx context.Context, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("suspending kind cluster")
+	err := r.clusterProvider.StopNodes(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to stop nodes")
+		return ctrl.Result{}, err
+	}
+
+	status.Ready = false
+	status.Phase = kclusterv1.ClusterPhaseSuspended
+	// The request count starts over with the API server, so it is not
+	// compared across a suspension.
+	status.Activity = nil
+	setCondition(status, conditions.FalseCondition(kclusterv1.WorkloadAPIReachableCondition,
+		kclusterv1.SuspendedReason, clusterv1.ConditionSeverityInfo, "kind cluster is suspended"))
+	r.refreshClusterDetails(logger, kindCluster, status, nil)
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "Suspended",
+		"Stopped the node containers of kind cluster %q", kindCluster.Spec.Name)
+
+	return ctrl.Result{}, nil
+}
+
+// suspendIfIdle suspends the kind cluster once its API server has served no
+// mutating requests for the idle timeout, recording when and why in its
+// annotations. Failing to tell whether it is idle does not fail the
+// reconcile. Otherwise the KindCluster is requeued no later than when it
+// would have been idle for the timeout.
+func (r *KindClusterReconciler) suspendIfIdle(ctx context.Context, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus, kubeconfig []byte, result ctrl.Result) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	timeout, err := r.idleTimeout(ctx, kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to get idle timeout")
+		return result, nil
+	}
+
+	if timeout == 0 {
+		status.Activity = nil
+		return result, nil
+	}
+
+	requests, err := r.clusterProvider.GetMutatingRequests(kubeconfig)
+	if err != nil {
+		logger.Error(err, "failed to get api server requests")
+		return result, nil
+	}
+
+	now := time.Now()
+	if status.Activity == nil || status.Activity.MutatingRequests != requests {
+		status.Activity = &kclusterv1.ActivityStatus{
+			MutatingRequests: requests,
+			LastActiveTime:   metav1.Time{Time: now},
+		}
+	}
+
+	idle := now.Sub(status.Activity.LastActiveTime.Time)
+	if remaining := timeout - idle; remaining > 0 {
+		if result.RequeueAfter == 0 || remaining < result.RequeueAfter {
+			result.RequeueAfter = remaining
+		}
+		return result, nil
+	}
+
+	reason := fmt.Sprintf("API server served no mutating requests for %s", idle.Round(time.Second))
+	logger.Info("suspending idle kind cluster", "reason", reason)
+	err = r.kindClusters.SuspendIdle(ctx, reason, kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to suspend idle kind cluster")
+		return ctrl.Result{}, err
+	}
+
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "IdleSuspended",
+		"Suspending kind cluster %q: %s", kindCluster.Spec.Name, reason)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// idleTimeout returns for how long the kind cluster may be idle before it is
+// suspended, as set by the kclusterv1.IdleTimeoutAnnotation of the
+// KindCluster, else of its namespace, else by the IdleTimeout option. Zero
+// never suspends it.
+func (r *KindClusterReconciler) idleTimeout(ctx context.Context, kindCluster *kclusterv1.KindCluster) (time.Duration, error) {
+	if value, ok := kindCluster.Annotations[kclusterv1.IdleTimeoutAnnotation]; ok {
+		return parseIdleTimeout(value)
+	}
+
+	if r.options.Namespaces != nil {
+		namespace, err := r.options.Namespaces.Get(ctx, kindCluster.Namespace)
+		if err != nil {
+			return 0, err
+		}
+
+		if value, ok := namespace.Annotations[kclusterv1.IdleTimeoutAnnotation]; ok {
+			return parseIdleTimeout(value)
+		}
+	}
+
+	return r.options.IdleTimeout, nil
+}
+
+func parseIdleTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation %q: %w", kclusterv1.IdleTimeoutAnnotation, value, err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid %s annotation %q: must not be negative", kclusterv1.IdleTimeoutAnnotation, value)
+	}
+
+	return timeout, nil
+}
+
+// resume starts the node containers of a suspended kind cluster and waits
+// for its API server to be ready. The port the API server is published on
+// can change when the containers are started again, so the kind cluster
+// then goes through the Provisioned phase to export its endpoint and
+// kubeconfig again.
+func (r *KindClusterReconciler) resume(ctx context.Context, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("resuming kind cluster")
+	err := r.clusterProvider.RestartNodes(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to start nodes")
+		return ctrl.Result{}, err
+	}
+
+	_, err = r.clusterProvider.CheckHealth(kindCluster)
+	if err != nil {
+		logger.Info("waiting for the resumed kind cluster", "reason", err.Error())
+		setCondition(status, conditions.FalseCondition(kclusterv1.WorkloadAPIReachableCondition,
+			kclusterv1.ResumingReason, clusterv1.ConditionSeverityInfo, "%v", err))
+		return ctrl.Result{RequeueAfter: resumeRetryInterval}, nil
+	}
+
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "Resumed",
+		"Started the node containers of kind cluster %q", kindCluster.Spec.Name)
+	status.Phase = kclusterv1.ClusterPhaseProvisioned
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// adoptCluster takes over the kind cluster of a KindCluster that was already
+// provisioned, but has lost its status. This is the case when it has been
+// moved to another management cluster by clusterctl move, or when it has
+// claimed a kind cluster from a KindClusterPool.
+func (r *KindClusterReconciler) adoptCluster(ctx context.Context, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	exists, err := r.clusterProvider.Exists(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to check if kind cluster exists")
+		return ctrl.Result{}, err
+	}
+
+	if !exists {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("adopting existing kind cluster")
+	err = r.kindClusters.AddFinalizer(ctx, kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to add finalizer")
+		return ctrl.Result{}, err
+	}
+
+	status.Phase = kclusterv1.ClusterPhaseProvisioned
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// withControlPlane returns the KindCluster to create the kind cluster from.
+// If the Cluster references a KindControlPlane its replicas and version
+// replace the control plane nodes and Kubernetes version of the KindCluster.
+func withControlPlane(kindCluster *kclusterv1.KindCluster, controlPlane *kclusterv1.KindControlPlane) *kclusterv1.KindCluster {
+	if controlPlane == nil {
+		return kindCluster
+	}
+
+	desired := kindCluster.DeepCopy()
+	desired.Spec.ControlPlaneNodes = controlPlane.GetReplicas()
+	desired.Spec.KubernetesVersion = controlPlane.Spec.Version
+	return desired
+}
+
+func (r *KindClusterReconciler) createCluster(logger logr.Logger, kindCluster, desired *kclusterv1.KindCluster) {
 	logger.Info("starting cluster creation")
 	defer logger.Info("cluster created")
 
-	status := &kclusterv1.KindClusterStatus{
-		Ready: false,
-		Phase: kclusterv1.ClusterPhaseProvisioned,
-	}
+	status := desired.Status.DeepCopy()
+	status.Ready = false
+	status.Phase = kclusterv1.ClusterPhaseProvisioned
+	status.FailureMessage = nil
 	defer r.updateStatus(logger, status, kindCluster)
 
-	err := r.clusterProvider.Create(kindCluster)
+	err := r.clusterProvider.Create(desired, kindClusterOwner(kindCluster))
 	if err != nil {
 		status.Phase = kclusterv1.ClusterPhasePending
-		status.FailureMessage = fmt.Sprintf("failed to create cluster: %v", err)
+		status.FailureMessage = ptr.To(fmt.Sprintf("failed to create cluster: %v", err))
 		logger.Error(err, "failed to create cluster")
+		if errors.Is(err, kclusterv1.ErrKindClusterExists) {
+			// The nodes are not from this create, so they are left alone.
+			return
+		}
+		r.captureDiagnostics(logger, desired, status)
+		return
+	}
+}
+
+// captureDiagnostics stores the logs of the nodes kind retained after a
+// failed create and then deletes the nodes, so that the next attempt does
+// not find a half created cluster. It is only called if the create failed
+// after kind found no cluster with the name, so the nodes are from this
+// create.
+func (r *KindClusterReconciler) captureDiagnostics(logger logr.Logger, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) {
+	defer func() {
+		err := r.clusterProvider.Delete(kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to delete nodes retained after failed create")
+		}
+	}()
+
+	if r.diagnostics == nil {
+		return
+	}
+
+	archive, err := r.clusterProvider.CollectDiagnostics(kindCluster, r.options.DiagnosticsMaxSize)
+	if err != nil {
+		logger.Error(err, "failed to collect diagnostics")
+		return
+	}
+
+	if archive == nil {
+		logger.Info("no nodes were retained, skipping diagnostics")
+		return
+	}
+
+	reference, err := r.diagnostics.Store(context.Background(), kindCluster, archive)
+	if err != nil {
+		logger.Error(err, "failed to store diagnostics")
+		return
+	}
+
+	logger.Info("stored diagnostics", "storage", reference.Storage, "size", reference.Size)
+	status.Diagnostics = reference
+}
+
+// checkHealth flips the Ready flag of an already Ready cluster depending on
+// whether its node containers and API server are up, and returns the
+// kubeconfig of a healthy cluster.
+func (r *KindClusterReconciler) checkHealth(logger logr.Logger, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) []byte {
+	kubeconfig, err := r.clusterProvider.CheckHealth(kindCluster)
+	if err != nil {
+		logger.Info("workload cluster is unreachable", "reason", err.Error())
+		status.Ready = false
+		setCondition(status, conditions.FalseCondition(
+			kclusterv1.WorkloadAPIReachableCondition,
+			kclusterv1.WorkloadAPIUnreachableReason,
+			clusterv1.ConditionSeverityError,
+			"%v", err,
+		))
+		return nil
+	}
+
+	if !kindCluster.Status.Ready {
+		logger.Info("workload cluster is reachable again")
+	}
+	status.Ready = true
+	setCondition(status, conditions.TrueCondition(kclusterv1.WorkloadAPIReachableCondition))
+
+	// Reset the remediation attempts once the cluster has stayed healthy for
+	// a full cooldown period after the last attempt.
+	remediation := status.Remediation
+	if remediation != nil && remediation.Attempts > 0 && kindCluster.Spec.Remediation != nil {
+		cooldown := kindCluster.Spec.Remediation.GetCooldown()
+		if remediation.LastAttemptTime == nil || time.Since(remediation.LastAttemptTime.Time) >= cooldown {
+			remediation.Attempts = 0
+			remediation.Exhausted = false
+		}
+	}
+
+	return kubeconfig
+}
+
+// refreshClusterDetails records the nodes of the kind cluster and, if the
+// cluster is ready, the Kubernetes version its API server reports with the
+// kubeconfig in the status. The details are informational, so failing to
+// read them does not fail the reconcile.
+func (r *KindClusterReconciler) refreshClusterDetails(logger logr.Logger, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus, kubeconfig []byte) {
+	nodes, err := r.clusterProvider.GetNodes(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to get nodes")
+	} else {
+		status.Nodes = nodes
+		status.NodeCount = int32(len(nodes)) //nolint:gosec
+	}
+
+	if !status.Ready {
 		return
 	}
+
+	version, err := r.clusterProvider.GetKubernetesVersion(kubeconfig)
+	if err != nil {
+		logger.Error(err, "failed to get kubernetes version")
+		return
+	}
+	status.KubernetesVersion = version
+}
+
+// remediate restarts the stopped node containers of an unhealthy cluster or,
+// if that has already been tried, deletes the kind cluster so that it is
+// created again.
+func (r *KindClusterReconciler) remediate(ctx context.Context, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	policy := kindCluster.Spec.Remediation
+	now := time.Now()
+
+	if status.Remediation == nil {
+		status.Remediation = &kclusterv1.RemediationStatus{}
+	}
+	remediation := status.Remediation
+
+	unhealthySince := getCondition(status, kclusterv1.WorkloadAPIReachableCondition).LastTransitionTime
+	if wait := unhealthySince.Add(policy.GetUnhealthyThreshold()).Sub(now); wait > 0 {
+		logger.Info("waiting for unhealthy threshold before remediating", "wait", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	if remediation.Attempts >= policy.GetMaxAttempts() {
+		if !remediation.Exhausted {
+			logger.Info("remediation attempts exhausted", "attempts", remediation.Attempts)
+			r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "RemediationExhausted",
+				"Giving up on remediating kind cluster %q after %d attempts", kindCluster.Spec.Name, remediation.Attempts)
+			remediation.Exhausted = true
+		}
+		return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+	}
+
+	if remediation.LastAttemptTime != nil {
+		if wait := remediation.LastAttemptTime.Add(policy.GetCooldown()).Sub(now); wait > 0 {
+			logger.Info("waiting for remediation cooldown", "wait", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+	}
+
+	action := kclusterv1.RemediationActionRestartNodes
+	if remediation.Attempts > 0 {
+		action = kclusterv1.RemediationActionRecreate
+	}
+
+	remediation.Attempts++
+	remediation.LastAction = action
+	remediation.LastAttemptTime = &metav1.Time{Time: now}
+
+	logger.Info("remediating cluster", "action", action, "attempt", remediation.Attempts)
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "Remediating",
+		"Remediating kind cluster %q with action %s (attempt %d/%d)",
+		kindCluster.Spec.Name, action, remediation.Attempts, policy.GetMaxAttempts())
+
+	if action == kclusterv1.RemediationActionRestartNodes {
+		err := r.clusterProvider.RestartNodes(kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to restart nodes")
+			r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "RemediationFailed",
+				"Failed to restart nodes of kind cluster %q: %v", kindCluster.Spec.Name, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+	}
+
+	err := r.clusterProvider.Delete(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to delete kind cluster for recreation")
+		r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "RemediationFailed",
+			"Failed to delete kind cluster %q for recreation: %v", kindCluster.Spec.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	status.Ready = false
+	status.Phase = kclusterv1.ClusterPhasePending
+
+	return ctrl.Result{Requeue: true}, nil
 }
 
 func (r *KindClusterReconciler) updateStatus(logger logr.Logger, status *kclusterv1.KindClusterStatus, kindCluster *kclusterv1.KindCluster) {
@@ -266,19 +1261,205 @@ func (r *KindClusterReconciler) updateStatus(logger logr.Logger, status *kcluste
 	}
 }
 
-func (r *KindClusterReconciler) setControlPlaneEndpoint(ctx context.Context, logger logr.Logger, kindCluster *kclusterv1.KindCluster) error {
-	host, port, err := r.clusterProvider.GetControlPlaneEndpoint(kindCluster)
+func (r *KindClusterReconciler) storeKubeconfig(ctx context.Context, cluster *clusterv1.Cluster, kindCluster *kclusterv1.KindCluster, kubeconfig []byte) error {
+	err := r.clusterProvider.ExportKubeconfig(kindCluster, kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	return r.kubeconfigs.Store(ctx, cluster, kubeconfig)
+}
+
+// refreshKubeconfig exports the kubeconfig of a Ready kind cluster again,
+// as the port its API server is published on changes when docker restarts
+// and its admin client certificate changes when it is renewed. The control
+// plane endpoint is only updated if it changed, and the Secret is only
+// written if the kubeconfig changed, like the file in the kubeconfig
+// directory.
+func (r *KindClusterReconciler) refreshKubeconfig(ctx context.Context, logger logr.Logger, cluster *clusterv1.Cluster, kindCluster *kclusterv1.KindCluster, kubeconfig []byte) error {
+	endpoint, err := r.getControlPlaneEndpoint(kubeconfig)
 	if err != nil {
 		return err
 	}
 
-	endpoint := kclusterv1.APIEndpoint{
+	if kindCluster.Spec.ControlPlaneEndpoint != endpoint {
+		err = r.setControlPlaneEndpoint(ctx, logger, kindCluster, endpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.storeKubeconfig(ctx, cluster, kindCluster, kubeconfig)
+}
+
+// renewCertificates records when the certificates of the control plane of
+// the kind cluster expire and starts renewing them in the background once
+// they expire within CertificateRenewBefore. It returns whether a renewal
+// was started. Failing to read the expiry does not fail the reconcile, as it
+// is checked again with the next health check.
+func (r *KindClusterReconciler) renewCertificates(logger logr.Logger, kindCluster *kclusterv1.KindCluster, status *kclusterv1.KindClusterStatus, kubeconfig []byte) bool {
+	expiry, err := r.clusterProvider.GetCertificateExpiry(kindCluster, kubeconfig)
+	if err != nil {
+		logger.Error(err, "failed to get certificate expiry")
+		return false
+	}
+	status.CertificatesExpireAt = &metav1.Time{Time: expiry}
+
+	renewBefore := r.options.CertificateRenewBefore
+	if renewBefore <= 0 || time.Until(expiry) > renewBefore {
+		return false
+	}
+
+	if !r.startRenewing(kindCluster) {
+		return false
+	}
+
+	logger.Info("renewing certificates", "expiresAt", expiry)
+	go r.renewCertificatesInBackground(logger, kindCluster.DeepCopy(), expiry)
+	return true
+}
+
+// renewCertificatesInBackground renews the certificates of the kind
+// cluster, which restarts its control plane one node at a time. The new
+// expiry and kubeconfig are recorded by the first health check after it
+// finishes. A failed renewal is tried again by the next health check.
+func (r *KindClusterReconciler) renewCertificatesInBackground(logger logr.Logger, kindCluster *kclusterv1.KindCluster, expiry time.Time) {
+	defer r.doneRenewing(kindCluster)
+
+	err := r.clusterProvider.RenewCertificates(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to renew certificates")
+		r.recorder.Eventf(kindCluster, corev1.EventTypeWarning, "CertificateRenewalFailed",
+			"Failed to renew certificates of kind cluster %q: %v", kindCluster.Spec.Name, err)
+		return
+	}
+
+	logger.Info("renewed certificates")
+	r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "CertificatesRenewed",
+		"Renewed certificates of kind cluster %q expiring at %s", kindCluster.Spec.Name, expiry.UTC().Format(time.RFC3339))
+}
+
+// startRenewing records that the certificates of the KindCluster are being
+// renewed and returns false if they already are.
+func (r *KindClusterReconciler) startRenewing(kindCluster *kclusterv1.KindCluster) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := client.ObjectKeyFromObject(kindCluster)
+	if _, ok := r.renewing[key]; ok {
+		return false
+	}
+	r.renewing[key] = struct{}{}
+	return true
+}
+
+func (r *KindClusterReconciler) doneRenewing(kindCluster *kclusterv1.KindCluster) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.renewing, client.ObjectKeyFromObject(kindCluster))
+}
+
+func (r *KindClusterReconciler) isRenewing(kindCluster *kclusterv1.KindCluster) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.renewing[client.ObjectKeyFromObject(kindCluster)]
+	return ok
+}
+
+func (r *KindClusterReconciler) getControlPlaneEndpoint(kubeconfig []byte) (clusterv1.APIEndpoint, error) {
+	host, port, err := r.clusterProvider.GetControlPlaneEndpoint(kubeconfig)
+	if err != nil {
+		return clusterv1.APIEndpoint{}, err
+	}
+
+	return clusterv1.APIEndpoint{
 		Host: host,
 		Port: port,
+	}, nil
+}
+
+func (r *KindClusterReconciler) setControlPlaneEndpoint(ctx context.Context, logger logr.Logger, kindCluster *kclusterv1.KindCluster, endpoint clusterv1.APIEndpoint) error {
+	previous := kindCluster.Spec.ControlPlaneEndpoint
+	if previous.IsValid() && previous != endpoint {
+		logger.Info("control plane endpoint changed", "previous", previous.String(), "endpoint", endpoint.String())
+		r.recorder.Eventf(kindCluster, corev1.EventTypeNormal, "ControlPlaneEndpointChanged",
+			"Control plane endpoint changed from %s to %s", previous.String(), endpoint.String())
 	}
+
 	return r.kindClusters.SetControlPlaneEndpoint(ctx, endpoint, kindCluster)
 }
 
+// kindClusterOwner returns the owner recorded in the kind cluster of the
+// KindCluster.
+func kindClusterOwner(kindCluster *kclusterv1.KindCluster) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: kclusterv1.GroupVersion.String(),
+		Kind:       "KindCluster",
+		Namespace:  kindCluster.Namespace,
+		Name:       kindCluster.Name,
+		UID:        kindCluster.UID,
+	}
+}
+
+// failureDomains returns the failure domains of the KindCluster in the form
+// Cluster API expects them in the status.
+func failureDomains(kindCluster *kclusterv1.KindCluster) clusterv1.FailureDomains {
+	if len(kindCluster.Spec.FailureDomains) == 0 {
+		return nil
+	}
+
+	failureDomains := clusterv1.FailureDomains{}
+	for _, failureDomain := range kindCluster.Spec.FailureDomains {
+		failureDomains[failureDomain.Name] = clusterv1.FailureDomainSpec{
+			ControlPlane: failureDomain.ControlPlane,
+			Attributes:   failureDomain.Attributes,
+		}
+	}
+
+	return failureDomains
+}
+
+// setCondition sets the condition on the status. Unlike conditions.Set the
+// transition time is preserved as long as the condition status does not
+// change, even if the reason or message do, so that it can be used to tell
+// for how long a cluster has been unhealthy.
+func setCondition(status *kclusterv1.KindClusterStatus, condition *clusterv1.Condition) {
+	holder := &kclusterv1.KindCluster{Status: *status}
+	if existing := conditions.Get(holder, condition.Type); existing != nil && existing.Status == condition.Status {
+		conditions.Delete(holder, condition.Type)
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	conditions.Set(holder, condition)
+	*status = holder.Status
+}
+
+func getCondition(status *kclusterv1.KindClusterStatus, conditionType clusterv1.ConditionType) *clusterv1.Condition {
+	condition := conditions.Get(&kclusterv1.KindCluster{Status: *status}, conditionType)
+	if condition == nil {
+		return &clusterv1.Condition{Type: conditionType}
+	}
+	return condition
+}
+
+// generateName returns the kind cluster name for a KindCluster that was
+// created without one, e.g. from a KindClusterTemplate. The name is prefixed
+// with the namespace and name of the KindCluster and suffixed with a hash
+// including its UID, so KindClusters with the same name in different
+// namespaces or recreated under the same name never share a kind cluster.
+func generateName(kindCluster *kclusterv1.KindCluster) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", kindCluster.Namespace, kindCluster.Name, kindCluster.UID)))
+	suffix := hex.EncodeToString(hash[:])[:generatedNameHashLength]
+
+	prefix := fmt.Sprintf("%s-%s", kindCluster.Namespace, kindCluster.Name)
+	maxPrefixLength := maxKindClusterNameLength - generatedNameHashLength - 1
+	if len(prefix) > maxPrefixLength {
+		prefix = strings.TrimRight(prefix[:maxPrefixLength], "-.")
+	}
+
+	return fmt.Sprintf("%s-%s", prefix, suffix)
+}
+
 func createdCluster(phase kclusterv1.ClusterPhase) bool {
-	return phase == kclusterv1.ClusterPhaseProvisioned || phase == kclusterv1.ClusterPhaseReady
+	return phase == kclusterv1.ClusterPhaseProvisioned || phase == kclusterv1.ClusterPhaseReady ||
+		phase == kclusterv1.ClusterPhaseSuspended
 }
@@ -3,18 +3,25 @@ package controllers_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/controllers"
 	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
 	"github.com/mnitchev/cluster-api-provider-kind/k8s"
@@ -26,6 +33,9 @@ var _ = Describe("KindclusterController", func() {
 		clusterProvider   *controllersfakes.FakeClusterProvider
 		kindClusterClient *controllersfakes.FakeKindClusterClient
 		clusterClient     *controllersfakes.FakeClusterClient
+		recorder          *record.FakeRecorder
+		diagnosticsStore  *controllersfakes.FakeDiagnosticsStore
+		kubeconfigStore   *controllersfakes.FakeKubeconfigStore
 		ctx               context.Context
 		result            ctrl.Result
 		reconcileErr      error
@@ -38,7 +48,13 @@ var _ = Describe("KindclusterController", func() {
 		clusterProvider = new(controllersfakes.FakeClusterProvider)
 		clusterClient = new(controllersfakes.FakeClusterClient)
 		kindClusterClient = new(controllersfakes.FakeKindClusterClient)
-		reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider)
+		recorder = record.NewFakeRecorder(10)
+		diagnosticsStore = new(controllersfakes.FakeDiagnosticsStore)
+		kubeconfigStore = new(controllersfakes.FakeKubeconfigStore)
+		reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+			HealthCheckInterval: time.Minute,
+			DiagnosticsMaxSize:  1024,
+		})
 
 		kindCluster = &kclusterv1.KindCluster{
 			ObjectMeta: metav1.ObjectMeta{
@@ -59,6 +75,19 @@ var _ = Describe("KindclusterController", func() {
 		}
 		clusterClient.GetReturns(cluster, nil)
 		clusterProvider.GetControlPlaneEndpointReturns("127.0.0.1", 1337, nil)
+		clusterProvider.GetNodesReturns([]kclusterv1.NodeStatus{
+			{
+				Name:        "the-kind-cluster-name-control-plane",
+				Role:        "control-plane",
+				ContainerID: "abc123",
+				Image:       "kindest/node:v1.31.0",
+				InternalIPs: []string{"172.18.0.2"},
+				State:       "running",
+			},
+		}, nil)
+		clusterProvider.GetKubernetesVersionReturns("v1.31.0", nil)
+		clusterProvider.GetKubeconfigReturns([]byte("the-kubeconfig"), nil)
+		clusterProvider.CheckHealthReturns([]byte("the-kubeconfig"), nil)
 	})
 
 	JustBeforeEach(func() {
@@ -102,6 +131,28 @@ var _ = Describe("KindclusterController", func() {
 		Expect(actualCluster).To(Equal(kindCluster))
 	})
 
+	It("does not publish failure domains", func() {
+		_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+		Expect(actualStatus.FailureDomains).To(BeNil())
+	})
+
+	When("the KindCluster has failure domains", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.FailureDomains = []kclusterv1.FailureDomain{
+				{Name: "zone-a", ControlPlane: true, Attributes: map[string]string{"rack": "1"}},
+				{Name: "zone-b", Network: "zone-b-network"},
+			}
+		})
+
+		It("publishes them in the status", func() {
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.FailureDomains).To(Equal(clusterv1.FailureDomains{
+				"zone-a": clusterv1.FailureDomainSpec{ControlPlane: true, Attributes: map[string]string{"rack": "1"}},
+				"zone-b": clusterv1.FailureDomainSpec{},
+			}))
+		})
+	})
+
 	When("getting the kind cluster fails", func() {
 		BeforeEach(func() {
 			kindClusterClient.GetReturns(nil, errors.New("boom"))
@@ -138,6 +189,80 @@ var _ = Describe("KindclusterController", func() {
 		})
 	})
 
+	When("the Cluster is paused", func() {
+		BeforeEach(func() {
+			cluster.Spec.Paused = true
+		})
+
+		It("does not reconcile the KindCluster", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(0))
+			Expect(clusterProvider.ExistsCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the KindCluster has the paused annotation", func() {
+		BeforeEach(func() {
+			kindCluster.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+		})
+
+		It("does not reconcile the KindCluster", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+
+		When("the KindCluster has no kind cluster name yet", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.Name = ""
+			})
+
+			It("does not generate one", func() {
+				Expect(kindClusterClient.SetNameCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	When("the KindCluster was already provisioned but has lost its status", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 1337}
+			clusterProvider.ExistsReturns(true, nil)
+		})
+
+		It("adopts the existing kind cluster", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+			Expect(clusterProvider.CreateCallCount()).To(Equal(0))
+			Expect(kindClusterClient.AddFinalizerCallCount()).To(Equal(1))
+
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioned))
+		})
+
+		When("the kind cluster does not exist", func() {
+			BeforeEach(func() {
+				clusterProvider.ExistsReturns(false, nil)
+			})
+
+			It("sets the phase to pending", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(kindClusterClient.AddFinalizerCallCount()).To(Equal(0))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+			})
+		})
+
+		When("checking if the kind cluster exists fails", func() {
+			BeforeEach(func() {
+				clusterProvider.ExistsReturns(false, errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
 	When("getting the owner cluster fails", func() {
 		BeforeEach(func() {
 			clusterClient.GetReturns(nil, errors.New("boom"))
@@ -148,6 +273,88 @@ var _ = Describe("KindclusterController", func() {
 		})
 	})
 
+	When("the KindCluster has no kind cluster name", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.Name = ""
+			kindClusterClient.SetNameStub = func(_ context.Context, name string, kindCluster *kclusterv1.KindCluster) error {
+				kindCluster.Spec.Name = name
+				return nil
+			}
+		})
+
+		It("generates one from the namespace, name and UID", func() {
+			Expect(kindClusterClient.SetNameCallCount()).To(Equal(1))
+			_, actualName, actualCluster := kindClusterClient.SetNameArgsForCall(0)
+			Expect(actualName).To(MatchRegexp(`^bar-foo-[0-9a-f]{8}$`))
+			Expect(actualCluster).To(Equal(kindCluster))
+		})
+
+		It("continues reconciling with the generated name", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
+			_, _, actualCluster := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualCluster.Spec.Name).To(HavePrefix("bar-foo-"))
+		})
+
+		It("generates the same name for the same KindCluster", func() {
+			kindCluster.Spec.Name = ""
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kindClusterClient.SetNameCallCount()).To(Equal(2))
+			_, firstName, _ := kindClusterClient.SetNameArgsForCall(0)
+			_, secondName, _ := kindClusterClient.SetNameArgsForCall(1)
+			Expect(secondName).To(Equal(firstName))
+		})
+
+		It("generates a different name for a recreated KindCluster", func() {
+			kindCluster.Spec.Name = ""
+			kindCluster.UID = "another-uid"
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kindClusterClient.SetNameCallCount()).To(Equal(2))
+			_, firstName, _ := kindClusterClient.SetNameArgsForCall(0)
+			_, secondName, _ := kindClusterClient.SetNameArgsForCall(1)
+			Expect(secondName).NotTo(Equal(firstName))
+		})
+
+		When("the namespace and name are long", func() {
+			BeforeEach(func() {
+				kindCluster.Namespace = strings.Repeat("a", 30)
+				kindCluster.Name = strings.Repeat("b", 40)
+			})
+
+			It("limits the length of the generated name", func() {
+				Expect(kindClusterClient.SetNameCallCount()).To(Equal(1))
+				_, actualName, _ := kindClusterClient.SetNameArgsForCall(0)
+				Expect(actualName).To(HaveLen(50))
+				Expect(actualName).To(MatchRegexp(`^a{30}-b{10}-[0-9a-f]{8}$`))
+			})
+		})
+
+		When("setting the name fails", func() {
+			BeforeEach(func() {
+				kindClusterClient.SetNameStub = nil
+				kindClusterClient.SetNameReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+
+			It("does not update the status", func() {
+				Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	When("the KindCluster has a kind cluster name", func() {
+		It("does not change it", func() {
+			Expect(kindClusterClient.SetNameCallCount()).To(Equal(0))
+		})
+	})
+
 	Describe("Phase Pending", func() {
 		BeforeEach(func() {
 			kindCluster.Status.Ready = false
@@ -180,10 +387,231 @@ var _ = Describe("KindclusterController", func() {
 		It("creates a cluster using the cluster provider", func() {
 			// use eventually as the implementation starts a go routine
 			Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
-			actualCluster := clusterProvider.CreateArgsForCall(0)
+			actualCluster, _ := clusterProvider.CreateArgsForCall(0)
 			Expect(actualCluster).To(Equal(kindCluster))
 		})
 
+		It("records the KindCluster as the owner while creating the cluster", func() {
+			Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+			_, owner := clusterProvider.CreateArgsForCall(0)
+			Expect(owner.Kind).To(Equal("KindCluster"))
+			Expect(owner.Namespace).To(Equal(kindCluster.Namespace))
+			Expect(owner.Name).To(Equal(kindCluster.Name))
+			Expect(owner.UID).To(Equal(kindCluster.UID))
+		})
+
+		When("the Cluster references a KindControlPlane", func() {
+			BeforeEach(func() {
+				clusterClient.GetControlPlaneReturns(&kclusterv1.KindControlPlane{
+					Spec: kclusterv1.KindControlPlaneSpec{
+						Replicas: ptr.To[int32](3),
+						Version:  "v1.30.0",
+					},
+				}, nil)
+			})
+
+			It("creates the cluster with the replicas and version of the control plane", func() {
+				Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+				actualCluster, _ := clusterProvider.CreateArgsForCall(0)
+				Expect(actualCluster.Spec.Name).To(Equal("the-kind-cluster-name"))
+				Expect(actualCluster.Spec.ControlPlaneNodes).To(Equal(int32(3)))
+				Expect(actualCluster.Spec.KubernetesVersion).To(Equal("v1.30.0"))
+			})
+
+			It("does not change the KindCluster", func() {
+				Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+				Expect(kindCluster.Spec.ControlPlaneNodes).To(Equal(int32(0)))
+			})
+		})
+
+		When("getting the control plane fails", func() {
+			BeforeEach(func() {
+				clusterClient.GetControlPlaneReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+
+			It("does not try to create the cluster", func() {
+				Expect(clusterProvider.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		When("a quota is configured", func() {
+			var quota *controllersfakes.FakeQuotaChecker
+
+			BeforeEach(func() {
+				quota = new(controllersfakes.FakeQuotaChecker)
+				reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+					HealthCheckInterval: time.Minute,
+					Quota:               quota,
+				})
+			})
+
+			It("checks the kind cluster against the quota", func() {
+				Expect(quota.CheckCallCount()).To(Equal(1))
+				_, actualCluster := quota.CheckArgsForCall(0)
+				Expect(actualCluster).To(Equal(kindCluster))
+			})
+
+			It("marks the kind cluster within quota and creates it", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioning))
+				Expect(conditions.IsTrue(&kclusterv1.KindCluster{Status: actualStatus}, kclusterv1.WithinQuotaCondition)).To(BeTrue())
+				Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+			})
+
+			When("the kind cluster exceeds the quota", func() {
+				BeforeEach(func() {
+					quota.CheckReturns(fmt.Errorf("%w: 6 KindClusters exceed the limit of 5", kclusterv1.ErrQuotaExceeded))
+				})
+
+				It("stays pending and checks again later", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+
+					condition := conditions.Get(&kclusterv1.KindCluster{Status: actualStatus}, kclusterv1.WithinQuotaCondition)
+					Expect(condition).NotTo(BeNil())
+					Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(kclusterv1.QuotaExceededReason))
+					Expect(condition.Message).To(ContainSubstring("6 KindClusters exceed the limit of 5"))
+				})
+
+				It("emits a QuotaExceeded warning", func() {
+					Expect(recorder.Events).To(Receive(ContainSubstring("Warning QuotaExceeded")))
+				})
+
+				It("does not create the cluster", func() {
+					Consistently(clusterProvider.CreateCallCount).Should(Equal(0))
+				})
+			})
+
+			When("checking the quota fails", func() {
+				BeforeEach(func() {
+					quota.CheckReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError("boom"))
+					Expect(clusterProvider.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		When("a host scheduler is configured", func() {
+			var hostScheduler *controllersfakes.FakeHostScheduler
+
+			BeforeEach(func() {
+				hostScheduler = new(controllersfakes.FakeHostScheduler)
+				hostScheduler.ScheduleReturns(&kclusterv1.KindHost{
+					ObjectMeta: metav1.ObjectMeta{Name: "remote"},
+				}, nil)
+				reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+					HealthCheckInterval: time.Minute,
+					HostScheduler:       hostScheduler,
+				})
+			})
+
+			It("schedules the kind cluster", func() {
+				Expect(hostScheduler.ScheduleCallCount()).To(Equal(1))
+				_, actualCluster := hostScheduler.ScheduleArgsForCall(0)
+				Expect(actualCluster).To(Equal(kindCluster))
+			})
+
+			It("records the host in the status", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioning))
+				Expect(actualStatus.HostRef).To(Equal(&corev1.LocalObjectReference{Name: "remote"}))
+				Expect(conditions.IsTrue(&kclusterv1.KindCluster{Status: actualStatus}, kclusterv1.HostScheduledCondition)).To(BeTrue())
+			})
+
+			It("emits a HostScheduled event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("HostScheduled")))
+			})
+
+			It("creates the cluster on the host", func() {
+				Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+				actualCluster, _ := clusterProvider.CreateArgsForCall(0)
+				Expect(actualCluster.GetHostName()).To(Equal("remote"))
+			})
+
+			It("keeps the host in the status after create finishes", func() {
+				Eventually(kindClusterClient.UpdateStatusCallCount).Should(Equal(2))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(1)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioned))
+				Expect(actualStatus.HostRef).To(Equal(&corev1.LocalObjectReference{Name: "remote"}))
+			})
+
+			When("the KindCluster references a host", func() {
+				BeforeEach(func() {
+					kindCluster.Spec.HostRef = &corev1.LocalObjectReference{Name: "explicit"}
+				})
+
+				It("does not schedule the kind cluster", func() {
+					Expect(hostScheduler.ScheduleCallCount()).To(Equal(0))
+					Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+					actualCluster, _ := clusterProvider.CreateArgsForCall(0)
+					Expect(actualCluster.GetHostName()).To(Equal("explicit"))
+				})
+			})
+
+			When("the kind cluster was already scheduled", func() {
+				BeforeEach(func() {
+					kindCluster.Status.HostRef = &corev1.LocalObjectReference{Name: "previous"}
+				})
+
+				It("keeps the host", func() {
+					Expect(hostScheduler.ScheduleCallCount()).To(Equal(0))
+					Eventually(clusterProvider.CreateCallCount).Should(Equal(1))
+					actualCluster, _ := clusterProvider.CreateArgsForCall(0)
+					Expect(actualCluster.GetHostName()).To(Equal("previous"))
+				})
+			})
+
+			When("no host has the capacity for the kind cluster", func() {
+				BeforeEach(func() {
+					hostScheduler.ScheduleReturns(nil, controllers.ErrInsufficientHostCapacity)
+				})
+
+				It("stays pending and retries later", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+					Expect(actualStatus.HostRef).To(BeNil())
+
+					condition := conditions.Get(&kclusterv1.KindCluster{Status: actualStatus}, kclusterv1.HostScheduledCondition)
+					Expect(condition).NotTo(BeNil())
+					Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+					Expect(condition.Reason).To(Equal(kclusterv1.InsufficientHostCapacityReason))
+				})
+
+				It("emits an InsufficientHostCapacity warning", func() {
+					Expect(recorder.Events).To(Receive(ContainSubstring("Warning InsufficientHostCapacity")))
+				})
+
+				It("does not create the cluster", func() {
+					Consistently(clusterProvider.CreateCallCount).Should(Equal(0))
+				})
+			})
+
+			When("scheduling fails", func() {
+				BeforeEach(func() {
+					hostScheduler.ScheduleReturns(nil, errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError("boom"))
+					Expect(clusterProvider.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
 		It("updates the status to provisioned after create finishes", func() {
 			Eventually(kindClusterClient.UpdateStatusCallCount).Should(Equal(2))
 			_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(1)
@@ -242,8 +670,82 @@ var _ = Describe("KindclusterController", func() {
 				_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(1)
 				Expect(actualStatus.Ready).To(BeFalse())
 				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
-				Expect(actualStatus.FailureMessage).To(Equal("failed to create cluster: boom"))
+				Expect(actualStatus.FailureMessage).To(HaveValue(Equal("failed to create cluster: boom")))
+				Expect(actualCluster).To(Equal(kindCluster))
+			})
+
+			It("collects the diagnostics from the retained nodes", func() {
+				Eventually(clusterProvider.CollectDiagnosticsCallCount).Should(Equal(1))
+				actualCluster, actualMaxSize := clusterProvider.CollectDiagnosticsArgsForCall(0)
 				Expect(actualCluster).To(Equal(kindCluster))
+				Expect(actualMaxSize).To(Equal(1024))
+			})
+
+			It("deletes the retained nodes", func() {
+				Eventually(clusterProvider.DeleteCallCount).Should(Equal(1))
+				Expect(clusterProvider.DeleteArgsForCall(0)).To(Equal(kindCluster))
+			})
+
+			When("diagnostics were collected", func() {
+				BeforeEach(func() {
+					clusterProvider.CollectDiagnosticsReturns([]byte("logs"), nil)
+					diagnosticsStore.StoreReturns(&kclusterv1.DiagnosticsReference{
+						Storage: kclusterv1.DiagnosticsStorageSecret,
+						Name:    "foo-diagnostics",
+						Size:    4,
+					}, nil)
+				})
+
+				It("stores the diagnostics", func() {
+					Eventually(diagnosticsStore.StoreCallCount).Should(Equal(1))
+					_, actualCluster, actualArchive := diagnosticsStore.StoreArgsForCall(0)
+					Expect(actualCluster).To(Equal(kindCluster))
+					Expect(actualArchive).To(Equal([]byte("logs")))
+				})
+
+				It("references the diagnostics in the status", func() {
+					Eventually(kindClusterClient.UpdateStatusCallCount).Should(Equal(2))
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(1)
+					Expect(actualStatus.Diagnostics).NotTo(BeNil())
+					Expect(actualStatus.Diagnostics.Name).To(Equal("foo-diagnostics"))
+				})
+			})
+
+			When("no nodes were retained", func() {
+				It("does not store any diagnostics", func() {
+					Eventually(clusterProvider.DeleteCallCount).Should(Equal(1))
+					Expect(diagnosticsStore.StoreCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the kind cluster already existed", func() {
+				BeforeEach(func() {
+					clusterProvider.CreateReturns(fmt.Errorf("%w: %q", kclusterv1.ErrKindClusterExists, "the-kind-cluster-name"))
+				})
+
+				It("leaves the existing kind cluster alone", func() {
+					Eventually(kindClusterClient.UpdateStatusCallCount).Should(Equal(2))
+					Expect(clusterProvider.CollectDiagnosticsCallCount()).To(Equal(0))
+					Expect(clusterProvider.DeleteCallCount()).To(Equal(0))
+				})
+
+				It("reports the failure", func() {
+					Eventually(kindClusterClient.UpdateStatusCallCount).Should(Equal(2))
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(1)
+					Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+					Expect(actualStatus.FailureMessage).To(HaveValue(ContainSubstring("already exists")))
+				})
+			})
+
+			When("collecting the diagnostics fails", func() {
+				BeforeEach(func() {
+					clusterProvider.CollectDiagnosticsReturns(nil, errors.New("boom"))
+				})
+
+				It("still deletes the retained nodes", func() {
+					Eventually(clusterProvider.DeleteCallCount).Should(Equal(1))
+					Expect(diagnosticsStore.StoreCallCount()).To(Equal(0))
+				})
 			})
 		})
 	})
@@ -284,20 +786,61 @@ var _ = Describe("KindclusterController", func() {
 			clusterProvider.ExistsReturns(true, nil)
 		})
 
-		It("gets the control plane endpoint", func() {
+		It("gets the kubeconfig once", func() {
+			Expect(clusterProvider.GetKubeconfigCallCount()).To(Equal(1))
+			Expect(clusterProvider.GetKubeconfigArgsForCall(0)).To(Equal(kindCluster))
+		})
+
+		It("gets the control plane endpoint from the kubeconfig", func() {
 			Expect(clusterProvider.GetControlPlaneEndpointCallCount()).To(Equal(1))
-			actualCluster := clusterProvider.GetControlPlaneEndpointArgsForCall(0)
-			Expect(actualCluster).To(Equal(kindCluster))
+			Expect(clusterProvider.GetControlPlaneEndpointArgsForCall(0)).To(Equal([]byte("the-kubeconfig")))
 		})
 
 		It("sets the control plane endpoint", func() {
 			Expect(kindClusterClient.SetControlPlaneEndpointCallCount()).To(Equal(1))
 			_, actualEndpoint, actualCluster := kindClusterClient.SetControlPlaneEndpointArgsForCall(0)
 			Expect(actualEndpoint.Host).To(Equal("127.0.0.1"))
-			Expect(actualEndpoint.Port).To(Equal(1337))
+			Expect(actualEndpoint.Port).To(Equal(int32(1337)))
 			Expect(actualCluster).To(Equal(kindCluster))
 		})
 
+		It("does not report a changed endpoint", func() {
+			Expect(recorder.Events).NotTo(Receive(ContainSubstring("ControlPlaneEndpointChanged")))
+		})
+
+		It("records the KindCluster as the owner of the kind cluster", func() {
+			Expect(clusterProvider.SetOwnerCallCount()).To(Equal(1))
+			actualCluster, owner := clusterProvider.SetOwnerArgsForCall(0)
+			Expect(actualCluster).To(Equal(kindCluster))
+			Expect(owner.Kind).To(Equal("KindCluster"))
+			Expect(owner.Namespace).To(Equal("bar"))
+			Expect(owner.Name).To(Equal("foo"))
+			Expect(owner.UID).To(Equal(kindCluster.UID))
+		})
+
+		When("recording the owner fails", func() {
+			BeforeEach(func() {
+				clusterProvider.SetOwnerReturns(errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioned))
+			})
+		})
+
+		When("the endpoint has changed", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 4242}
+			})
+
+			It("emits an event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring(
+					"Normal ControlPlaneEndpointChanged Control plane endpoint changed from 127.0.0.1:4242 to 127.0.0.1:1337")))
+			})
+		})
+
 		It("updates the status to ready and phase ready", func() {
 			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
 			_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(0)
@@ -306,6 +849,74 @@ var _ = Describe("KindclusterController", func() {
 			Expect(actualCluster).To(Equal(kindCluster))
 		})
 
+		It("marks the workload API as reachable", func() {
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			holder := &kclusterv1.KindCluster{Status: actualStatus}
+			Expect(conditions.IsTrue(holder, kclusterv1.WorkloadAPIReachableCondition)).To(BeTrue())
+		})
+
+		It("records the nodes and kubernetes version", func() {
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.Nodes).To(HaveLen(1))
+			Expect(actualStatus.Nodes[0].Name).To(Equal("the-kind-cluster-name-control-plane"))
+			Expect(actualStatus.Nodes[0].ContainerID).To(Equal("abc123"))
+			Expect(actualStatus.NodeCount).To(Equal(int32(1)))
+			Expect(actualStatus.KubernetesVersion).To(Equal("v1.31.0"))
+		})
+
+		It("requeues the event after the health check interval", func() {
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+
+		It("stores the kubeconfig of the workload cluster", func() {
+			Expect(clusterProvider.ExportKubeconfigCallCount()).To(Equal(1))
+			actualKindCluster, actualExported := clusterProvider.ExportKubeconfigArgsForCall(0)
+			Expect(actualKindCluster).To(Equal(kindCluster))
+			Expect(actualExported).To(Equal([]byte("the-kubeconfig")))
+
+			Expect(kubeconfigStore.StoreCallCount()).To(Equal(1))
+			_, actualCluster, actualKubeconfig := kubeconfigStore.StoreArgsForCall(0)
+			Expect(actualCluster).To(Equal(cluster))
+			Expect(actualKubeconfig).To(Equal([]byte("the-kubeconfig")))
+		})
+
+		When("getting the kubeconfig fails", func() {
+			BeforeEach(func() {
+				clusterProvider.GetKubeconfigReturns(nil, errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+
+			It("does not update the status to ready", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Ready).To(BeFalse())
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioned))
+			})
+		})
+
+		When("exporting the kubeconfig fails", func() {
+			BeforeEach(func() {
+				clusterProvider.ExportKubeconfigReturns(errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(kubeconfigStore.StoreCallCount()).To(Equal(0))
+			})
+		})
+
+		When("storing the kubeconfig fails", func() {
+			BeforeEach(func() {
+				kubeconfigStore.StoreReturns(errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+
 		When("getting the control plane endpoint fails", func() {
 			BeforeEach(func() {
 				clusterProvider.GetControlPlaneEndpointReturns("", 0, errors.New("boom"))
@@ -360,12 +971,465 @@ var _ = Describe("KindclusterController", func() {
 			Expect(clusterProvider.CreateCallCount()).To(Equal(0))
 		})
 
-		It("does not update the status", func() {
-			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
-			_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(0)
-			Expect(actualStatus.Ready).To(BeTrue())
-			Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseReady))
-			Expect(actualCluster).To(Equal(kindCluster))
+		It("checks the health of the cluster", func() {
+			Expect(clusterProvider.CheckHealthCallCount()).To(Equal(1))
+			Expect(clusterProvider.CheckHealthArgsForCall(0)).To(Equal(kindCluster))
+		})
+
+		It("requeues the event after the health check interval", func() {
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+
+		It("uses the kubeconfig read by the health check", func() {
+			Expect(clusterProvider.GetKubeconfigCallCount()).To(Equal(0))
+			Expect(clusterProvider.GetControlPlaneEndpointArgsForCall(0)).To(Equal([]byte("the-kubeconfig")))
+			_, actualExported := clusterProvider.ExportKubeconfigArgsForCall(0)
+			Expect(actualExported).To(Equal([]byte("the-kubeconfig")))
+		})
+
+		It("refreshes the nodes and kubernetes version", func() {
+			Expect(clusterProvider.GetNodesCallCount()).To(Equal(1))
+			Expect(clusterProvider.GetKubernetesVersionCallCount()).To(Equal(1))
+			Expect(clusterProvider.GetKubernetesVersionArgsForCall(0)).To(Equal([]byte("the-kubeconfig")))
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.NodeCount).To(Equal(int32(1)))
+			Expect(actualStatus.KubernetesVersion).To(Equal("v1.31.0"))
+		})
+
+		When("getting the nodes fails", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Nodes = []kclusterv1.NodeStatus{{Name: "old"}}
+				kindClusterClient.GetReturns(kindCluster, nil)
+				clusterProvider.GetNodesReturns(nil, errors.New("boom"))
+			})
+
+			It("keeps the previous nodes and does not fail", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Nodes).To(ConsistOf(kclusterv1.NodeStatus{Name: "old"}))
+			})
+		})
+
+		It("exports the kubeconfig again", func() {
+			Expect(kubeconfigStore.StoreCallCount()).To(Equal(1))
+			_, actualCluster, actualKubeconfig := kubeconfigStore.StoreArgsForCall(0)
+			Expect(actualCluster).To(Equal(cluster))
+			Expect(actualKubeconfig).To(Equal([]byte("the-kubeconfig")))
+		})
+
+		It("sets the control plane endpoint", func() {
+			Expect(kindClusterClient.SetControlPlaneEndpointCallCount()).To(Equal(1))
+			_, endpoint, _ := kindClusterClient.SetControlPlaneEndpointArgsForCall(0)
+			Expect(endpoint).To(Equal(clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 1337}))
+		})
+
+		When("the control plane endpoint has not changed", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 1337}
+			})
+
+			It("does not set it", func() {
+				Expect(kindClusterClient.SetControlPlaneEndpointCallCount()).To(Equal(0))
+				Expect(recorder.Events).NotTo(Receive(ContainSubstring("ControlPlaneEndpointChanged")))
+			})
+		})
+
+		When("the API server port has changed", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 4242}
+			})
+
+			It("updates the control plane endpoint", func() {
+				Expect(kindClusterClient.SetControlPlaneEndpointCallCount()).To(Equal(1))
+				_, endpoint, _ := kindClusterClient.SetControlPlaneEndpointArgsForCall(0)
+				Expect(endpoint).To(Equal(clusterv1.APIEndpoint{Host: "127.0.0.1", Port: 1337}))
+				Expect(recorder.Events).To(Receive(ContainSubstring(
+					"Normal ControlPlaneEndpointChanged Control plane endpoint changed from 127.0.0.1:4242 to 127.0.0.1:1337")))
+			})
+
+			It("gets the control plane endpoint once", func() {
+				Expect(clusterProvider.GetControlPlaneEndpointCallCount()).To(Equal(1))
+			})
+
+			It("exports the kubeconfig again", func() {
+				Expect(clusterProvider.ExportKubeconfigCallCount()).To(Equal(1))
+				Expect(kubeconfigStore.StoreCallCount()).To(Equal(1))
+			})
+		})
+
+		When("getting the control plane endpoint fails", func() {
+			BeforeEach(func() {
+				clusterProvider.GetControlPlaneEndpointReturns("", 0, errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(kubeconfigStore.StoreCallCount()).To(Equal(0))
+			})
+
+			It("still updates the status", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Ready).To(BeTrue())
+			})
+		})
+
+		When("storing the kubeconfig fails", func() {
+			BeforeEach(func() {
+				kubeconfigStore.StoreReturns(errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+
+		Describe("certificates", func() {
+			var expiry time.Time
+
+			BeforeEach(func() {
+				expiry = time.Now().Add(300 * 24 * time.Hour)
+				clusterProvider.GetCertificateExpiryReturns(expiry, nil)
+				reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+					HealthCheckInterval:    time.Minute,
+					CertificateRenewBefore: 30 * 24 * time.Hour,
+				})
+			})
+
+			It("records when the certificates expire", func() {
+				Expect(clusterProvider.GetCertificateExpiryCallCount()).To(Equal(1))
+				actualKindCluster, actualKubeconfig := clusterProvider.GetCertificateExpiryArgsForCall(0)
+				Expect(actualKindCluster).To(Equal(kindCluster))
+				Expect(actualKubeconfig).To(Equal([]byte("the-kubeconfig")))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.CertificatesExpireAt.Time).To(BeTemporally("==", expiry))
+			})
+
+			It("does not renew them", func() {
+				Expect(clusterProvider.RenewCertificatesCallCount()).To(Equal(0))
+			})
+
+			When("they expire within the renewal period", func() {
+				var release chan struct{}
+
+				BeforeEach(func() {
+					expiry = time.Now().Add(10 * 24 * time.Hour)
+					clusterProvider.GetCertificateExpiryReturns(expiry, nil)
+					release = make(chan struct{})
+					released := release
+					clusterProvider.RenewCertificatesStub = func(*kclusterv1.KindCluster) error {
+						<-released
+						return nil
+					}
+				})
+
+				AfterEach(func() {
+					close(release)
+				})
+
+				It("renews them in the background", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Eventually(clusterProvider.RenewCertificatesCallCount).Should(Equal(1))
+					Expect(clusterProvider.RenewCertificatesArgsForCall(0)).To(Equal(kindCluster))
+				})
+
+				It("checks back while they are renewed", func() {
+					Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+				})
+
+				It("records the expiry", func() {
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Ready).To(BeTrue())
+					Expect(actualStatus.CertificatesExpireAt.Time).To(BeTemporally("==", expiry))
+				})
+
+				When("the kind cluster is reconciled while they are renewed", func() {
+					JustBeforeEach(func() {
+						result, reconcileErr = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}})
+					})
+
+					It("does not check it or renew them again", func() {
+						Expect(reconcileErr).NotTo(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+						Expect(clusterProvider.CheckHealthCallCount()).To(Equal(1))
+						Consistently(clusterProvider.RenewCertificatesCallCount).Should(BeNumerically("<=", 1))
+					})
+				})
+
+				When("the renewal finishes", func() {
+					JustBeforeEach(func() {
+						release <- struct{}{}
+					})
+
+					It("emits an event", func() {
+						Eventually(recorder.Events).Should(Receive(ContainSubstring("Normal CertificatesRenewed")))
+					})
+
+					It("checks the kind cluster again on the next reconcile", func() {
+						Eventually(recorder.Events).Should(Receive(ContainSubstring("CertificatesRenewed")))
+						Eventually(func() int {
+							_, _ = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}})
+							return clusterProvider.CheckHealthCallCount()
+						}).Should(BeNumerically(">=", 2))
+					})
+				})
+
+				When("renewing them fails", func() {
+					BeforeEach(func() {
+						clusterProvider.RenewCertificatesStub = nil
+						clusterProvider.RenewCertificatesReturns(errors.New("boom"))
+					})
+
+					It("emits a warning", func() {
+						Expect(reconcileErr).NotTo(HaveOccurred())
+						Eventually(recorder.Events).Should(Receive(ContainSubstring("Warning CertificateRenewalFailed")))
+					})
+				})
+
+				When("the renewal is disabled", func() {
+					BeforeEach(func() {
+						reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+							HealthCheckInterval: time.Minute,
+						})
+					})
+
+					It("only records when they expire", func() {
+						Expect(clusterProvider.RenewCertificatesCallCount()).To(Equal(0))
+						_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+						Expect(actualStatus.CertificatesExpireAt.Time).To(BeTemporally("==", expiry))
+					})
+				})
+			})
+
+			When("getting the expiry fails", func() {
+				BeforeEach(func() {
+					clusterProvider.GetCertificateExpiryReturns(time.Time{}, errors.New("boom"))
+				})
+
+				It("does not fail", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(clusterProvider.RenewCertificatesCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		When("the cluster is unhealthy", func() {
+			BeforeEach(func() {
+				clusterProvider.CheckHealthReturns(nil, errors.New("api server is not ready"))
+			})
+
+			It("does not return an error", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(time.Minute))
+			})
+
+			It("updates the status to not ready", func() {
+				Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Ready).To(BeFalse())
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseReady))
+			})
+
+			It("does not read the kubernetes version", func() {
+				Expect(clusterProvider.GetNodesCallCount()).To(Equal(1))
+				Expect(clusterProvider.GetKubernetesVersionCallCount()).To(Equal(0))
+			})
+
+			It("does not export the kubeconfig", func() {
+				Expect(kubeconfigStore.StoreCallCount()).To(Equal(0))
+				Expect(clusterProvider.GetCertificateExpiryCallCount()).To(Equal(0))
+			})
+
+			It("marks the workload API as unreachable", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				holder := &kclusterv1.KindCluster{Status: actualStatus}
+				Expect(conditions.IsFalse(holder, kclusterv1.WorkloadAPIReachableCondition)).To(BeTrue())
+				Expect(conditions.GetReason(holder, kclusterv1.WorkloadAPIReachableCondition)).To(Equal(kclusterv1.WorkloadAPIUnreachableReason))
+				Expect(conditions.GetMessage(holder, kclusterv1.WorkloadAPIReachableCondition)).To(Equal("api server is not ready"))
+			})
+		})
+
+		When("the cluster is unhealthy and has a remediation policy", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.Remediation = &kclusterv1.RemediationPolicy{
+					MaxAttempts:        2,
+					UnhealthyThreshold: &metav1.Duration{Duration: time.Minute},
+					Cooldown:           &metav1.Duration{Duration: 10 * time.Minute},
+				}
+				conditions.Set(kindCluster, &clusterv1.Condition{
+					Type:               kclusterv1.WorkloadAPIReachableCondition,
+					Status:             corev1.ConditionFalse,
+					Reason:             kclusterv1.WorkloadAPIUnreachableReason,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Minute)),
+				})
+				kindClusterClient.GetReturns(kindCluster, nil)
+				clusterProvider.CheckHealthReturns(nil, errors.New("node is exited"))
+			})
+
+			It("restarts the node containers", func() {
+				Expect(clusterProvider.RestartNodesCallCount()).To(Equal(1))
+				Expect(clusterProvider.RestartNodesArgsForCall(0)).To(Equal(kindCluster))
+				Expect(clusterProvider.DeleteCallCount()).To(Equal(0))
+			})
+
+			It("records the remediation in the status", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Remediation).NotTo(BeNil())
+				Expect(actualStatus.Remediation.Attempts).To(Equal(1))
+				Expect(actualStatus.Remediation.LastAction).To(Equal(kclusterv1.RemediationActionRestartNodes))
+				Expect(actualStatus.Remediation.LastAttemptTime).NotTo(BeNil())
+			})
+
+			It("records an event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Remediating")))
+			})
+
+			When("the unhealthy threshold has not been reached", func() {
+				BeforeEach(func() {
+					kindCluster.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-30 * time.Second))
+				})
+
+				It("does not remediate and requeues when the threshold is reached", func() {
+					Expect(clusterProvider.RestartNodesCallCount()).To(Equal(0))
+					Expect(result.RequeueAfter).To(BeNumerically("~", 30*time.Second, time.Second))
+				})
+			})
+
+			When("the nodes have already been restarted", func() {
+				BeforeEach(func() {
+					kindCluster.Status.Remediation = &kclusterv1.RemediationStatus{
+						Attempts:        1,
+						LastAction:      kclusterv1.RemediationActionRestartNodes,
+						LastAttemptTime: &metav1.Time{Time: time.Now().Add(-11 * time.Minute)},
+					}
+				})
+
+				It("deletes the kind cluster so it is recreated", func() {
+					Expect(clusterProvider.RestartNodesCallCount()).To(Equal(0))
+					Expect(clusterProvider.DeleteCallCount()).To(Equal(1))
+					Expect(result.Requeue).To(BeTrue())
+				})
+
+				It("moves the cluster back to pending", func() {
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Ready).To(BeFalse())
+					Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+					Expect(actualStatus.Remediation.Attempts).To(Equal(2))
+					Expect(actualStatus.Remediation.LastAction).To(Equal(kclusterv1.RemediationActionRecreate))
+				})
+
+				When("deleting the cluster fails", func() {
+					BeforeEach(func() {
+						clusterProvider.DeleteReturns(errors.New("boom"))
+					})
+
+					It("returns an error", func() {
+						Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+					})
+				})
+			})
+
+			When("the cooldown has not passed since the last attempt", func() {
+				BeforeEach(func() {
+					kindCluster.Status.Remediation = &kclusterv1.RemediationStatus{
+						Attempts:        1,
+						LastAction:      kclusterv1.RemediationActionRestartNodes,
+						LastAttemptTime: &metav1.Time{Time: time.Now().Add(-time.Minute)},
+					}
+				})
+
+				It("does not remediate", func() {
+					Expect(clusterProvider.RestartNodesCallCount()).To(Equal(0))
+					Expect(clusterProvider.DeleteCallCount()).To(Equal(0))
+					Expect(result.RequeueAfter).To(BeNumerically("~", 9*time.Minute, time.Second))
+				})
+			})
+
+			When("the remediation attempts are exhausted", func() {
+				BeforeEach(func() {
+					kindCluster.Status.Remediation = &kclusterv1.RemediationStatus{
+						Attempts:        2,
+						LastAction:      kclusterv1.RemediationActionRecreate,
+						LastAttemptTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+					}
+				})
+
+				It("does not remediate", func() {
+					Expect(clusterProvider.RestartNodesCallCount()).To(Equal(0))
+					Expect(clusterProvider.DeleteCallCount()).To(Equal(0))
+				})
+
+				It("records a warning event", func() {
+					Expect(recorder.Events).To(Receive(ContainSubstring("RemediationExhausted")))
+				})
+
+				It("records that the attempts are exhausted", func() {
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Remediation.Exhausted).To(BeTrue())
+				})
+
+				When("it was already reported", func() {
+					BeforeEach(func() {
+						kindCluster.Status.Remediation.Exhausted = true
+					})
+
+					It("does not record another event", func() {
+						Expect(recorder.Events).NotTo(Receive(ContainSubstring("RemediationExhausted")))
+					})
+				})
+			})
+
+			When("restarting the nodes fails", func() {
+				BeforeEach(func() {
+					clusterProvider.RestartNodesReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				})
+			})
+		})
+
+		When("the cluster is healthy after a cooldown since the last remediation", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.Remediation = &kclusterv1.RemediationPolicy{}
+				kindCluster.Status.Remediation = &kclusterv1.RemediationStatus{
+					Attempts:        1,
+					LastAction:      kclusterv1.RemediationActionRestartNodes,
+					LastAttemptTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+					Exhausted:       true,
+				}
+				kindClusterClient.GetReturns(kindCluster, nil)
+			})
+
+			It("resets the remediation attempts", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Remediation.Attempts).To(BeZero())
+				Expect(actualStatus.Remediation.Exhausted).To(BeFalse())
+			})
+		})
+
+		When("the cluster recovers", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Ready = false
+				conditions.MarkFalse(kindCluster, kclusterv1.WorkloadAPIReachableCondition, kclusterv1.WorkloadAPIUnreachableReason, clusterv1.ConditionSeverityError, "boom")
+				kindClusterClient.GetReturns(kindCluster, nil)
+			})
+
+			It("updates the status to ready", func() {
+				Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Ready).To(BeTrue())
+				holder := &kclusterv1.KindCluster{Status: actualStatus}
+				Expect(conditions.IsTrue(holder, kclusterv1.WorkloadAPIReachableCondition)).To(BeTrue())
+			})
+		})
+
+		It("does not update the status", func() {
+			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
+			_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.Ready).To(BeTrue())
+			Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseReady))
+			Expect(actualCluster).To(Equal(kindCluster))
 		})
 
 		When("the cluster doesn't exist", func() {
@@ -373,22 +1437,932 @@ var _ = Describe("KindclusterController", func() {
 				clusterProvider.ExistsReturns(false, nil)
 			})
 
-			It("updates the status to pending", func() {
-				Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
-				_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(0)
+			It("updates the status to pending", func() {
+				Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
+				_, actualStatus, actualCluster := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Ready).To(BeFalse())
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+				Expect(actualCluster).To(Equal(kindCluster))
+			})
+		})
+
+		When("checking if the cluster exists fails", func() {
+			BeforeEach(func() {
+				clusterProvider.ExistsReturns(false, errors.New("boom"))
+			})
+
+			It("requeues the event", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
+	Describe("KindClustersForEvent", func() {
+		var (
+			requests    []reconcile.Request
+			eventObject *kclusterv1.KindCluster
+		)
+
+		BeforeEach(func() {
+			eventObject = &kclusterv1.KindCluster{
+				Spec: kclusterv1.KindClusterSpec{
+					Name: "the-kind-cluster-name",
+				},
+			}
+			kindClusterClient.ListByNameReturns([]kclusterv1.KindCluster{*kindCluster}, nil)
+		})
+
+		JustBeforeEach(func() {
+			requests = reconciler.KindClustersForEvent(ctx, eventObject)
+		})
+
+		It("lists the KindClusters by the kind cluster name", func() {
+			Expect(kindClusterClient.ListByNameCallCount()).To(Equal(1))
+			_, actualName := kindClusterClient.ListByNameArgsForCall(0)
+			Expect(actualName).To(Equal("the-kind-cluster-name"))
+		})
+
+		It("returns a request for each matching KindCluster", func() {
+			Expect(requests).To(ConsistOf(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"},
+			}))
+		})
+
+		When("the kind cluster of the event runs on a KindHost", func() {
+			BeforeEach(func() {
+				eventObject.Spec.HostRef = &corev1.LocalObjectReference{Name: "the-host"}
+
+				onHost := kindCluster.DeepCopy()
+				onHost.Name = "on-host"
+				onHost.Status.HostRef = &corev1.LocalObjectReference{Name: "the-host"}
+				onOtherHost := kindCluster.DeepCopy()
+				onOtherHost.Name = "on-other-host"
+				onOtherHost.Spec.HostRef = &corev1.LocalObjectReference{Name: "other-host"}
+				kindClusterClient.ListByNameReturns([]kclusterv1.KindCluster{*kindCluster, *onHost, *onOtherHost}, nil)
+			})
+
+			It("only returns requests for the KindClusters on that host", func() {
+				Expect(requests).To(ConsistOf(reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: "on-host", Namespace: "bar"},
+				}))
+			})
+		})
+
+		When("a KindCluster with the same kind cluster name runs on a KindHost", func() {
+			BeforeEach(func() {
+				onHost := kindCluster.DeepCopy()
+				onHost.Name = "on-host"
+				onHost.Spec.HostRef = &corev1.LocalObjectReference{Name: "the-host"}
+				kindClusterClient.ListByNameReturns([]kclusterv1.KindCluster{*kindCluster, *onHost}, nil)
+			})
+
+			It("does not return a request for it", func() {
+				Expect(requests).To(ConsistOf(reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"},
+				}))
+			})
+		})
+
+		When("listing the KindClusters fails", func() {
+			BeforeEach(func() {
+				kindClusterClient.ListByNameReturns(nil, errors.New("boom"))
+			})
+
+			It("does not return any requests", func() {
+				Expect(requests).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Expiry", func() {
+		BeforeEach(func() {
+			kindCluster.Status.Ready = true
+			kindCluster.Status.Phase = kclusterv1.ClusterPhaseReady
+			kindCluster.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			clusterProvider.ExistsReturns(true, nil)
+		})
+
+		It("publishes the expiry in the status", func() {
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.ExpiresAt).To(Equal(kindCluster.Spec.ExpiresAt))
+		})
+
+		It("does not emit a warning yet", func() {
+			Expect(recorder.Events).NotTo(Receive())
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.ExpiryWarningTime).To(BeNil())
+		})
+
+		It("keeps the health check interval when it is sooner", func() {
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+
+		When("the KindCluster has a TTL", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ExpiresAt = nil
+				kindCluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+				kindCluster.Spec.TTL = &metav1.Duration{Duration: 3 * time.Hour}
+			})
+
+			It("expires the TTL after its creation", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.ExpiresAt.Time).To(Equal(kindCluster.CreationTimestamp.Add(3 * time.Hour)))
+			})
+		})
+
+		When("the warning is due before the next health check", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(10*time.Minute + 30*time.Second)}
+			})
+
+			It("requeues when the warning is due", func() {
+				Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Second))
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			})
+		})
+
+		When("the KindCluster is about to expire", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(30 * time.Second)}
+			})
+
+			It("emits a warning", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Warning ExpiringSoon")))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.ExpiryWarningTime).NotTo(BeNil())
+			})
+
+			It("requeues at the expiry", func() {
+				Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Second))
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			})
+
+			When("the warning was already emitted", func() {
+				BeforeEach(func() {
+					kindCluster.Status.ExpiryWarningTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+				})
+
+				It("does not emit it again", func() {
+					Expect(recorder.Events).NotTo(Receive())
+				})
+			})
+
+			When("the warning was emitted before the lease was extended", func() {
+				BeforeEach(func() {
+					kindCluster.Status.ExpiryWarningTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+				})
+
+				It("emits it again", func() {
+					Expect(recorder.Events).To(Receive(ContainSubstring("Warning ExpiringSoon")))
+				})
+			})
+		})
+
+		When("the KindCluster has expired", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Second)}
+			})
+
+			It("deletes the KindCluster", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(kindClusterClient.DeleteCallCount()).To(Equal(1))
+				_, actualCluster := kindClusterClient.DeleteArgsForCall(0)
+				Expect(actualCluster).To(Equal(kindCluster))
+				Expect(clusterClient.DeleteCallCount()).To(Equal(0))
+			})
+
+			It("emits an Expired event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Expired")))
+			})
+
+			It("does not reconcile the kind cluster", func() {
+				Expect(clusterProvider.CheckHealthCallCount()).To(Equal(0))
+				Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(0))
+			})
+
+			When("the expiration policy is DeleteCluster", func() {
+				BeforeEach(func() {
+					kindCluster.Spec.ExpirationPolicy = kclusterv1.ExpirationPolicyDeleteCluster
+				})
+
+				It("deletes the owning Cluster", func() {
+					Expect(clusterClient.DeleteCallCount()).To(Equal(1))
+					_, actualCluster := clusterClient.DeleteArgsForCall(0)
+					Expect(actualCluster).To(Equal(cluster))
+					Expect(kindClusterClient.DeleteCallCount()).To(Equal(0))
+				})
+
+				When("deleting the Cluster fails", func() {
+					BeforeEach(func() {
+						clusterClient.DeleteReturns(errors.New("boom"))
+					})
+
+					It("returns an error", func() {
+						Expect(reconcileErr).To(MatchError("boom"))
+					})
+				})
+			})
+
+			When("the KindCluster is protected from deletion", func() {
+				BeforeEach(func() {
+					kindCluster.Spec.DeletionProtection = true
+				})
+
+				It("does not delete the KindCluster", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(kindClusterClient.DeleteCallCount()).To(Equal(0))
+					Expect(clusterClient.DeleteCallCount()).To(Equal(0))
+				})
+
+				It("keeps reconciling the kind cluster", func() {
+					Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(1))
+				})
+			})
+
+			When("the KindCluster is already gone", func() {
+				BeforeEach(func() {
+					kindClusterClient.DeleteReturns(k8serrors.NewNotFound(schema.GroupResource{}, "foo"))
+				})
+
+				It("does not return an error", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+				})
+			})
+
+			When("deleting the KindCluster fails", func() {
+				BeforeEach(func() {
+					kindClusterClient.DeleteReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError("boom"))
+				})
+			})
+		})
+
+		When("the lease is extended", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Second)}
+				kindCluster.Annotations = map[string]string{kclusterv1.ExtendLeaseAnnotation: "2h"}
+				kindClusterClient.ExtendLeaseCalls(func(_ context.Context, expiresAt metav1.Time, kc *kclusterv1.KindCluster) error {
+					kc.Spec.ExpiresAt = &expiresAt
+					delete(kc.Annotations, kclusterv1.ExtendLeaseAnnotation)
+					return nil
+				})
+			})
+
+			It("moves the expiry", func() {
+				Expect(kindClusterClient.ExtendLeaseCallCount()).To(Equal(1))
+				_, expiresAt, _ := kindClusterClient.ExtendLeaseArgsForCall(0)
+				Expect(expiresAt.Time).To(BeTemporally("~", time.Now().Add(2*time.Hour), time.Minute))
+			})
+
+			It("emits a LeaseExtended event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("LeaseExtended")))
+			})
+
+			It("does not delete the KindCluster", func() {
+				Expect(kindClusterClient.DeleteCallCount()).To(Equal(0))
+			})
+
+			When("the expiry already is later", func() {
+				BeforeEach(func() {
+					kindCluster.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(5 * time.Hour)}
+				})
+
+				It("keeps the expiry", func() {
+					_, expiresAt, _ := kindClusterClient.ExtendLeaseArgsForCall(0)
+					Expect(expiresAt.Time).To(Equal(kindCluster.Spec.ExpiresAt.Time))
+				})
+			})
+
+			When("the KindCluster does not expire", func() {
+				BeforeEach(func() {
+					kindCluster.Spec.ExpiresAt = nil
+				})
+
+				It("ignores the annotation", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(kindClusterClient.ExtendLeaseCallCount()).To(Equal(0))
+				})
+			})
+
+			When("the annotation is invalid", func() {
+				BeforeEach(func() {
+					kindCluster.Annotations[kclusterv1.ExtendLeaseAnnotation] = "carrot"
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError(ContainSubstring("invalid")))
+					Expect(kindClusterClient.DeleteCallCount()).To(Equal(0))
+				})
+			})
+
+			When("extending the lease fails", func() {
+				BeforeEach(func() {
+					kindClusterClient.ExtendLeaseCalls(nil)
+					kindClusterClient.ExtendLeaseReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError("boom"))
+					Expect(kindClusterClient.DeleteCallCount()).To(Equal(0))
+				})
+			})
+		})
+	})
+
+	Describe("Pools", func() {
+		var (
+			poolClient *controllersfakes.FakeKindClusterPoolClient
+			pool       *kclusterv1.KindClusterPool
+		)
+
+		BeforeEach(func() {
+			poolClient = new(controllersfakes.FakeKindClusterPoolClient)
+			reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+				HealthCheckInterval: time.Minute,
+				Pools:               poolClient,
+			})
+
+			kindCluster.UID = "foo-uid"
+			kindCluster.Spec.Name = ""
+			kindCluster.Spec.WorkerNodes = 1
+			kindCluster.Spec.PoolRef = &corev1.LocalObjectReference{Name: "ci"}
+			kindClusterClient.SetNameStub = func(_ context.Context, name string, kindCluster *kclusterv1.KindCluster) error {
+				kindCluster.Spec.Name = name
+				return nil
+			}
+
+			pool = &kclusterv1.KindClusterPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ci",
+					Namespace: "bar",
+				},
+				Spec: kclusterv1.KindClusterPoolSpec{
+					Size:     2,
+					Template: kclusterv1.KindClusterSpec{WorkerNodes: 1},
+				},
+			}
+			pool.Status.Clusters = []kclusterv1.PooledCluster{
+				{Name: "bar-ci-provisioning", Phase: kclusterv1.PooledClusterPhaseProvisioning, TemplateHash: pool.TemplateHash()},
+				{Name: "bar-ci-old", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: "old"},
+				{Name: "bar-ci-idle", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: pool.TemplateHash()},
+			}
+			poolClient.GetReturns(pool, nil)
+		})
+
+		It("gets the pool from the namespace of the KindCluster", func() {
+			Expect(poolClient.GetCallCount()).To(Equal(1))
+			_, namespacedName := poolClient.GetArgsForCall(0)
+			Expect(namespacedName).To(Equal(types.NamespacedName{Namespace: "bar", Name: "ci"}))
+		})
+
+		It("claims an idle kind cluster created from the current template", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+
+			Expect(poolClient.ClaimCallCount()).To(Equal(1))
+			_, actualName, actualKindCluster, actualPool := poolClient.ClaimArgsForCall(0)
+			Expect(actualName).To(Equal("bar-ci-idle"))
+			Expect(actualKindCluster).To(Equal(kindCluster))
+			Expect(actualPool).To(Equal(pool))
+		})
+
+		It("takes over the claimed kind cluster", func() {
+			Expect(kindClusterClient.SetNameCallCount()).To(Equal(1))
+			_, actualName, _ := kindClusterClient.SetNameArgsForCall(0)
+			Expect(actualName).To(Equal("bar-ci-idle"))
+			Expect(kindClusterClient.AddFinalizerCallCount()).To(Equal(1))
+		})
+
+		It("records the KindCluster as the owner of the kind cluster", func() {
+			Expect(clusterProvider.SetOwnerCallCount()).To(Equal(1))
+			actualKindCluster, owner := clusterProvider.SetOwnerArgsForCall(0)
+			Expect(actualKindCluster.Spec.Name).To(Equal("bar-ci-idle"))
+			Expect(owner.Kind).To(Equal("KindCluster"))
+			Expect(owner.Namespace).To(Equal("bar"))
+			Expect(owner.Name).To(Equal("foo"))
+			Expect(owner.UID).To(BeEquivalentTo("foo-uid"))
+		})
+
+		It("emits an event", func() {
+			Expect(recorder.Events).To(Receive(ContainSubstring("Normal ClaimedFromPool")))
+		})
+
+		It("does not create a kind cluster", func() {
+			Expect(clusterProvider.CreateCallCount()).To(Equal(0))
+			Expect(kindClusterClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+
+		When("the claimed kind cluster is adopted", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.Name = "bar-ci-idle"
+				clusterProvider.ExistsReturns(true, nil)
+			})
+
+			It("does not claim another kind cluster", func() {
+				Expect(poolClient.ClaimCallCount()).To(Equal(0))
+			})
+
+			It("sets the phase to provisioned", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioned))
+			})
+		})
+
+		When("the pool has no idle kind cluster", func() {
+			BeforeEach(func() {
+				pool.Status.Clusters = pool.Status.Clusters[:2]
+			})
+
+			It("generates a name to create the kind cluster", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(poolClient.ClaimCallCount()).To(Equal(0))
+				_, actualName, _ := kindClusterClient.SetNameArgsForCall(0)
+				Expect(actualName).To(HavePrefix("bar-foo-"))
+			})
+		})
+
+		When("the KindCluster does not match the template of the pool", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.WorkerNodes = 2
+			})
+
+			It("does not claim a kind cluster", func() {
+				Expect(poolClient.ClaimCallCount()).To(Equal(0))
+				_, actualName, _ := kindClusterClient.SetNameArgsForCall(0)
+				Expect(actualName).To(HavePrefix("bar-foo-"))
+			})
+
+			It("emits a warning", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Warning PoolMismatch")))
+			})
+		})
+
+		When("the KindControlPlane replaces the nodes of the KindCluster", func() {
+			BeforeEach(func() {
+				clusterClient.GetControlPlaneReturns(&kclusterv1.KindControlPlane{
+					Spec: kclusterv1.KindControlPlaneSpec{Replicas: ptr.To(int32(3))},
+				}, nil)
+			})
+
+			It("does not claim a kind cluster", func() {
+				Expect(poolClient.ClaimCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the pool does not exist", func() {
+			BeforeEach(func() {
+				poolClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "ci"))
+			})
+
+			It("generates a name to create the kind cluster", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				_, actualName, _ := kindClusterClient.SetNameArgsForCall(0)
+				Expect(actualName).To(HavePrefix("bar-foo-"))
+			})
+		})
+
+		When("claiming the kind cluster fails", func() {
+			BeforeEach(func() {
+				poolClient.ClaimReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(kindClusterClient.SetNameCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the KindCluster already claimed a kind cluster", func() {
+			BeforeEach(func() {
+				pool.Status.Clusters = append(pool.Status.Clusters, kclusterv1.PooledCluster{
+					Name:         "bar-ci-claimed",
+					Phase:        kclusterv1.PooledClusterPhaseClaimed,
+					TemplateHash: pool.TemplateHash(),
+					ClaimedBy:    &corev1.ObjectReference{Namespace: "bar", Name: "foo", UID: "foo-uid"},
+				})
+			})
+
+			It("takes over the claimed kind cluster without claiming another", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(poolClient.ClaimCallCount()).To(Equal(0))
+				Expect(kindClusterClient.SetNameCallCount()).To(Equal(1))
+				_, actualName, _ := kindClusterClient.SetNameArgsForCall(0)
+				Expect(actualName).To(Equal("bar-ci-claimed"))
+				Expect(clusterProvider.SetOwnerCallCount()).To(Equal(1))
+			})
+		})
+
+		When("another KindCluster claimed a kind cluster", func() {
+			BeforeEach(func() {
+				pool.Status.Clusters = append(pool.Status.Clusters, kclusterv1.PooledCluster{
+					Name:         "bar-ci-claimed",
+					Phase:        kclusterv1.PooledClusterPhaseClaimed,
+					TemplateHash: pool.TemplateHash(),
+					ClaimedBy:    &corev1.ObjectReference{Namespace: "bar", Name: "other", UID: "other-uid"},
+				})
+			})
+
+			It("claims an idle kind cluster", func() {
+				Expect(poolClient.ClaimCallCount()).To(Equal(1))
+				_, actualName, _, _ := poolClient.ClaimArgsForCall(0)
+				Expect(actualName).To(Equal("bar-ci-idle"))
+			})
+		})
+
+		When("recording the owner fails", func() {
+			BeforeEach(func() {
+				clusterProvider.SetOwnerReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+
+		When("the KindCluster has a kind cluster name", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.Name = "the-kind-cluster-name"
+			})
+
+			It("does not claim a kind cluster", func() {
+				Expect(poolClient.GetCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Suspend", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.Suspended = true
+			kindCluster.Status.Ready = true
+			kindCluster.Status.Phase = kclusterv1.ClusterPhaseReady
+			clusterProvider.ExistsReturns(true, nil)
+		})
+
+		It("stops the nodes", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(clusterProvider.StopNodesCallCount()).To(Equal(1))
+			Expect(clusterProvider.StopNodesArgsForCall(0)).To(Equal(kindCluster))
+		})
+
+		It("does not check the health of the kind cluster", func() {
+			Expect(clusterProvider.CheckHealthCallCount()).To(Equal(0))
+		})
+
+		It("moves the KindCluster to the suspended phase", func() {
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.Ready).To(BeFalse())
+			Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseSuspended))
+
+			condition := conditions.Get(&kclusterv1.KindCluster{Status: actualStatus}, kclusterv1.WorkloadAPIReachableCondition)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(kclusterv1.SuspendedReason))
+		})
+
+		It("records the stopped nodes", func() {
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.Nodes).To(HaveLen(1))
+		})
+
+		When("the activity of the API server was tracked", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Activity = &kclusterv1.ActivityStatus{MutatingRequests: 42}
+			})
+
+			It("stops tracking it", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Activity).To(BeNil())
+			})
+		})
+
+		It("emits an event", func() {
+			Expect(recorder.Events).To(Receive(ContainSubstring("Normal Suspended")))
+		})
+
+		It("does not requeue", func() {
+			Expect(result).To(Equal(ctrl.Result{}))
+		})
+
+		When("stopping the nodes fails", func() {
+			BeforeEach(func() {
+				clusterProvider.StopNodesReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseReady))
+			})
+		})
+
+		When("the kind cluster is still pending", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Ready = false
+				kindCluster.Status.Phase = kclusterv1.ClusterPhasePending
+				clusterProvider.ExistsReturns(false, nil)
+			})
+
+			It("creates it first", func() {
+				Expect(clusterProvider.StopNodesCallCount()).To(Equal(0))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioning))
+			})
+		})
+
+		When("the KindCluster is already suspended", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Ready = false
+				kindCluster.Status.Phase = kclusterv1.ClusterPhaseSuspended
+			})
+
+			It("does nothing", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(clusterProvider.StopNodesCallCount()).To(Equal(0))
+				Expect(clusterProvider.RestartNodesCallCount()).To(Equal(0))
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseSuspended))
+			})
+
+			When("the kind cluster no longer exists", func() {
+				BeforeEach(func() {
+					clusterProvider.ExistsReturns(false, nil)
+				})
+
+				It("sets the phase to pending", func() {
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+				})
+			})
+		})
+
+		When("the KindCluster is resumed", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.Suspended = false
+				kindCluster.Status.Ready = false
+				kindCluster.Status.Phase = kclusterv1.ClusterPhaseSuspended
+			})
+
+			It("starts the nodes", func() {
+				Expect(clusterProvider.RestartNodesCallCount()).To(Equal(1))
+				Expect(clusterProvider.RestartNodesArgsForCall(0)).To(Equal(kindCluster))
+			})
+
+			It("waits for the API server", func() {
+				Expect(clusterProvider.CheckHealthCallCount()).To(Equal(1))
+			})
+
+			It("exports the endpoint and kubeconfig again", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseProvisioned))
 				Expect(actualStatus.Ready).To(BeFalse())
-				Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhasePending))
+			})
+
+			It("emits an event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Normal Resumed")))
+			})
+
+			When("the API server is not ready yet", func() {
+				BeforeEach(func() {
+					clusterProvider.CheckHealthReturns(nil, errors.New("api server is not ready"))
+				})
+
+				It("stays suspended and checks again", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(5 * time.Second))
+
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Phase).To(Equal(kclusterv1.ClusterPhaseSuspended))
+					condition := conditions.Get(&kclusterv1.KindCluster{Status: actualStatus}, kclusterv1.WorkloadAPIReachableCondition)
+					Expect(condition.Reason).To(Equal(kclusterv1.ResumingReason))
+				})
+			})
+
+			When("starting the nodes fails", func() {
+				BeforeEach(func() {
+					clusterProvider.RestartNodesReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				})
+			})
+		})
+	})
+
+	Describe("Idle suspension", func() {
+		var namespaceClient *controllersfakes.FakeNamespaceClient
+
+		BeforeEach(func() {
+			namespaceClient = new(controllersfakes.FakeNamespaceClient)
+			namespaceClient.GetReturns(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "bar"}}, nil)
+			reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+				HealthCheckInterval: time.Minute,
+				IdleTimeout:         time.Hour,
+				Namespaces:          namespaceClient,
+			})
+
+			kindCluster.Status.Ready = true
+			kindCluster.Status.Phase = kclusterv1.ClusterPhaseReady
+			clusterProvider.ExistsReturns(true, nil)
+			clusterProvider.GetMutatingRequestsReturns(42, nil)
+		})
+
+		It("starts tracking the activity of the API server", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(clusterProvider.GetMutatingRequestsCallCount()).To(Equal(1))
+			Expect(clusterProvider.GetMutatingRequestsArgsForCall(0)).To(Equal([]byte("the-kubeconfig")))
+
+			_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+			Expect(actualStatus.Activity).NotTo(BeNil())
+			Expect(actualStatus.Activity.MutatingRequests).To(BeEquivalentTo(42))
+			Expect(actualStatus.Activity.LastActiveTime.Time).To(BeTemporally("~", time.Now(), time.Second))
+		})
+
+		It("does not suspend the KindCluster", func() {
+			Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+
+		When("the API server has served requests since the last check", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Activity = &kclusterv1.ActivityStatus{
+					MutatingRequests: 40,
+					LastActiveTime:   metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+				}
+			})
+
+			It("records the activity", func() {
+				Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.Activity.MutatingRequests).To(BeEquivalentTo(42))
+				Expect(actualStatus.Activity.LastActiveTime.Time).To(BeTemporally("~", time.Now(), time.Second))
+			})
+		})
+
+		When("the kind cluster is about to become idle", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Activity = &kclusterv1.ActivityStatus{
+					MutatingRequests: 42,
+					LastActiveTime:   metav1.NewTime(time.Now().Add(-time.Hour + 10*time.Second)),
+				}
+			})
+
+			It("requeues when it would be idle for the timeout", func() {
+				Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+				Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Second, time.Second))
+			})
+		})
+
+		When("the kind cluster has been idle for the timeout", func() {
+			BeforeEach(func() {
+				kindCluster.Status.Activity = &kclusterv1.ActivityStatus{
+					MutatingRequests: 42,
+					LastActiveTime:   metav1.NewTime(time.Now().Add(-time.Hour)),
+				}
+			})
+
+			It("suspends the KindCluster", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeTrue())
+
+				Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(1))
+				_, reason, actualCluster := kindClusterClient.SuspendIdleArgsForCall(0)
+				Expect(reason).To(Equal("API server served no mutating requests for 1h0m0s"))
 				Expect(actualCluster).To(Equal(kindCluster))
 			})
+
+			It("emits an event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Normal IdleSuspended")))
+			})
+
+			When("suspending the KindCluster fails", func() {
+				BeforeEach(func() {
+					kindClusterClient.SuspendIdleReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				})
+			})
+
+			When("the KindCluster opts out", func() {
+				BeforeEach(func() {
+					kindCluster.Annotations = map[string]string{kclusterv1.IdleTimeoutAnnotation: "0"}
+				})
+
+				It("does not suspend it", func() {
+					Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+					Expect(clusterProvider.GetMutatingRequestsCallCount()).To(Equal(0))
+				})
+
+				It("stops tracking the activity", func() {
+					_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+					Expect(actualStatus.Activity).To(BeNil())
+				})
+			})
+
+			When("the namespace sets a longer timeout", func() {
+				BeforeEach(func() {
+					namespaceClient.GetReturns(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+						Name:        "bar",
+						Annotations: map[string]string{kclusterv1.IdleTimeoutAnnotation: "2h"},
+					}}, nil)
+				})
+
+				It("does not suspend the KindCluster yet", func() {
+					Expect(namespaceClient.GetCallCount()).To(Equal(1))
+					_, name := namespaceClient.GetArgsForCall(0)
+					Expect(name).To(Equal("bar"))
+					Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+				})
+
+				When("the KindCluster sets its own timeout", func() {
+					BeforeEach(func() {
+						kindCluster.Annotations = map[string]string{kclusterv1.IdleTimeoutAnnotation: "30m"}
+					})
+
+					It("uses the timeout of the KindCluster", func() {
+						Expect(namespaceClient.GetCallCount()).To(Equal(0))
+						Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			When("the namespace has an invalid timeout", func() {
+				BeforeEach(func() {
+					namespaceClient.GetReturns(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+						Name:        "bar",
+						Annotations: map[string]string{kclusterv1.IdleTimeoutAnnotation: "never"},
+					}}, nil)
+				})
+
+				It("does not suspend the KindCluster", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+				})
+			})
+
+			When("getting the namespace fails", func() {
+				BeforeEach(func() {
+					namespaceClient.GetReturns(nil, errors.New("boom"))
+				})
+
+				It("does not fail the reconcile", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+				})
+			})
 		})
 
-		When("checking if the cluster exists fails", func() {
+		When("getting the requests fails", func() {
 			BeforeEach(func() {
-				clusterProvider.ExistsReturns(false, errors.New("boom"))
+				clusterProvider.GetMutatingRequestsReturns(0, errors.New("boom"))
 			})
 
-			It("requeues the event", func() {
-				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			It("does not fail the reconcile", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(time.Minute))
+				Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the kind cluster is unhealthy", func() {
+			BeforeEach(func() {
+				clusterProvider.CheckHealthReturns(nil, errors.New("api server is not ready"))
+			})
+
+			It("does not check whether it is idle", func() {
+				Expect(clusterProvider.GetMutatingRequestsCallCount()).To(Equal(0))
+			})
+		})
+
+		When("idle suspension is disabled", func() {
+			BeforeEach(func() {
+				reconciler = controllers.NewKindClusterReconciler(clusterClient, kindClusterClient, clusterProvider, kubeconfigStore, diagnosticsStore, recorder, controllers.Options{
+					HealthCheckInterval: time.Minute,
+				})
+			})
+
+			It("does not check whether the kind cluster is idle", func() {
+				Expect(clusterProvider.GetMutatingRequestsCallCount()).To(Equal(0))
+				Expect(kindClusterClient.SuspendIdleCallCount()).To(Equal(0))
+			})
+
+			When("the KindCluster sets a timeout", func() {
+				BeforeEach(func() {
+					kindCluster.Annotations = map[string]string{kclusterv1.IdleTimeoutAnnotation: "1h"}
+				})
+
+				It("checks whether it is idle", func() {
+					Expect(clusterProvider.GetMutatingRequestsCallCount()).To(Equal(1))
+				})
 			})
 		})
 	})
@@ -419,6 +2393,78 @@ var _ = Describe("KindclusterController", func() {
 			Expect(actualCluster).To(Equal(kindCluster))
 		})
 
+		When("the KindCluster is protected from deletion", func() {
+			BeforeEach(func() {
+				kindCluster.Annotations = map[string]string{kclusterv1.DeletionProtectionAnnotation: "true"}
+			})
+
+			It("does not delete the cluster", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(clusterProvider.DeleteCallCount()).To(Equal(0))
+				Expect(clusterProvider.ReleaseOwnerCallCount()).To(Equal(0))
+			})
+
+			It("does not remove the finalizer", func() {
+				Expect(kindClusterClient.RemoveFinalizerCallCount()).To(Equal(0))
+			})
+
+			It("emits a warning event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Warning DeletionProtected")))
+			})
+		})
+
+		When("the deletion policy is Retain", func() {
+			BeforeEach(func() {
+				kindCluster.Spec.DeletionPolicy = kclusterv1.DeletionPolicyRetain
+			})
+
+			It("does not delete the cluster", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(clusterProvider.DeleteCallCount()).To(Equal(0))
+			})
+
+			It("releases the ownership of the kind cluster", func() {
+				Expect(clusterProvider.ReleaseOwnerCallCount()).To(Equal(1))
+				Expect(clusterProvider.ReleaseOwnerArgsForCall(0)).To(Equal(kindCluster))
+			})
+
+			It("removes the finalizer", func() {
+				Expect(kindClusterClient.RemoveFinalizerCallCount()).To(Equal(1))
+			})
+
+			It("emits an event with the name of the retained cluster", func() {
+				Expect(recorder.Events).To(Receive(And(
+					ContainSubstring("Normal Retained"),
+					ContainSubstring("the-kind-cluster-name"),
+				)))
+			})
+
+			When("releasing the ownership fails", func() {
+				BeforeEach(func() {
+					clusterProvider.ReleaseOwnerReturns(errors.New("boom"))
+				})
+
+				It("returns an error", func() {
+					Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				})
+
+				It("does not remove the finalizer", func() {
+					Expect(kindClusterClient.RemoveFinalizerCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		When("the kind cluster was scheduled on a host", func() {
+			BeforeEach(func() {
+				kindCluster.Status.HostRef = &corev1.LocalObjectReference{Name: "remote"}
+			})
+
+			It("keeps the host in the status", func() {
+				_, actualStatus, _ := kindClusterClient.UpdateStatusArgsForCall(0)
+				Expect(actualStatus.HostRef).To(Equal(&corev1.LocalObjectReference{Name: "remote"}))
+			})
+		})
+
 		When("updating the status fails", func() {
 			BeforeEach(func() {
 				kindClusterClient.UpdateStatusReturns(errors.New("boom"))
@@ -0,0 +1,321 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+//counterfeiter:generate . AccessProvider
+//counterfeiter:generate . KindClusterAccessClient
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusteraccesses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusteraccesses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusteraccesses/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+
+type AccessProvider interface {
+	GrantAccess(*kclusterv1.KindCluster, *kclusterv1.KindClusterAccess) error
+	RevokeAccess(*kclusterv1.KindCluster, *kclusterv1.KindClusterAccess) error
+	GetCACertHash(*kclusterv1.KindCluster) (string, error)
+	IssueKubeconfig(*kclusterv1.KindCluster, *kclusterv1.KindClusterAccess) ([]byte, time.Time, error)
+}
+
+type KindClusterAccessClient interface {
+	Get(context.Context, types.NamespacedName) (*kclusterv1.KindClusterAccess, error)
+	ListForKindCluster(context.Context, *kclusterv1.KindCluster) ([]kclusterv1.KindClusterAccess, error)
+	AddFinalizer(context.Context, *kclusterv1.KindClusterAccess) error
+	RemoveFinalizer(context.Context, *kclusterv1.KindClusterAccess) error
+	UpdateStatus(context.Context, kclusterv1.KindClusterAccessStatus, *kclusterv1.KindClusterAccess) error
+	StoreKubeconfig(context.Context, *kclusterv1.KindClusterAccess, []byte) error
+	KubeconfigExists(context.Context, *kclusterv1.KindClusterAccess) (bool, error)
+}
+
+// KindClusterAccessReconciler binds the ClusterRole of a KindClusterAccess in
+// the kind cluster of its KindCluster and keeps a kubeconfig with a client
+// certificate for its user in a Secret, issuing a new certificate once two
+// thirds of the expiry of the current one have passed.
+type KindClusterAccessReconciler struct {
+	accesses       KindClusterAccessClient
+	kindClusters   KindClusterClient
+	accessProvider AccessProvider
+	recorder       record.EventRecorder
+	options        Options
+}
+
+// NewKindClusterAccessReconciler creates a KindClusterAccessReconciler. Only
+// the HealthCheckInterval of the options is used.
+func NewKindClusterAccessReconciler(
+	accesses KindClusterAccessClient,
+	kindClusters KindClusterClient,
+	accessProvider AccessProvider,
+	recorder record.EventRecorder,
+	options Options,
+) *KindClusterAccessReconciler {
+	return &KindClusterAccessReconciler{
+		accesses:       accesses,
+		kindClusters:   kindClusters,
+		accessProvider: accessProvider,
+		recorder:       recorder,
+		options:        options,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KindClusterAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kclusterv1.KindClusterAccess{}).
+		Owns(&corev1.Secret{}).
+		Watches(&kclusterv1.KindCluster{}, handler.EnqueueRequestsFromMapFunc(r.AccessesForKindCluster)).
+		Complete(r)
+}
+
+// AccessesForKindCluster maps a KindCluster to reconcile requests for the
+// KindClusterAccesses referencing it, so that access is granted once it is
+// Ready and again after its kind cluster was created again.
+func (r *KindClusterAccessReconciler) AccessesForKindCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	kindCluster, ok := obj.(*kclusterv1.KindCluster)
+	if !ok {
+		return nil
+	}
+
+	accesses, err := r.accesses.ListForKindCluster(ctx, kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to list KindClusterAccesses", "kind-cluster", kindCluster.Name)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, access := range accesses {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: access.Namespace, Name: access.Name},
+		})
+	}
+
+	return requests
+}
+
+func (r *KindClusterAccessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	access, err := r.accesses.Get(ctx, req.NamespacedName)
+	if k8serrors.IsNotFound(err) {
+		logger.Info("KindClusterAccess no longer exists")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to get KindClusterAccess")
+		return ctrl.Result{}, err
+	}
+
+	if !access.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, access)
+	}
+
+	if !controllerutil.ContainsFinalizer(access, k8s.ClusterAccessFinalizer) {
+		err = r.accesses.AddFinalizer(ctx, access)
+		if err != nil {
+			logger.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	status := access.Status.DeepCopy()
+	result, err := r.reconcileAccess(ctx, access, status)
+
+	updateErr := r.accesses.UpdateStatus(ctx, *status, access)
+	if updateErr != nil {
+		logger.Error(updateErr, "failed to update status")
+		if err == nil {
+			err = updateErr
+		}
+	}
+
+	return result, err
+}
+
+// reconcileAccess binds the ClusterRole in the kind cluster and issues a new
+// client certificate if there is none yet, it is due for rotation, the
+// KindClusterAccess changed or the kind cluster has a new CA.
+func (r *KindClusterAccessReconciler) reconcileAccess(ctx context.Context, access *kclusterv1.KindClusterAccess, status *kclusterv1.KindClusterAccessStatus) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	kindCluster, err := r.kindClusters.Get(ctx, types.NamespacedName{Namespace: access.Namespace, Name: access.Spec.KindClusterRef.Name})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "failed to get KindCluster")
+		return ctrl.Result{}, err
+	}
+	if err != nil || !kindCluster.Status.Ready {
+		logger.Info("waiting for KindCluster to be ready", "kind-cluster", access.Spec.KindClusterRef.Name)
+		status.Ready = false
+		setAccessCondition(status, conditions.FalseCondition(kclusterv1.AccessGrantedCondition,
+			kclusterv1.WaitingForKindClusterReason, clusterv1.ConditionSeverityInfo,
+			"KindCluster %s is not ready", access.Spec.KindClusterRef.Name))
+		return ctrl.Result{}, nil
+	}
+
+	err = r.accessProvider.GrantAccess(kindCluster, access)
+	if err != nil {
+		logger.Error(err, "failed to grant access")
+		return ctrl.Result{}, r.accessFailed(access, status, "Failed to bind ClusterRole %s: %v", access.Spec.ClusterRole, err)
+	}
+
+	caCertHash, err := r.accessProvider.GetCACertHash(kindCluster)
+	if err != nil {
+		logger.Error(err, "failed to get CA certificate")
+		return ctrl.Result{}, r.accessFailed(access, status, "Failed to read CA certificate: %v", err)
+	}
+
+	exists, err := r.accesses.KubeconfigExists(ctx, access)
+	if err != nil {
+		logger.Error(err, "failed to get kubeconfig Secret")
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	if !exists || status.IssuedAt == nil || status.ExpiresAt == nil || !now.Before(renewAt(status)) ||
+		status.ObservedGeneration != access.Generation || status.CACertHash != caCertHash {
+		rotated := status.ExpiresAt != nil
+
+		kubeconfig, expiresAt, err := r.accessProvider.IssueKubeconfig(kindCluster, access)
+		if err != nil {
+			logger.Error(err, "failed to issue client certificate")
+			return ctrl.Result{}, r.accessFailed(access, status, "Failed to issue client certificate: %v", err)
+		}
+
+		err = r.accesses.StoreKubeconfig(ctx, access, kubeconfig)
+		if err != nil {
+			logger.Error(err, "failed to store kubeconfig")
+			return ctrl.Result{}, err
+		}
+
+		status.SecretName = access.GetSecretName()
+		status.IssuedAt = &metav1.Time{Time: now}
+		status.ExpiresAt = &metav1.Time{Time: expiresAt}
+		status.CACertHash = caCertHash
+		status.ObservedGeneration = access.Generation
+
+		reason, verb := "CertificateIssued", "Issued"
+		if rotated {
+			reason, verb = "CertificateRotated", "Rotated"
+		}
+		logger.Info("issued client certificate", "expires-at", expiresAt)
+		r.recorder.Eventf(access, corev1.EventTypeNormal, reason,
+			"%s client certificate for user %s, valid until %s", verb, access.Spec.User, expiresAt.UTC().Format(time.RFC3339))
+	}
+
+	status.Ready = true
+	setAccessCondition(status, conditions.TrueCondition(kclusterv1.AccessGrantedCondition))
+
+	result := ctrl.Result{RequeueAfter: time.Until(renewAt(status))}
+	if r.options.HealthCheckInterval > 0 && r.options.HealthCheckInterval < result.RequeueAfter {
+		result.RequeueAfter = r.options.HealthCheckInterval
+	}
+	return result, nil
+}
+
+func (r *KindClusterAccessReconciler) reconcileDeletion(ctx context.Context, access *kclusterv1.KindClusterAccess) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling delete")
+	defer logger.Info("done reconciling delete")
+
+	if !controllerutil.ContainsFinalizer(access, k8s.ClusterAccessFinalizer) {
+		logger.Info("access does not have finalizer")
+		return ctrl.Result{}, nil
+	}
+
+	// The bindings go with the kind cluster, so there is nothing to revoke
+	// once the KindCluster is gone or being deleted. Otherwise the
+	// KindClusterAccess is kept until the bindings are deleted, as the
+	// issued client certificates stay valid until they expire.
+	kindCluster, err := r.kindClusters.Get(ctx, types.NamespacedName{Namespace: access.Namespace, Name: access.Spec.KindClusterRef.Name})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "failed to get KindCluster")
+		return ctrl.Result{}, err
+	}
+	if err == nil && kindCluster.DeletionTimestamp.IsZero() {
+		err = r.accessProvider.RevokeAccess(kindCluster, access)
+		if err != nil {
+			logger.Error(err, "failed to revoke access")
+			r.recorder.Eventf(access, corev1.EventTypeWarning, "RevokeFailed",
+				"Failed to delete bindings of user %s: %v", access.Spec.User, err)
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(access, corev1.EventTypeNormal, "AccessRevoked",
+			"Deleted bindings of user %s", access.Spec.User)
+	}
+
+	err = r.accesses.RemoveFinalizer(ctx, access)
+	if err != nil {
+		logger.Error(err, "failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// accessFailed records that granting access failed and returns an error, so
+// that it is retried.
+func (r *KindClusterAccessReconciler) accessFailed(access *kclusterv1.KindClusterAccess, status *kclusterv1.KindClusterAccessStatus, format string, args ...any) error {
+	condition := conditions.FalseCondition(kclusterv1.AccessGrantedCondition,
+		kclusterv1.AccessGrantFailedReason, clusterv1.ConditionSeverityWarning, format, args...)
+	status.Ready = false
+	setAccessCondition(status, condition)
+	r.recorder.Event(access, corev1.EventTypeWarning, kclusterv1.AccessGrantFailedReason, condition.Message)
+	return errors.New(condition.Message)
+}
+
+// renewAt returns when the current client certificate is rotated, once two
+// thirds of its expiry have passed.
+func renewAt(status *kclusterv1.KindClusterAccessStatus) time.Time {
+	validFor := status.ExpiresAt.Sub(status.IssuedAt.Time)
+	return status.IssuedAt.Add(validFor * 2 / 3)
+}
+
+// setAccessCondition sets the condition on the status, preserving the
+// transition time as long as the condition status does not change.
+func setAccessCondition(status *kclusterv1.KindClusterAccessStatus, condition *clusterv1.Condition) {
+	holder := &kclusterv1.KindClusterAccess{Status: *status}
+	if existing := conditions.Get(holder, condition.Type); existing != nil && existing.Status == condition.Status {
+		conditions.Delete(holder, condition.Type)
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	conditions.Set(holder, condition)
+	*status = holder.Status
+}
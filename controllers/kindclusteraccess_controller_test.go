@@ -0,0 +1,406 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindClusterAccessController", func() {
+	var (
+		reconciler        *controllers.KindClusterAccessReconciler
+		accessProvider    *controllersfakes.FakeAccessProvider
+		accessClient      *controllersfakes.FakeKindClusterAccessClient
+		kindClusterClient *controllersfakes.FakeKindClusterClient
+		recorder          *record.FakeRecorder
+		ctx               context.Context
+		result            ctrl.Result
+		reconcileErr      error
+		access            *kclusterv1.KindClusterAccess
+		kindCluster       *kclusterv1.KindCluster
+		expiresAt         time.Time
+	)
+
+	lastStatus := func() kclusterv1.KindClusterAccessStatus {
+		count := accessClient.UpdateStatusCallCount()
+		Expect(count).To(BeNumerically(">=", 1))
+		_, status, _ := accessClient.UpdateStatusArgsForCall(count - 1)
+		return status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		accessProvider = new(controllersfakes.FakeAccessProvider)
+		accessClient = new(controllersfakes.FakeKindClusterAccessClient)
+		kindClusterClient = new(controllersfakes.FakeKindClusterClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = controllers.NewKindClusterAccessReconciler(accessClient, kindClusterClient, accessProvider, recorder, controllers.Options{
+			HealthCheckInterval: time.Minute,
+		})
+
+		access = &kclusterv1.KindClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "jane",
+				Namespace:  "bar",
+				UID:        "access-uid",
+				Generation: 1,
+				Finalizers: []string{k8s.ClusterAccessFinalizer},
+			},
+			Spec: kclusterv1.KindClusterAccessSpec{
+				KindClusterRef: corev1.LocalObjectReference{Name: "foo"},
+				User:           "jane",
+				Groups:         []string{"developers"},
+				ClusterRole:    "view",
+				Expiry:         &metav1.Duration{Duration: 3 * time.Hour},
+			},
+		}
+		accessClient.GetReturns(access, nil)
+		accessClient.KubeconfigExistsReturns(true, nil)
+
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: "the-kind-cluster-name",
+			},
+			Status: kclusterv1.KindClusterStatus{
+				Ready: true,
+				Phase: kclusterv1.ClusterPhaseReady,
+			},
+		}
+		kindClusterClient.GetReturns(kindCluster, nil)
+
+		expiresAt = time.Now().Add(3 * time.Hour)
+		accessProvider.GetCACertHashReturns("sha256:ca", nil)
+		accessProvider.IssueKubeconfigReturns([]byte("kubeconfig"), expiresAt, nil)
+	})
+
+	JustBeforeEach(func() {
+		result, reconcileErr = reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "jane", Namespace: "bar"},
+		})
+	})
+
+	It("gets the KindCluster of the access", func() {
+		Expect(kindClusterClient.GetCallCount()).To(Equal(1))
+		_, namespacedName := kindClusterClient.GetArgsForCall(0)
+		Expect(namespacedName).To(Equal(types.NamespacedName{Name: "foo", Namespace: "bar"}))
+	})
+
+	It("binds the ClusterRole in the kind cluster", func() {
+		Expect(accessProvider.GrantAccessCallCount()).To(Equal(1))
+		actualCluster, actualAccess := accessProvider.GrantAccessArgsForCall(0)
+		Expect(actualCluster).To(Equal(kindCluster))
+		Expect(actualAccess).To(Equal(access))
+	})
+
+	It("issues a client certificate", func() {
+		Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(1))
+		actualCluster, actualAccess := accessProvider.IssueKubeconfigArgsForCall(0)
+		Expect(actualCluster).To(Equal(kindCluster))
+		Expect(actualAccess).To(Equal(access))
+	})
+
+	It("stores the kubeconfig", func() {
+		Expect(accessClient.StoreKubeconfigCallCount()).To(Equal(1))
+		_, actualAccess, kubeconfig := accessClient.StoreKubeconfigArgsForCall(0)
+		Expect(actualAccess).To(Equal(access))
+		Expect(kubeconfig).To(Equal([]byte("kubeconfig")))
+	})
+
+	It("records the certificate in the status", func() {
+		status := lastStatus()
+		Expect(status.Ready).To(BeTrue())
+		Expect(status.SecretName).To(Equal("jane-access-kubeconfig"))
+		Expect(status.IssuedAt).NotTo(BeNil())
+		Expect(status.ExpiresAt.Time).To(BeTemporally("==", expiresAt))
+		Expect(status.CACertHash).To(Equal("sha256:ca"))
+		Expect(status.ObservedGeneration).To(BeEquivalentTo(1))
+		Expect(conditions.IsTrue(&kclusterv1.KindClusterAccess{Status: status}, kclusterv1.AccessGrantedCondition)).To(BeTrue())
+	})
+
+	It("emits a CertificateIssued event", func() {
+		Expect(recorder.Events).To(Receive(ContainSubstring("Normal CertificateIssued")))
+	})
+
+	It("checks the access again after the health check interval", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+
+	When("the certificate is still valid", func() {
+		BeforeEach(func() {
+			access.Status = kclusterv1.KindClusterAccessStatus{
+				Ready:              true,
+				SecretName:         "jane-access-kubeconfig",
+				IssuedAt:           &metav1.Time{Time: time.Now().Add(-time.Hour)},
+				ExpiresAt:          &metav1.Time{Time: time.Now().Add(2 * time.Hour)},
+				CACertHash:         "sha256:ca",
+				ObservedGeneration: 1,
+			}
+		})
+
+		It("does not issue a new one", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(0))
+			Expect(accessClient.StoreKubeconfigCallCount()).To(Equal(0))
+		})
+
+		It("still binds the ClusterRole", func() {
+			Expect(accessProvider.GrantAccessCallCount()).To(Equal(1))
+		})
+
+		When("the health checks are disabled", func() {
+			BeforeEach(func() {
+				reconciler = controllers.NewKindClusterAccessReconciler(accessClient, kindClusterClient, accessProvider, recorder, controllers.Options{})
+			})
+
+			It("checks the access again once the certificate is due for rotation", func() {
+				Expect(result.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+			})
+		})
+
+		When("two thirds of its expiry have passed", func() {
+			BeforeEach(func() {
+				access.Status.IssuedAt = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+				access.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			})
+
+			It("rotates the certificate", func() {
+				Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(1))
+				Expect(accessClient.StoreKubeconfigCallCount()).To(Equal(1))
+				Expect(lastStatus().ExpiresAt.Time).To(BeTemporally("==", expiresAt))
+			})
+
+			It("emits a CertificateRotated event", func() {
+				Expect(recorder.Events).To(Receive(ContainSubstring("Normal CertificateRotated")))
+			})
+		})
+
+		When("the access has changed", func() {
+			BeforeEach(func() {
+				access.Generation = 2
+			})
+
+			It("issues a new certificate", func() {
+				Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(1))
+				Expect(lastStatus().ObservedGeneration).To(BeEquivalentTo(2))
+			})
+		})
+
+		When("the kind cluster has a new CA", func() {
+			BeforeEach(func() {
+				accessProvider.GetCACertHashReturns("sha256:new-ca", nil)
+			})
+
+			It("issues a new certificate", func() {
+				Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(1))
+				Expect(lastStatus().CACertHash).To(Equal("sha256:new-ca"))
+			})
+		})
+
+		When("the kubeconfig Secret is gone", func() {
+			BeforeEach(func() {
+				accessClient.KubeconfigExistsReturns(false, nil)
+			})
+
+			It("issues a new certificate", func() {
+				Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(1))
+				Expect(accessClient.StoreKubeconfigCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	When("the access does not have the finalizer", func() {
+		BeforeEach(func() {
+			access.Finalizers = nil
+		})
+
+		It("adds the finalizer", func() {
+			Expect(accessClient.AddFinalizerCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the access no longer exists", func() {
+		BeforeEach(func() {
+			accessClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "jane"))
+		})
+
+		It("does nothing", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(accessProvider.GrantAccessCallCount()).To(Equal(0))
+			Expect(accessClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the KindCluster is not ready", func() {
+		BeforeEach(func() {
+			kindCluster.Status.Ready = false
+		})
+
+		It("does not grant access", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(accessProvider.GrantAccessCallCount()).To(Equal(0))
+			Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(0))
+		})
+
+		It("waits for the KindCluster", func() {
+			status := lastStatus()
+			Expect(status.Ready).To(BeFalse())
+			condition := conditions.Get(&kclusterv1.KindClusterAccess{Status: status}, kclusterv1.AccessGrantedCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal(kclusterv1.WaitingForKindClusterReason))
+		})
+	})
+
+	When("the KindCluster does not exist", func() {
+		BeforeEach(func() {
+			kindClusterClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "foo"))
+		})
+
+		It("waits for the KindCluster", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(accessProvider.GrantAccessCallCount()).To(Equal(0))
+			Expect(lastStatus().Ready).To(BeFalse())
+		})
+	})
+
+	When("binding the ClusterRole fails", func() {
+		BeforeEach(func() {
+			accessProvider.GrantAccessReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+		})
+
+		It("does not issue a certificate", func() {
+			Expect(accessProvider.IssueKubeconfigCallCount()).To(Equal(0))
+		})
+
+		It("reports the failure", func() {
+			status := lastStatus()
+			Expect(status.Ready).To(BeFalse())
+			condition := conditions.Get(&kclusterv1.KindClusterAccess{Status: status}, kclusterv1.AccessGrantedCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.AccessGrantFailedReason))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning AccessGrantFailed")))
+		})
+	})
+
+	When("issuing the certificate fails", func() {
+		BeforeEach(func() {
+			accessProvider.IssueKubeconfigReturns(nil, time.Time{}, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+		})
+
+		It("does not store a kubeconfig", func() {
+			Expect(accessClient.StoreKubeconfigCallCount()).To(Equal(0))
+		})
+	})
+
+	When("storing the kubeconfig fails", func() {
+		BeforeEach(func() {
+			accessClient.StoreKubeconfigReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError("boom"))
+		})
+
+		It("does not record the certificate", func() {
+			Expect(lastStatus().ExpiresAt).To(BeNil())
+		})
+	})
+
+	Describe("AccessesForKindCluster", func() {
+		It("maps the KindCluster to its accesses", func() {
+			accessClient.ListForKindClusterReturns([]kclusterv1.KindClusterAccess{*access}, nil)
+
+			requests := reconciler.AccessesForKindCluster(ctx, kindCluster)
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].NamespacedName).To(Equal(types.NamespacedName{Name: "jane", Namespace: "bar"}))
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			now := metav1.Now()
+			access.DeletionTimestamp = &now
+		})
+
+		It("revokes the access", func() {
+			Expect(accessProvider.RevokeAccessCallCount()).To(Equal(1))
+			actualCluster, actualAccess := accessProvider.RevokeAccessArgsForCall(0)
+			Expect(actualCluster).To(Equal(kindCluster))
+			Expect(actualAccess).To(Equal(access))
+			Expect(recorder.Events).To(Receive(ContainSubstring("Normal AccessRevoked")))
+		})
+
+		It("removes the finalizer", func() {
+			Expect(accessClient.RemoveFinalizerCallCount()).To(Equal(1))
+		})
+
+		It("does not grant access", func() {
+			Expect(accessProvider.GrantAccessCallCount()).To(Equal(0))
+		})
+
+		When("revoking the access fails", func() {
+			BeforeEach(func() {
+				accessProvider.RevokeAccessReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError("boom"))
+			})
+
+			It("does not remove the finalizer", func() {
+				Expect(accessClient.RemoveFinalizerCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the KindCluster no longer exists", func() {
+			BeforeEach(func() {
+				kindClusterClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "foo"))
+			})
+
+			It("removes the finalizer without revoking the access", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(accessProvider.RevokeAccessCallCount()).To(Equal(0))
+				Expect(accessClient.RemoveFinalizerCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the KindCluster is being deleted", func() {
+			BeforeEach(func() {
+				now := metav1.Now()
+				kindCluster.DeletionTimestamp = &now
+			})
+
+			It("removes the finalizer without revoking the access", func() {
+				Expect(accessProvider.RevokeAccessCallCount()).To(Equal(0))
+				Expect(accessClient.RemoveFinalizerCallCount()).To(Equal(1))
+			})
+		})
+	})
+})
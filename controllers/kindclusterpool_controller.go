@@ -0,0 +1,432 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+//counterfeiter:generate . PoolProvider
+//counterfeiter:generate . KindClusterPoolClient
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusterpools,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusterpools/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindclusterpools/finalizers,verbs=update
+
+type PoolProvider interface {
+	Create(*kclusterv1.KindCluster, corev1.ObjectReference) error
+	Delete(*kclusterv1.KindCluster) error
+	CheckHealth(*kclusterv1.KindCluster) ([]byte, error)
+	SetOwner(*kclusterv1.KindCluster, corev1.ObjectReference) error
+}
+
+type KindClusterPoolClient interface {
+	Get(context.Context, types.NamespacedName) (*kclusterv1.KindClusterPool, error)
+	AddFinalizer(context.Context, *kclusterv1.KindClusterPool) error
+	RemoveFinalizer(context.Context, *kclusterv1.KindClusterPool) error
+	Claim(context.Context, string, *kclusterv1.KindCluster, *kclusterv1.KindClusterPool) error
+	UpdateStatus(context.Context, kclusterv1.KindClusterPoolStatus, *kclusterv1.KindClusterPool) error
+	MarkDeleting(context.Context, string, *kclusterv1.KindClusterPool) error
+}
+
+const (
+	// poolResyncInterval is how often a pool is reconciled while kind
+	// clusters are being created or handed over.
+	poolResyncInterval = 10 * time.Second
+
+	// poolClaimTimeout is how long a claimed kind cluster may take to be
+	// handed over to the KindCluster claiming it, before it is returned to
+	// the pool.
+	poolClaimTimeout = time.Minute
+
+	pooledClusterSuffixLength = 5
+)
+
+// KindClusterPoolReconciler keeps the number of idle kind clusters of a
+// KindClusterPool at its size. Kind clusters are created in the background
+// and handed over to the KindClusters claiming them.
+type KindClusterPoolReconciler struct {
+	pools        KindClusterPoolClient
+	kindClusters KindClusterClient
+	poolProvider PoolProvider
+	recorder     record.EventRecorder
+	options      Options
+
+	mu sync.Mutex
+	// creating are the names of the kind clusters being created.
+	creating map[string]struct{}
+}
+
+// NewKindClusterPoolReconciler creates a KindClusterPoolReconciler. Only the
+// HealthCheckInterval of the options is used.
+func NewKindClusterPoolReconciler(
+	pools KindClusterPoolClient,
+	kindClusters KindClusterClient,
+	poolProvider PoolProvider,
+	recorder record.EventRecorder,
+	options Options,
+) *KindClusterPoolReconciler {
+	return &KindClusterPoolReconciler{
+		pools:        pools,
+		kindClusters: kindClusters,
+		poolProvider: poolProvider,
+		recorder:     recorder,
+		options:      options,
+		creating:     map[string]struct{}{},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KindClusterPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kclusterv1.KindClusterPool{}).
+		Complete(r)
+}
+
+func (r *KindClusterPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	pool, err := r.pools.Get(ctx, req.NamespacedName)
+	if k8serrors.IsNotFound(err) {
+		logger.Info("KindClusterPool no longer exists")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to get KindClusterPool")
+		return ctrl.Result{}, err
+	}
+
+	if !pool.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, pool)
+	}
+
+	if !controllerutil.ContainsFinalizer(pool, k8s.ClusterPoolFinalizer) {
+		err = r.pools.AddFinalizer(ctx, pool)
+		if err != nil {
+			logger.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	hash := pool.TemplateHash()
+	status := kclusterv1.KindClusterPoolStatus{}
+	var available []kclusterv1.PooledCluster
+	for _, pooled := range pool.Status.Clusters {
+		keep, err := r.reconcilePooledCluster(ctx, pool, &pooled, hash)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !keep {
+			continue
+		}
+
+		if pooled.Phase == kclusterv1.PooledClusterPhaseClaimed {
+			status.Clusters = append(status.Clusters, pooled)
+		} else {
+			available = append(available, pooled)
+		}
+	}
+
+	// Remove the surplus idle kind clusters. The ones still being created
+	// are removed once they are idle.
+	for i := len(available) - 1; i >= 0 && len(available) > int(pool.Spec.Size); i-- {
+		if available[i].Phase != kclusterv1.PooledClusterPhaseIdle {
+			continue
+		}
+
+		logger.Info("deleting surplus kind cluster", "pooled-cluster", available[i].Name)
+		err = r.removePooledCluster(ctx, pool, available[i].Name)
+		if err != nil {
+			logger.Error(err, "failed to delete surplus kind cluster", "pooled-cluster", available[i].Name)
+			return ctrl.Result{}, err
+		}
+		available = append(available[:i], available[i+1:]...)
+	}
+
+	var created []string
+	for len(available) < int(pool.Spec.Size) {
+		name := pooledClusterName(pool)
+		created = append(created, name)
+		available = append(available, kclusterv1.PooledCluster{
+			Name:         name,
+			Phase:        kclusterv1.PooledClusterPhaseProvisioning,
+			TemplateHash: hash,
+		})
+	}
+
+	for _, pooled := range available {
+		if pooled.Phase == kclusterv1.PooledClusterPhaseIdle {
+			status.Ready++
+		}
+	}
+	status.Clusters = append(available, status.Clusters...)
+
+	err = r.pools.UpdateStatus(ctx, status, pool)
+	if err != nil {
+		logger.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	// The kind clusters are only created once they are recorded in the
+	// status, so that they are never lost track of.
+	for _, name := range created {
+		r.startCreating(name)
+		go r.createPooledCluster(logger.WithValues("pooled-cluster", name), pool.DeepCopy(), name)
+	}
+
+	if status.Ready < int32(len(status.Clusters)) { //nolint:gosec
+		return ctrl.Result{RequeueAfter: poolResyncInterval}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+}
+
+// reconcilePooledCluster updates the phase of the kind cluster of the pool
+// and returns whether it is still part of the pool. Kind clusters that
+// failed to create, are unhealthy or were created from an older template
+// are deleted. Claimed kind clusters are dropped from the pool once they
+// have been handed over to the KindCluster claiming them. Kind clusters
+// being deleted are dropped once their delete succeeds.
+func (r *KindClusterPoolReconciler) reconcilePooledCluster(ctx context.Context, pool *kclusterv1.KindClusterPool, pooled *kclusterv1.PooledCluster, hash string) (bool, error) {
+	logger := log.FromContext(ctx).WithValues("pooled-cluster", pooled.Name)
+
+	switch pooled.Phase {
+	case kclusterv1.PooledClusterPhaseClaimed:
+		return r.reconcileClaim(ctx, pool, pooled)
+
+	case kclusterv1.PooledClusterPhaseProvisioning:
+		if r.isCreating(pooled.Name) {
+			return true, nil
+		}
+
+	case kclusterv1.PooledClusterPhaseDeleting:
+		return false, r.deletePooledCluster(pool, pooled.Name)
+
+	case kclusterv1.PooledClusterPhaseIdle:
+		if pooled.TemplateHash != hash {
+			logger.Info("replacing kind cluster created from an older template")
+			return false, r.removePooledCluster(ctx, pool, pooled.Name)
+		}
+	}
+
+	_, err := r.poolProvider.CheckHealth(pooledKindCluster(pool, pooled.Name))
+	if err != nil {
+		logger.Info("kind cluster is unhealthy, replacing it", "reason", err.Error())
+		r.recorder.Eventf(pool, corev1.EventTypeWarning, "ClusterUnhealthy",
+			"Replacing kind cluster %s: %v", pooled.Name, err)
+		return false, r.removePooledCluster(ctx, pool, pooled.Name)
+	}
+
+	pooled.Phase = kclusterv1.PooledClusterPhaseIdle
+	return true, nil
+}
+
+// reconcileClaim drops the claimed kind cluster from the pool once it has
+// been handed over to the KindCluster claiming it, or returns it to the pool
+// if the KindCluster is gone or does not take it over in time.
+func (r *KindClusterPoolReconciler) reconcileClaim(ctx context.Context, pool *kclusterv1.KindClusterPool, pooled *kclusterv1.PooledCluster) (bool, error) {
+	logger := log.FromContext(ctx).WithValues("pooled-cluster", pooled.Name)
+
+	claimedBy := pooled.ClaimedBy
+	if claimedBy != nil && pooled.ClaimedAt != nil {
+		kindCluster, err := r.kindClusters.Get(ctx, types.NamespacedName{Namespace: claimedBy.Namespace, Name: claimedBy.Name})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			logger.Error(err, "failed to get claiming KindCluster")
+			return false, err
+		}
+
+		if err == nil && kindCluster.UID == claimedBy.UID {
+			if kindCluster.Spec.Name == pooled.Name {
+				logger.Info("kind cluster handed over", "kind-cluster", claimedBy.Name)
+				return false, nil
+			}
+			if time.Since(pooled.ClaimedAt.Time) < poolClaimTimeout {
+				return true, nil
+			}
+		}
+	}
+
+	logger.Info("returning abandoned claim to the pool")
+	err := r.poolProvider.SetOwner(pooledKindCluster(pool, pooled.Name), poolOwner(pool))
+	if err != nil {
+		logger.Error(err, "failed to reset owner of kind cluster")
+		return false, err
+	}
+
+	r.recorder.Eventf(pool, corev1.EventTypeNormal, "ClaimReleased",
+		"Returned abandoned claim of kind cluster %s to the pool", pooled.Name)
+	pooled.Phase = kclusterv1.PooledClusterPhaseIdle
+	pooled.ClaimedBy = nil
+	pooled.ClaimedAt = nil
+	return true, nil
+}
+
+func (r *KindClusterPoolReconciler) reconcileDeletion(ctx context.Context, pool *kclusterv1.KindClusterPool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("reconciling delete")
+	defer logger.Info("done reconciling delete")
+
+	if !controllerutil.ContainsFinalizer(pool, k8s.ClusterPoolFinalizer) {
+		logger.Info("pool does not have finalizer")
+		return ctrl.Result{}, nil
+	}
+
+	// Claimed kind clusters are left to the KindClusters claiming them.
+	for _, pooled := range pool.Status.Clusters {
+		if pooled.Phase == kclusterv1.PooledClusterPhaseClaimed {
+			continue
+		}
+
+		if r.isCreating(pooled.Name) {
+			logger.Info("waiting for kind cluster to be created before deleting it", "pooled-cluster", pooled.Name)
+			return ctrl.Result{RequeueAfter: poolResyncInterval}, nil
+		}
+
+		err := r.removePooledCluster(ctx, pool, pooled.Name)
+		if err != nil {
+			logger.Error(err, "failed to delete kind cluster", "pooled-cluster", pooled.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	err := r.pools.RemoveFinalizer(ctx, pool)
+	if err != nil {
+		logger.Error(err, "failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// createPooledCluster creates the kind cluster and records the pool as its
+// owner. The kind cluster becomes idle on the next reconcile of the pool
+// once it is healthy. If creating it fails its retained nodes are deleted,
+// so that the pool replaces it.
+func (r *KindClusterPoolReconciler) createPooledCluster(logger logr.Logger, pool *kclusterv1.KindClusterPool, name string) {
+	defer r.doneCreating(name)
+
+	logger.Info("creating pooled kind cluster")
+	kindCluster := pooledKindCluster(pool, name)
+	err := r.poolProvider.Create(kindCluster, poolOwner(pool))
+	if err != nil {
+		logger.Error(err, "failed to create pooled kind cluster")
+		r.recorder.Eventf(pool, corev1.EventTypeWarning, "CreateFailed",
+			"Failed to create kind cluster %s: %v", name, err)
+		err = r.poolProvider.Delete(kindCluster)
+		if err != nil {
+			logger.Error(err, "failed to delete nodes retained after failed create")
+		}
+		return
+	}
+
+	err = r.poolProvider.SetOwner(kindCluster, poolOwner(pool))
+	if err != nil {
+		logger.Error(err, "failed to set owner of pooled kind cluster")
+	}
+
+	logger.Info("pooled kind cluster created")
+	r.recorder.Eventf(pool, corev1.EventTypeNormal, "ClusterCreated", "Created kind cluster %s", name)
+}
+
+// removePooledCluster marks the kind cluster as being deleted before
+// deleting it. The status of the pool may be stale, so marking it fails if
+// the kind cluster has been claimed since the pool was read.
+func (r *KindClusterPoolReconciler) removePooledCluster(ctx context.Context, pool *kclusterv1.KindClusterPool, name string) error {
+	err := r.pools.MarkDeleting(ctx, name, pool)
+	if err != nil {
+		return fmt.Errorf("failed to mark kind cluster %s as deleting: %w", name, err)
+	}
+
+	return r.deletePooledCluster(pool, name)
+}
+
+func (r *KindClusterPoolReconciler) deletePooledCluster(pool *kclusterv1.KindClusterPool, name string) error {
+	return r.poolProvider.Delete(pooledKindCluster(pool, name))
+}
+
+func (r *KindClusterPoolReconciler) startCreating(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.creating[name] = struct{}{}
+}
+
+func (r *KindClusterPoolReconciler) doneCreating(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.creating, name)
+}
+
+func (r *KindClusterPoolReconciler) isCreating(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.creating[name]
+	return ok
+}
+
+// pooledKindCluster returns the KindCluster the kind cluster of the pool with
+// the given name is created and managed through.
+func pooledKindCluster(pool *kclusterv1.KindClusterPool, name string) *kclusterv1.KindCluster {
+	spec := pool.Spec.Template.DeepCopy()
+	spec.Name = name
+	return &kclusterv1.KindCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: pool.Namespace,
+		},
+		Spec: *spec,
+	}
+}
+
+// pooledClusterName returns a new random kind cluster name for the pool,
+// prefixed with its namespace and name.
+func pooledClusterName(pool *kclusterv1.KindClusterPool) string {
+	prefix := fmt.Sprintf("%s-%s", pool.Namespace, pool.Name)
+	maxPrefixLength := maxKindClusterNameLength - pooledClusterSuffixLength - 1
+	if len(prefix) > maxPrefixLength {
+		prefix = strings.TrimRight(prefix[:maxPrefixLength], "-.")
+	}
+
+	return fmt.Sprintf("%s-%s", prefix, rand.String(pooledClusterSuffixLength))
+}
+
+func poolOwner(pool *kclusterv1.KindClusterPool) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: kclusterv1.GroupVersion.String(),
+		Kind:       "KindClusterPool",
+		Namespace:  pool.Namespace,
+		Name:       pool.Name,
+		UID:        pool.UID,
+	}
+}
@@ -0,0 +1,543 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindClusterPoolController", func() {
+	var (
+		reconciler        *controllers.KindClusterPoolReconciler
+		poolProvider      *controllersfakes.FakePoolProvider
+		poolClient        *controllersfakes.FakeKindClusterPoolClient
+		kindClusterClient *controllersfakes.FakeKindClusterClient
+		recorder          *record.FakeRecorder
+		ctx               context.Context
+		result            ctrl.Result
+		reconcileErr      error
+		pool              *kclusterv1.KindClusterPool
+		hash              string
+	)
+
+	lastStatus := func() kclusterv1.KindClusterPoolStatus {
+		count := poolClient.UpdateStatusCallCount()
+		Expect(count).To(BeNumerically(">=", 1))
+		_, status, _ := poolClient.UpdateStatusArgsForCall(count - 1)
+		return status
+	}
+
+	clusterNames := func(clusters []kclusterv1.PooledCluster) []string {
+		names := []string{}
+		for _, pooled := range clusters {
+			names = append(names, pooled.Name)
+		}
+		return names
+	}
+
+	reconcile := func() (ctrl.Result, error) {
+		return reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "ci", Namespace: "bar"},
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		poolProvider = new(controllersfakes.FakePoolProvider)
+		poolClient = new(controllersfakes.FakeKindClusterPoolClient)
+		kindClusterClient = new(controllersfakes.FakeKindClusterClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = controllers.NewKindClusterPoolReconciler(poolClient, kindClusterClient, poolProvider, recorder, controllers.Options{
+			HealthCheckInterval: time.Minute,
+		})
+
+		pool = &kclusterv1.KindClusterPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "ci",
+				Namespace:  "bar",
+				UID:        "pool-uid",
+				Finalizers: []string{k8s.ClusterPoolFinalizer},
+			},
+			Spec: kclusterv1.KindClusterPoolSpec{
+				Size: 2,
+				Template: kclusterv1.KindClusterSpec{
+					WorkerNodes:       1,
+					KubernetesVersion: "v1.31.0",
+				},
+			},
+		}
+		hash = pool.TemplateHash()
+		pool.Status.Clusters = []kclusterv1.PooledCluster{
+			{Name: "bar-ci-aaaaa", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: hash},
+			{Name: "bar-ci-bbbbb", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: hash},
+		}
+		pool.Status.Ready = 2
+		poolClient.GetReturns(pool, nil)
+	})
+
+	JustBeforeEach(func() {
+		result, reconcileErr = reconcile()
+	})
+
+	It("gets the pool", func() {
+		Expect(poolClient.GetCallCount()).To(Equal(1))
+		_, namespacedName := poolClient.GetArgsForCall(0)
+		Expect(namespacedName).To(Equal(types.NamespacedName{Name: "ci", Namespace: "bar"}))
+	})
+
+	It("checks the health of the idle kind clusters", func() {
+		Expect(poolProvider.CheckHealthCallCount()).To(Equal(2))
+		kindCluster := poolProvider.CheckHealthArgsForCall(0)
+		Expect(kindCluster.Spec.Name).To(Equal("bar-ci-aaaaa"))
+		Expect(kindCluster.Spec.WorkerNodes).To(Equal(int32(1)))
+		Expect(kindCluster.Spec.KubernetesVersion).To(Equal("v1.31.0"))
+	})
+
+	It("keeps the idle kind clusters", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		status := lastStatus()
+		Expect(status.Ready).To(Equal(int32(2)))
+		Expect(status.Clusters).To(Equal(pool.Status.Clusters))
+		Expect(poolProvider.CreateCallCount()).To(Equal(0))
+		Expect(poolProvider.DeleteCallCount()).To(Equal(0))
+	})
+
+	It("requeues after the health check interval", func() {
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+
+	When("the pool does not have the finalizer", func() {
+		BeforeEach(func() {
+			pool.Finalizers = nil
+		})
+
+		It("adds it", func() {
+			Expect(poolClient.AddFinalizerCallCount()).To(Equal(1))
+		})
+	})
+
+	It("does not add the finalizer again", func() {
+		Expect(poolClient.AddFinalizerCallCount()).To(Equal(0))
+	})
+
+	When("the pool does not exist", func() {
+		BeforeEach(func() {
+			poolClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "ci"))
+		})
+
+		It("does nothing", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(poolClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("getting the pool fails", func() {
+		BeforeEach(func() {
+			poolClient.GetReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("the pool has fewer kind clusters than its size", func() {
+		BeforeEach(func() {
+			pool.Spec.Size = 3
+		})
+
+		It("records a new kind cluster as provisioning", func() {
+			status := lastStatus()
+			Expect(status.Clusters).To(HaveLen(3))
+			created := status.Clusters[2]
+			Expect(created.Name).To(MatchRegexp(`^bar-ci-[a-z0-9]{5}$`))
+			Expect(created.Phase).To(Equal(kclusterv1.PooledClusterPhaseProvisioning))
+			Expect(created.TemplateHash).To(Equal(hash))
+			Expect(status.Ready).To(Equal(int32(2)))
+		})
+
+		It("creates the kind cluster in the background", func() {
+			Eventually(poolProvider.CreateCallCount).Should(Equal(1))
+			kindCluster, _ := poolProvider.CreateArgsForCall(0)
+			Expect(kindCluster.Spec.Name).To(Equal(lastStatus().Clusters[2].Name))
+			Expect(kindCluster.Spec.WorkerNodes).To(Equal(int32(1)))
+		})
+
+		It("records the pool as the owner while creating the kind cluster", func() {
+			Eventually(poolProvider.CreateCallCount).Should(Equal(1))
+			_, owner := poolProvider.CreateArgsForCall(0)
+			Expect(owner.Kind).To(Equal("KindClusterPool"))
+			Expect(owner.Name).To(Equal("ci"))
+			Expect(owner.UID).To(BeEquivalentTo("pool-uid"))
+		})
+
+		It("records the pool as the owner of the kind cluster", func() {
+			Eventually(poolProvider.SetOwnerCallCount).Should(Equal(1))
+			_, owner := poolProvider.SetOwnerArgsForCall(0)
+			Expect(owner.Kind).To(Equal("KindClusterPool"))
+			Expect(owner.Name).To(Equal("ci"))
+			Expect(owner.UID).To(BeEquivalentTo("pool-uid"))
+			Eventually(recorder.Events).Should(Receive(ContainSubstring("Normal ClusterCreated")))
+		})
+
+		It("requeues to pick up the created kind cluster", func() {
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+		})
+
+		When("creating the kind cluster fails", func() {
+			BeforeEach(func() {
+				poolProvider.CreateReturns(errors.New("boom"))
+			})
+
+			It("deletes the retained nodes", func() {
+				Eventually(poolProvider.DeleteCallCount).Should(Equal(1))
+				Eventually(recorder.Events).Should(Receive(ContainSubstring("Warning CreateFailed")))
+			})
+		})
+
+		When("updating the status fails", func() {
+			BeforeEach(func() {
+				poolClient.UpdateStatusReturns(errors.New("boom"))
+			})
+
+			It("does not create the kind cluster", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Consistently(poolProvider.CreateCallCount).Should(Equal(0))
+			})
+		})
+	})
+
+	When("a kind cluster is still being created", func() {
+		var release chan struct{}
+
+		BeforeEach(func() {
+			pool.Spec.Size = 3
+			release = make(chan struct{})
+			poolProvider.CreateStub = func(*kclusterv1.KindCluster, corev1.ObjectReference) error {
+				<-release
+				return nil
+			}
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("keeps it provisioning without checking its health", func() {
+			pool.Status = lastStatus()
+			Eventually(poolProvider.CreateCallCount).Should(Equal(1))
+
+			_, err := reconcile()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poolProvider.CheckHealthCallCount()).To(Equal(4))
+			status := lastStatus()
+			Expect(status.Clusters[2].Phase).To(Equal(kclusterv1.PooledClusterPhaseProvisioning))
+			Expect(poolProvider.CreateCallCount()).To(Equal(1))
+		})
+	})
+
+	When("a kind cluster has been created", func() {
+		BeforeEach(func() {
+			pool.Status.Clusters[1].Phase = kclusterv1.PooledClusterPhaseProvisioning
+		})
+
+		It("marks it idle", func() {
+			status := lastStatus()
+			Expect(status.Clusters[1].Phase).To(Equal(kclusterv1.PooledClusterPhaseIdle))
+			Expect(status.Ready).To(Equal(int32(2)))
+		})
+	})
+
+	When("a kind cluster is unhealthy", func() {
+		BeforeEach(func() {
+			poolProvider.CheckHealthStub = func(kindCluster *kclusterv1.KindCluster) ([]byte, error) {
+				if kindCluster.Spec.Name == "bar-ci-aaaaa" {
+					return nil, errors.New("boom")
+				}
+				return []byte("the-kubeconfig"), nil
+			}
+		})
+
+		It("replaces it", func() {
+			Expect(poolClient.MarkDeletingCallCount()).To(Equal(1))
+			_, actualName, _ := poolClient.MarkDeletingArgsForCall(0)
+			Expect(actualName).To(Equal("bar-ci-aaaaa"))
+			Expect(poolProvider.DeleteCallCount()).To(Equal(1))
+			Expect(poolProvider.DeleteArgsForCall(0).Spec.Name).To(Equal("bar-ci-aaaaa"))
+
+			status := lastStatus()
+			Expect(status.Clusters).To(HaveLen(2))
+			Expect(clusterNames(status.Clusters)).NotTo(ContainElement("bar-ci-aaaaa"))
+			Expect(status.Clusters[1].Phase).To(Equal(kclusterv1.PooledClusterPhaseProvisioning))
+			Eventually(poolProvider.CreateCallCount).Should(Equal(1))
+		})
+
+		It("emits a warning", func() {
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning ClusterUnhealthy")))
+		})
+
+		When("deleting it fails", func() {
+			BeforeEach(func() {
+				poolProvider.DeleteReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(poolClient.UpdateStatusCallCount()).To(Equal(0))
+			})
+		})
+
+		When("marking it as deleting fails", func() {
+			BeforeEach(func() {
+				poolClient.MarkDeletingReturns(errors.New("conflict"))
+			})
+
+			It("does not delete it", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("conflict")))
+				Expect(poolProvider.DeleteCallCount()).To(Equal(0))
+				Expect(poolClient.UpdateStatusCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	When("a kind cluster is being deleted", func() {
+		BeforeEach(func() {
+			pool.Status.Clusters[0].Phase = kclusterv1.PooledClusterPhaseDeleting
+		})
+
+		It("deletes it again and drops it from the pool", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(poolClient.MarkDeletingCallCount()).To(Equal(0))
+			Expect(poolProvider.DeleteCallCount()).To(Equal(1))
+			Expect(poolProvider.DeleteArgsForCall(0).Spec.Name).To(Equal("bar-ci-aaaaa"))
+			Expect(clusterNames(lastStatus().Clusters)).NotTo(ContainElement("bar-ci-aaaaa"))
+		})
+
+		It("does not check its health", func() {
+			Expect(poolProvider.CheckHealthCallCount()).To(Equal(1))
+		})
+
+		When("deleting it fails", func() {
+			BeforeEach(func() {
+				poolProvider.DeleteReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(poolClient.UpdateStatusCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	When("a kind cluster was created from an older template", func() {
+		BeforeEach(func() {
+			pool.Status.Clusters[0].TemplateHash = "old"
+		})
+
+		It("replaces it", func() {
+			Expect(poolProvider.DeleteCallCount()).To(Equal(1))
+			Expect(poolProvider.DeleteArgsForCall(0).Spec.Name).To(Equal("bar-ci-aaaaa"))
+			Expect(clusterNames(lastStatus().Clusters)).NotTo(ContainElement("bar-ci-aaaaa"))
+		})
+	})
+
+	When("the pool has more kind clusters than its size", func() {
+		BeforeEach(func() {
+			pool.Spec.Size = 1
+		})
+
+		It("deletes the surplus", func() {
+			Expect(poolClient.MarkDeletingCallCount()).To(Equal(1))
+			_, actualName, _ := poolClient.MarkDeletingArgsForCall(0)
+			Expect(actualName).To(Equal("bar-ci-bbbbb"))
+			Expect(poolProvider.DeleteCallCount()).To(Equal(1))
+			Expect(poolProvider.DeleteArgsForCall(0).Spec.Name).To(Equal("bar-ci-bbbbb"))
+
+			status := lastStatus()
+			Expect(clusterNames(status.Clusters)).To(Equal([]string{"bar-ci-aaaaa"}))
+			Expect(status.Ready).To(Equal(int32(1)))
+		})
+
+		When("the surplus kind cluster has been claimed since the pool was read", func() {
+			BeforeEach(func() {
+				poolClient.MarkDeletingReturns(errors.New("conflict"))
+			})
+
+			It("does not delete it", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("conflict")))
+				Expect(poolProvider.DeleteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("claimed kind clusters", func() {
+		var claimer *kclusterv1.KindCluster
+
+		BeforeEach(func() {
+			pool.Status.Clusters[0].Phase = kclusterv1.PooledClusterPhaseClaimed
+			pool.Status.Clusters[0].ClaimedBy = &corev1.ObjectReference{
+				Kind:      "KindCluster",
+				Namespace: "bar",
+				Name:      "foo",
+				UID:       "foo-uid",
+			}
+			pool.Status.Clusters[0].ClaimedAt = &metav1.Time{Time: time.Now()}
+
+			claimer = &kclusterv1.KindCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+					UID:       "foo-uid",
+				},
+			}
+			kindClusterClient.GetReturns(claimer, nil)
+		})
+
+		It("gets the claiming KindCluster", func() {
+			Expect(kindClusterClient.GetCallCount()).To(Equal(1))
+			_, namespacedName := kindClusterClient.GetArgsForCall(0)
+			Expect(namespacedName).To(Equal(types.NamespacedName{Namespace: "bar", Name: "foo"}))
+		})
+
+		It("keeps the claim while it is being handed over", func() {
+			status := lastStatus()
+			Expect(status.Clusters).To(ContainElement(pool.Status.Clusters[0]))
+			Expect(status.Ready).To(Equal(int32(1)))
+		})
+
+		It("replaces it", func() {
+			status := lastStatus()
+			Expect(status.Clusters).To(HaveLen(3))
+			Eventually(poolProvider.CreateCallCount).Should(Equal(1))
+		})
+
+		It("does not check its health", func() {
+			Expect(poolProvider.CheckHealthCallCount()).To(Equal(1))
+		})
+
+		It("requeues to follow the hand over", func() {
+			Expect(result.RequeueAfter).To(Equal(10 * time.Second))
+		})
+
+		When("the KindCluster has taken it over", func() {
+			BeforeEach(func() {
+				claimer.Spec.Name = "bar-ci-aaaaa"
+			})
+
+			It("drops it from the pool", func() {
+				Expect(clusterNames(lastStatus().Clusters)).NotTo(ContainElement("bar-ci-aaaaa"))
+				Expect(poolProvider.DeleteCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the KindCluster no longer exists", func() {
+			BeforeEach(func() {
+				kindClusterClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "foo"))
+			})
+
+			It("returns the kind cluster to the pool", func() {
+				status := lastStatus()
+				Expect(status.Clusters[0].Name).To(Equal("bar-ci-aaaaa"))
+				Expect(status.Clusters[0].Phase).To(Equal(kclusterv1.PooledClusterPhaseIdle))
+				Expect(status.Clusters[0].ClaimedBy).To(BeNil())
+				Expect(status.Ready).To(Equal(int32(2)))
+				Expect(recorder.Events).To(Receive(ContainSubstring("Normal ClaimReleased")))
+			})
+
+			It("records the pool as the owner again", func() {
+				Expect(poolProvider.SetOwnerCallCount()).To(Equal(1))
+				kindCluster, owner := poolProvider.SetOwnerArgsForCall(0)
+				Expect(kindCluster.Spec.Name).To(Equal("bar-ci-aaaaa"))
+				Expect(owner.Kind).To(Equal("KindClusterPool"))
+			})
+		})
+
+		When("the KindCluster has been recreated", func() {
+			BeforeEach(func() {
+				claimer.UID = "another-uid"
+			})
+
+			It("returns the kind cluster to the pool", func() {
+				Expect(lastStatus().Clusters[0].Phase).To(Equal(kclusterv1.PooledClusterPhaseIdle))
+			})
+		})
+
+		When("the KindCluster does not take it over in time", func() {
+			BeforeEach(func() {
+				pool.Status.Clusters[0].ClaimedAt = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+			})
+
+			It("returns the kind cluster to the pool", func() {
+				Expect(lastStatus().Clusters[0].Phase).To(Equal(kclusterv1.PooledClusterPhaseIdle))
+			})
+		})
+
+		When("getting the KindCluster fails", func() {
+			BeforeEach(func() {
+				kindClusterClient.GetReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			pool.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			pool.Status.Clusters[0].Phase = kclusterv1.PooledClusterPhaseClaimed
+		})
+
+		It("deletes the kind clusters still in the pool", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(poolProvider.DeleteCallCount()).To(Equal(1))
+			Expect(poolProvider.DeleteArgsForCall(0).Spec.Name).To(Equal("bar-ci-bbbbb"))
+		})
+
+		It("removes the finalizer", func() {
+			Expect(poolClient.RemoveFinalizerCallCount()).To(Equal(1))
+		})
+
+		It("does not update the status", func() {
+			Expect(poolClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+
+		When("deleting a kind cluster fails", func() {
+			BeforeEach(func() {
+				poolProvider.DeleteReturns(errors.New("boom"))
+			})
+
+			It("keeps the finalizer", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(poolClient.RemoveFinalizerCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the pool does not have the finalizer", func() {
+			BeforeEach(func() {
+				pool.Finalizers = nil
+			})
+
+			It("does nothing", func() {
+				Expect(poolProvider.DeleteCallCount()).To(Equal(0))
+				Expect(poolClient.RemoveFinalizerCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
@@ -0,0 +1,346 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+//counterfeiter:generate . ControlPlaneProvider
+//counterfeiter:generate . KindControlPlaneClient
+//counterfeiter:generate . ControlPlaneClusterClient
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindcontrolplanes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindcontrolplanes/status,verbs=get;update;patch
+
+// waitForKindClusterInterval is how often a KindControlPlane checks whether
+// the kind cluster of its Cluster has become ready.
+const waitForKindClusterInterval = 10 * time.Second
+
+type ControlPlaneProvider interface {
+	GetNodes(*kclusterv1.KindCluster) ([]kclusterv1.NodeStatus, error)
+	GetControlPlaneComponents(*kclusterv1.KindCluster) ([]kclusterv1.ControlPlaneComponent, error)
+	GetKubeconfig(*kclusterv1.KindCluster) ([]byte, error)
+	GetKubernetesVersion([]byte) (string, error)
+	AddControlPlaneNode(*kclusterv1.KindCluster, string) (string, error)
+	RemoveControlPlaneNode(*kclusterv1.KindCluster, string) error
+}
+
+type KindControlPlaneClient interface {
+	Get(context.Context, types.NamespacedName) (*kclusterv1.KindControlPlane, error)
+	UpdateStatus(context.Context, kclusterv1.KindControlPlaneStatus, *kclusterv1.KindControlPlane) error
+}
+
+type ControlPlaneClusterClient interface {
+	GetForControlPlane(context.Context, *kclusterv1.KindControlPlane) (*clusterv1.Cluster, error)
+	GetKindCluster(context.Context, *clusterv1.Cluster) (*kclusterv1.KindCluster, error)
+}
+
+// KindControlPlaneReconciler reconciles a KindControlPlane object
+type KindControlPlaneReconciler struct {
+	clusters             ControlPlaneClusterClient
+	controlPlanes        KindControlPlaneClient
+	controlPlaneProvider ControlPlaneProvider
+	recorder             record.EventRecorder
+	options              Options
+}
+
+// NewKindControlPlaneReconciler creates a KindControlPlaneReconciler. Only
+// the HealthCheckInterval of the options is used.
+func NewKindControlPlaneReconciler(
+	clusters ControlPlaneClusterClient,
+	controlPlanes KindControlPlaneClient,
+	controlPlaneProvider ControlPlaneProvider,
+	recorder record.EventRecorder,
+	options Options,
+) *KindControlPlaneReconciler {
+	return &KindControlPlaneReconciler{
+		clusters:             clusters,
+		controlPlanes:        controlPlanes,
+		controlPlaneProvider: controlPlaneProvider,
+		recorder:             recorder,
+		options:              options,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KindControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kclusterv1.KindControlPlane{}).
+		Complete(r)
+}
+
+func (r *KindControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	controlPlane, err := r.controlPlanes.Get(ctx, req.NamespacedName)
+	if k8serrors.IsNotFound(err) {
+		logger.Info("KindControlPlane no longer exists")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to get KindControlPlane")
+		return ctrl.Result{}, err
+	}
+
+	cluster, err := r.clusters.GetForControlPlane(ctx, controlPlane)
+	if err != nil {
+		logger.Error(err, "failed to get owner cluster")
+		return ctrl.Result{}, err
+	}
+
+	if cluster == nil {
+		logger.Info("KindControlPlane not owned by Cluster yet")
+		return ctrl.Result{}, nil
+	}
+
+	if annotations.IsPaused(cluster, controlPlane) {
+		logger.Info("reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
+	// The control plane nodes are deleted together with the kind cluster.
+	if !controlPlane.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	kindCluster, err := r.clusters.GetKindCluster(ctx, cluster)
+	if err != nil {
+		logger.Error(err, "failed to get KindCluster")
+		return ctrl.Result{}, err
+	}
+
+	// Conditions are set on a copy, the status of which is patched onto the
+	// KindControlPlane at the end of the reconcile.
+	observed := controlPlane.DeepCopy()
+	status := &observed.Status
+	defer r.updateStatus(logger, status, controlPlane)
+
+	if kindCluster == nil || !kindCluster.Status.Ready {
+		logger.Info("waiting for kind cluster to become ready")
+		status.Ready = false
+		conditions.Set(observed, conditions.FalseCondition(
+			clusterv1.ReadyCondition,
+			kclusterv1.WaitingForKindClusterReason,
+			clusterv1.ConditionSeverityInfo,
+			"kind cluster is not ready",
+		))
+		return ctrl.Result{RequeueAfter: waitForKindClusterInterval}, nil
+	}
+
+	logger = logger.WithValues("cluster-name", kindCluster.Spec.Name)
+	ctx = log.IntoContext(ctx, logger)
+
+	nodes, err := r.refreshStatus(ctx, cluster, kindCluster, observed)
+	if err != nil {
+		logger.Error(err, "failed to get control plane nodes")
+		return ctrl.Result{}, err
+	}
+
+	return r.scale(ctx, kindCluster, controlPlane, observed, nodes)
+}
+
+// refreshStatus records the control plane nodes, the health of their
+// components and the Kubernetes version in the status of observed and
+// returns the control plane nodes.
+func (r *KindControlPlaneReconciler) refreshStatus(
+	ctx context.Context,
+	cluster *clusterv1.Cluster,
+	kindCluster *kclusterv1.KindCluster,
+	observed *kclusterv1.KindControlPlane,
+) ([]kclusterv1.NodeStatus, error) {
+	logger := log.FromContext(ctx)
+	status := &observed.Status
+
+	nodes, err := r.controlPlaneProvider.GetNodes(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := r.controlPlaneProvider.GetControlPlaneComponents(kindCluster)
+	if err != nil {
+		logger.Info("failed to get control plane components", "reason", err.Error())
+		components = nil
+	}
+
+	ready := map[string]map[string]bool{}
+	for _, component := range components {
+		if ready[component.Node] == nil {
+			ready[component.Node] = map[string]bool{}
+		}
+		ready[component.Node][component.Name] = component.Ready
+	}
+
+	controlPlaneNodes := []kclusterv1.NodeStatus{}
+	status.UpdatedReplicas = 0
+	status.ReadyReplicas = 0
+	unhealthy := []string{}
+	for _, node := range nodes {
+		if node.Role != constants.ControlPlaneNodeRoleValue {
+			continue
+		}
+		controlPlaneNodes = append(controlPlaneNodes, node)
+
+		if kclusterv1.IsNodeImage(node.Image, observed.Spec.Version) {
+			status.UpdatedReplicas++
+		}
+
+		nodeReady := true
+		for _, name := range kclusterv1.ControlPlaneComponents {
+			if !ready[node.Name][name] {
+				nodeReady = false
+				unhealthy = append(unhealthy, fmt.Sprintf("%s/%s", node.Name, name))
+			}
+		}
+		if nodeReady {
+			status.ReadyReplicas++
+		}
+	}
+
+	status.Replicas = int32(len(controlPlaneNodes))
+	status.UnavailableReplicas = status.Replicas - status.ReadyReplicas
+	status.Selector = fmt.Sprintf("%s=%s", clusterv1.ClusterNameLabel, cluster.Name)
+	status.Components = components
+	// The control plane is initialized once the components of one of its
+	// nodes are ready, and stays initialized after that.
+	status.Initialized = status.Initialized || status.ReadyReplicas > 0
+	status.Ready = status.ReadyReplicas > 0
+
+	if len(unhealthy) == 0 {
+		conditions.MarkTrue(observed, kclusterv1.ControlPlaneComponentsHealthyCondition)
+	} else {
+		conditions.MarkFalse(observed, kclusterv1.ControlPlaneComponentsHealthyCondition,
+			kclusterv1.ControlPlaneComponentsUnhealthyReason, clusterv1.ConditionSeverityWarning,
+			"components not ready: %s", strings.Join(unhealthy, ", "))
+	}
+	if status.Ready {
+		conditions.MarkTrue(observed, clusterv1.ReadyCondition)
+	} else {
+		conditions.MarkFalse(observed, clusterv1.ReadyCondition, kclusterv1.ControlPlaneComponentsUnhealthyReason,
+			clusterv1.ConditionSeverityError, "no control plane node is ready")
+	}
+
+	version, err := r.getKubernetesVersion(kindCluster)
+	if err != nil {
+		logger.Info("failed to get kubernetes version", "reason", err.Error())
+	} else {
+		status.Version = ptr.To(version)
+	}
+
+	return controlPlaneNodes, nil
+}
+
+func (r *KindControlPlaneReconciler) getKubernetesVersion(kindCluster *kclusterv1.KindCluster) (string, error) {
+	kubeconfig, err := r.controlPlaneProvider.GetKubeconfig(kindCluster)
+	if err != nil {
+		return "", err
+	}
+
+	return r.controlPlaneProvider.GetKubernetesVersion(kubeconfig)
+}
+
+// scale adds or removes a single control plane node if the number of nodes
+// differs from the desired replicas. Nodes are added and removed one at a
+// time, so that etcd keeps its quorum.
+func (r *KindControlPlaneReconciler) scale(
+	ctx context.Context,
+	kindCluster *kclusterv1.KindCluster,
+	controlPlane *kclusterv1.KindControlPlane,
+	observed *kclusterv1.KindControlPlane,
+	nodes []kclusterv1.NodeStatus,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	desired := int(controlPlane.GetReplicas())
+	status := &observed.Status
+
+	if len(nodes) == desired {
+		status.FailureMessage = nil
+		conditions.MarkTrue(observed, kclusterv1.ResizedCondition)
+		return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+	}
+
+	if len(nodes) < desired {
+		conditions.MarkFalse(observed, kclusterv1.ResizedCondition, kclusterv1.ScalingUpReason, clusterv1.ConditionSeverityInfo,
+			"scaling up from %d to %d replicas", len(nodes), desired)
+		r.updateStatus(logger, status, controlPlane)
+
+		logger.Info("adding control plane node", "replicas", len(nodes), "desired", desired)
+		name, err := r.controlPlaneProvider.AddControlPlaneNode(kindCluster, controlPlane.Spec.Version)
+		if err != nil {
+			logger.Error(err, "failed to add control plane node")
+			status.FailureMessage = ptr.To(fmt.Sprintf("failed to add control plane node: %v", err))
+			r.recorder.Eventf(controlPlane, corev1.EventTypeWarning, "ScaleUpFailed",
+				"Failed to add control plane node to kind cluster %q: %v", kindCluster.Spec.Name, err)
+			return ctrl.Result{}, err
+		}
+
+		r.recorder.Eventf(controlPlane, corev1.EventTypeNormal, "ScaledUp",
+			"Added control plane node %q to kind cluster %q", name, kindCluster.Spec.Name)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	conditions.MarkFalse(observed, kclusterv1.ResizedCondition, kclusterv1.ScalingDownReason, clusterv1.ConditionSeverityInfo,
+		"scaling down from %d to %d replicas", len(nodes), desired)
+	r.updateStatus(logger, status, controlPlane)
+
+	// Remove the most recently created node. The first control plane node is
+	// the oldest and is never removed.
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].CreationTime.Before(nodes[j].CreationTime)
+	})
+	name := nodes[len(nodes)-1].Name
+
+	logger.Info("removing control plane node", "node", name, "replicas", len(nodes), "desired", desired)
+	err := r.controlPlaneProvider.RemoveControlPlaneNode(kindCluster, name)
+	if err != nil {
+		logger.Error(err, "failed to remove control plane node", "node", name)
+		status.FailureMessage = ptr.To(fmt.Sprintf("failed to remove control plane node %q: %v", name, err))
+		r.recorder.Eventf(controlPlane, corev1.EventTypeWarning, "ScaleDownFailed",
+			"Failed to remove control plane node %q from kind cluster %q: %v", name, kindCluster.Spec.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	r.recorder.Eventf(controlPlane, corev1.EventTypeNormal, "ScaledDown",
+		"Removed control plane node %q from kind cluster %q", name, kindCluster.Spec.Name)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+func (r *KindControlPlaneReconciler) updateStatus(logger logr.Logger, status *kclusterv1.KindControlPlaneStatus, controlPlane *kclusterv1.KindControlPlane) {
+	err := r.controlPlanes.UpdateStatus(context.Background(), *status, controlPlane)
+	if err != nil {
+		logger.Error(err, "failed to update status")
+	}
+}
@@ -0,0 +1,403 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+)
+
+var _ = Describe("KindControlPlaneController", func() {
+	var (
+		reconciler           *controllers.KindControlPlaneReconciler
+		controlPlaneProvider *controllersfakes.FakeControlPlaneProvider
+		controlPlaneClient   *controllersfakes.FakeKindControlPlaneClient
+		clusterClient        *controllersfakes.FakeControlPlaneClusterClient
+		recorder             *record.FakeRecorder
+		ctx                  context.Context
+		result               ctrl.Result
+		reconcileErr         error
+		controlPlane         *kclusterv1.KindControlPlane
+		kindCluster          *kclusterv1.KindCluster
+		cluster              *clusterv1.Cluster
+	)
+
+	controlPlaneNode := func(name string, age time.Duration) kclusterv1.NodeStatus {
+		return kclusterv1.NodeStatus{
+			Name:         name,
+			Role:         "control-plane",
+			Image:        "kindest/node:v1.31.0",
+			State:        "running",
+			CreationTime: ptr.To(metav1.NewTime(time.Now().Add(-age))),
+		}
+	}
+
+	readyComponents := func(node string) []kclusterv1.ControlPlaneComponent {
+		components := []kclusterv1.ControlPlaneComponent{}
+		for _, name := range kclusterv1.ControlPlaneComponents {
+			components = append(components, kclusterv1.ControlPlaneComponent{Node: node, Name: name, Ready: true})
+		}
+		return components
+	}
+
+	lastStatus := func() kclusterv1.KindControlPlaneStatus {
+		count := controlPlaneClient.UpdateStatusCallCount()
+		Expect(count).To(BeNumerically(">=", 1))
+		_, status, _ := controlPlaneClient.UpdateStatusArgsForCall(count - 1)
+		return status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		controlPlaneProvider = new(controllersfakes.FakeControlPlaneProvider)
+		controlPlaneClient = new(controllersfakes.FakeKindControlPlaneClient)
+		clusterClient = new(controllersfakes.FakeControlPlaneClusterClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = controllers.NewKindControlPlaneReconciler(clusterClient, controlPlaneClient, controlPlaneProvider, recorder, controllers.Options{
+			HealthCheckInterval: time.Minute,
+		})
+
+		controlPlane = &kclusterv1.KindControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-control-plane",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindControlPlaneSpec{
+				Replicas: ptr.To[int32](1),
+				Version:  "v1.31.0",
+			},
+		}
+		controlPlaneClient.GetReturns(controlPlane, nil)
+
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+		clusterClient.GetForControlPlaneReturns(cluster, nil)
+
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: "the-kind-cluster-name",
+			},
+			Status: kclusterv1.KindClusterStatus{
+				Ready: true,
+				Phase: kclusterv1.ClusterPhaseReady,
+			},
+		}
+		clusterClient.GetKindClusterReturns(kindCluster, nil)
+
+		controlPlaneProvider.GetNodesReturns([]kclusterv1.NodeStatus{
+			controlPlaneNode("the-kind-cluster-name-control-plane", time.Hour),
+			{Name: "the-kind-cluster-name-worker", Role: "worker"},
+		}, nil)
+		controlPlaneProvider.GetControlPlaneComponentsReturns(readyComponents("the-kind-cluster-name-control-plane"), nil)
+		controlPlaneProvider.GetKubeconfigReturns([]byte("the-kubeconfig"), nil)
+		controlPlaneProvider.GetKubernetesVersionReturns("v1.31.0", nil)
+		controlPlaneProvider.AddControlPlaneNodeReturns("the-kind-cluster-name-control-plane2", nil)
+	})
+
+	JustBeforeEach(func() {
+		request := ctrl.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      "foo-control-plane",
+				Namespace: "bar",
+			},
+		}
+		result, reconcileErr = reconciler.Reconcile(ctx, request)
+	})
+
+	It("does not return an error", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+	})
+
+	It("requeues the event after the health check interval", func() {
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+
+	It("gets the kind cluster of the owner cluster", func() {
+		Expect(clusterClient.GetKindClusterCallCount()).To(Equal(1))
+		_, actualCluster := clusterClient.GetKindClusterArgsForCall(0)
+		Expect(actualCluster).To(Equal(cluster))
+	})
+
+	It("records the control plane nodes in the status", func() {
+		status := lastStatus()
+		Expect(status.Replicas).To(Equal(int32(1)))
+		Expect(status.UpdatedReplicas).To(Equal(int32(1)))
+		Expect(status.ReadyReplicas).To(Equal(int32(1)))
+		Expect(status.UnavailableReplicas).To(Equal(int32(0)))
+		Expect(status.Selector).To(Equal("cluster.x-k8s.io/cluster-name=foo"))
+	})
+
+	When("the nodes run images of other versions with the same prefix", func() {
+		BeforeEach(func() {
+			controlPlane.Spec.Version = "v1.29.1"
+			pinned := controlPlaneNode("the-kind-cluster-name-control-plane", time.Hour)
+			pinned.Image = "kindest/node:v1.29.1@sha256:e8a8b7b1a4d8e2a1c6b0f0d3ca6b1f2b4e0c4b5e9f8e2d3c1b0a9f8e7d6c5b4a"
+			other := controlPlaneNode("the-kind-cluster-name-control-plane2", time.Minute)
+			other.Image = "kindest/node:v1.29.10"
+			controlPlane.Spec.Replicas = ptr.To[int32](2)
+			controlPlaneProvider.GetNodesReturns([]kclusterv1.NodeStatus{pinned, other}, nil)
+		})
+
+		It("only counts the nodes running the exact version as updated", func() {
+			Expect(lastStatus().UpdatedReplicas).To(Equal(int32(1)))
+		})
+	})
+
+	It("marks the control plane as initialized and ready", func() {
+		status := lastStatus()
+		Expect(status.Initialized).To(BeTrue())
+		Expect(status.Ready).To(BeTrue())
+		Expect(conditions.IsTrue(&kclusterv1.KindControlPlane{Status: status}, clusterv1.ReadyCondition)).To(BeTrue())
+	})
+
+	It("records the components and version", func() {
+		status := lastStatus()
+		Expect(status.Components).To(Equal(readyComponents("the-kind-cluster-name-control-plane")))
+		Expect(status.Version).To(Equal(ptr.To("v1.31.0")))
+		Expect(controlPlaneProvider.GetKubeconfigArgsForCall(0)).To(Equal(kindCluster))
+		Expect(controlPlaneProvider.GetKubernetesVersionArgsForCall(0)).To(Equal([]byte("the-kubeconfig")))
+		Expect(conditions.IsTrue(&kclusterv1.KindControlPlane{Status: status}, kclusterv1.ControlPlaneComponentsHealthyCondition)).To(BeTrue())
+	})
+
+	When("getting the kubeconfig fails", func() {
+		BeforeEach(func() {
+			controlPlaneProvider.GetKubeconfigReturns(nil, errors.New("boom"))
+		})
+
+		It("does not record the version", func() {
+			Expect(lastStatus().Version).To(BeNil())
+			Expect(controlPlaneProvider.GetKubernetesVersionCallCount()).To(Equal(0))
+		})
+	})
+
+	It("marks the control plane as resized", func() {
+		Expect(conditions.IsTrue(&kclusterv1.KindControlPlane{Status: lastStatus()}, kclusterv1.ResizedCondition)).To(BeTrue())
+	})
+
+	It("does not add or remove nodes", func() {
+		Expect(controlPlaneProvider.AddControlPlaneNodeCallCount()).To(Equal(0))
+		Expect(controlPlaneProvider.RemoveControlPlaneNodeCallCount()).To(Equal(0))
+	})
+
+	When("the KindControlPlane does not exist", func() {
+		BeforeEach(func() {
+			controlPlaneClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "foo-control-plane"))
+		})
+
+		It("does not requeue the event", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(controlPlaneClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("getting the KindControlPlane fails", func() {
+		BeforeEach(func() {
+			controlPlaneClient.GetReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("the KindControlPlane is not owned by a Cluster", func() {
+		BeforeEach(func() {
+			clusterClient.GetForControlPlaneReturns(nil, nil)
+		})
+
+		It("does not reconcile the KindControlPlane", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(clusterClient.GetKindClusterCallCount()).To(Equal(0))
+			Expect(controlPlaneClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the Cluster is paused", func() {
+		BeforeEach(func() {
+			cluster.Spec.Paused = true
+		})
+
+		It("does not reconcile the KindControlPlane", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(controlPlaneClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the kind cluster is not ready", func() {
+		BeforeEach(func() {
+			kindCluster.Status.Ready = false
+			kindCluster.Status.Phase = kclusterv1.ClusterPhaseProvisioning
+		})
+
+		It("waits for the kind cluster", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(controlPlaneProvider.GetNodesCallCount()).To(Equal(0))
+		})
+
+		It("marks the control plane as not ready", func() {
+			status := lastStatus()
+			Expect(status.Ready).To(BeFalse())
+			condition := conditions.Get(&kclusterv1.KindControlPlane{Status: status}, clusterv1.ReadyCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.WaitingForKindClusterReason))
+		})
+	})
+
+	When("getting the nodes fails", func() {
+		BeforeEach(func() {
+			controlPlaneProvider.GetNodesReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+		})
+	})
+
+	When("a control plane component is not ready", func() {
+		BeforeEach(func() {
+			components := readyComponents("the-kind-cluster-name-control-plane")
+			components[0].Ready = false
+			controlPlaneProvider.GetControlPlaneComponentsReturns(components, nil)
+		})
+
+		It("reports the component as unhealthy", func() {
+			status := lastStatus()
+			Expect(status.ReadyReplicas).To(Equal(int32(0)))
+			Expect(status.UnavailableReplicas).To(Equal(int32(1)))
+			Expect(status.Ready).To(BeFalse())
+
+			condition := conditions.Get(&kclusterv1.KindControlPlane{Status: status}, kclusterv1.ControlPlaneComponentsHealthyCondition)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("the-kind-cluster-name-control-plane/etcd"))
+		})
+
+		It("does not mark the control plane as initialized", func() {
+			Expect(lastStatus().Initialized).To(BeFalse())
+		})
+
+		When("the control plane was initialized before", func() {
+			BeforeEach(func() {
+				controlPlane.Status.Initialized = true
+			})
+
+			It("keeps the control plane initialized", func() {
+				Expect(lastStatus().Initialized).To(BeTrue())
+			})
+		})
+	})
+
+	When("more replicas are desired", func() {
+		BeforeEach(func() {
+			controlPlane.Spec.Replicas = ptr.To[int32](3)
+		})
+
+		It("adds a control plane node with the desired version", func() {
+			Expect(controlPlaneProvider.AddControlPlaneNodeCallCount()).To(Equal(1))
+			actualCluster, version := controlPlaneProvider.AddControlPlaneNodeArgsForCall(0)
+			Expect(actualCluster).To(Equal(kindCluster))
+			Expect(version).To(Equal("v1.31.0"))
+		})
+
+		It("requeues the event to add the next node", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+		})
+
+		It("marks the control plane as scaling up", func() {
+			condition := conditions.Get(&kclusterv1.KindControlPlane{Status: lastStatus()}, kclusterv1.ResizedCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.ScalingUpReason))
+		})
+
+		When("adding the node fails", func() {
+			BeforeEach(func() {
+				controlPlaneProvider.AddControlPlaneNodeReturns("", errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+
+			It("records the failure in the status", func() {
+				Expect(lastStatus().FailureMessage).To(Equal(ptr.To("failed to add control plane node: boom")))
+			})
+		})
+	})
+
+	When("fewer replicas are desired", func() {
+		BeforeEach(func() {
+			controlPlaneProvider.GetNodesReturns([]kclusterv1.NodeStatus{
+				controlPlaneNode("the-kind-cluster-name-control-plane2", 2*time.Minute),
+				controlPlaneNode("the-kind-cluster-name-control-plane", time.Hour),
+				controlPlaneNode("the-kind-cluster-name-control-plane3", time.Minute),
+			}, nil)
+		})
+
+		It("removes the most recently added node", func() {
+			Expect(controlPlaneProvider.RemoveControlPlaneNodeCallCount()).To(Equal(1))
+			actualCluster, name := controlPlaneProvider.RemoveControlPlaneNodeArgsForCall(0)
+			Expect(actualCluster).To(Equal(kindCluster))
+			Expect(name).To(Equal("the-kind-cluster-name-control-plane3"))
+		})
+
+		When("there are ten or more control plane nodes", func() {
+			BeforeEach(func() {
+				controlPlane.Spec.Replicas = ptr.To[int32](2)
+				controlPlaneProvider.GetNodesReturns([]kclusterv1.NodeStatus{
+					controlPlaneNode("the-kind-cluster-name-control-plane", time.Hour),
+					controlPlaneNode("the-kind-cluster-name-control-plane10", 2*time.Minute),
+					controlPlaneNode("the-kind-cluster-name-control-plane2", time.Minute),
+				}, nil)
+			})
+
+			It("removes the most recently created node", func() {
+				Expect(controlPlaneProvider.RemoveControlPlaneNodeCallCount()).To(Equal(1))
+				_, name := controlPlaneProvider.RemoveControlPlaneNodeArgsForCall(0)
+				Expect(name).To(Equal("the-kind-cluster-name-control-plane2"))
+			})
+		})
+
+		It("requeues the event to remove the next node", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result.Requeue).To(BeTrue())
+		})
+
+		It("marks the control plane as scaling down", func() {
+			condition := conditions.Get(&kclusterv1.KindControlPlane{Status: lastStatus()}, kclusterv1.ResizedCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.ScalingDownReason))
+		})
+
+		When("removing the node fails", func() {
+			BeforeEach(func() {
+				controlPlaneProvider.RemoveControlPlaneNodeReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+})
@@ -0,0 +1,142 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+//counterfeiter:generate . HostProvider
+//counterfeiter:generate . KindHostClient
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindhosts/status,verbs=get;update;patch
+
+type HostProvider interface {
+	GetHostStatus(*kclusterv1.KindHost) (kclusterv1.KindHostStatus, error)
+	// ForgetHost releases what the provider keeps for the KindHost with the
+	// given name, e.g. its container event stream, once it no longer exists.
+	ForgetHost(string)
+}
+
+type KindHostClient interface {
+	Get(context.Context, string) (*kclusterv1.KindHost, error)
+	UpdateStatus(context.Context, kclusterv1.KindHostStatus, *kclusterv1.KindHost) error
+}
+
+// KindHostReconciler keeps the status of a KindHost up to date with the
+// kind clusters, nodes and resources of its docker daemon.
+type KindHostReconciler struct {
+	hosts        KindHostClient
+	hostProvider HostProvider
+	recorder     record.EventRecorder
+	options      Options
+}
+
+// NewKindHostReconciler creates a KindHostReconciler. Only the
+// HealthCheckInterval of the options is used.
+func NewKindHostReconciler(
+	hosts KindHostClient,
+	hostProvider HostProvider,
+	recorder record.EventRecorder,
+	options Options,
+) *KindHostReconciler {
+	return &KindHostReconciler{
+		hosts:        hosts,
+		hostProvider: hostProvider,
+		recorder:     recorder,
+		options:      options,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KindHostReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kclusterv1.KindHost{}).
+		Complete(r)
+}
+
+func (r *KindHostReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	host, err := r.hosts.Get(ctx, req.Name)
+	if k8serrors.IsNotFound(err) {
+		logger.Info("KindHost no longer exists")
+		r.hostProvider.ForgetHost(req.Name)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to get KindHost")
+		return ctrl.Result{}, err
+	}
+
+	if !host.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	status, err := r.hostProvider.GetHostStatus(host)
+	if err != nil {
+		logger.Error(err, "failed to get host status")
+
+		// Keep the last known usage, so that the clusters already on the
+		// host are still accounted for once it is back.
+		status = *host.Status.DeepCopy()
+		status.Ready = false
+		setHostCondition(&status, conditions.FalseCondition(kclusterv1.HostReachableCondition,
+			kclusterv1.HostUnreachableReason, clusterv1.ConditionSeverityWarning, "%v", err))
+	} else {
+		status.Ready = true
+		status.Conditions = host.Status.Conditions
+		setHostCondition(&status, conditions.TrueCondition(kclusterv1.HostReachableCondition))
+	}
+
+	if !host.Status.Ready && status.Ready {
+		r.recorder.Event(host, corev1.EventTypeNormal, "HostReachable", "Docker daemon of the host is reachable")
+	}
+	if host.Status.Ready && !status.Ready {
+		r.recorder.Eventf(host, corev1.EventTypeWarning, kclusterv1.HostUnreachableReason, "Docker daemon of the host is unreachable: %v", err)
+	}
+
+	err = r.hosts.UpdateStatus(ctx, status, host)
+	if err != nil {
+		logger.Error(err, "failed to update status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+}
+
+// setHostCondition sets the condition on the status, preserving the
+// transition time as long as the condition status does not change.
+func setHostCondition(status *kclusterv1.KindHostStatus, condition *clusterv1.Condition) {
+	holder := &kclusterv1.KindHost{Status: *status}
+	if existing := conditions.Get(holder, condition.Type); existing != nil && existing.Status == condition.Status {
+		conditions.Delete(holder, condition.Type)
+		condition.LastTransitionTime = existing.LastTransitionTime
+	}
+	conditions.Set(holder, condition)
+	*status = holder.Status
+}
@@ -0,0 +1,190 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+)
+
+var _ = Describe("KindHostController", func() {
+	var (
+		reconciler   *controllers.KindHostReconciler
+		hostProvider *controllersfakes.FakeHostProvider
+		hostClient   *controllersfakes.FakeKindHostClient
+		recorder     *record.FakeRecorder
+		ctx          context.Context
+		result       ctrl.Result
+		reconcileErr error
+		host         *kclusterv1.KindHost
+	)
+
+	lastStatus := func() kclusterv1.KindHostStatus {
+		count := hostClient.UpdateStatusCallCount()
+		Expect(count).To(BeNumerically(">=", 1))
+		_, status, _ := hostClient.UpdateStatusArgsForCall(count - 1)
+		return status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hostProvider = new(controllersfakes.FakeHostProvider)
+		hostClient = new(controllersfakes.FakeKindHostClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = controllers.NewKindHostReconciler(hostClient, hostProvider, recorder, controllers.Options{
+			HealthCheckInterval: time.Minute,
+		})
+
+		host = &kclusterv1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "remote",
+			},
+			Spec: kclusterv1.KindHostSpec{
+				Endpoint: "tcp://10.0.0.5:2376",
+			},
+		}
+		hostClient.GetReturns(host, nil)
+
+		hostProvider.GetHostStatusReturns(kclusterv1.KindHostStatus{
+			Clusters: 2,
+			Nodes:    5,
+			CPUs:     8,
+			Memory:   resource.NewQuantity(16<<30, resource.BinarySI),
+		}, nil)
+	})
+
+	JustBeforeEach(func() {
+		result, reconcileErr = reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "remote"},
+		})
+	})
+
+	It("gets the host by name", func() {
+		Expect(hostClient.GetCallCount()).To(Equal(1))
+		_, name := hostClient.GetArgsForCall(0)
+		Expect(name).To(Equal("remote"))
+	})
+
+	It("updates the status with the usage of the host", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+		Expect(hostProvider.GetHostStatusCallCount()).To(Equal(1))
+		Expect(hostProvider.GetHostStatusArgsForCall(0)).To(Equal(host))
+
+		status := lastStatus()
+		Expect(status.Ready).To(BeTrue())
+		Expect(status.Clusters).To(BeEquivalentTo(2))
+		Expect(status.Nodes).To(BeEquivalentTo(5))
+		Expect(status.CPUs).To(BeEquivalentTo(8))
+		Expect(status.Memory.Value()).To(BeEquivalentTo(16 << 30))
+		Expect(conditions.IsTrue(&kclusterv1.KindHost{Status: status}, kclusterv1.HostReachableCondition)).To(BeTrue())
+	})
+
+	It("emits a HostReachable event", func() {
+		Expect(recorder.Events).To(Receive(ContainSubstring("HostReachable")))
+	})
+
+	It("requeues after the health check interval", func() {
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+
+	When("the host does not exist", func() {
+		BeforeEach(func() {
+			hostClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "remote"))
+		})
+
+		It("does not requeue", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(hostProvider.GetHostStatusCallCount()).To(Equal(0))
+		})
+
+		It("forgets the host", func() {
+			Expect(hostProvider.ForgetHostCallCount()).To(Equal(1))
+			Expect(hostProvider.ForgetHostArgsForCall(0)).To(Equal("remote"))
+		})
+	})
+
+	When("getting the host fails", func() {
+		BeforeEach(func() {
+			hostClient.GetReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError("boom"))
+		})
+	})
+
+	When("the host is being deleted", func() {
+		BeforeEach(func() {
+			host.DeletionTimestamp = ptr.To(metav1.Now())
+		})
+
+		It("does not check the host", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(hostProvider.GetHostStatusCallCount()).To(Equal(0))
+			Expect(hostClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the docker daemon of the host is unreachable", func() {
+		BeforeEach(func() {
+			host.Status = kclusterv1.KindHostStatus{
+				Ready:    true,
+				Clusters: 1,
+				Nodes:    3,
+				CPUs:     4,
+			}
+			hostProvider.GetHostStatusReturns(kclusterv1.KindHostStatus{}, errors.New("connection refused"))
+		})
+
+		It("marks the host not ready and keeps the last known usage", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+
+			status := lastStatus()
+			Expect(status.Ready).To(BeFalse())
+			Expect(status.Clusters).To(BeEquivalentTo(1))
+			Expect(status.Nodes).To(BeEquivalentTo(3))
+			Expect(status.CPUs).To(BeEquivalentTo(4))
+
+			condition := conditions.Get(&kclusterv1.KindHost{Status: status}, kclusterv1.HostReachableCondition)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(kclusterv1.HostUnreachableReason))
+			Expect(condition.Message).To(ContainSubstring("connection refused"))
+		})
+
+		It("emits a HostUnreachable warning", func() {
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning HostUnreachable")))
+		})
+
+		It("keeps checking the host", func() {
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+	})
+
+	When("updating the status fails", func() {
+		BeforeEach(func() {
+			hostClient.UpdateStatusReturns(errors.New("boom"))
+		})
+
+		It("returns an error", func() {
+			Expect(reconcileErr).To(MatchError("boom"))
+		})
+	})
+})
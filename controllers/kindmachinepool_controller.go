@@ -0,0 +1,383 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+//counterfeiter:generate . MachinePoolProvider
+//counterfeiter:generate . KindMachinePoolClient
+//counterfeiter:generate . MachinePoolClusterClient
+
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindmachinepools,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindmachinepools/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=kindmachinepools/finalizers,verbs=update
+//+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch
+
+type MachinePoolProvider interface {
+	GetMachinePoolNodes(*kclusterv1.KindCluster, string) ([]kclusterv1.MachinePoolNode, error)
+	AddWorkerNode(*kclusterv1.KindCluster, string, string, []string) (string, error)
+	RemoveWorkerNode(*kclusterv1.KindCluster, string) error
+}
+
+type KindMachinePoolClient interface {
+	Get(context.Context, types.NamespacedName) (*kclusterv1.KindMachinePool, error)
+	AddFinalizer(context.Context, *kclusterv1.KindMachinePool) error
+	RemoveFinalizer(context.Context, *kclusterv1.KindMachinePool) error
+	SetProviderIDList(context.Context, []string, *kclusterv1.KindMachinePool) error
+	UpdateStatus(context.Context, kclusterv1.KindMachinePoolStatus, *kclusterv1.KindMachinePool) error
+}
+
+type MachinePoolClusterClient interface {
+	GetMachinePool(context.Context, *kclusterv1.KindMachinePool) (*expv1.MachinePool, error)
+	GetForMachinePool(context.Context, *expv1.MachinePool) (*clusterv1.Cluster, error)
+	GetKindCluster(context.Context, *clusterv1.Cluster) (*kclusterv1.KindCluster, error)
+}
+
+// KindMachinePoolReconciler reconciles a KindMachinePool object
+type KindMachinePoolReconciler struct {
+	clusters            MachinePoolClusterClient
+	machinePools        KindMachinePoolClient
+	machinePoolProvider MachinePoolProvider
+	recorder            record.EventRecorder
+	options             Options
+}
+
+// NewKindMachinePoolReconciler creates a KindMachinePoolReconciler. Only the
+// HealthCheckInterval of the options is used.
+func NewKindMachinePoolReconciler(
+	clusters MachinePoolClusterClient,
+	machinePools KindMachinePoolClient,
+	machinePoolProvider MachinePoolProvider,
+	recorder record.EventRecorder,
+	options Options,
+) *KindMachinePoolReconciler {
+	return &KindMachinePoolReconciler{
+		clusters:            clusters,
+		machinePools:        machinePools,
+		machinePoolProvider: machinePoolProvider,
+		recorder:            recorder,
+		options:             options,
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. Changes to the
+// replicas or version of a MachinePool trigger a reconcile of its
+// KindMachinePool.
+func (r *KindMachinePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&kclusterv1.KindMachinePool{}).
+		Watches(
+			&expv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(exputil.MachinePoolToInfrastructureMapFunc(
+				context.Background(), kclusterv1.GroupVersion.WithKind("KindMachinePool"),
+			)),
+		).
+		Complete(r)
+}
+
+func (r *KindMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	pool, err := r.machinePools.Get(ctx, req.NamespacedName)
+	if k8serrors.IsNotFound(err) {
+		logger.Info("KindMachinePool no longer exists")
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "failed to get KindMachinePool")
+		return ctrl.Result{}, err
+	}
+
+	machinePool, err := r.clusters.GetMachinePool(ctx, pool)
+	if k8serrors.IsNotFound(err) && !pool.DeletionTimestamp.IsZero() {
+		// The MachinePool or Cluster is gone, so the Cluster is being deleted.
+		// The kind cluster and its worker nodes are deleted with the
+		// KindCluster.
+		return r.removeFinalizer(ctx, pool)
+	}
+	if err != nil {
+		logger.Error(err, "failed to get owner machine pool")
+		return ctrl.Result{}, err
+	}
+
+	if machinePool == nil {
+		logger.Info("KindMachinePool not owned by MachinePool yet")
+		return ctrl.Result{}, nil
+	}
+
+	cluster, err := r.clusters.GetForMachinePool(ctx, machinePool)
+	if k8serrors.IsNotFound(err) && !pool.DeletionTimestamp.IsZero() {
+		return r.removeFinalizer(ctx, pool)
+	}
+	if err != nil {
+		logger.Error(err, "failed to get cluster")
+		return ctrl.Result{}, err
+	}
+
+	if annotations.IsPaused(cluster, pool) {
+		logger.Info("reconciliation is paused")
+		return ctrl.Result{}, nil
+	}
+
+	kindCluster, err := r.clusters.GetKindCluster(ctx, cluster)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "failed to get KindCluster")
+		return ctrl.Result{}, err
+	}
+
+	if !pool.DeletionTimestamp.IsZero() {
+		return r.reconcileDeletion(ctx, kindCluster, pool)
+	}
+
+	return r.reconcileNormal(ctx, kindCluster, machinePool, pool)
+}
+
+func (r *KindMachinePoolReconciler) reconcileDeletion(ctx context.Context, kindCluster *kclusterv1.KindCluster, pool *kclusterv1.KindMachinePool) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(pool, k8s.MachinePoolFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// Once the KindCluster is being deleted the whole kind cluster goes away,
+	// so there is no need to remove the nodes one by one.
+	if kindCluster != nil && kindCluster.DeletionTimestamp.IsZero() {
+		nodes, err := r.machinePoolProvider.GetMachinePoolNodes(kindCluster, pool.Name)
+		if err != nil {
+			logger.Error(err, "failed to get machine pool nodes")
+			return ctrl.Result{}, err
+		}
+
+		for _, node := range nodes {
+			logger.Info("removing worker node", "node", node.Name)
+			err = r.machinePoolProvider.RemoveWorkerNode(kindCluster, node.Name)
+			if err != nil {
+				logger.Error(err, "failed to remove worker node", "node", node.Name)
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	return r.removeFinalizer(ctx, pool)
+}
+
+func (r *KindMachinePoolReconciler) removeFinalizer(ctx context.Context, pool *kclusterv1.KindMachinePool) (ctrl.Result, error) {
+	err := r.machinePools.RemoveFinalizer(ctx, pool)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to remove finalizer")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *KindMachinePoolReconciler) reconcileNormal(
+	ctx context.Context,
+	kindCluster *kclusterv1.KindCluster,
+	machinePool *expv1.MachinePool,
+	pool *kclusterv1.KindMachinePool,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(pool, k8s.MachinePoolFinalizer) {
+		err := r.machinePools.AddFinalizer(ctx, pool)
+		if err != nil {
+			logger.Error(err, "failed to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Conditions are set on a copy, the status of which is patched onto the
+	// KindMachinePool at the end of the reconcile.
+	observed := pool.DeepCopy()
+	status := &observed.Status
+	defer r.updateStatus(logger, status, pool)
+
+	if kindCluster == nil || !kindCluster.Status.Ready {
+		logger.Info("waiting for kind cluster to become ready")
+		status.Ready = false
+		conditions.MarkFalse(observed, clusterv1.ReadyCondition, kclusterv1.WaitingForKindClusterReason,
+			clusterv1.ConditionSeverityInfo, "kind cluster is not ready")
+		return ctrl.Result{RequeueAfter: waitForKindClusterInterval}, nil
+	}
+
+	logger = logger.WithValues("cluster-name", kindCluster.Spec.Name)
+	ctx = log.IntoContext(ctx, logger)
+
+	nodes, err := r.machinePoolProvider.GetMachinePoolNodes(kindCluster, pool.Name)
+	if err != nil {
+		logger.Error(err, "failed to get machine pool nodes")
+		return ctrl.Result{}, err
+	}
+
+	status.Nodes = nodes
+	status.Replicas = int32(len(nodes))
+
+	providerIDs := []string{}
+	for _, node := range nodes {
+		providerIDs = append(providerIDs, node.ProviderID)
+	}
+	if !reflect.DeepEqual(pool.Spec.ProviderIDList, providerIDs) {
+		err = r.machinePools.SetProviderIDList(ctx, providerIDs, pool)
+		if err != nil {
+			logger.Error(err, "failed to set provider ID list")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return r.scale(ctx, kindCluster, machinePool, pool, observed, nodes)
+}
+
+// scale adds or removes a single worker node if the nodes of the pool differ
+// from the desired replicas or run an outdated Kubernetes version. Outdated
+// nodes are replaced by adding up to MaxSurge nodes above the desired
+// replicas before removing the oldest outdated node.
+func (r *KindMachinePoolReconciler) scale(
+	ctx context.Context,
+	kindCluster *kclusterv1.KindCluster,
+	machinePool *expv1.MachinePool,
+	pool *kclusterv1.KindMachinePool,
+	observed *kclusterv1.KindMachinePool,
+	nodes []kclusterv1.MachinePoolNode,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	status := &observed.Status
+
+	desired := int(ptr.Deref(machinePool.Spec.Replicas, 1))
+	version := ptr.Deref(machinePool.Spec.Template.Spec.Version, "")
+
+	current, outdated := splitOutdated(nodes, version)
+	reason := kclusterv1.ScalingUpReason
+	if len(outdated) > 0 {
+		reason = kclusterv1.ReplacingOutdatedNodesReason
+	}
+
+	if len(current) < desired && len(nodes) < desired+surge(pool, outdated) {
+		status.Ready = false
+		conditions.MarkFalse(observed, kclusterv1.ResizedCondition, reason, clusterv1.ConditionSeverityInfo,
+			"%d of %d replicas up to date", len(current), desired)
+		r.updateStatus(logger, status, pool)
+
+		logger.Info("adding worker node", "replicas", len(nodes), "desired", desired)
+		name, err := r.machinePoolProvider.AddWorkerNode(kindCluster, pool.Name, version, machinePool.Spec.FailureDomains)
+		if err != nil {
+			logger.Error(err, "failed to add worker node")
+			status.FailureMessage = ptr.To(fmt.Sprintf("failed to add worker node: %v", err))
+			r.recorder.Eventf(pool, corev1.EventTypeWarning, "ScaleUpFailed",
+				"Failed to add worker node to kind cluster %q: %v", kindCluster.Spec.Name, err)
+			return ctrl.Result{}, err
+		}
+
+		r.recorder.Eventf(pool, corev1.EventTypeNormal, "ScaledUp",
+			"Added worker node %q to kind cluster %q", name, kindCluster.Spec.Name)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	var remove *kclusterv1.MachinePoolNode
+	switch {
+	case len(outdated) > 0:
+		// nodes are sorted oldest first
+		remove = &outdated[0]
+	case len(nodes) > desired:
+		reason = kclusterv1.ScalingDownReason
+		remove = &current[len(current)-1]
+		if pool.GetDeletePolicy() == kclusterv1.MachinePoolDeletePolicyOldest {
+			remove = &current[0]
+		}
+	}
+
+	if remove == nil {
+		status.Ready = true
+		status.FailureMessage = nil
+		conditions.MarkTrue(observed, kclusterv1.ResizedCondition)
+		conditions.MarkTrue(observed, clusterv1.ReadyCondition)
+		return ctrl.Result{RequeueAfter: r.options.HealthCheckInterval}, nil
+	}
+
+	status.Ready = false
+	conditions.MarkFalse(observed, kclusterv1.ResizedCondition, reason, clusterv1.ConditionSeverityInfo,
+		"%d of %d replicas up to date, %d nodes", len(current), desired, len(nodes))
+	r.updateStatus(logger, status, pool)
+
+	logger.Info("removing worker node", "node", remove.Name, "replicas", len(nodes), "desired", desired)
+	err := r.machinePoolProvider.RemoveWorkerNode(kindCluster, remove.Name)
+	if err != nil {
+		logger.Error(err, "failed to remove worker node", "node", remove.Name)
+		status.FailureMessage = ptr.To(fmt.Sprintf("failed to remove worker node %q: %v", remove.Name, err))
+		r.recorder.Eventf(pool, corev1.EventTypeWarning, "ScaleDownFailed",
+			"Failed to remove worker node %q from kind cluster %q: %v", remove.Name, kindCluster.Spec.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	r.recorder.Eventf(pool, corev1.EventTypeNormal, "ScaledDown",
+		"Removed worker node %q from kind cluster %q", remove.Name, kindCluster.Spec.Name)
+	return ctrl.Result{Requeue: true}, nil
+}
+
+func (r *KindMachinePoolReconciler) updateStatus(logger logr.Logger, status *kclusterv1.KindMachinePoolStatus, pool *kclusterv1.KindMachinePool) {
+	err := r.machinePools.UpdateStatus(context.Background(), *status, pool)
+	if err != nil {
+		logger.Error(err, "failed to update status")
+	}
+}
+
+// splitOutdated splits the nodes into those running the image of the
+// version and those that do not, keeping their order. Without a version all
+// nodes are up to date.
+func splitOutdated(nodes []kclusterv1.MachinePoolNode, version string) (current, outdated []kclusterv1.MachinePoolNode) {
+	for _, node := range nodes {
+		if version != "" && !kclusterv1.IsNodeImage(node.Image, version) {
+			outdated = append(outdated, node)
+			continue
+		}
+		current = append(current, node)
+	}
+
+	return current, outdated
+}
+
+// surge returns how many nodes can be added above the desired replicas.
+func surge(pool *kclusterv1.KindMachinePool, outdated []kclusterv1.MachinePoolNode) int {
+	if len(outdated) == 0 {
+		return 0
+	}
+
+	return pool.GetMaxSurge()
+}
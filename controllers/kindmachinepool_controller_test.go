@@ -0,0 +1,447 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindMachinePoolController", func() {
+	var (
+		reconciler          *controllers.KindMachinePoolReconciler
+		machinePoolProvider *controllersfakes.FakeMachinePoolProvider
+		machinePoolClient   *controllersfakes.FakeKindMachinePoolClient
+		clusterClient       *controllersfakes.FakeMachinePoolClusterClient
+		recorder            *record.FakeRecorder
+		ctx                 context.Context
+		result              ctrl.Result
+		reconcileErr        error
+		pool                *kclusterv1.KindMachinePool
+		machinePool         *expv1.MachinePool
+		kindCluster         *kclusterv1.KindCluster
+		cluster             *clusterv1.Cluster
+	)
+
+	poolNode := func(name, version string, age time.Duration) kclusterv1.MachinePoolNode {
+		return kclusterv1.MachinePoolNode{
+			Name:         name,
+			ProviderID:   "kind://docker/the-kind-cluster-name/" + name,
+			Image:        "kindest/node:" + version,
+			CreationTime: metav1.NewTime(time.Now().Add(-age)),
+		}
+	}
+
+	lastStatus := func() kclusterv1.KindMachinePoolStatus {
+		count := machinePoolClient.UpdateStatusCallCount()
+		Expect(count).To(BeNumerically(">=", 1))
+		_, status, _ := machinePoolClient.UpdateStatusArgsForCall(count - 1)
+		return status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		machinePoolProvider = new(controllersfakes.FakeMachinePoolProvider)
+		machinePoolClient = new(controllersfakes.FakeKindMachinePoolClient)
+		clusterClient = new(controllersfakes.FakeMachinePoolClusterClient)
+		recorder = record.NewFakeRecorder(10)
+		reconciler = controllers.NewKindMachinePoolReconciler(clusterClient, machinePoolClient, machinePoolProvider, recorder, controllers.Options{
+			HealthCheckInterval: time.Minute,
+		})
+
+		pool = &kclusterv1.KindMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "foo-pool",
+				Namespace:  "bar",
+				Finalizers: []string{k8s.MachinePoolFinalizer},
+			},
+			Spec: kclusterv1.KindMachinePoolSpec{
+				ProviderIDList: []string{
+					"kind://docker/the-kind-cluster-name/worker",
+					"kind://docker/the-kind-cluster-name/worker2",
+				},
+			},
+		}
+		machinePoolClient.GetReturns(pool, nil)
+
+		machinePool = &expv1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-pool",
+				Namespace: "bar",
+			},
+			Spec: expv1.MachinePoolSpec{
+				ClusterName: "foo",
+				Replicas:    ptr.To[int32](2),
+				Template: clusterv1.MachineTemplateSpec{
+					Spec: clusterv1.MachineSpec{
+						Version: ptr.To("v1.31.0"),
+					},
+				},
+			},
+		}
+		clusterClient.GetMachinePoolReturns(machinePool, nil)
+
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+		clusterClient.GetForMachinePoolReturns(cluster, nil)
+
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: "the-kind-cluster-name",
+			},
+			Status: kclusterv1.KindClusterStatus{
+				Ready: true,
+				Phase: kclusterv1.ClusterPhaseReady,
+			},
+		}
+		clusterClient.GetKindClusterReturns(kindCluster, nil)
+
+		machinePoolProvider.GetMachinePoolNodesReturns([]kclusterv1.MachinePoolNode{
+			poolNode("worker", "v1.31.0", 2*time.Hour),
+			poolNode("worker2", "v1.31.0", time.Hour),
+		}, nil)
+		machinePoolProvider.AddWorkerNodeReturns("worker3", nil)
+	})
+
+	JustBeforeEach(func() {
+		request := ctrl.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      "foo-pool",
+				Namespace: "bar",
+			},
+		}
+		result, reconcileErr = reconciler.Reconcile(ctx, request)
+	})
+
+	It("does not return an error", func() {
+		Expect(reconcileErr).NotTo(HaveOccurred())
+	})
+
+	It("requeues the event after the health check interval", func() {
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+	})
+
+	It("gets the nodes of the pool", func() {
+		Expect(machinePoolProvider.GetMachinePoolNodesCallCount()).To(Equal(1))
+		actualCluster, poolName := machinePoolProvider.GetMachinePoolNodesArgsForCall(0)
+		Expect(actualCluster).To(Equal(kindCluster))
+		Expect(poolName).To(Equal("foo-pool"))
+	})
+
+	It("marks the pool as ready", func() {
+		status := lastStatus()
+		Expect(status.Ready).To(BeTrue())
+		Expect(status.Replicas).To(Equal(int32(2)))
+		Expect(status.Nodes).To(HaveLen(2))
+		Expect(conditions.IsTrue(&kclusterv1.KindMachinePool{Status: status}, kclusterv1.ResizedCondition)).To(BeTrue())
+	})
+
+	It("does not update an unchanged provider ID list", func() {
+		Expect(machinePoolClient.SetProviderIDListCallCount()).To(Equal(0))
+	})
+
+	It("does not add or remove nodes", func() {
+		Expect(machinePoolProvider.AddWorkerNodeCallCount()).To(Equal(0))
+		Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(0))
+	})
+
+	When("the KindMachinePool does not have the finalizer", func() {
+		BeforeEach(func() {
+			pool.Finalizers = nil
+		})
+
+		It("adds the finalizer", func() {
+			Expect(machinePoolClient.AddFinalizerCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the KindMachinePool does not exist", func() {
+		BeforeEach(func() {
+			machinePoolClient.GetReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "foo-pool"))
+		})
+
+		It("does not requeue the event", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(machinePoolClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the KindMachinePool is not owned by a MachinePool", func() {
+		BeforeEach(func() {
+			clusterClient.GetMachinePoolReturns(nil, nil)
+		})
+
+		It("does not reconcile the KindMachinePool", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(machinePoolClient.UpdateStatusCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the Cluster is paused", func() {
+		BeforeEach(func() {
+			cluster.Spec.Paused = true
+		})
+
+		It("does not reconcile the KindMachinePool", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(machinePoolProvider.GetMachinePoolNodesCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the kind cluster is not ready", func() {
+		BeforeEach(func() {
+			kindCluster.Status.Ready = false
+		})
+
+		It("waits for the kind cluster", func() {
+			Expect(reconcileErr).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(machinePoolProvider.GetMachinePoolNodesCallCount()).To(Equal(0))
+			Expect(lastStatus().Ready).To(BeFalse())
+		})
+	})
+
+	When("the nodes changed", func() {
+		BeforeEach(func() {
+			pool.Spec.ProviderIDList = nil
+		})
+
+		It("updates the provider ID list", func() {
+			Expect(machinePoolClient.SetProviderIDListCallCount()).To(Equal(1))
+			_, providerIDs, _ := machinePoolClient.SetProviderIDListArgsForCall(0)
+			Expect(providerIDs).To(Equal([]string{
+				"kind://docker/the-kind-cluster-name/worker",
+				"kind://docker/the-kind-cluster-name/worker2",
+			}))
+		})
+	})
+
+	When("more replicas are desired", func() {
+		BeforeEach(func() {
+			machinePool.Spec.Replicas = ptr.To[int32](3)
+		})
+
+		It("adds a worker node with the version of the machine pool", func() {
+			Expect(machinePoolProvider.AddWorkerNodeCallCount()).To(Equal(1))
+			actualCluster, poolName, version, _ := machinePoolProvider.AddWorkerNodeArgsForCall(0)
+			Expect(actualCluster).To(Equal(kindCluster))
+			Expect(poolName).To(Equal("foo-pool"))
+			Expect(version).To(Equal("v1.31.0"))
+		})
+
+		It("requeues the event", func() {
+			Expect(result.Requeue).To(BeTrue())
+		})
+
+		When("the machine pool has failure domains", func() {
+			BeforeEach(func() {
+				machinePool.Spec.FailureDomains = []string{"zone-a", "zone-b"}
+			})
+
+			It("places the worker node in one of them", func() {
+				Expect(machinePoolProvider.AddWorkerNodeCallCount()).To(Equal(1))
+				_, _, _, failureDomains := machinePoolProvider.AddWorkerNodeArgsForCall(0)
+				Expect(failureDomains).To(Equal([]string{"zone-a", "zone-b"}))
+			})
+		})
+
+		It("marks the pool as scaling up", func() {
+			condition := conditions.Get(&kclusterv1.KindMachinePool{Status: lastStatus()}, kclusterv1.ResizedCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.ScalingUpReason))
+		})
+
+		When("adding the node fails", func() {
+			BeforeEach(func() {
+				machinePoolProvider.AddWorkerNodeReturns("", errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+
+			It("records the failure in the status", func() {
+				Expect(lastStatus().FailureMessage).To(Equal(ptr.To("failed to add worker node: boom")))
+			})
+		})
+	})
+
+	When("fewer replicas are desired", func() {
+		BeforeEach(func() {
+			machinePool.Spec.Replicas = ptr.To[int32](1)
+		})
+
+		It("removes the newest node", func() {
+			Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(1))
+			_, name := machinePoolProvider.RemoveWorkerNodeArgsForCall(0)
+			Expect(name).To(Equal("worker2"))
+		})
+
+		It("marks the pool as scaling down", func() {
+			condition := conditions.Get(&kclusterv1.KindMachinePool{Status: lastStatus()}, kclusterv1.ResizedCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.ScalingDownReason))
+		})
+
+		When("the delete policy is Oldest", func() {
+			BeforeEach(func() {
+				pool.Spec.Strategy = &kclusterv1.MachinePoolStrategy{
+					DeletePolicy: kclusterv1.MachinePoolDeletePolicyOldest,
+				}
+			})
+
+			It("removes the oldest node", func() {
+				Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(1))
+				_, name := machinePoolProvider.RemoveWorkerNodeArgsForCall(0)
+				Expect(name).To(Equal("worker"))
+			})
+		})
+
+		When("removing the node fails", func() {
+			BeforeEach(func() {
+				machinePoolProvider.RemoveWorkerNodeReturns(errors.New("boom"))
+			})
+
+			It("returns an error", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+			})
+		})
+	})
+
+	When("nodes run an outdated version", func() {
+		BeforeEach(func() {
+			machinePool.Spec.Template.Spec.Version = ptr.To("v1.32.0")
+		})
+
+		It("surges a node with the new version", func() {
+			Expect(machinePoolProvider.AddWorkerNodeCallCount()).To(Equal(1))
+			_, _, version, _ := machinePoolProvider.AddWorkerNodeArgsForCall(0)
+			Expect(version).To(Equal("v1.32.0"))
+			Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(0))
+		})
+
+		It("marks the pool as replacing outdated nodes", func() {
+			condition := conditions.Get(&kclusterv1.KindMachinePool{Status: lastStatus()}, kclusterv1.ResizedCondition)
+			Expect(condition.Reason).To(Equal(kclusterv1.ReplacingOutdatedNodesReason))
+		})
+
+		When("the surge node has been added", func() {
+			BeforeEach(func() {
+				machinePoolProvider.GetMachinePoolNodesReturns([]kclusterv1.MachinePoolNode{
+					poolNode("worker", "v1.31.0", 2*time.Hour),
+					poolNode("worker2", "v1.31.0", time.Hour),
+					poolNode("worker3", "v1.32.0", time.Minute),
+				}, nil)
+			})
+
+			It("removes the oldest outdated node", func() {
+				Expect(machinePoolProvider.AddWorkerNodeCallCount()).To(Equal(0))
+				Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(1))
+				_, name := machinePoolProvider.RemoveWorkerNodeArgsForCall(0)
+				Expect(name).To(Equal("worker"))
+			})
+		})
+
+		When("a node runs a version with the desired version as prefix", func() {
+			BeforeEach(func() {
+				machinePool.Spec.Template.Spec.Version = ptr.To("v1.31.1")
+				machinePoolProvider.GetMachinePoolNodesReturns([]kclusterv1.MachinePoolNode{
+					poolNode("worker", "v1.31.10", 2*time.Hour),
+					poolNode("worker2", "v1.31.1@sha256:e8a8b7b1a4d8e2a1c6b0f0d3ca6b1f2b4e0c4b5e9f8e2d3c1b0a9f8e7d6c5b4a", time.Hour),
+					poolNode("worker3", "v1.31.1", time.Minute),
+				}, nil)
+			})
+
+			It("replaces the node", func() {
+				Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(1))
+				_, name := machinePoolProvider.RemoveWorkerNodeArgsForCall(0)
+				Expect(name).To(Equal("worker"))
+			})
+		})
+
+		When("surging is disabled", func() {
+			BeforeEach(func() {
+				pool.Spec.Strategy = &kclusterv1.MachinePoolStrategy{
+					MaxSurge: ptr.To[int32](0),
+				}
+			})
+
+			It("removes an outdated node before adding its replacement", func() {
+				Expect(machinePoolProvider.AddWorkerNodeCallCount()).To(Equal(0))
+				Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(1))
+				_, name := machinePoolProvider.RemoveWorkerNodeArgsForCall(0)
+				Expect(name).To(Equal("worker"))
+			})
+		})
+	})
+
+	When("the KindMachinePool is being deleted", func() {
+		BeforeEach(func() {
+			pool.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		})
+
+		It("removes all nodes of the pool", func() {
+			Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(2))
+		})
+
+		It("removes the finalizer", func() {
+			Expect(machinePoolClient.RemoveFinalizerCallCount()).To(Equal(1))
+		})
+
+		When("the kind cluster is being deleted", func() {
+			BeforeEach(func() {
+				kindCluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			})
+
+			It("only removes the finalizer", func() {
+				Expect(machinePoolProvider.RemoveWorkerNodeCallCount()).To(Equal(0))
+				Expect(machinePoolClient.RemoveFinalizerCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the MachinePool no longer exists", func() {
+			BeforeEach(func() {
+				clusterClient.GetMachinePoolReturns(nil, k8serrors.NewNotFound(schema.GroupResource{}, "foo-pool"))
+			})
+
+			It("removes the finalizer", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(machinePoolClient.RemoveFinalizerCallCount()).To(Equal(1))
+			})
+		})
+
+		When("removing a node fails", func() {
+			BeforeEach(func() {
+				machinePoolProvider.RemoveWorkerNodeReturns(errors.New("boom"))
+			})
+
+			It("keeps the finalizer", func() {
+				Expect(reconcileErr).To(MatchError(ContainSubstring("boom")))
+				Expect(machinePoolClient.RemoveFinalizerCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
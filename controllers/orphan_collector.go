@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+//counterfeiter:generate . OrphanProvider
+//counterfeiter:generate . OwnerClient
+
+type OrphanProvider interface {
+	ListOwnedClusters(string) ([]kclusterv1.OwnedKindCluster, error)
+	Delete(*kclusterv1.KindCluster) error
+}
+
+type OwnerClient interface {
+	Exists(context.Context, corev1.ObjectReference) (bool, error)
+}
+
+var (
+	orphanedClusters = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "capk_orphaned_kind_clusters",
+		Help: "Number of kind clusters whose owner no longer exists, as of the last collection.",
+	})
+	reclaimedClusters = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capk_reclaimed_kind_clusters_total",
+		Help: "Number of orphaned kind clusters deleted.",
+	})
+	reclaimFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "capk_reclaim_failures_total",
+		Help: "Number of failed deletes of orphaned kind clusters.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedClusters, reclaimedClusters, reclaimFailures)
+}
+
+// OrphanCollector periodically deletes the kind clusters left behind by the
+// controller, e.g. when the manager crashed while creating one or the
+// KindCluster was removed without its finalizer running. A kind cluster is
+// orphaned if the KindCluster or KindClusterPool recorded as its owner no
+// longer exists with the recorded UID. It is only deleted once it has been
+// orphaned for the grace period, so that kind clusters being handed over,
+// e.g. by clusterctl move, are left alone.
+type OrphanCollector struct {
+	provider OrphanProvider
+	owners   OwnerClient
+	hosts    HostLister
+	options  Options
+
+	// orphanedSince is when each orphaned kind cluster, keyed by host and
+	// name, was first found.
+	orphanedSince map[orphanKey]time.Time
+}
+
+type orphanKey struct {
+	host string
+	name string
+}
+
+// NewOrphanCollector creates an OrphanCollector. If hosts is nil only the
+// docker daemon of the manager is checked for orphaned kind clusters.
+func NewOrphanCollector(provider OrphanProvider, owners OwnerClient, hosts HostLister, options Options) *OrphanCollector {
+	return &OrphanCollector{
+		provider:      provider,
+		owners:        owners,
+		hosts:         hosts,
+		options:       options,
+		orphanedSince: map[orphanKey]time.Time{},
+	}
+}
+
+// Start collects orphaned kind clusters every OrphanCollectionInterval until
+// the context is cancelled. It implements manager.Runnable.
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithName("orphan-collector"))
+
+	ticker := time.NewTicker(c.options.OrphanCollectionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.Collect(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection makes only the leader collect orphaned kind clusters.
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect deletes the kind clusters that have been orphaned for the grace
+// period, or only logs them in dry run mode. Hosts that can not be listed
+// are skipped until the next collection.
+func (c *OrphanCollector) Collect(ctx context.Context) {
+	logger := log.FromContext(ctx)
+	now := time.Now()
+
+	hostNames := []string{""}
+	if c.hosts != nil {
+		hosts, err := c.hosts.List(ctx)
+		if err != nil {
+			logger.Error(err, "failed to list hosts")
+		}
+		for _, host := range hosts {
+			hostNames = append(hostNames, host.Name)
+		}
+	}
+
+	found := map[orphanKey]bool{}
+	listed := map[string]bool{}
+	for _, hostName := range hostNames {
+		clusters, err := c.provider.ListOwnedClusters(hostName)
+		if err != nil {
+			logger.Error(err, "failed to list kind clusters", "host", hostName)
+			continue
+		}
+		listed[hostName] = true
+
+		for _, owned := range clusters {
+			key := orphanKey{host: hostName, name: owned.Name}
+			clusterLogger := logger.WithValues("cluster-name", owned.Name, "host", hostName,
+				"owner", owned.Owner.Kind+" "+owned.Owner.Namespace+"/"+owned.Owner.Name)
+
+			exists, err := c.owners.Exists(ctx, owned.Owner)
+			if err != nil {
+				clusterLogger.Error(err, "failed to get owner of kind cluster")
+				if _, ok := c.orphanedSince[key]; ok {
+					found[key] = true
+				}
+				continue
+			}
+			if exists {
+				continue
+			}
+			found[key] = true
+
+			since, ok := c.orphanedSince[key]
+			if !ok {
+				clusterLogger.Info("found orphaned kind cluster")
+				c.orphanedSince[key] = now
+				continue
+			}
+
+			if now.Sub(since) < c.options.OrphanGracePeriod {
+				continue
+			}
+
+			if c.options.OrphanDryRun {
+				clusterLogger.Info("would delete orphaned kind cluster (dry run)", "orphaned-since", since)
+				continue
+			}
+
+			clusterLogger.Info("deleting orphaned kind cluster", "orphaned-since", since)
+			err = c.provider.Delete(owned.KindCluster())
+			if err != nil {
+				clusterLogger.Error(err, "failed to delete orphaned kind cluster")
+				reclaimFailures.Inc()
+				continue
+			}
+
+			reclaimedClusters.Inc()
+			delete(c.orphanedSince, key)
+			delete(found, key)
+		}
+	}
+
+	for key := range c.orphanedSince {
+		if listed[key.host] && !found[key] {
+			delete(c.orphanedSince, key)
+		}
+	}
+
+	orphanedClusters.Set(float64(len(found)))
+}
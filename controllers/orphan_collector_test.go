@@ -0,0 +1,240 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers"
+	"github.com/mnitchev/cluster-api-provider-kind/controllers/controllersfakes"
+)
+
+var _ = Describe("OrphanCollector", func() {
+	var (
+		collector  *controllers.OrphanCollector
+		provider   *controllersfakes.FakeOrphanProvider
+		owners     *controllersfakes.FakeOwnerClient
+		hostLister *controllersfakes.FakeHostLister
+		options    controllers.Options
+		orphan     kclusterv1.OwnedKindCluster
+		ctx        context.Context
+	)
+
+	metricValue := func(name string) float64 {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+			metric := family.GetMetric()[0]
+			if metric.GetGauge() != nil {
+				return metric.GetGauge().GetValue()
+			}
+			return metric.GetCounter().GetValue()
+		}
+		Fail("metric " + name + " is not registered")
+		return 0
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		provider = new(controllersfakes.FakeOrphanProvider)
+		owners = new(controllersfakes.FakeOwnerClient)
+		hostLister = new(controllersfakes.FakeHostLister)
+		options = controllers.Options{
+			OrphanCollectionInterval: time.Minute,
+		}
+
+		orphan = kclusterv1.OwnedKindCluster{
+			Name: "the-kind-cluster-name",
+			Owner: corev1.ObjectReference{
+				APIVersion: kclusterv1.GroupVersion.String(),
+				Kind:       "KindCluster",
+				Namespace:  "bar",
+				Name:       "foo",
+				UID:        "foo-uid",
+			},
+		}
+		provider.ListOwnedClustersReturns([]kclusterv1.OwnedKindCluster{orphan}, nil)
+		owners.ExistsReturns(false, nil)
+	})
+
+	JustBeforeEach(func() {
+		collector = controllers.NewOrphanCollector(provider, owners, hostLister, options)
+	})
+
+	It("only collects on the leader", func() {
+		Expect(collector.NeedLeaderElection()).To(BeTrue())
+	})
+
+	It("looks up the owner of the kind cluster", func() {
+		collector.Collect(ctx)
+
+		Expect(provider.ListOwnedClustersCallCount()).To(Equal(1))
+		Expect(provider.ListOwnedClustersArgsForCall(0)).To(BeEmpty())
+		Expect(owners.ExistsCallCount()).To(Equal(1))
+		_, owner := owners.ExistsArgsForCall(0)
+		Expect(owner).To(Equal(orphan.Owner))
+	})
+
+	It("does not delete a kind cluster the first time it is found orphaned", func() {
+		collector.Collect(ctx)
+
+		Expect(provider.DeleteCallCount()).To(Equal(0))
+		Expect(metricValue("capk_orphaned_kind_clusters")).To(BeEquivalentTo(1))
+	})
+
+	It("deletes the orphaned kind cluster once the grace period has passed", func() {
+		reclaimed := metricValue("capk_reclaimed_kind_clusters_total")
+
+		collector.Collect(ctx)
+		collector.Collect(ctx)
+
+		Expect(provider.DeleteCallCount()).To(Equal(1))
+		actualCluster := provider.DeleteArgsForCall(0)
+		Expect(actualCluster.Spec.Name).To(Equal("the-kind-cluster-name"))
+		Expect(actualCluster.GetHostName()).To(BeEmpty())
+
+		Expect(metricValue("capk_reclaimed_kind_clusters_total")).To(Equal(reclaimed + 1))
+		Expect(metricValue("capk_orphaned_kind_clusters")).To(BeZero())
+	})
+
+	When("the owner exists", func() {
+		BeforeEach(func() {
+			owners.ExistsReturns(true, nil)
+		})
+
+		It("does not delete the kind cluster", func() {
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+
+			Expect(provider.DeleteCallCount()).To(Equal(0))
+			Expect(metricValue("capk_orphaned_kind_clusters")).To(BeZero())
+		})
+	})
+
+	When("the grace period has not passed", func() {
+		BeforeEach(func() {
+			options.OrphanGracePeriod = time.Hour
+		})
+
+		It("does not delete the kind cluster", func() {
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+
+			Expect(provider.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the kind cluster has an owner again", func() {
+		It("starts the grace period over once it is orphaned again", func() {
+			collector.Collect(ctx)
+
+			owners.ExistsReturns(true, nil)
+			collector.Collect(ctx)
+
+			owners.ExistsReturns(false, nil)
+			collector.Collect(ctx)
+
+			Expect(provider.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	When("in dry run mode", func() {
+		BeforeEach(func() {
+			options.OrphanDryRun = true
+		})
+
+		It("does not delete the kind cluster", func() {
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+
+			Expect(provider.DeleteCallCount()).To(Equal(0))
+			Expect(metricValue("capk_orphaned_kind_clusters")).To(BeEquivalentTo(1))
+		})
+	})
+
+	When("looking up the owner fails", func() {
+		BeforeEach(func() {
+			owners.ExistsReturns(false, errors.New("boom"))
+		})
+
+		It("does not delete the kind cluster", func() {
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+
+			Expect(provider.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	When("deleting the kind cluster fails", func() {
+		BeforeEach(func() {
+			provider.DeleteReturns(errors.New("boom"))
+		})
+
+		It("tries again on the next collection", func() {
+			failures := metricValue("capk_reclaim_failures_total")
+
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+
+			Expect(provider.DeleteCallCount()).To(Equal(2))
+			Expect(metricValue("capk_reclaim_failures_total")).To(Equal(failures + 2))
+		})
+	})
+
+	When("there are KindHosts", func() {
+		BeforeEach(func() {
+			hostLister.ListReturns([]kclusterv1.KindHost{
+				{ObjectMeta: metav1.ObjectMeta{Name: "remote"}},
+			}, nil)
+			provider.ListOwnedClustersStub = func(hostName string) ([]kclusterv1.OwnedKindCluster, error) {
+				if hostName == "" {
+					return nil, nil
+				}
+				remoteOrphan := orphan
+				remoteOrphan.HostName = hostName
+				return []kclusterv1.OwnedKindCluster{remoteOrphan}, nil
+			}
+		})
+
+		It("collects the orphaned kind clusters on them", func() {
+			collector.Collect(ctx)
+			collector.Collect(ctx)
+
+			Expect(provider.ListOwnedClustersCallCount()).To(Equal(4))
+			Expect(provider.ListOwnedClustersArgsForCall(1)).To(Equal("remote"))
+			Expect(provider.DeleteCallCount()).To(Equal(1))
+			Expect(provider.DeleteArgsForCall(0).GetHostName()).To(Equal("remote"))
+		})
+
+		When("a host can not be listed", func() {
+			It("keeps the orphaned kind clusters found on it before", func() {
+				collector.Collect(ctx)
+
+				stub := provider.ListOwnedClustersStub
+				provider.ListOwnedClustersStub = func(hostName string) ([]kclusterv1.OwnedKindCluster, error) {
+					if hostName == "remote" {
+						return nil, errors.New("boom")
+					}
+					return stub(hostName)
+				}
+				collector.Collect(ctx)
+
+				provider.ListOwnedClustersStub = stub
+				collector.Collect(ctx)
+
+				Expect(provider.DeleteCallCount()).To(Equal(1))
+			})
+		})
+	})
+})
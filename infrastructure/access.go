@@ -0,0 +1,381 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	certutil "k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const (
+	caCertPath = "/etc/kubernetes/pki/ca.crt"
+	caKeyPath  = "/etc/kubernetes/pki/ca.key"
+
+	// accessLabel marks the bindings in the workload cluster created for a
+	// KindClusterAccess with its UID.
+	accessLabel = "infrastructure.cluster.x-k8s.io/kind-cluster-access"
+
+	// reservedPrefix is the prefix of the users and groups Kubernetes grants
+	// permissions to on its own, e.g. system:masters.
+	reservedPrefix = "system:"
+
+	// clockSkew is how far back client certificates are valid from, so that
+	// they are not rejected by API servers with a clock running behind.
+	clockSkew = time.Minute
+)
+
+// GrantAccess binds the ClusterRole of the KindClusterAccess to its user and
+// groups in the workload cluster, cluster wide or in its namespace. Bindings
+// left over from an earlier spec of the KindClusterAccess are removed.
+func (p *KindProvider) GrantAccess(kindCluster *kclusterv1.KindCluster, access *kclusterv1.KindClusterAccess) error {
+	if err := checkIdentity(access); err != nil {
+		return err
+	}
+
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := p.clientset(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	name := accessBindingName(access)
+	roleRef := rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     access.Spec.ClusterRole,
+	}
+
+	// The role of a binding can not be changed, so bindings of another role
+	// are deleted and created again.
+	err = deleteAccessBindings(ctx, clientset, access, func(namespace, bindingName string, bindingRoleRef rbacv1.RoleRef) bool {
+		return namespace == access.Spec.Namespace && bindingName == name && bindingRoleRef == roleRef
+	})
+	if err != nil {
+		return err
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: access.Spec.Namespace,
+		Labels:    map[string]string{accessLabel: string(access.UID)},
+	}
+	subjects := accessSubjects(access)
+
+	if access.Spec.Namespace == "" {
+		bindings := clientset.RbacV1().ClusterRoleBindings()
+		binding, err := bindings.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			_, err = bindings.Create(ctx, &rbacv1.ClusterRoleBinding{ObjectMeta: meta, RoleRef: roleRef, Subjects: subjects}, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		binding.Labels = meta.Labels
+		binding.Subjects = subjects
+		_, err = bindings.Update(ctx, binding, metav1.UpdateOptions{})
+		return err
+	}
+
+	bindings := clientset.RbacV1().RoleBindings(access.Spec.Namespace)
+	binding, err := bindings.Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = bindings.Create(ctx, &rbacv1.RoleBinding{ObjectMeta: meta, RoleRef: roleRef, Subjects: subjects}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	binding.Labels = meta.Labels
+	binding.Subjects = subjects
+	_, err = bindings.Update(ctx, binding, metav1.UpdateOptions{})
+	return err
+}
+
+// RevokeAccess deletes the bindings of the KindClusterAccess from the
+// workload cluster. Client certificates can not be revoked, but without the
+// bindings the certificates issued for the KindClusterAccess no longer grant
+// anything.
+func (p *KindProvider) RevokeAccess(kindCluster *kclusterv1.KindCluster, access *kclusterv1.KindClusterAccess) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := p.clientset(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	return deleteAccessBindings(ctx, clientset, access, func(string, string, rbacv1.RoleRef) bool {
+		return false
+	})
+}
+
+// GetCACertHash returns the hash of the CA certificate of the kind cluster,
+// which changes when the kind cluster is created again.
+func (p *KindProvider) GetCACertHash(kindCluster *kclusterv1.KindCluster) (string, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return "", err
+	}
+
+	caCert, err := p.readCACert(kindCluster)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(caCert.Raw)
+	return "sha256:" + hex.EncodeToString(hash[:]), nil
+}
+
+// IssueKubeconfig signs a client certificate for the user and groups of the
+// KindClusterAccess with the CA of the kind cluster, read from its first
+// control plane node, and returns a kubeconfig using it together with when
+// it expires. The certificate does not outlive the CA.
+func (p *KindProvider) IssueKubeconfig(kindCluster *kclusterv1.KindCluster, access *kclusterv1.KindClusterAccess) ([]byte, time.Time, error) {
+	if err := checkIdentity(access); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	caCert, err := p.readCACert(kindCluster)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	caKeyPEM, err := p.readNodeFile(initNodeName(kindCluster), caKeyPath)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read CA key: %w", err)
+	}
+	caKey, err := keyutil.ParsePrivateKeyPEM(caKeyPEM)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse CA key: %w", err)
+	}
+	signer, ok := caKey.(crypto.Signer)
+	if !ok {
+		return nil, time.Time{}, errors.New("CA key can not sign certificates")
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(access.GetExpiry())
+	if notAfter.After(caCert.NotAfter) {
+		notAfter = caCert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   access.Spec.User,
+			Organization: access.Spec.Groups,
+		},
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, clientKey.Public(), signer)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to sign client certificate: %w", err)
+	}
+
+	clientKeyPEM, err := keyutil.MarshalPrivateKeyToPEM(clientKey)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	kubeconfig, err := p.userKubeconfig(kindCluster, access.Spec.User,
+		pem.EncodeToMemory(&pem.Block{Type: certutil.CertificateBlockType, Bytes: certDER}), clientKeyPEM)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return kubeconfig, notAfter, nil
+}
+
+func (p *KindProvider) readCACert(kindCluster *kclusterv1.KindCluster) (*x509.Certificate, error) {
+	caCertPEM, err := p.readNodeFile(initNodeName(kindCluster), caCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+
+	caCerts, err := certutil.ParseCertsPEM(caCertPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	return caCerts[0], nil
+}
+
+// userKubeconfig returns the kubeconfig of the kind cluster with the admin
+// credentials replaced by the client certificate and key of the user.
+func (p *KindProvider) userKubeconfig(kindCluster *kclusterv1.KindCluster, user string, certPEM, keyPEM []byte) ([]byte, error) {
+	adminKubeconfig, err := p.GetKubeconfig(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	adminConfig, err := clientcmd.Load(adminKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	adminContext, ok := adminConfig.Contexts[adminConfig.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no context %q", adminConfig.CurrentContext)
+	}
+	clusterName := adminContext.Cluster
+	cluster, ok := adminConfig.Clusters[clusterName]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no cluster %q", clusterName)
+	}
+
+	contextName := fmt.Sprintf("%s@%s", user, clusterName)
+	config := clientcmdapi.NewConfig()
+	config.Clusters[clusterName] = cluster
+	config.AuthInfos[user] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: certPEM,
+		ClientKeyData:         keyPEM,
+	}
+	config.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:  clusterName,
+		AuthInfo: user,
+	}
+	config.CurrentContext = contextName
+
+	return clientcmd.Write(*config)
+}
+
+func (p *KindProvider) clientset(kindCluster *kclusterv1.KindCluster) (*kubernetes.Clientset, error) {
+	restConfig, err := p.restConfig(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Timeout = healthCheckTimeout
+
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// deleteAccessBindings deletes the bindings of the KindClusterAccess in the
+// workload cluster that are not to be kept.
+func deleteAccessBindings(ctx context.Context, clientset kubernetes.Interface, access *kclusterv1.KindClusterAccess, keep func(namespace, name string, roleRef rbacv1.RoleRef) bool) error {
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", accessLabel, access.UID)}
+
+	clusterBindings, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, selector)
+	if err != nil {
+		return err
+	}
+	for _, binding := range clusterBindings.Items {
+		if keep("", binding.Name, binding.RoleRef) {
+			continue
+		}
+		err := clientset.RbacV1().ClusterRoleBindings().Delete(ctx, binding.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	bindings, err := clientset.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, selector)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings.Items {
+		if keep(binding.Namespace, binding.Name, binding.RoleRef) {
+			continue
+		}
+		err := clientset.RbacV1().RoleBindings(binding.Namespace).Delete(ctx, binding.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accessBindingName returns the name of the binding of the KindClusterAccess
+// in the workload cluster. The KindClusterAccesses of a KindCluster are all
+// in its namespace, so their names are unique.
+func accessBindingName(access *kclusterv1.KindClusterAccess) string {
+	return "kind-cluster-access:" + access.Name
+}
+
+func accessSubjects(access *kclusterv1.KindClusterAccess) []rbacv1.Subject {
+	subjects := []rbacv1.Subject{{
+		APIGroup: rbacv1.GroupName,
+		Kind:     rbacv1.UserKind,
+		Name:     access.Spec.User,
+	}}
+	for _, group := range access.Spec.Groups {
+		subjects = append(subjects, rbacv1.Subject{
+			APIGroup: rbacv1.GroupName,
+			Kind:     rbacv1.GroupKind,
+			Name:     group,
+		})
+	}
+
+	return subjects
+}
+
+// checkIdentity refuses users and groups Kubernetes grants permissions to on
+// its own, which the CRD validation rejects as well, as a certificate for
+// them, e.g. for the system:masters group, would bypass the ClusterRole.
+func checkIdentity(access *kclusterv1.KindClusterAccess) error {
+	if access.Spec.User == "" || strings.HasPrefix(access.Spec.User, reservedPrefix) {
+		return fmt.Errorf("user %q can not be granted access", access.Spec.User)
+	}
+	for _, group := range access.Spec.Groups {
+		if strings.HasPrefix(group, reservedPrefix) {
+			return fmt.Errorf("group %q can not be granted access", group)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+const requestsMetric = "apiserver_request_total"
+
+// mutatingVerbs are the values of the verb label of requestsMetric for
+// requests changing objects.
+var mutatingVerbs = map[string]bool{
+	"POST":             true,
+	"PUT":              true,
+	"PATCH":            true,
+	"APPLY":            true,
+	"DELETE":           true,
+	"DELETECOLLECTION": true,
+}
+
+// backgroundResources are the resources the kubelets and control plane
+// components of a kind cluster keep changing on their own, e.g. to renew
+// their leases or to check the tokens of requests, so changes to them say
+// nothing about whether the kind cluster is being used.
+var backgroundResources = map[string]bool{
+	"leases":               true,
+	"events":               true,
+	"tokenreviews":         true,
+	"subjectaccessreviews": true,
+}
+
+// backgroundSubresources are the subresources the kubelets and control plane
+// components of a kind cluster keep changing on their own, e.g. to report
+// the status of nodes or to refresh service account tokens.
+var backgroundSubresources = map[string]bool{
+	"status": true,
+	"token":  true,
+}
+
+// GetMutatingRequests returns the number of mutating requests to workload
+// resources the API server of the kind cluster has served since it started,
+// read from its metrics. Requests the kind cluster makes on its own, e.g.
+// renewing leases or reporting node status, are not counted, so the number
+// only changes while the kind cluster is used. The API server is reached
+// with the kubeconfig of the kind cluster.
+func (p *KindProvider) GetMutatingRequests(kubeconfig []byte) (int64, error) {
+	clientset, err := clientsetFor(kubeconfig)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	metrics, err := clientset.Discovery().RESTClient().Get().
+		AbsPath("/metrics").
+		SetHeader("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain))).
+		DoRaw(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get api server metrics: %w", err)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(metrics))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse api server metrics: %w", err)
+	}
+
+	family, ok := families[requestsMetric]
+	if !ok {
+		return 0, fmt.Errorf("api server metrics have no %s", requestsMetric)
+	}
+
+	return countMutatingRequests(family), nil
+}
+
+func countMutatingRequests(family *dto.MetricFamily) int64 {
+	var count int64
+	for _, metric := range family.GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+
+		if !mutatingVerbs[labels["verb"]] || backgroundResources[labels["resource"]] ||
+			backgroundSubresources[labels["subresource"]] {
+			continue
+		}
+
+		count += int64(metric.GetCounter().GetValue())
+	}
+
+	return count
+}
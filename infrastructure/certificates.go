@@ -0,0 +1,159 @@
+package infrastructure
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const (
+	apiServerCertPath = "/etc/kubernetes/pki/apiserver.crt"
+
+	// controlPlaneContainers matches the containers of the static pods of a
+	// control plane node, which only load their certificates on start.
+	controlPlaneContainers = "^(kube-apiserver|kube-controller-manager|kube-scheduler|etcd)$"
+
+	// controlPlaneRestartTimeout is how long the API server of a control
+	// plane node may take to run again after its certificates are renewed.
+	controlPlaneRestartTimeout = 2 * time.Minute
+)
+
+// GetCertificateExpiry returns when the first of the certificates kubeadm
+// issued for the control plane of the kind cluster expires: the admin client
+// certificate of its kubeconfig or the API server certificate of one of its
+// control plane nodes. They are all issued for a year.
+func (p *KindProvider) GetCertificateExpiry(kindCluster *kclusterv1.KindCluster, kubeconfig []byte) (time.Time, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return time.Time{}, err
+	}
+	expiry, err := firstExpiry(restConfig.CertData)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse admin client certificate: %w", err)
+	}
+
+	controlPlaneNodes, err := p.controlPlaneNodes(kindCluster)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, node := range controlPlaneNodes {
+		certPEM, err := p.readNodeFile(node.String(), apiServerCertPath)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read API server certificate of node %q: %w", node.String(), err)
+		}
+
+		nodeExpiry, err := firstExpiry(certPEM)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse API server certificate of node %q: %w", node.String(), err)
+		}
+
+		if nodeExpiry.Before(expiry) {
+			expiry = nodeExpiry
+		}
+	}
+
+	return expiry, nil
+}
+
+// RenewCertificates renews the certificates kubeadm issued on every control
+// plane node of the kind cluster, including the admin kubeconfig, with
+// kubeadm certs renew. The static pods of the control plane are restarted to
+// load them one node at a time, waiting for the API server of each node to
+// run again, so that kind clusters with several control plane nodes stay
+// available.
+func (p *KindProvider) RenewCertificates(kindCluster *kclusterv1.KindCluster) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	controlPlaneNodes, err := p.controlPlaneNodes(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range controlPlaneNodes {
+		output, err := exec.CombinedOutputLines(node.Command("kubeadm", "certs", "renew", "all"))
+		if err != nil {
+			return fmt.Errorf("failed to renew certificates of node %q: %w: %v", node.String(), err, output)
+		}
+
+		err = node.Command("sh", "-c",
+			fmt.Sprintf("crictl ps --quiet --name '%s' | xargs -r crictl stop", controlPlaneContainers)).Run()
+		if err != nil {
+			return fmt.Errorf("failed to restart control plane of node %q: %w", node.String(), err)
+		}
+
+		if err := waitForAPIServer(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *KindProvider) controlPlaneNodes(kindCluster *kclusterv1.KindCluster) ([]nodes.Node, error) {
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	controlPlaneNodes, err := nodeutils.SelectNodesByRole(clusterNodes, constants.ControlPlaneNodeRoleValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(controlPlaneNodes) == 0 {
+		return nil, fmt.Errorf("cluster %q has no control plane nodes", kindCluster.Spec.Name)
+	}
+
+	return controlPlaneNodes, nil
+}
+
+// waitForAPIServer waits until the kubelet of the control plane node runs
+// its API server again.
+func waitForAPIServer(node nodes.Node) error {
+	deadline := time.Now().Add(controlPlaneRestartTimeout)
+	for time.Now().Before(deadline) {
+		lines, err := exec.OutputLines(node.Command(
+			"crictl", "ps", "--quiet", "--state", "running", "--name", "^kube-apiserver$"))
+		if err == nil && len(lines) > 0 && lines[0] != "" {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("API server of node %q did not restart within %s", node.String(), controlPlaneRestartTimeout)
+}
+
+// firstExpiry returns when the first of the PEM encoded certificates
+// expires.
+func firstExpiry(certPEM []byte) (time.Time, error) {
+	certs, err := certutil.ParseCertsPEM(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var expiry time.Time
+	for _, cert := range certs {
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+
+	return expiry, nil
+}
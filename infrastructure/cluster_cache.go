@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"sync"
+)
+
+// ClusterLister lists the names of the kind clusters on the container
+// runtime.
+type ClusterLister interface {
+	List() ([]string, error)
+}
+
+// ClusterCache keeps the names of the known kind clusters so that checking
+// whether a cluster exists does not list the container runtime every time.
+// The cache is invalidated by container events and by the provider's own
+// create and delete operations and is refilled lazily on the next read.
+type ClusterCache struct {
+	lister ClusterLister
+
+	mu       sync.Mutex
+	synced   bool
+	clusters map[string]struct{}
+}
+
+func NewClusterCache(lister ClusterLister) *ClusterCache {
+	return &ClusterCache{
+		lister:   lister,
+		clusters: map[string]struct{}{},
+	}
+}
+
+// Contains returns true if a kind cluster with the given name exists.
+func (c *ClusterCache) Contains(name string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.synced {
+		if err := c.refresh(); err != nil {
+			return false, err
+		}
+	}
+
+	_, ok := c.clusters[name]
+	return ok, nil
+}
+
+// Invalidate marks the cache as stale, forcing the next read to list the
+// clusters again.
+func (c *ClusterCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.synced = false
+}
+
+func (c *ClusterCache) refresh() error {
+	names, err := c.lister.List()
+	if err != nil {
+		return err
+	}
+
+	c.clusters = make(map[string]struct{}, len(names))
+	for _, name := range names {
+		c.clusters[name] = struct{}{}
+	}
+	c.synced = true
+
+	return nil
+}
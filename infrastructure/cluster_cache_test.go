@@ -0,0 +1,98 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusters     [][]string
+		listErr      error
+		invalidate   bool
+		lookup       string
+		want         bool
+		wantErr      bool
+		wantListings int
+	}{
+		{
+			name:         "finds a listed cluster",
+			clusters:     [][]string{{"foo", "bar"}},
+			lookup:       "foo",
+			want:         true,
+			wantListings: 1,
+		},
+		{
+			name:         "does not find a cluster that is not listed",
+			clusters:     [][]string{{"foo"}},
+			lookup:       "bar",
+			wantListings: 1,
+		},
+		{
+			name:         "keeps the listed clusters until invalidated",
+			clusters:     [][]string{{"foo"}, {}},
+			lookup:       "foo",
+			want:         true,
+			wantListings: 1,
+		},
+		{
+			name:         "lists the clusters again once invalidated",
+			clusters:     [][]string{{"foo"}, {}},
+			invalidate:   true,
+			lookup:       "foo",
+			wantListings: 2,
+		},
+		{
+			name:         "returns the error of the listing and lists again",
+			listErr:      errors.New("docker is down"),
+			lookup:       "foo",
+			wantErr:      true,
+			wantListings: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			lister := &sequenceLister{clusters: tt.clusters, err: tt.listErr}
+			cache := NewClusterCache(lister)
+
+			_, err := cache.Contains(tt.lookup)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			}
+			if tt.invalidate {
+				cache.Invalidate()
+			}
+
+			found, err := cache.Contains(tt.lookup)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(tt.listErr))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(found).To(Equal(tt.want))
+			}
+			g.Expect(lister.calls).To(Equal(tt.wantListings))
+		})
+	}
+}
+
+// sequenceLister returns the next of its listings on every call, or its
+// error if it has one.
+type sequenceLister struct {
+	clusters [][]string
+	err      error
+	calls    int
+}
+
+func (l *sequenceLister) List() ([]string, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.clusters[min(l.calls, len(l.clusters))-1], nil
+}
@@ -0,0 +1,196 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const loadBalancerConfigPath = "/usr/local/etc/haproxy/haproxy.cfg"
+
+// loadBalancerConfigTemplate is the haproxy configuration kind writes to the
+// external load balancer of clusters with more than one control plane node.
+var loadBalancerConfigTemplate = template.Must(template.New("haproxy").Parse(`# generated by kind
+global
+  log /dev/log local0
+  log /dev/log local1 notice
+  daemon
+  maxconn 100000
+
+resolvers docker
+  nameserver dns 127.0.0.11:53
+
+defaults
+  log global
+  mode tcp
+  option dontlognull
+  timeout connect 5000
+  timeout client 50000
+  timeout server 50000
+  default-server init-addr none
+
+frontend control-plane
+  bind *:{{ .Port }}
+  default_backend kube-apiservers
+
+backend kube-apiservers
+  option httpchk GET /healthz
+  {{- range .Servers }}
+  server {{ . }} {{ . }}:{{ $.Port }} check check-ssl verify none resolvers docker resolve-prefer ipv4
+  {{- end }}
+`))
+
+// AddControlPlaneNode adds a control plane node running the given Kubernetes
+// version to the kind cluster and returns its name. If version is empty the
+// node uses the image of the first control plane node. The node is placed in
+// the least used failure domain marked for the control plane.
+func (p *KindProvider) AddControlPlaneNode(kindCluster *kclusterv1.KindCluster, version string) (string, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return "", err
+	}
+
+	name, err := p.addNode(kindCluster, constants.ControlPlaneNodeRoleValue, version, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return name, p.reconfigureLoadBalancer(kindCluster)
+}
+
+// RemoveControlPlaneNode removes the control plane node from the kind
+// cluster. The first control plane node can not be removed, as kind uses it
+// to administer the cluster.
+func (p *KindProvider) RemoveControlPlaneNode(kindCluster *kclusterv1.KindCluster, name string) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	if name == initNodeName(kindCluster) {
+		return fmt.Errorf("node %q is the first control plane node and can not be removed", name)
+	}
+
+	if err := p.removeNode(kindCluster, name); err != nil {
+		return err
+	}
+
+	return p.reconfigureLoadBalancer(kindCluster)
+}
+
+// GetControlPlaneComponents returns the control plane static pods of all
+// control plane nodes and whether they are ready.
+func (p *KindProvider) GetControlPlaneComponents(kindCluster *kclusterv1.KindCluster) ([]kclusterv1.ControlPlaneComponent, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig, err := p.restConfig(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Timeout = healthCheckTimeout
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceSystem).List(context.Background(), metav1.ListOptions{
+		LabelSelector: "tier=control-plane",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	components := []kclusterv1.ControlPlaneComponent{}
+	for _, pod := range pods.Items {
+		components = append(components, kclusterv1.ControlPlaneComponent{
+			Node:  pod.Spec.NodeName,
+			Name:  pod.Labels["component"],
+			Ready: podReady(pod),
+		})
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Node != components[j].Node {
+			return components[i].Node < components[j].Node
+		}
+		return components[i].Name < components[j].Name
+	})
+
+	return components, nil
+}
+
+// reconfigureLoadBalancer points the external load balancer, if the kind
+// cluster has one, at the current control plane nodes.
+func (p *KindProvider) reconfigureLoadBalancer(kindCluster *kclusterv1.KindCluster) error {
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	loadBalancer, err := nodeutils.ExternalLoadBalancerNode(clusterNodes)
+	if err != nil {
+		return err
+	}
+
+	if loadBalancer == nil {
+		return nil
+	}
+
+	controlPlaneNodes, err := nodeutils.SelectNodesByRole(clusterNodes, constants.ControlPlaneNodeRoleValue)
+	if err != nil {
+		return err
+	}
+
+	servers := []string{}
+	for _, node := range controlPlaneNodes {
+		servers = append(servers, node.String())
+	}
+	sort.Strings(servers)
+
+	var buf bytes.Buffer
+	err = loadBalancerConfigTemplate.Execute(&buf, struct {
+		Port    int
+		Servers []string
+	}{
+		Port:    apiServerInternalPort,
+		Servers: servers,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := nodeutils.WriteFile(loadBalancer, loadBalancerConfigPath, buf.String()); err != nil {
+		return fmt.Errorf("failed to write load balancer config: %w", err)
+	}
+
+	// haproxy reloads its configuration on SIGHUP
+	if err := loadBalancer.Command("kill", "-s", "HUP", "1").Run(); err != nil {
+		return fmt.Errorf("failed to reload load balancer: %w", err)
+	}
+
+	return nil
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,260 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const (
+	// archiveHeadroom is reserved in the archive for the tar and gzip
+	// framing and the list of skipped files.
+	archiveHeadroom = 16 * 1024
+
+	// MinDiagnosticsMaxSize is the smallest maximum size of a diagnostics
+	// archive that leaves room for logs next to the headroom.
+	MinDiagnosticsMaxSize = 2 * archiveHeadroom
+
+	// tarBlockSize is the size of the blocks of a tarball, which the
+	// header and the content of each file are padded to.
+	tarBlockSize = 512
+	// tarMaxNameLength is the longest file name that fits into the header
+	// block of a file.
+	tarMaxNameLength = 100
+	// paxRecordOverhead is more than the length and key an extended header
+	// adds to a long file name.
+	paxRecordOverhead = 32
+
+	skippedFilesName     = "SKIPPED.txt"
+	collectionErrorsName = "collection-errors.txt"
+)
+
+// CollectDiagnostics runs the equivalent of `kind export logs` against the
+// nodes of the kind cluster and returns them as a gzipped tarball of at most
+// maxSize bytes. Files that do not fit are left out and listed in
+// SKIPPED.txt inside the archive. If the cluster has no nodes, nil is
+// returned.
+func (p *KindProvider) CollectDiagnostics(kindCluster *kclusterv1.KindCluster, maxSize int) ([]byte, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "kind-diagnostics-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// Collecting logs is best effort - some of the nodes may never have
+	// started. Keep whatever was collected and record the errors.
+	collectErr := p.clusters.CollectLogs(kindCluster.Spec.Name, dir)
+	if collectErr != nil {
+		err = os.WriteFile(filepath.Join(dir, collectionErrorsName), []byte(collectErr.Error()), 0o600)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return archiveDir(dir, maxSize)
+}
+
+// archiveDir returns the files under dir as a gzipped tarball of at most
+// maxSize bytes. The files are picked by the size they take up in the
+// tarball, including their headers and padding, before compression. If the
+// archive still comes out larger, e.g. because gzip has nothing to compress,
+// the largest files are dropped until it fits.
+func archiveDir(dir string, maxSize int) ([]byte, error) {
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := int64(maxSize - archiveHeadroom)
+	included := []archiveFile{}
+	skipped := []string{}
+	for _, file := range files {
+		entrySize := tarEntrySize(file.path, file.size)
+		if entrySize > budget {
+			skipped = append(skipped, file.path)
+			continue
+		}
+
+		included = append(included, file)
+		budget -= entrySize
+	}
+
+	for {
+		archive, err := writeArchive(dir, included, skipped, maxSize)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(archive) <= maxSize {
+			return archive, nil
+		}
+
+		if len(included) == 0 {
+			return nil, fmt.Errorf("diagnostics archive is %d bytes even without logs, more than the limit of %d bytes", len(archive), maxSize)
+		}
+
+		// The files are sorted smallest first, so the last one is the
+		// largest.
+		last := included[len(included)-1]
+		included = included[:len(included)-1]
+		skipped = append(skipped, last.path)
+	}
+}
+
+func writeArchive(dir string, files []archiveFile, skipped []string, maxSize int) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, file := range files {
+		if err := addFile(tarWriter, dir, file.path, file.size); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(skipped) > 0 {
+		if err := addContent(tarWriter, skippedFilesName, skippedFilesContent(skipped, maxSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// skippedFilesContent lists the skipped files in as much of the archive
+// headroom as is left after the framing of the archive, and counts the rest.
+func skippedFilesContent(skipped []string, maxSize int) []byte {
+	content := &strings.Builder{}
+	fmt.Fprintf(content, "The following files exceeded the size limit of %d bytes:\n", maxSize)
+	for i, path := range skipped {
+		if content.Len()+len(path) > archiveHeadroom/2 {
+			fmt.Fprintf(content, "... and %d more\n", len(skipped)-i)
+			break
+		}
+		content.WriteString(path + "\n")
+	}
+
+	return []byte(content.String())
+}
+
+// tarEntrySize returns how many bytes a file takes up in a tarball: a header
+// block, an extended header for names that do not fit into it, and the
+// content padded to whole blocks.
+func tarEntrySize(name string, size int64) int64 {
+	entrySize := tarBlockSize + roundUpToBlock(size)
+	if len(name) > tarMaxNameLength {
+		entrySize += tarBlockSize + roundUpToBlock(int64(len(name))+paxRecordOverhead)
+	}
+
+	return entrySize
+}
+
+func roundUpToBlock(size int64) int64 {
+	return (size + tarBlockSize - 1) / tarBlockSize * tarBlockSize
+}
+
+type archiveFile struct {
+	path string
+	size int64
+}
+
+// listFiles returns the regular files under dir, smallest first, so that as
+// many files as possible fit into a size limited archive.
+func listFiles(dir string) ([]archiveFile, error) {
+	files := []archiveFile{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, archiveFile{path: relPath, size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].size < files[j].size
+	})
+
+	return files, nil
+}
+
+func addFile(tarWriter *tar.Writer, dir, relPath string, size int64) error {
+	file, err := os.Open(filepath.Join(dir, relPath))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name: relPath,
+		Mode: 0o644,
+		Size: size,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(tarWriter, file, size)
+	return err
+}
+
+func addContent(tarWriter *tar.Writer, name string, content []byte) error {
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0o644,
+		Size: int64(len(content)),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tarWriter.Write(content)
+	return err
+}
@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestArchiveDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]int
+		maxSize     int
+		wantFiles   []string
+		wantSkipped []string
+	}{
+		{
+			name:      "includes all files that fit",
+			files:     map[string]int{"a.log": 10, "node/b.log": 100},
+			maxSize:   1024 * 1024,
+			wantFiles: []string{"a.log", "node/b.log"},
+		},
+		{
+			name:        "skips files larger than the limit",
+			files:       map[string]int{"a.log": 10, "big.log": 64 * 1024},
+			maxSize:     MinDiagnosticsMaxSize,
+			wantFiles:   []string{"a.log", skippedFilesName},
+			wantSkipped: []string{"big.log"},
+		},
+		{
+			name:        "counts the headers and padding of the files",
+			files:       map[string]int{"a.log": 1, "b.log": 1, "c.log": 1},
+			maxSize:     archiveHeadroom + 2*(2*tarBlockSize),
+			wantFiles:   []string{"a.log", "b.log", skippedFilesName},
+			wantSkipped: []string{"c.log"},
+		},
+		{
+			name:        "counts the extended header of long file names",
+			files:       map[string]int{strings.Repeat("a", 200) + ".log": 1},
+			maxSize:     archiveHeadroom + 2*tarBlockSize,
+			wantFiles:   []string{skippedFilesName},
+			wantSkipped: []string{strings.Repeat("a", 200) + ".log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := t.TempDir()
+			for name, size := range tt.files {
+				writeRandomFile(g, filepath.Join(dir, name), size)
+			}
+
+			archive, err := archiveDir(dir, tt.maxSize)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(len(archive)).To(BeNumerically("<=", tt.maxSize))
+
+			contents := readArchive(g, archive)
+			g.Expect(contents).To(HaveLen(len(tt.wantFiles)))
+			for _, name := range tt.wantFiles {
+				g.Expect(contents).To(HaveKey(name))
+			}
+			for _, name := range tt.wantSkipped {
+				g.Expect(string(contents[skippedFilesName])).To(ContainSubstring(name + "\n"))
+			}
+		})
+	}
+}
+
+func TestArchiveDirManySmallFiles(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	for i := range 5000 {
+		writeRandomFile(g, filepath.Join(dir, fmt.Sprintf("node-%d/file-%d.log", i%10, i)), 16)
+	}
+
+	archive, err := archiveDir(dir, MinDiagnosticsMaxSize)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(len(archive)).To(BeNumerically("<=", MinDiagnosticsMaxSize))
+
+	contents := readArchive(g, archive)
+	g.Expect(len(contents)).To(BeNumerically(">", 1))
+	g.Expect(string(contents[skippedFilesName])).To(ContainSubstring("more\n"))
+}
+
+func writeRandomFile(g *WithT, path string, size int) {
+	content := make([]byte, size)
+	_, err := rand.Read(content)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(os.MkdirAll(filepath.Dir(path), 0o700)).To(Succeed())
+	g.Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+}
+
+func readArchive(g *WithT, archive []byte) map[string][]byte {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	g.Expect(err).NotTo(HaveOccurred())
+	tarReader := tar.NewReader(gzipReader)
+
+	contents := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return contents
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+
+		content, err := io.ReadAll(tarReader)
+		g.Expect(err).NotTo(HaveOccurred())
+		contents[header.Name] = content
+	}
+}
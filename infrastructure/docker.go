@@ -0,0 +1,236 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// dockerCLI runs the docker CLI against a docker daemon.
+type dockerCLI struct {
+	// env points the docker CLI at the docker daemon of a KindHost. It is
+	// empty for the local docker daemon, which the docker CLI finds through
+	// the environment of the manager.
+	env map[string]string
+}
+
+// command returns the docker command with the given arguments.
+func (d dockerCLI) command(args ...string) exec.Cmd {
+	return d.apply(exec.Command("docker", args...))
+}
+
+// commandContext is like command, but the command is killed once the
+// context is done.
+func (d dockerCLI) commandContext(ctx context.Context, args ...string) exec.Cmd {
+	return d.apply(exec.CommandContext(ctx, "docker", args...))
+}
+
+func (d dockerCLI) apply(cmd exec.Cmd) exec.Cmd {
+	if environ := d.environ(); environ != nil {
+		cmd.SetEnv(environ...)
+	}
+	return cmd
+}
+
+// environ returns the environment of the manager with the variables of the
+// docker daemon applied, leaving out those with an empty value, so that the
+// settings of the local docker daemon do not leak into the host. It returns
+// nil for the local docker daemon, which runs commands with the environment
+// of the manager.
+func (d dockerCLI) environ() []string {
+	if len(d.env) == 0 {
+		return nil
+	}
+
+	environ := []string{}
+	for _, variable := range os.Environ() {
+		key, _, _ := strings.Cut(variable, "=")
+		if _, ok := d.env[key]; !ok {
+			environ = append(environ, variable)
+		}
+	}
+	for key, value := range d.env {
+		if value != "" {
+			environ = append(environ, key+"="+value)
+		}
+	}
+
+	return environ
+}
+
+// listClusters returns the names of the kind clusters on the docker daemon.
+func (d dockerCLI) listClusters() ([]string, error) {
+	lines, err := exec.OutputLines(d.command(
+		"ps", "--all",
+		"--filter", "label="+ClusterLabelKey,
+		"--format", fmt.Sprintf(`{{.Label "%s"}}`, ClusterLabelKey),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	clusters := map[string]bool{}
+	for _, line := range lines {
+		if name := strings.TrimSpace(line); name != "" {
+			clusters[name] = true
+		}
+	}
+
+	names := []string{}
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// listNodes returns the node containers of the kind cluster on the docker
+// daemon.
+func (d dockerCLI) listNodes(name string) ([]nodes.Node, error) {
+	lines, err := exec.OutputLines(d.command(
+		"ps", "--all",
+		"--filter", fmt.Sprintf("label=%s=%s", ClusterLabelKey, name),
+		"--format", "{{.Names}}",
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	clusterNodes := []nodes.Node{}
+	for _, line := range lines {
+		if nodeName := strings.TrimSpace(line); nodeName != "" {
+			clusterNodes = append(clusterNodes, &dockerNode{name: nodeName, docker: d})
+		}
+	}
+
+	return clusterNodes, nil
+}
+
+// dockerNode is a kind node container on the docker daemon of a KindHost.
+// The nodes kind returns run their commands with the environment of the
+// manager, so they only reach the local docker daemon.
+type dockerNode struct {
+	name   string
+	docker dockerCLI
+}
+
+var _ nodes.Node = &dockerNode{}
+
+func (n *dockerNode) String() string {
+	return n.name
+}
+
+func (n *dockerNode) Role() (string, error) {
+	lines, err := exec.OutputLines(n.docker.command(
+		"inspect", "--format", fmt.Sprintf(`{{ index .Config.Labels "%s" }}`, NodeRoleLabelKey), n.name))
+	if err != nil {
+		return "", fmt.Errorf("failed to get role of node %q: %w", n.name, err)
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("failed to get role of node %q: unexpected output %v", n.name, lines)
+	}
+
+	return lines[0], nil
+}
+
+func (n *dockerNode) IP() (ipv4 string, ipv6 string, err error) {
+	lines, err := exec.OutputLines(n.docker.command(
+		"inspect", "--format", "{{range .NetworkSettings.Networks}}{{.IPAddress}},{{.GlobalIPv6Address}}{{end}}", n.name))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get addresses of node %q: %w", n.name, err)
+	}
+	if len(lines) != 1 {
+		return "", "", fmt.Errorf("failed to get addresses of node %q: unexpected output %v", n.name, lines)
+	}
+
+	ips := strings.Split(lines[0], ",")
+	if len(ips) < 2 {
+		return "", "", fmt.Errorf("failed to get addresses of node %q: unexpected output %q", n.name, lines[0])
+	}
+
+	return ips[0], ips[1], nil
+}
+
+func (n *dockerNode) SerialLogs(writer io.Writer) error {
+	return n.docker.command("logs", n.name).SetStdout(writer).SetStderr(writer).Run()
+}
+
+func (n *dockerNode) Command(command string, args ...string) exec.Cmd {
+	return &dockerNodeCmd{node: n, command: command, args: args}
+}
+
+func (n *dockerNode) CommandContext(ctx context.Context, command string, args ...string) exec.Cmd {
+	return &dockerNodeCmd{node: n, command: command, args: args, ctx: ctx}
+}
+
+// dockerNodeCmd runs a command in a node container with docker exec, like
+// the commands of the nodes kind returns.
+type dockerNodeCmd struct {
+	node    *dockerNode
+	command string
+	args    []string
+	ctx     context.Context
+
+	env    []string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *dockerNodeCmd) Run() error {
+	args := []string{"exec", "--privileged"}
+	if c.stdin != nil {
+		args = append(args, "--interactive")
+	}
+	for _, env := range c.env {
+		args = append(args, "--env", env)
+	}
+	args = append(args, c.node.name, c.command)
+	args = append(args, c.args...)
+
+	var cmd exec.Cmd
+	if c.ctx != nil {
+		cmd = c.node.docker.commandContext(c.ctx, args...)
+	} else {
+		cmd = c.node.docker.command(args...)
+	}
+	if c.stdin != nil {
+		cmd.SetStdin(c.stdin)
+	}
+	if c.stdout != nil {
+		cmd.SetStdout(c.stdout)
+	}
+	if c.stderr != nil {
+		cmd.SetStderr(c.stderr)
+	}
+
+	return cmd.Run()
+}
+
+// SetEnv sets the environment of the command in the node container.
+func (c *dockerNodeCmd) SetEnv(env ...string) exec.Cmd {
+	c.env = env
+	return c
+}
+
+func (c *dockerNodeCmd) SetStdin(r io.Reader) exec.Cmd {
+	c.stdin = r
+	return c
+}
+
+func (c *dockerNodeCmd) SetStdout(w io.Writer) exec.Cmd {
+	c.stdout = w
+	return c
+}
+
+func (c *dockerNodeCmd) SetStderr(w io.Writer) exec.Cmd {
+	c.stderr = w
+	return c
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+var dockerVariables = []string{"DOCKER_HOST", "DOCKER_CONTEXT", "DOCKER_TLS_VERIFY", "DOCKER_CERT_PATH"}
+
+func TestDockerCLIEnviron(t *testing.T) {
+	tests := []struct {
+		name    string
+		environ map[string]string
+		env     map[string]string
+		want    []string
+		wantNil bool
+	}{
+		{
+			name:    "keeps the environment of the manager for the local docker daemon",
+			environ: map[string]string{"DOCKER_HOST": "unix:///var/run/docker.sock"},
+			wantNil: true,
+		},
+		{
+			name:    "replaces the variables of the host",
+			environ: map[string]string{"DOCKER_HOST": "unix:///var/run/docker.sock"},
+			env:     map[string]string{"DOCKER_HOST": "tcp://10.0.0.1:2376"},
+			want:    []string{"DOCKER_HOST=tcp://10.0.0.1:2376"},
+		},
+		{
+			name:    "adds the variables of the host",
+			environ: map[string]string{},
+			env:     map[string]string{"DOCKER_HOST": "tcp://10.0.0.1:2376", "DOCKER_TLS_VERIFY": "1"},
+			want:    []string{"DOCKER_HOST=tcp://10.0.0.1:2376", "DOCKER_TLS_VERIFY=1"},
+		},
+		{
+			name:    "unsets the variables of the host with an empty value",
+			environ: map[string]string{"DOCKER_CONTEXT": "remote", "DOCKER_CERT_PATH": "/certs"},
+			env:     map[string]string{"DOCKER_HOST": "tcp://10.0.0.1:2376", "DOCKER_CONTEXT": "", "DOCKER_CERT_PATH": ""},
+			want:    []string{"DOCKER_HOST=tcp://10.0.0.1:2376"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			for _, key := range dockerVariables {
+				// t.Setenv restores the variable after the test, also if it
+				// is unset.
+				t.Setenv(key, tt.environ[key])
+				if _, ok := tt.environ[key]; !ok {
+					g.Expect(os.Unsetenv(key)).To(Succeed())
+				}
+			}
+			t.Setenv("PATH", "/usr/bin")
+
+			environ := dockerCLI{env: tt.env}.environ()
+			if tt.wantNil {
+				g.Expect(environ).To(BeNil())
+				return
+			}
+
+			g.Expect(environ).To(ContainElement("PATH=/usr/bin"))
+			docker := []string{}
+			for _, variable := range environ {
+				key, _, _ := strings.Cut(variable, "=")
+				if slices.Contains(dockerVariables, key) {
+					docker = append(docker, variable)
+				}
+			}
+			g.Expect(docker).To(ConsistOf(tt.want))
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const (
+	// ClusterLabelKey is the label kind sets on every node container with
+	// the name of the cluster the node belongs to.
+	ClusterLabelKey = "io.x-k8s.kind.cluster"
+	// NodeRoleLabelKey is the label kind sets on every node container with
+	// the role of the node.
+	NodeRoleLabelKey = "io.x-k8s.kind.role"
+)
+
+const eventsRetryInterval = 5 * time.Second
+
+var watchedActions = []string{"start", "stop", "die", "destroy"}
+
+type containerEvent struct {
+	Action string `json:"Action"`
+	Actor  struct {
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+}
+
+// ContainerEvents subscribes to the container runtime event streams of the
+// local docker daemon and of the KindHosts in use and emits a generic event
+// for every state change of a kind node container. The emitted objects only
+// carry the kind cluster name in Spec.Name and the KindHost it runs on in
+// Spec.HostRef, which is nil for the local docker daemon, and have to be
+// mapped back to the owning KindCluster by the consumer.
+type ContainerEvents struct {
+	cache  *ClusterCache
+	events chan event.GenericEvent
+
+	mu sync.Mutex
+	// ctx is the context Start was called with, nil before. The event
+	// streams of the hosts are stopped once it is done.
+	ctx   context.Context
+	hosts map[string]*hostEvents
+}
+
+// hostEvents is the event stream of the docker daemon of a KindHost, which
+// invalidates the cluster cache of the host.
+type hostEvents struct {
+	docker dockerCLI
+	cache  *ClusterCache
+	cancel context.CancelFunc
+}
+
+func NewContainerEvents(cache *ClusterCache) *ContainerEvents {
+	return &ContainerEvents{
+		cache:  cache,
+		events: make(chan event.GenericEvent),
+		hosts:  map[string]*hostEvents{},
+	}
+}
+
+// Events returns the channel on which the container events are emitted.
+func (e *ContainerEvents) Events() <-chan event.GenericEvent {
+	return e.events
+}
+
+// Start streams the container events of the local docker daemon until the
+// context is cancelled, together with those of the hosts being watched. It
+// implements manager.Runnable.
+func (e *ContainerEvents) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("container-events")
+
+	e.mu.Lock()
+	e.ctx = ctx
+	for name, host := range e.hosts {
+		e.startHost(name, host)
+	}
+	e.mu.Unlock()
+
+	e.stream(ctx, logger, "", dockerCLI{}, e.cache)
+	return nil
+}
+
+// watchHost streams the container events of the docker daemon of the
+// KindHost with the given name, replacing the stream of a previous version
+// of the host.
+func (e *ContainerEvents) watchHost(name string, docker dockerCLI, cache *ClusterCache) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopHost(name)
+	host := &hostEvents{docker: docker, cache: cache, cancel: func() {}}
+	e.hosts[name] = host
+	if e.ctx != nil {
+		e.startHost(name, host)
+	}
+}
+
+// forgetHost stops streaming the container events of the KindHost with the
+// given name.
+func (e *ContainerEvents) forgetHost(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stopHost(name)
+}
+
+func (e *ContainerEvents) startHost(name string, host *hostEvents) {
+	ctx, cancel := context.WithCancel(e.ctx)
+	host.cancel = cancel
+	logger := log.FromContext(ctx).WithName("container-events").WithValues("host", name)
+	go e.stream(ctx, logger, name, host.docker, host.cache)
+}
+
+func (e *ContainerEvents) stopHost(name string) {
+	if host, ok := e.hosts[name]; ok {
+		host.cancel()
+		delete(e.hosts, name)
+	}
+}
+
+// stream streams the container events of the docker daemon of the KindHost
+// with the given name, or of the local one for an empty name, until the
+// context is cancelled, resubscribing if the event stream breaks.
+func (e *ContainerEvents) stream(ctx context.Context, logger logr.Logger, hostName string, docker dockerCLI, cache *ClusterCache) {
+	for {
+		err := e.watch(ctx, logger, hostName, docker, cache)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Error(err, "container event stream broke, resubscribing")
+
+		// Events may have been missed while the stream was down.
+		cache.Invalidate()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(eventsRetryInterval):
+		}
+	}
+}
+
+func (e *ContainerEvents) watch(ctx context.Context, logger logr.Logger, hostName string, docker dockerCLI, cache *ClusterCache) error {
+	args := []string{
+		"events",
+		"--format", "{{json .}}",
+		"--filter", "type=container",
+		"--filter", "label=" + ClusterLabelKey,
+	}
+	for _, action := range watchedActions {
+		args = append(args, "--filter", "event="+action)
+	}
+
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Env = docker.environ()
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	e.consume(ctx, logger, hostName, stdout, cache)
+	return cmd.Wait()
+}
+
+func (e *ContainerEvents) consume(ctx context.Context, logger logr.Logger, hostName string, stream io.Reader, cache *ClusterCache) {
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		var containerEvent containerEvent
+		if err := json.Unmarshal(scanner.Bytes(), &containerEvent); err != nil {
+			logger.Error(err, "failed to parse container event", "event", scanner.Text())
+			continue
+		}
+
+		clusterName := containerEvent.Actor.Attributes[ClusterLabelKey]
+		if clusterName == "" {
+			continue
+		}
+
+		logger.V(1).Info("kind node container changed",
+			"cluster-name", clusterName,
+			"action", containerEvent.Action,
+			"role", containerEvent.Actor.Attributes[NodeRoleLabelKey],
+		)
+		cache.Invalidate()
+
+		select {
+		case e.events <- event.GenericEvent{Object: eventObject(clusterName, hostName)}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func eventObject(clusterName, hostName string) *kclusterv1.KindCluster {
+	object := &kclusterv1.KindCluster{
+		Spec: kclusterv1.KindClusterSpec{
+			Name: clusterName,
+		},
+	}
+	if hostName != "" {
+		object.Spec.HostRef = &corev1.LocalObjectReference{Name: hostName}
+	}
+
+	return object
+}
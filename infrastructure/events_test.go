@@ -0,0 +1,108 @@
+package infrastructure
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+func TestContainerEventsConsume(t *testing.T) {
+	tests := []struct {
+		name           string
+		hostName       string
+		stream         []string
+		wantEvents     []*kclusterv1.KindCluster
+		wantInvalidate bool
+	}{
+		{
+			name:   "emits an event with the cluster name for a node container",
+			stream: []string{`{"Action":"die","Actor":{"Attributes":{"io.x-k8s.kind.cluster":"foo","io.x-k8s.kind.role":"worker"}}}`},
+			wantEvents: []*kclusterv1.KindCluster{
+				{Spec: kclusterv1.KindClusterSpec{Name: "foo"}},
+			},
+			wantInvalidate: true,
+		},
+		{
+			name:     "emits an event with the host of the stream",
+			hostName: "the-host",
+			stream:   []string{`{"Action":"start","Actor":{"Attributes":{"io.x-k8s.kind.cluster":"foo"}}}`},
+			wantEvents: []*kclusterv1.KindCluster{
+				{Spec: kclusterv1.KindClusterSpec{Name: "foo", HostRef: &corev1.LocalObjectReference{Name: "the-host"}}},
+			},
+			wantInvalidate: true,
+		},
+		{
+			name: "emits an event for every line",
+			stream: []string{
+				`{"Action":"stop","Actor":{"Attributes":{"io.x-k8s.kind.cluster":"foo"}}}`,
+				`{"Action":"destroy","Actor":{"Attributes":{"io.x-k8s.kind.cluster":"bar"}}}`,
+			},
+			wantEvents: []*kclusterv1.KindCluster{
+				{Spec: kclusterv1.KindClusterSpec{Name: "foo"}},
+				{Spec: kclusterv1.KindClusterSpec{Name: "bar"}},
+			},
+			wantInvalidate: true,
+		},
+		{
+			name:       "skips containers without a cluster name",
+			stream:     []string{`{"Action":"die","Actor":{"Attributes":{"name":"not-a-node"}}}`},
+			wantEvents: []*kclusterv1.KindCluster{},
+		},
+		{
+			name: "skips lines that can not be parsed",
+			stream: []string{
+				`not json`,
+				`{"Action":"die","Actor":{"Attributes":{"io.x-k8s.kind.cluster":"foo"}}}`,
+			},
+			wantEvents: []*kclusterv1.KindCluster{
+				{Spec: kclusterv1.KindClusterSpec{Name: "foo"}},
+			},
+			wantInvalidate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			lister := &countingLister{}
+			cache := NewClusterCache(lister)
+			_, err := cache.Contains("foo")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			events := &ContainerEvents{events: make(chan event.GenericEvent, len(tt.stream))}
+			events.consume(context.Background(), log.Log, tt.hostName, strings.NewReader(strings.Join(tt.stream, "\n")), cache)
+			close(events.events)
+
+			actualEvents := []*kclusterv1.KindCluster{}
+			for evt := range events.events {
+				actualEvents = append(actualEvents, evt.Object.(*kclusterv1.KindCluster))
+			}
+			g.Expect(actualEvents).To(Equal(tt.wantEvents))
+
+			_, err = cache.Contains("foo")
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantInvalidate {
+				g.Expect(lister.calls).To(Equal(2))
+			} else {
+				g.Expect(lister.calls).To(Equal(1))
+			}
+		})
+	}
+}
+
+type countingLister struct {
+	calls int
+}
+
+func (l *countingLister) List() ([]string, error) {
+	l.calls++
+	return []string{"foo"}, nil
+}
@@ -0,0 +1,204 @@
+package infrastructure
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// initialPlacement returns the failure domain of every node kind creates for
+// the kind cluster, keyed by node name. The nodes of each role are spread
+// round-robin across the failure domains eligible for the role.
+func initialPlacement(kindCluster *kclusterv1.KindCluster, controlPlaneNodes, workerNodes int) map[string]string {
+	placement := map[string]string{}
+
+	for role, count := range map[string]int{
+		constants.ControlPlaneNodeRoleValue: controlPlaneNodes,
+		constants.WorkerNodeRoleValue:       workerNodes,
+	} {
+		failureDomains := eligibleFailureDomains(kindCluster, role, nil)
+		if len(failureDomains) == 0 {
+			continue
+		}
+
+		for i := 0; i < count; i++ {
+			placement[nodeName(kindCluster, role, i)] = failureDomains[i%len(failureDomains)].Name
+		}
+	}
+
+	return placement
+}
+
+// eligibleFailureDomains returns the failure domains of the kind cluster
+// nodes with the role can be placed in. Control plane nodes can only be
+// placed in failure domains marked for the control plane. If candidates are
+// given, only the failure domains named in them are eligible.
+func eligibleFailureDomains(kindCluster *kclusterv1.KindCluster, role string, candidates []string) []kclusterv1.FailureDomain {
+	eligible := []kclusterv1.FailureDomain{}
+	for _, failureDomain := range kindCluster.Spec.FailureDomains {
+		if role == constants.ControlPlaneNodeRoleValue && !failureDomain.ControlPlane {
+			continue
+		}
+		if len(candidates) > 0 && !slices.Contains(candidates, failureDomain.Name) {
+			continue
+		}
+		eligible = append(eligible, failureDomain)
+	}
+
+	return eligible
+}
+
+// selectFailureDomain returns the eligible failure domain with the fewest
+// nodes of the role, or nil if the kind cluster has no eligible failure
+// domain.
+func (p *KindProvider) selectFailureDomain(kindCluster *kclusterv1.KindCluster, role string, candidates []string) (*kclusterv1.FailureDomain, error) {
+	eligible := eligibleFailureDomains(kindCluster, role, candidates)
+	if len(eligible) == 0 {
+		return nil, nil
+	}
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, node := range clusterNodes {
+		nodeRole, err := node.Role()
+		if err != nil {
+			return nil, err
+		}
+		if nodeRole != role {
+			continue
+		}
+
+		container, err := p.inspectContainer(node.String())
+		if err != nil {
+			return nil, err
+		}
+		counts[failureDomainOf(kindCluster, container.networks)]++
+	}
+
+	selected := eligible[0]
+	for _, failureDomain := range eligible[1:] {
+		if counts[failureDomain.Name] < counts[selected.Name] {
+			selected = failureDomain
+		}
+	}
+
+	return &selected, nil
+}
+
+// placeNodes attaches the nodes kind created for the kind cluster to the
+// networks of their failure domains.
+func (p *KindProvider) placeNodes(kindCluster *kclusterv1.KindCluster, placement map[string]string) error {
+	for name, failureDomainName := range placement {
+		failureDomain := findFailureDomain(kindCluster, failureDomainName)
+		if failureDomain == nil {
+			continue
+		}
+
+		if err := p.attachToFailureDomain(kindCluster, name, failureDomain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attachToFailureDomain connects the node container to the network of the
+// failure domain, creating the network if it does not exist yet.
+func (p *KindProvider) attachToFailureDomain(kindCluster *kclusterv1.KindCluster, name string, failureDomain *kclusterv1.FailureDomain) error {
+	network := failureDomain.GetNetwork(kindCluster.Spec.Name)
+	if err := p.ensureNetwork(kindCluster, network); err != nil {
+		return err
+	}
+
+	if err := p.docker.command("network", "connect", network, name).Run(); err != nil {
+		return fmt.Errorf("failed to connect node %q to network %q: %w", name, network, err)
+	}
+
+	return nil
+}
+
+// ensureNetwork creates the docker network of a failure domain, labelled
+// with the kind cluster so that it is removed together with the cluster.
+// Networks that already exist are used as they are.
+func (p *KindProvider) ensureNetwork(kindCluster *kclusterv1.KindCluster, network string) error {
+	if p.docker.command("network", "inspect", network).Run() == nil {
+		return nil
+	}
+
+	err := p.docker.command(
+		"network", "create",
+		"--label", fmt.Sprintf("%s=%s", ClusterLabelKey, kindCluster.Spec.Name),
+		network,
+	).Run()
+	if err != nil {
+		return fmt.Errorf("failed to create network %q: %w", network, err)
+	}
+
+	return nil
+}
+
+// deleteNetworks removes the failure domain networks created for the kind
+// cluster.
+func (p *KindProvider) deleteNetworks(kindCluster *kclusterv1.KindCluster) error {
+	lines, err := exec.OutputLines(p.docker.command(
+		"network", "ls",
+		"--filter", fmt.Sprintf("label=%s=%s", ClusterLabelKey, kindCluster.Spec.Name),
+		"--format", "{{.Name}}",
+	))
+	if err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		network := strings.TrimSpace(line)
+		if network == "" {
+			continue
+		}
+
+		if err := p.docker.command("network", "rm", network).Run(); err != nil {
+			return fmt.Errorf("failed to remove network %q: %w", network, err)
+		}
+	}
+
+	return nil
+}
+
+// failureDomainOf returns the failure domain whose network is one of the
+// given container networks, or an empty string if there is none.
+func failureDomainOf(kindCluster *kclusterv1.KindCluster, networks []string) string {
+	for _, failureDomain := range kindCluster.Spec.FailureDomains {
+		if slices.Contains(networks, failureDomain.GetNetwork(kindCluster.Spec.Name)) {
+			return failureDomain.Name
+		}
+	}
+
+	return ""
+}
+
+func findFailureDomain(kindCluster *kclusterv1.KindCluster, name string) *kclusterv1.FailureDomain {
+	for i := range kindCluster.Spec.FailureDomains {
+		if kindCluster.Spec.FailureDomains[i].Name == name {
+			return &kindCluster.Spec.FailureDomains[i]
+		}
+	}
+
+	return nil
+}
+
+func zoneLabels(failureDomain string) map[string]string {
+	if failureDomain == "" {
+		return nil
+	}
+
+	return map[string]string{corev1.LabelTopologyZone: failureDomain}
+}
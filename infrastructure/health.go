@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const healthCheckTimeout = 10 * time.Second
+
+// CheckHealth returns an error describing why the kind cluster is unhealthy,
+// or nil if all of its node containers are running and its API server
+// reports ready. A healthy kind cluster's kubeconfig is returned as well, so
+// that callers can pass it to the methods reading from its API server
+// instead of reading it from the control plane node again.
+func (p *KindProvider) CheckHealth(kindCluster *kclusterv1.KindCluster) ([]byte, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("cluster %q has no nodes", kindCluster.Spec.Name)
+	}
+
+	for _, node := range nodes {
+		state, err := p.containerState(node.String())
+		if err != nil {
+			return nil, err
+		}
+
+		if state != containerRunning {
+			return nil, fmt.Errorf("node %q is %s", node.String(), state)
+		}
+	}
+
+	kubeconfig, err := p.GetKubeconfig(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := clientsetFor(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	_, err = clientset.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("api server is not ready: %w", err)
+	}
+
+	return kubeconfig, nil
+}
+
+// GetKubernetesVersion returns the version reported by the API server of the
+// kubeconfig of a kind cluster.
+func (p *KindProvider) GetKubernetesVersion(kubeconfig []byte) (string, error) {
+	clientset, err := clientsetFor(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", err
+	}
+
+	return version.GitVersion, nil
+}
+
+// clientsetFor returns a clientset for the API server of the kubeconfig of a
+// kind cluster, whose requests time out after the health check timeout.
+func clientsetFor(kubeconfig []byte) (*kubernetes.Clientset, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Timeout = healthCheckTimeout
+
+	return kubernetes.NewForConfig(restConfig)
+}
@@ -0,0 +1,136 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// HostCommand is the first argument of the manager when it runs an
+// operation of kind against the docker daemon of a KindHost, see
+// RunHostCommand.
+const HostCommand = "kind-host"
+
+const (
+	hostCreate      = "create"
+	hostDelete      = "delete"
+	hostKubeconfig  = "kubeconfig"
+	hostCollectLogs = "collect-logs"
+)
+
+// RunHostCommand runs the operation of kind given by the arguments following
+// HostCommand. kind only talks to the docker daemon the environment of the
+// process points at, so the operations on a KindHost run in a child process
+// of the manager with the environment of the host, instead of changing the
+// environment of the manager for all operations.
+func RunHostCommand(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		return fmt.Errorf("expected an operation and a cluster name, got %v", args)
+	}
+	operation, name, args := args[0], args[1], args[2:]
+	provider := cluster.NewProvider(cluster.ProviderWithDocker())
+
+	switch {
+	case operation == hostCreate && len(args) == 1:
+		config, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		return localClusters{provider: provider}.createWithRawConfig(name, config, args[0])
+	case operation == hostDelete && len(args) == 1:
+		return provider.Delete(name, args[0])
+	case operation == hostKubeconfig && len(args) == 0:
+		kubeconfig, err := provider.KubeConfig(name, false)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(stdout, kubeconfig)
+		return err
+	case operation == hostCollectLogs && len(args) == 1:
+		return provider.CollectLogs(name, args[0])
+	}
+
+	return fmt.Errorf("unknown operation %s %v", operation, args)
+}
+
+// hostClusters runs kind against the docker daemon of a KindHost. Listing
+// the clusters and nodes and running commands in the nodes use the docker
+// CLI directly, the operations only kind implements run in a child process
+// of the manager.
+type hostClusters struct {
+	docker dockerCLI
+}
+
+var _ kindClusters = hostClusters{}
+
+func (c hostClusters) List() ([]string, error) {
+	return c.docker.listClusters()
+}
+
+func (c hostClusters) ListNodes(name string) ([]nodes.Node, error) {
+	return c.docker.listNodes(name)
+}
+
+func (c hostClusters) Create(name string, config *v1alpha4.Cluster, kubeconfigPath string) error {
+	config = config.DeepCopy()
+	config.TypeMeta = v1alpha4.TypeMeta{Kind: "Cluster", APIVersion: "kind.x-k8s.io/v1alpha4"}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.run(bytes.NewReader(data), hostCreate, name, kubeconfigPath)
+	return err
+}
+
+func (c hostClusters) Delete(name, kubeconfigPath string) error {
+	_, err := c.run(nil, hostDelete, name, kubeconfigPath)
+	return err
+}
+
+func (c hostClusters) KubeConfig(name string) (string, error) {
+	kubeconfig, err := c.run(nil, hostKubeconfig, name)
+	return string(kubeconfig), err
+}
+
+func (c hostClusters) CollectLogs(name, dir string) error {
+	_, err := c.run(nil, hostCollectLogs, name, dir)
+	return err
+}
+
+// run runs the manager with HostCommand and the arguments in the environment
+// of the host and returns its output. The error of the child process is
+// returned as it is, so that callers can inspect the error of kind.
+func (c hostClusters) run(stdin io.Reader, args ...string) ([]byte, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(executable, append([]string{HostCommand}, args...)...).
+		SetEnv(c.docker.environ()...).
+		SetStdout(&stdout).
+		SetStderr(&stderr)
+	if stdin != nil {
+		cmd.SetStdin(stdin)
+	}
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, errors.New(message)
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// HostDiagnostics stores diagnostic archives in a directory on the manager
+// host.
+type HostDiagnostics struct {
+	dir string
+}
+
+func NewHostDiagnostics(dir string) *HostDiagnostics {
+	return &HostDiagnostics{
+		dir: dir,
+	}
+}
+
+func (d *HostDiagnostics) Store(_ context.Context, kindCluster *kclusterv1.KindCluster, archive []byte) (*kclusterv1.DiagnosticsReference, error) {
+	if err := os.MkdirAll(d.dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	collectedAt := time.Now()
+	path := filepath.Join(d.dir, fmt.Sprintf("%s-%s-%s.tar.gz",
+		kindCluster.Namespace, kindCluster.Name, collectedAt.UTC().Format("20060102T150405Z")))
+
+	if err := os.WriteFile(path, archive, 0o600); err != nil {
+		return nil, err
+	}
+
+	return &kclusterv1.DiagnosticsReference{
+		Storage:     kclusterv1.DiagnosticsStorageHostPath,
+		Path:        path,
+		Size:        len(archive),
+		CollectedAt: metav1.NewTime(collectedAt),
+	}, nil
+}
@@ -0,0 +1,303 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// HostClient reads the KindHosts kind clusters can be created on. Every call
+// of the provider for a kind cluster on a KindHost reads the KindHost and its
+// TLS Secret, so the client is expected to read from a cache, as the client
+// of the manager does, rather than from the API server.
+type HostClient interface {
+	Get(context.Context, string) (*kclusterv1.KindHost, error)
+	GetTLSSecret(context.Context, *kclusterv1.KindHost) (*corev1.Secret, error)
+}
+
+// Hosts keeps a kind cluster provider for every KindHost. kind and the
+// docker CLI find the docker daemon to talk to through the environment, so
+// the commands on a remote host are run with the environment of the host
+// rather than the one of the manager. Each host has its own cache of kind
+// clusters, kept up to date by an event stream of its own.
+type Hosts struct {
+	client  HostClient
+	certDir string
+	events  *ContainerEvents
+
+	mu    sync.Mutex
+	hosts map[string]*runtimeHost
+}
+
+// runtimeHost is the kind cluster provider of a KindHost together with the
+// environment pointing kind and docker at it.
+type runtimeHost struct {
+	// version identifies the KindHost and TLS Secret the host was built
+	// from, so that it is rebuilt when either changes.
+	version          string
+	docker           dockerCLI
+	apiServerAddress string
+	clusters         kindClusters
+	clusterCache     *ClusterCache
+}
+
+// NewHosts creates Hosts reading the KindHosts with the client. The TLS
+// certificates of the hosts are written to certDir. The container events of
+// the hosts are emitted by events, unless it is nil.
+func NewHosts(client HostClient, certDir string, events *ContainerEvents) *Hosts {
+	return &Hosts{
+		client:  client,
+		certDir: certDir,
+		events:  events,
+		hosts:   map[string]*runtimeHost{},
+	}
+}
+
+// get returns the runtime host of the KindHost with the given name, building
+// it again if the KindHost or its TLS Secret have changed. The address of the
+// host is resolved and the certificates are written without holding the lock,
+// so that a slow DNS lookup of one host does not block the others.
+func (h *Hosts) get(name string) (*runtimeHost, error) {
+	ctx := context.Background()
+	host, err := h.client.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := h.client.GetTLSSecret(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TLS secret: %w", err)
+	}
+
+	version := host.ResourceVersion
+	if secret != nil {
+		version = fmt.Sprintf("%s/%s", version, secret.ResourceVersion)
+	}
+
+	if existing, ok := h.lookup(name, version); ok {
+		return existing, nil
+	}
+
+	apiServerAddress, err := hostAPIServerAddress(host)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty value unsets the variable, so that the settings of the local
+	// docker daemon do not leak into the host.
+	env := map[string]string{
+		"DOCKER_HOST":       host.Spec.Endpoint,
+		"DOCKER_CONTEXT":    "",
+		"DOCKER_TLS_VERIFY": "",
+		"DOCKER_CERT_PATH":  "",
+	}
+	if secret != nil {
+		certPath := filepath.Join(h.certDir, name)
+		if err := writeCertificates(certPath, secret); err != nil {
+			return nil, err
+		}
+		env["DOCKER_TLS_VERIFY"] = "1"
+		env["DOCKER_CERT_PATH"] = certPath
+	}
+
+	docker := dockerCLI{env: env}
+	clusters := hostClusters{docker: docker}
+	runtimeHost := &runtimeHost{
+		version:          version,
+		docker:           docker,
+		apiServerAddress: apiServerAddress,
+		clusters:         clusters,
+		clusterCache:     NewClusterCache(clusters),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Another call may have built the same version of the host in the
+	// meantime, whose event stream is already running.
+	if existing, ok := h.hosts[name]; ok && existing.version == version {
+		return existing, nil
+	}
+	h.hosts[name] = runtimeHost
+
+	if h.events != nil {
+		h.events.watchHost(name, runtimeHost.docker, runtimeHost.clusterCache)
+	}
+
+	return runtimeHost, nil
+}
+
+// lookup returns the runtime host of the KindHost with the given name if it
+// was built from the given version.
+func (h *Hosts) lookup(name, version string) (*runtimeHost, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	existing, ok := h.hosts[name]
+	if !ok || existing.version != version {
+		return nil, false
+	}
+	return existing, true
+}
+
+// Forget drops the runtime host of the KindHost with the given name and
+// stops its event stream, once the KindHost no longer exists.
+func (h *Hosts) Forget(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.hosts, name)
+	if h.events != nil {
+		h.events.forgetHost(name)
+	}
+}
+
+// writeCertificates writes the certificates of the TLS Secret to dir using
+// the file names the docker CLI expects in DOCKER_CERT_PATH. Each file is
+// replaced by a rename, as the docker CLI of a running command may be reading
+// it at the same time.
+func writeCertificates(dir string, secret *corev1.Secret) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create certificate directory: %w", err)
+	}
+
+	files := map[string]string{
+		"ca.pem":   kclusterv1.HostTLSCAKey,
+		"cert.pem": kclusterv1.HostTLSCertKey,
+		"key.pem":  kclusterv1.HostTLSKeyKey,
+	}
+	for file, key := range files {
+		data, ok := secret.Data[key]
+		if !ok {
+			return fmt.Errorf("TLS secret %s/%s has no %s", secret.Namespace, secret.Name, key)
+		}
+
+		if err := writeFileAtomically(filepath.Join(dir, file), data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+func writeFileAtomically(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// hostAPIServerAddress returns the IP address the API servers of the kind
+// clusters on the host are published on. Unless set explicitly it is the
+// address of the endpoint of the host.
+func hostAPIServerAddress(host *kclusterv1.KindHost) (string, error) {
+	address := host.Spec.APIServerAddress
+	if address == "" {
+		endpoint, err := url.Parse(host.Spec.Endpoint)
+		if err != nil {
+			return "", fmt.Errorf("invalid endpoint %q: %w", host.Spec.Endpoint, err)
+		}
+		address = endpoint.Hostname()
+	}
+
+	if net.ParseIP(address) != nil {
+		return address, nil
+	}
+
+	// kind publishes the API server port on an IP address only.
+	ips, err := net.LookupIP(address)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", address, err)
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String(), nil
+		}
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("%q does not resolve to an IP address", address)
+	}
+
+	return ips[0].String(), nil
+}
+
+// ForgetHost drops the provider of the KindHost with the given name and
+// stops its container event stream.
+func (p *KindProvider) ForgetHost(name string) {
+	if p.hosts != nil {
+		p.hosts.Forget(name)
+	}
+}
+
+// GetHostStatus returns the kind clusters and nodes on the docker daemon of
+// the KindHost and the CPUs and memory the daemon reports.
+func (p *KindProvider) GetHostStatus(host *kclusterv1.KindHost) (kclusterv1.KindHostStatus, error) {
+	p, err := p.bind(host.Name)
+	if err != nil {
+		return kclusterv1.KindHostStatus{}, err
+	}
+
+	lines, err := exec.OutputLines(p.docker.command("info", "--format", "{{.NCPU}}\t{{.MemTotal}}"))
+	if err != nil {
+		return kclusterv1.KindHostStatus{}, fmt.Errorf("failed to get docker info: %w", err)
+	}
+
+	if len(lines) != 1 {
+		return kclusterv1.KindHostStatus{}, fmt.Errorf("unexpected docker info output: %v", lines)
+	}
+
+	var cpus int32
+	var memory int64
+	if _, err := fmt.Sscanf(lines[0], "%d\t%d", &cpus, &memory); err != nil {
+		return kclusterv1.KindHostStatus{}, fmt.Errorf("unexpected docker info output %q: %w", lines[0], err)
+	}
+
+	lines, err = exec.OutputLines(p.docker.command(
+		"ps", "--all",
+		"--filter", fmt.Sprintf("label=%s", ClusterLabelKey),
+		"--format", fmt.Sprintf(`{{.Label "%s"}}`, ClusterLabelKey),
+	))
+	if err != nil {
+		return kclusterv1.KindHostStatus{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	clusters := map[string]bool{}
+	var nodes int32
+	for _, line := range lines {
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+		clusters[name] = true
+		nodes++
+	}
+
+	return kclusterv1.KindHostStatus{
+		Clusters: int32(len(clusters)), //nolint:gosec
+		Nodes:    nodes,
+		CPUs:     cpus,
+		Memory:   resource.NewQuantity(memory, resource.BinarySI),
+	}, nil
+}
@@ -0,0 +1,173 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+func TestHostAPIServerAddress(t *testing.T) {
+	tests := []struct {
+		name             string
+		endpoint         string
+		apiServerAddress string
+		want             string
+		wantErr          bool
+	}{
+		{
+			name:     "uses the address of the endpoint",
+			endpoint: "tcp://10.0.0.1:2376",
+			want:     "10.0.0.1",
+		},
+		{
+			name:             "prefers the explicit address",
+			endpoint:         "tcp://10.0.0.1:2376",
+			apiServerAddress: "10.0.0.2",
+			want:             "10.0.0.2",
+		},
+		{
+			name:     "resolves a host name",
+			endpoint: "tcp://localhost:2376",
+			want:     "127.0.0.1",
+		},
+		{
+			name:     "uses an IPv6 address",
+			endpoint: "tcp://[fd00::1]:2376",
+			want:     "fd00::1",
+		},
+		{
+			name:     "fails on an invalid endpoint",
+			endpoint: "tcp://%zz",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			host := &kclusterv1.KindHost{Spec: kclusterv1.KindHostSpec{
+				Endpoint:         tt.endpoint,
+				APIServerAddress: tt.apiServerAddress,
+			}}
+
+			address, err := hostAPIServerAddress(host)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(address).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestHostsGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      func(*fakeHostClient)
+		wantRebuilt bool
+		wantErr     bool
+	}{
+		{
+			name:   "reuses the host while nothing changed",
+			update: func(*fakeHostClient) {},
+		},
+		{
+			name: "rebuilds the host when the KindHost changed",
+			update: func(c *fakeHostClient) {
+				c.host.ResourceVersion = "2"
+			},
+			wantRebuilt: true,
+		},
+		{
+			name: "rebuilds the host when the TLS Secret changed",
+			update: func(c *fakeHostClient) {
+				c.secret.ResourceVersion = "2"
+				c.secret.Data[kclusterv1.HostTLSCAKey] = []byte("new-ca")
+			},
+			wantRebuilt: true,
+		},
+		{
+			name: "fails when the TLS Secret has no certificate",
+			update: func(c *fakeHostClient) {
+				c.secret.ResourceVersion = "2"
+				delete(c.secret.Data, kclusterv1.HostTLSCertKey)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client := &fakeHostClient{
+				host: &kclusterv1.KindHost{
+					ObjectMeta: metav1.ObjectMeta{Name: "remote", ResourceVersion: "1"},
+					Spec: kclusterv1.KindHostSpec{
+						Endpoint:     "tcp://10.0.0.1:2376",
+						TLSSecretRef: &corev1.SecretReference{Name: "remote-tls", Namespace: "default"},
+					},
+				},
+				secret: &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"},
+					Data: map[string][]byte{
+						kclusterv1.HostTLSCAKey:   []byte("ca"),
+						kclusterv1.HostTLSCertKey: []byte("cert"),
+						kclusterv1.HostTLSKeyKey:  []byte("key"),
+					},
+				},
+			}
+			certDir := t.TempDir()
+			hosts := NewHosts(client, certDir, nil)
+
+			first, err := hosts.get("remote")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(first.apiServerAddress).To(Equal("10.0.0.1"))
+			g.Expect(first.docker.env).To(HaveKeyWithValue("DOCKER_HOST", "tcp://10.0.0.1:2376"))
+			g.Expect(first.docker.env).To(HaveKeyWithValue("DOCKER_CERT_PATH", filepath.Join(certDir, "remote")))
+
+			tt.update(client)
+			second, err := hosts.get("remote")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantRebuilt {
+				g.Expect(second).NotTo(BeIdenticalTo(first))
+			} else {
+				g.Expect(second).To(BeIdenticalTo(first))
+			}
+
+			ca, err := os.ReadFile(filepath.Join(certDir, "remote", "ca.pem"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ca).To(Equal(client.secret.Data[kclusterv1.HostTLSCAKey]))
+
+			entries, err := os.ReadDir(filepath.Join(certDir, "remote"))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(entries).To(HaveLen(3))
+		})
+	}
+}
+
+type fakeHostClient struct {
+	host   *kclusterv1.KindHost
+	secret *corev1.Secret
+}
+
+func (c *fakeHostClient) Get(context.Context, string) (*kclusterv1.KindHost, error) {
+	return c.host.DeepCopy(), nil
+}
+
+func (c *fakeHostClient) GetTLSSecret(context.Context, *kclusterv1.KindHost) (*corev1.Secret, error) {
+	return c.secret.DeepCopy(), nil
+}
@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// kubeconfigFile returns the file kind exports the kubeconfig of the kind
+// cluster to when creating it. Kind always exports the kubeconfig, falling
+// back to the KUBECONFIG of the manager if it is given no file, so without a
+// kubeconfig directory it exports to a temporary directory removed by
+// cleanup.
+func (p *KindProvider) kubeconfigFile(name string) (path string, cleanup func(), err error) {
+	if p.kubeconfigDir == "" {
+		return scratchKubeconfigFile()
+	}
+
+	if err := os.MkdirAll(p.kubeconfigDir, 0o700); err != nil {
+		return "", nil, err
+	}
+
+	return p.kubeconfigFilePath(name), func() {}, nil
+}
+
+// ExportKubeconfig writes the kubeconfig of the kind cluster to its file in
+// the kubeconfig directory if it changed since kind exported it on create,
+// e.g. because the port of the API server changed when the nodes were
+// started again or the certificates were renewed.
+func (p *KindProvider) ExportKubeconfig(kindCluster *kclusterv1.KindCluster, kubeconfig []byte) error {
+	return p.writeKubeconfigFile(kindCluster.Spec.Name, kubeconfig)
+}
+
+// writeKubeconfigFile writes the kubeconfig to the file of the kind cluster
+// in the kubeconfig directory, if there is one and the file has different
+// content.
+func (p *KindProvider) writeKubeconfigFile(name string, kubeconfig []byte) error {
+	if p.kubeconfigDir == "" {
+		return nil
+	}
+
+	path := p.kubeconfigFilePath(name)
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, kubeconfig) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := os.MkdirAll(p.kubeconfigDir, 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, kubeconfig, 0o600)
+}
+
+// removeKubeconfigFile removes the kubeconfig file of the kind cluster from
+// the kubeconfig directory, if there is one.
+func (p *KindProvider) removeKubeconfigFile(name string) error {
+	if p.kubeconfigDir == "" {
+		return nil
+	}
+
+	err := os.Remove(p.kubeconfigFilePath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (p *KindProvider) kubeconfigFilePath(name string) string {
+	return filepath.Join(p.kubeconfigDir, name+".kubeconfig")
+}
+
+// scratchKubeconfigFile returns a file in a temporary directory for kind to
+// export the kubeconfig of a kind cluster to, or remove it from, instead of
+// the KUBECONFIG of the manager. The directory is removed by cleanup.
+func scratchKubeconfigFile() (path string, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "kind-kubeconfig-")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return filepath.Join(dir, "kubeconfig"), func() { os.RemoveAll(dir) }, nil
+}
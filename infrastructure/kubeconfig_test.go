@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWriteKubeconfigFile(t *testing.T) {
+	modTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		existing    []byte
+		kubeconfig  []byte
+		wantWritten bool
+	}{
+		{
+			name:        "writes the kubeconfig if there is no file",
+			kubeconfig:  []byte("new"),
+			wantWritten: true,
+		},
+		{
+			name:        "writes the kubeconfig if it changed",
+			existing:    []byte("old"),
+			kubeconfig:  []byte("new"),
+			wantWritten: true,
+		},
+		{
+			name:       "leaves the file alone if the kubeconfig did not change",
+			existing:   []byte("new"),
+			kubeconfig: []byte("new"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			dir := filepath.Join(t.TempDir(), "kubeconfigs")
+			path := filepath.Join(dir, "foo.kubeconfig")
+			if tt.existing != nil {
+				g.Expect(os.MkdirAll(dir, 0o700)).To(Succeed())
+				g.Expect(os.WriteFile(path, tt.existing, 0o600)).To(Succeed())
+				g.Expect(os.Chtimes(path, modTime, modTime)).To(Succeed())
+			}
+
+			p := &KindProvider{kubeconfigDir: dir}
+			g.Expect(p.writeKubeconfigFile("foo", tt.kubeconfig)).To(Succeed())
+
+			content, err := os.ReadFile(path)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(content).To(Equal(tt.kubeconfig))
+
+			info, err := os.Stat(path)
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantWritten {
+				g.Expect(info.ModTime()).NotTo(BeTemporally("==", modTime))
+			} else {
+				g.Expect(info.ModTime()).To(BeTemporally("==", modTime))
+			}
+		})
+	}
+}
+
+func TestWriteKubeconfigFileWithoutDirectory(t *testing.T) {
+	g := NewWithT(t)
+
+	p := &KindProvider{}
+	g.Expect(p.writeKubeconfigFile("foo", []byte("new"))).To(Succeed())
+}
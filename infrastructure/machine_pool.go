@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// MachinePoolLabel is the docker label recording the KindMachinePool a
+// worker node container belongs to.
+const MachinePoolLabel = "io.x-k8s.kind.machine-pool"
+
+const drainTimeout = 2 * time.Minute
+
+// AddWorkerNode adds a worker node running the given Kubernetes version to
+// the kind cluster, labelled as belonging to the machine pool, and returns
+// its name. If version is empty the node uses the image of the first control
+// plane node. The node is placed in the least used of the given failure
+// domains, or of all failure domains of the kind cluster if none are given.
+func (p *KindProvider) AddWorkerNode(kindCluster *kclusterv1.KindCluster, pool, version string, failureDomains []string) (string, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return "", err
+	}
+
+	return p.addNode(kindCluster, constants.WorkerNodeRoleValue, version, map[string]string{
+		MachinePoolLabel: pool,
+	}, failureDomains)
+}
+
+// RemoveWorkerNode drains the worker node and removes it from the kind
+// cluster. The node is removed even if it can not be drained in time.
+func (p *KindProvider) RemoveWorkerNode(kindCluster *kclusterv1.KindCluster, name string) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	initNode, err := findNode(clusterNodes, initNodeName(kindCluster))
+	if err != nil {
+		return err
+	}
+
+	_ = initNode.Command(
+		"kubectl", "drain", name,
+		"--ignore-daemonsets", "--delete-emptydir-data", "--force",
+		fmt.Sprintf("--timeout=%s", drainTimeout),
+	).Run()
+
+	return p.removeNode(kindCluster, name)
+}
+
+// GetMachinePoolNodes returns the worker node containers of the machine
+// pool, oldest first.
+func (p *KindProvider) GetMachinePoolNodes(kindCluster *kclusterv1.KindCluster, pool string) ([]kclusterv1.MachinePoolNode, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := exec.OutputLines(p.docker.command(
+		"ps", "--all",
+		"--filter", fmt.Sprintf("label=%s=%s", ClusterLabelKey, kindCluster.Spec.Name),
+		"--filter", fmt.Sprintf("label=%s=%s", MachinePoolLabel, pool),
+		"--format", "{{.Names}}",
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	poolNodes := []kclusterv1.MachinePoolNode{}
+	for _, line := range lines {
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+
+		container, err := p.inspectContainer(name)
+		if err != nil {
+			return nil, err
+		}
+
+		poolNodes = append(poolNodes, kclusterv1.MachinePoolNode{
+			Name:          name,
+			ProviderID:    ProviderID(kindCluster.Spec.Name, name),
+			Image:         container.image,
+			CreationTime:  metav1.NewTime(container.created),
+			FailureDomain: failureDomainOf(kindCluster, container.networks),
+		})
+	}
+
+	sort.Slice(poolNodes, func(i, j int) bool {
+		return poolNodes[i].CreationTime.Before(&poolNodes[j].CreationTime)
+	})
+
+	return poolNodes, nil
+}
@@ -0,0 +1,448 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const (
+	joinConfigPath        = "/kind/kubeadm-join.conf"
+	apiServerInternalPort = 6443
+	nodeBootTimeout       = 2 * time.Minute
+)
+
+// systemdReady matches the container log line printed once systemd inside a
+// node container has booted, which kind waits for as well before running
+// kubeadm.
+var systemdReady = regexp.MustCompile(`Reached target .*Multi-User System.*|detected cgroup v1`)
+
+var joinConfigTemplate = template.Must(template.New("join").Parse(`apiVersion: kubeadm.k8s.io/v1beta3
+kind: JoinConfiguration
+{{- if .CertificateKey }}
+controlPlane:
+  localAPIEndpoint:
+    advertiseAddress: "{{ .NodeAddress }}"
+    bindPort: {{ .APIServerPort }}
+  certificateKey: "{{ .CertificateKey }}"
+{{- end }}
+nodeRegistration:
+  criSocket: unix:///run/containerd/containerd.sock
+  kubeletExtraArgs:
+    node-ip: "{{ .NodeAddress }}"
+    provider-id: "{{ .ProviderID }}"
+{{- if .NodeLabels }}
+    node-labels: "{{ .NodeLabels }}"
+{{- end }}
+discovery:
+  bootstrapToken:
+    apiServerEndpoint: "{{ .APIServerEndpoint }}"
+    token: "{{ .Token }}"
+    caCertHashes:
+    - "{{ .CACertHash }}"
+`))
+
+type joinConfig struct {
+	NodeAddress       string
+	APIServerPort     int
+	CertificateKey    string
+	ProviderID        string
+	NodeLabels        string
+	APIServerEndpoint string
+	Token             string
+	CACertHash        string
+}
+
+// nodeContainer is the part of the docker inspect output of a node
+// container that is needed to run another node container like it.
+type nodeContainer struct {
+	Config struct {
+		Image   string
+		Env     []string
+		Labels  map[string]string
+		Volumes map[string]struct{}
+	}
+	HostConfig struct {
+		NetworkMode   string
+		Privileged    bool
+		SecurityOpt   []string
+		Tmpfs         map[string]string
+		Binds         []string
+		CgroupnsMode  string
+		Init          *bool
+		RestartPolicy struct {
+			Name              string
+			MaximumRetryCount int
+		}
+		Devices []struct {
+			PathOnHost        string
+			PathInContainer   string
+			CgroupPermissions string
+		}
+	}
+}
+
+// inspectNodeContainer returns how kind ran the node container.
+func (p *KindProvider) inspectNodeContainer(node nodes.Node) (nodeContainer, error) {
+	output, err := exec.Output(p.docker.command("inspect", "--format", "{{json .}}", node.String()))
+	if err != nil {
+		return nodeContainer{}, err
+	}
+
+	container := nodeContainer{}
+	if err := json.Unmarshal(output, &container); err != nil {
+		return nodeContainer{}, fmt.Errorf("unexpected docker inspect output for %q: %w", node.String(), err)
+	}
+
+	return container, nil
+}
+
+// ProviderID returns the provider ID kind nodes are registered with.
+func ProviderID(clusterName, nodeName string) string {
+	return fmt.Sprintf("kind://docker/%s/%s", clusterName, nodeName)
+}
+
+// addNode runs a node container with the given role and additional labels
+// and joins it to the kind cluster with kubeadm. The node is placed in the
+// least used of the candidate failure domains. The container is removed
+// again if it fails to join.
+func (p *KindProvider) addNode(kindCluster *kclusterv1.KindCluster, role, version string, labels map[string]string, failureDomains []string) (name string, err error) {
+	defer p.clusterCache.Invalidate()
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return "", err
+	}
+
+	initNode, err := findNode(clusterNodes, initNodeName(kindCluster))
+	if err != nil {
+		return "", err
+	}
+
+	// The node is run like the first control plane node, so that it
+	// follows whatever kind version created the kind cluster.
+	initContainer, err := p.inspectNodeContainer(initNode)
+	if err != nil {
+		return "", err
+	}
+
+	image := initContainer.Config.Image
+	if version != "" {
+		image = kclusterv1.NodeImage(version)
+	}
+
+	failureDomain, err := p.selectFailureDomain(kindCluster, role, failureDomains)
+	if err != nil {
+		return "", err
+	}
+
+	name = nextNodeName(kindCluster, role, clusterNodes)
+	if err := p.docker.command(runArgs(initContainer, name, role, image, labels)...).Run(); err != nil {
+		return "", fmt.Errorf("failed to run node %q: %w", name, err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = p.docker.command("rm", "-f", "-v", name).Run()
+		}
+	}()
+
+	var nodeLabels map[string]string
+	if failureDomain != nil {
+		if err := p.attachToFailureDomain(kindCluster, name, failureDomain); err != nil {
+			return "", err
+		}
+		nodeLabels = zoneLabels(failureDomain.Name)
+	}
+
+	if err := p.waitForBoot(name); err != nil {
+		return "", err
+	}
+
+	clusterNodes, err = p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return "", err
+	}
+
+	node, err := findNode(clusterNodes, name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := joinNode(kindCluster, initNode, node, role == constants.ControlPlaneNodeRoleValue, nodeLabels); err != nil {
+		return "", fmt.Errorf("failed to join node %q: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// removeNode resets kubeadm on the node, deletes it from the Kubernetes API
+// and removes its container.
+func (p *KindProvider) removeNode(kindCluster *kclusterv1.KindCluster, name string) error {
+	defer p.clusterCache.Invalidate()
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	initNode, err := findNode(clusterNodes, initNodeName(kindCluster))
+	if err != nil {
+		return err
+	}
+
+	if node, err := findNode(clusterNodes, name); err == nil {
+		// Resetting removes the etcd member of control plane nodes. The node
+		// is removed regardless, so a failing reset is not fatal.
+		_ = node.Command("kubeadm", "reset", "--force").Run()
+	}
+
+	if err := initNode.Command("kubectl", "delete", "node", name, "--ignore-not-found").Run(); err != nil {
+		return fmt.Errorf("failed to delete node %q: %w", name, err)
+	}
+
+	if err := p.docker.command("rm", "-f", "-v", name).Run(); err != nil {
+		return fmt.Errorf("failed to remove node container %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// runArgs returns the docker run arguments for a node container with the
+// name, role, image and additional labels, run like the node container of
+// the kind cluster kind ran as the template: on the same network, with the
+// same security options, mounts and environment. Published ports are left
+// out, as only the first control plane node or the load balancer publish the
+// API server. The template is a control plane node, so the KUBECONFIG kind
+// sets for control plane nodes is left out for workers.
+func runArgs(template nodeContainer, name, role, image string, labels map[string]string) []string {
+	args := []string{
+		"run",
+		"--detach",
+		"--tty",
+		"--hostname", name,
+		"--name", name,
+		"--net", template.HostConfig.NetworkMode,
+	}
+
+	nodeLabels := map[string]string{}
+	maps.Copy(nodeLabels, template.Config.Labels)
+	maps.Copy(nodeLabels, labels)
+	nodeLabels[NodeRoleLabelKey] = role
+	for _, key := range slices.Sorted(maps.Keys(nodeLabels)) {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", key, nodeLabels[key]))
+	}
+
+	hostConfig := template.HostConfig
+	if hostConfig.RestartPolicy.Name != "" {
+		restart := hostConfig.RestartPolicy.Name
+		if hostConfig.RestartPolicy.MaximumRetryCount > 0 {
+			restart = fmt.Sprintf("%s:%d", restart, hostConfig.RestartPolicy.MaximumRetryCount)
+		}
+		args = append(args, "--restart="+restart)
+	}
+	if hostConfig.Init != nil {
+		args = append(args, fmt.Sprintf("--init=%t", *hostConfig.Init))
+	}
+	if hostConfig.CgroupnsMode != "" {
+		args = append(args, "--cgroupns="+hostConfig.CgroupnsMode)
+	}
+	if hostConfig.Privileged {
+		args = append(args, "--privileged")
+	}
+	for _, opt := range hostConfig.SecurityOpt {
+		args = append(args, "--security-opt", opt)
+	}
+	for _, path := range slices.Sorted(maps.Keys(hostConfig.Tmpfs)) {
+		tmpfs := path
+		if options := hostConfig.Tmpfs[path]; options != "" {
+			tmpfs += ":" + options
+		}
+		args = append(args, "--tmpfs", tmpfs)
+	}
+	for _, path := range slices.Sorted(maps.Keys(template.Config.Volumes)) {
+		args = append(args, "--volume", path)
+	}
+	for _, bind := range hostConfig.Binds {
+		args = append(args, "--volume", bind)
+	}
+	for _, device := range hostConfig.Devices {
+		args = append(args, "--device", fmt.Sprintf("%s:%s:%s", device.PathOnHost, device.PathInContainer, device.CgroupPermissions))
+	}
+
+	for _, variable := range template.Config.Env {
+		if role != constants.ControlPlaneNodeRoleValue && strings.HasPrefix(variable, "KUBECONFIG=") {
+			continue
+		}
+		args = append(args, "-e", variable)
+	}
+
+	return append(args, image)
+}
+
+func (p *KindProvider) waitForBoot(name string) error {
+	deadline := time.Now().Add(nodeBootTimeout)
+	for time.Now().Before(deadline) {
+		lines, err := exec.CombinedOutputLines(p.docker.command("logs", name))
+		if err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			if systemdReady.MatchString(line) {
+				return nil
+			}
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("node %q did not boot within %s", name, nodeBootTimeout)
+}
+
+func joinNode(kindCluster *kclusterv1.KindCluster, initNode, node nodes.Node, controlPlane bool, nodeLabels map[string]string) error {
+	lines, err := exec.OutputLines(initNode.Command("kubeadm", "token", "create", "--print-join-command"))
+	if err != nil {
+		return fmt.Errorf("failed to create join token: %w", err)
+	}
+
+	config, err := parseJoinCommand(lines)
+	if err != nil {
+		return err
+	}
+
+	if controlPlane {
+		lines, err = exec.OutputLines(initNode.Command("kubeadm", "init", "phase", "upload-certs", "--upload-certs"))
+		if err != nil {
+			return fmt.Errorf("failed to upload certificates: %w", err)
+		}
+		if len(lines) == 0 {
+			return fmt.Errorf("kubeadm did not print a certificate key")
+		}
+		config.CertificateKey = strings.TrimSpace(lines[len(lines)-1])
+	}
+
+	ipv4, ipv6, err := node.IP()
+	if err != nil {
+		return err
+	}
+	config.NodeAddress = ipv4
+	if config.NodeAddress == "" {
+		config.NodeAddress = ipv6
+	}
+	config.APIServerPort = apiServerInternalPort
+	config.ProviderID = ProviderID(kindCluster.Spec.Name, node.String())
+	config.NodeLabels = formatLabels(nodeLabels)
+
+	var buf bytes.Buffer
+	if err := joinConfigTemplate.Execute(&buf, config); err != nil {
+		return err
+	}
+
+	if err := nodeutils.WriteFile(node, joinConfigPath, buf.String()); err != nil {
+		return err
+	}
+
+	output, err := exec.CombinedOutputLines(node.Command(
+		"kubeadm", "join", "--config", joinConfigPath, "--skip-phases=preflight", "--v=6",
+	))
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.Join(output, "\n"))
+	}
+
+	return nil
+}
+
+// parseJoinCommand reads the endpoint, token and CA hash from the output of
+// kubeadm token create --print-join-command.
+func parseJoinCommand(lines []string) (joinConfig, error) {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "kubeadm" || fields[1] != "join" {
+			continue
+		}
+
+		config := joinConfig{APIServerEndpoint: fields[2]}
+		for i := 3; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "--token":
+				config.Token = fields[i+1]
+			case "--discovery-token-ca-cert-hash":
+				config.CACertHash = fields[i+1]
+			}
+		}
+
+		if config.Token == "" || config.CACertHash == "" {
+			break
+		}
+		return config, nil
+	}
+
+	return joinConfig{}, fmt.Errorf("unexpected join command: %v", lines)
+}
+
+// formatLabels formats the labels as the comma separated key=value pairs
+// the kubelet --node-labels flag expects.
+func formatLabels(labels map[string]string) string {
+	pairs := []string{}
+	for key, value := range labels {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func initNodeName(kindCluster *kclusterv1.KindCluster) string {
+	return nodeName(kindCluster, constants.ControlPlaneNodeRoleValue, 0)
+}
+
+// nodeName returns the name of the node with the index among the nodes of
+// the role, following the kind naming scheme of <cluster>-<role>,
+// <cluster>-<role>2 and so on.
+func nodeName(kindCluster *kclusterv1.KindCluster, role string, index int) string {
+	if index == 0 {
+		return fmt.Sprintf("%s-%s", kindCluster.Spec.Name, role)
+	}
+
+	return fmt.Sprintf("%s-%s%d", kindCluster.Spec.Name, role, index+1)
+}
+
+// nextNodeName returns the first free node name for the role.
+func nextNodeName(kindCluster *kclusterv1.KindCluster, role string, clusterNodes []nodes.Node) string {
+	taken := map[string]bool{}
+	for _, node := range clusterNodes {
+		taken[node.String()] = true
+	}
+
+	for i := 0; ; i++ {
+		name := nodeName(kindCluster, role, i)
+		if !taken[name] {
+			return name
+		}
+	}
+}
+
+func findNode(clusterNodes []nodes.Node, name string) (nodes.Node, error) {
+	for _, node := range clusterNodes {
+		if node.String() == name {
+			return node, nil
+		}
+	}
+
+	return nil, fmt.Errorf("node %q not found", name)
+}
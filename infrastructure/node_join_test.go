@@ -0,0 +1,175 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// initNodeInspect is the relevant part of the docker inspect output of a
+// control plane node container kind ran.
+const initNodeInspect = `{
+	"Config": {
+		"Image": "kindest/node:v1.31.0",
+		"Env": ["KUBECONFIG=/etc/kubernetes/admin.conf", "container=docker"],
+		"Labels": {"io.x-k8s.kind.cluster": "foo", "io.x-k8s.kind.role": "control-plane"},
+		"Volumes": {"/var": {}}
+	},
+	"HostConfig": {
+		"NetworkMode": "kind",
+		"Privileged": true,
+		"SecurityOpt": ["seccomp=unconfined", "apparmor=unconfined"],
+		"Tmpfs": {"/run": "", "/tmp": ""},
+		"Binds": ["/lib/modules:/lib/modules:ro"],
+		"CgroupnsMode": "private",
+		"Init": false,
+		"RestartPolicy": {"Name": "on-failure", "MaximumRetryCount": 1},
+		"PortBindings": {"6443/tcp": [{"HostIp": "127.0.0.1", "HostPort": "41234"}]},
+		"Devices": []
+	}
+}`
+
+func TestRunArgs(t *testing.T) {
+	hostConfigArgs := []string{
+		"--restart=on-failure:1",
+		"--init=false",
+		"--cgroupns=private",
+		"--privileged",
+		"--security-opt", "seccomp=unconfined",
+		"--security-opt", "apparmor=unconfined",
+		"--tmpfs", "/run",
+		"--tmpfs", "/tmp",
+		"--volume", "/var",
+		"--volume", "/lib/modules:/lib/modules:ro",
+	}
+
+	tests := []struct {
+		name   string
+		node   string
+		role   string
+		image  string
+		labels map[string]string
+		want   []string
+	}{
+		{
+			name:  "runs a control plane node like the first one",
+			node:  "foo-control-plane2",
+			role:  "control-plane",
+			image: "kindest/node:v1.31.0",
+			want: slices.Concat(
+				[]string{
+					"run", "--detach", "--tty",
+					"--hostname", "foo-control-plane2",
+					"--name", "foo-control-plane2",
+					"--net", "kind",
+					"--label", "io.x-k8s.kind.cluster=foo",
+					"--label", "io.x-k8s.kind.role=control-plane",
+				},
+				hostConfigArgs,
+				[]string{
+					"-e", "KUBECONFIG=/etc/kubernetes/admin.conf",
+					"-e", "container=docker",
+					"kindest/node:v1.31.0",
+				},
+			),
+		},
+		{
+			name:   "runs a worker with its role, labels and image but without the kubeconfig",
+			node:   "foo-worker",
+			role:   "worker",
+			image:  "kindest/node:v1.30.0",
+			labels: map[string]string{"pool": "workers"},
+			want: slices.Concat(
+				[]string{
+					"run", "--detach", "--tty",
+					"--hostname", "foo-worker",
+					"--name", "foo-worker",
+					"--net", "kind",
+					"--label", "io.x-k8s.kind.cluster=foo",
+					"--label", "io.x-k8s.kind.role=worker",
+					"--label", "pool=workers",
+				},
+				hostConfigArgs,
+				[]string{
+					"-e", "container=docker",
+					"kindest/node:v1.30.0",
+				},
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template := nodeContainer{}
+			g.Expect(json.Unmarshal([]byte(initNodeInspect), &template)).To(Succeed())
+
+			g.Expect(runArgs(template, tt.node, tt.role, tt.image, tt.labels)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestParseJoinCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    joinConfig
+		wantErr bool
+	}{
+		{
+			name: "reads the endpoint, token and CA hash",
+			lines: []string{
+				"kubeadm join foo-control-plane:6443 --token abcdef.0123456789abcdef --discovery-token-ca-cert-hash sha256:1234 ",
+			},
+			want: joinConfig{
+				APIServerEndpoint: "foo-control-plane:6443",
+				Token:             "abcdef.0123456789abcdef",
+				CACertHash:        "sha256:1234",
+			},
+		},
+		{
+			name: "skips the lines before the join command",
+			lines: []string{
+				"W1019 10:00:00.000000 1 version.go:104] could not fetch a Kubernetes version from the internet",
+				"kubeadm join 172.18.0.2:6443 --discovery-token-ca-cert-hash sha256:1234 --token abcdef.0123456789abcdef",
+			},
+			want: joinConfig{
+				APIServerEndpoint: "172.18.0.2:6443",
+				Token:             "abcdef.0123456789abcdef",
+				CACertHash:        "sha256:1234",
+			},
+		},
+		{
+			name:    "fails without a token",
+			lines:   []string{"kubeadm join foo-control-plane:6443 --discovery-token-ca-cert-hash sha256:1234"},
+			wantErr: true,
+		},
+		{
+			name:    "fails without a CA hash",
+			lines:   []string{"kubeadm join foo-control-plane:6443 --token abcdef.0123456789abcdef"},
+			wantErr: true,
+		},
+		{
+			name:    "fails without a join command",
+			lines:   []string{"error: timed out waiting for the condition"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			config, err := parseJoinCommand(tt.lines)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(config).To(Equal(tt.want))
+		})
+	}
+}
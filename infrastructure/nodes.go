@@ -0,0 +1,221 @@
+package infrastructure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const containerRunning = "running"
+
+// startOrder is the order in which node containers have to be started so
+// that the API server is reachable through the load balancer before the
+// kubelets try to register.
+var startOrder = map[string]int{
+	constants.ExternalLoadBalancerNodeRoleValue: 0,
+	constants.ControlPlaneNodeRoleValue:         1,
+	constants.WorkerNodeRoleValue:               2,
+}
+
+// RestartNodes starts all node containers of the kind cluster that are not
+// running.
+func (p *KindProvider) RestartNodes(kindCluster *kclusterv1.KindCluster) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := sortByStartOrder(clusterNodes); err != nil {
+		return err
+	}
+
+	for _, node := range clusterNodes {
+		state, err := p.containerState(node.String())
+		if err != nil {
+			return err
+		}
+
+		if state == containerRunning {
+			continue
+		}
+
+		if err := p.docker.command("start", node.String()).Run(); err != nil {
+			return fmt.Errorf("failed to start node %q: %w", node.String(), err)
+		}
+	}
+
+	return nil
+}
+
+// StopNodes stops all running node containers of the kind cluster, keeping
+// the containers and their state so that RestartNodes can start them again.
+// They are stopped in the reverse of the order they are started in.
+func (p *KindProvider) StopNodes(kindCluster *kclusterv1.KindCluster) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := sortByStartOrder(clusterNodes); err != nil {
+		return err
+	}
+
+	for i := len(clusterNodes) - 1; i >= 0; i-- {
+		node := clusterNodes[i]
+		state, err := p.containerState(node.String())
+		if err != nil {
+			return err
+		}
+
+		if state != containerRunning {
+			continue
+		}
+
+		if err := p.docker.command("stop", node.String()).Run(); err != nil {
+			return fmt.Errorf("failed to stop node %q: %w", node.String(), err)
+		}
+	}
+
+	return nil
+}
+
+func sortByStartOrder(clusterNodes []nodes.Node) error {
+	roles := map[string]string{}
+	for _, node := range clusterNodes {
+		role, err := node.Role()
+		if err != nil {
+			return err
+		}
+		roles[node.String()] = role
+	}
+
+	sort.SliceStable(clusterNodes, func(i, j int) bool {
+		return startOrder[roles[clusterNodes[i].String()]] < startOrder[roles[clusterNodes[j].String()]]
+	})
+
+	return nil
+}
+
+// GetNodes returns the node containers of the kind cluster.
+func (p *KindProvider) GetNodes(kindCluster *kclusterv1.KindCluster) ([]kclusterv1.NodeStatus, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeStatuses := []kclusterv1.NodeStatus{}
+	for _, node := range clusterNodes {
+		role, err := node.Role()
+		if err != nil {
+			return nil, err
+		}
+
+		container, err := p.inspectContainer(node.String())
+		if err != nil {
+			return nil, err
+		}
+
+		nodeStatus := kclusterv1.NodeStatus{
+			Name:          node.String(),
+			Role:          role,
+			ContainerID:   container.id,
+			Image:         container.image,
+			State:         container.state,
+			FailureDomain: failureDomainOf(kindCluster, container.networks),
+			CreationTime:  ptr.To(metav1.NewTime(container.created)),
+		}
+
+		// The node addresses can only be read from running containers.
+		if container.state == containerRunning {
+			ipv4, ipv6, err := node.IP()
+			if err != nil {
+				return nil, err
+			}
+			for _, ip := range []string{ipv4, ipv6} {
+				if ip != "" {
+					nodeStatus.InternalIPs = append(nodeStatus.InternalIPs, ip)
+				}
+			}
+		}
+
+		nodeStatuses = append(nodeStatuses, nodeStatus)
+	}
+
+	sort.Slice(nodeStatuses, func(i, j int) bool {
+		return nodeStatuses[i].Name < nodeStatuses[j].Name
+	})
+
+	return nodeStatuses, nil
+}
+
+type containerInfo struct {
+	id       string
+	image    string
+	state    string
+	networks []string
+	created  time.Time
+}
+
+func (p *KindProvider) inspectContainer(containerName string) (containerInfo, error) {
+	lines, err := exec.OutputLines(p.docker.command(
+		"inspect", "--format", "{{.Id}}\t{{.Config.Image}}\t{{.State.Status}}\t{{range $name, $_ := .NetworkSettings.Networks}}{{$name}},{{end}}\t{{.Created}}", containerName,
+	))
+	if err != nil {
+		return containerInfo{}, err
+	}
+
+	if len(lines) != 1 {
+		return containerInfo{}, fmt.Errorf("unexpected docker inspect output for %q: %v", containerName, lines)
+	}
+
+	fields := strings.Split(strings.TrimSpace(lines[0]), "\t")
+	if len(fields) != 5 {
+		return containerInfo{}, fmt.Errorf("unexpected docker inspect output for %q: %v", containerName, lines)
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, fields[4])
+	if err != nil {
+		return containerInfo{}, fmt.Errorf("unexpected creation time of %q: %w", containerName, err)
+	}
+
+	return containerInfo{
+		id:       fields[0],
+		image:    fields[1],
+		state:    fields[2],
+		networks: strings.FieldsFunc(fields[3], func(r rune) bool { return r == ',' }),
+		created:  created,
+	}, nil
+}
+
+func (p *KindProvider) containerState(containerName string) (string, error) {
+	container, err := p.inspectContainer(containerName)
+	if err != nil {
+		return "", err
+	}
+
+	return container.state, nil
+}
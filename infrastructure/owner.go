@@ -0,0 +1,268 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// ownerPath is the file in every node container recording the object owning
+// the kind cluster. Unlike the labels of the containers it can be changed,
+// so that a kind cluster can be handed over, e.g. from a KindClusterPool to
+// the KindCluster claiming it.
+const ownerPath = "/kind/owner.json"
+
+// ownerLabelKey is the label of the docker volume recording the owner of a
+// kind cluster while it is created, as the node containers only exist once
+// kind has created them. The owner is read from the volume until it is
+// recorded in the node containers, so that a kind cluster the manager
+// stopped creating, e.g. because it crashed, is still known to be owned.
+const ownerLabelKey = "infrastructure.cluster.x-k8s.io/kind-owner"
+
+// owner is the content of ownerPath.
+type owner struct {
+	// Manager identifies the management cluster whose controller recorded
+	// the owner.
+	Manager string `json:"manager,omitempty"`
+	// Object references the object owning the kind cluster.
+	Object corev1.ObjectReference `json:"object"`
+}
+
+// SetOwner records the owner of the kind cluster in its node containers.
+func (p *KindProvider) SetOwner(kindCluster *kclusterv1.KindCluster, object corev1.ObjectReference) error {
+	return p.writeOwner(kindCluster, owner{Manager: p.managerID, Object: object})
+}
+
+// ReleaseOwner records in the node containers that the kind cluster no
+// longer has an owner, so that it is left alone once its KindCluster is
+// gone, and removes the owner volume. The owner file is overwritten with an
+// empty owner rather than removed, as docker cp can only write files into a
+// stopped container. The labels of the node containers stay, as docker does
+// not allow changing them.
+func (p *KindProvider) ReleaseOwner(kindCluster *kclusterv1.KindCluster) error {
+	return p.writeOwner(kindCluster, owner{})
+}
+
+// writeOwner records the owner in the node containers of the kind cluster
+// and then removes the owner recorded while it was created.
+func (p *KindProvider) writeOwner(kindCluster *kclusterv1.KindCluster, clusterOwner owner) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(clusterOwner)
+	if err != nil {
+		return err
+	}
+
+	clusterNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range clusterNodes {
+		if err := p.writeNodeFile(node.String(), ownerPath, data); err != nil {
+			return fmt.Errorf("failed to write owner of node %q: %w", node.String(), err)
+		}
+	}
+
+	return p.removeCreationOwner(kindCluster.Spec.Name)
+}
+
+// recordCreationOwner records the owner of the kind cluster about to be
+// created in a docker volume labeled with the name of the kind cluster. An
+// existing volume is kept, as it may be from another manager creating a
+// kind cluster with the same name.
+func (p *KindProvider) recordCreationOwner(name string, object corev1.ObjectReference) error {
+	data, err := json.Marshal(owner{Manager: p.managerID, Object: object})
+	if err != nil {
+		return err
+	}
+
+	return p.docker.command(
+		"volume", "create",
+		"--label", fmt.Sprintf("%s=%s", ClusterLabelKey, name),
+		"--label", fmt.Sprintf("%s=%s", ownerLabelKey, data),
+		ownerVolumeName(name),
+	).Run()
+}
+
+// removeCreationOwner removes the owner recorded while the kind cluster was
+// created, if there is one.
+func (p *KindProvider) removeCreationOwner(name string) error {
+	err := p.docker.command("volume", "rm", "--force", ownerVolumeName(name)).Run()
+	if err != nil {
+		return fmt.Errorf("failed to remove owner volume of %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// listCreationOwners returns the owners recorded while the kind clusters
+// were created by their names.
+func (p *KindProvider) listCreationOwners() (map[string]*owner, error) {
+	lines, err := exec.OutputLines(p.docker.command(
+		"volume", "ls",
+		"--filter", "label="+ownerLabelKey,
+		"--format", fmt.Sprintf(`{{.Label "%s"}} {{.Label "%s"}}`, ClusterLabelKey, ownerLabelKey),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list owner volumes: %w", err)
+	}
+
+	return parseCreationOwners(lines)
+}
+
+// parseCreationOwners reads the owners from the lines of docker volume ls,
+// each with the name of the kind cluster and the owner label of a volume.
+func parseCreationOwners(lines []string) (map[string]*owner, error) {
+	owners := map[string]*owner{}
+	for _, line := range lines {
+		name, data, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || name == "" {
+			continue
+		}
+
+		clusterOwner := &owner{}
+		if err := json.Unmarshal([]byte(data), clusterOwner); err != nil {
+			return nil, fmt.Errorf("failed to parse owner volume of %q: %w", name, err)
+		}
+		owners[name] = clusterOwner
+	}
+
+	return owners, nil
+}
+
+func ownerVolumeName(name string) string {
+	return "kind-owner-" + name
+}
+
+// ListOwnedClusters returns the kind clusters on the KindHost with the given
+// name, or on the docker daemon of the manager if it is empty, that have an
+// owner recorded by this manager, including the ones still being created or
+// whose create was interrupted. Kind clusters without an owner, e.g. created
+// with kind directly or retained, are left out.
+func (p *KindProvider) ListOwnedClusters(hostName string) ([]kclusterv1.OwnedKindCluster, error) {
+	p, err := p.bind(hostName)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := p.clusters.List()
+	if err != nil {
+		return nil, err
+	}
+
+	creationOwners, err := p.listCreationOwners()
+	if err != nil {
+		return nil, err
+	}
+	for name := range creationOwners {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	owned := []kclusterv1.OwnedKindCluster{}
+	for _, name := range names {
+		clusterOwner, err := p.readOwner(name)
+		if err != nil {
+			return nil, err
+		}
+		if clusterOwner == nil {
+			clusterOwner = creationOwners[name]
+		}
+
+		if clusterOwner == nil || clusterOwner.Manager != p.managerID || clusterOwner.Object.UID == "" {
+			continue
+		}
+
+		owned = append(owned, kclusterv1.OwnedKindCluster{
+			Name:     name,
+			HostName: hostName,
+			Owner:    clusterOwner.Object,
+		})
+	}
+
+	return owned, nil
+}
+
+// readOwner returns the owner recorded in the first node container of the
+// kind cluster that has one, or nil if none has. Nodes added after the
+// owner was recorded, e.g. by a KindMachinePool, do not have it.
+func (p *KindProvider) readOwner(name string) (*owner, error) {
+	clusterNodes, err := p.clusters.ListNodes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range clusterNodes {
+		data, err := p.readNodeFile(node.String(), ownerPath)
+		if err != nil {
+			continue
+		}
+
+		clusterOwner := &owner{}
+		if err := json.Unmarshal(data, clusterOwner); err != nil {
+			return nil, fmt.Errorf("failed to parse owner of node %q: %w", node.String(), err)
+		}
+		return clusterOwner, nil
+	}
+
+	return nil, nil
+}
+
+// writeNodeFile writes the file into the node container with docker cp,
+// which unlike exec also works while the container is stopped, e.g. when
+// the kind cluster is suspended.
+func (p *KindProvider) writeNodeFile(node, filePath string, data []byte) error {
+	archive := &bytes.Buffer{}
+	writer := tar.NewWriter(archive)
+	err := writer.WriteHeader(&tar.Header{
+		Name:    path.Base(filePath),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return p.docker.command("cp", "-", node+":"+path.Dir(filePath)).SetStdin(archive).Run()
+}
+
+// readNodeFile reads the file from the node container with docker cp, which
+// also works while the container is stopped.
+func (p *KindProvider) readNodeFile(node, filePath string) ([]byte, error) {
+	archive, err := exec.Output(p.docker.command("cp", node+":"+filePath, "-"))
+	if err != nil {
+		return nil, err
+	}
+
+	reader := tar.NewReader(bytes.NewReader(archive))
+	if _, err := reader.Next(); err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(reader)
+}
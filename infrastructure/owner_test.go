@@ -0,0 +1,68 @@
+package infrastructure
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseCreationOwners(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    map[string]*owner
+		wantErr bool
+	}{
+		{
+			name: "reads the owner of every kind cluster",
+			lines: []string{
+				`foo {"manager":"manager-uid","object":{"kind":"KindCluster","namespace":"default","name":"foo","uid":"foo-uid"}}`,
+				`bar {"manager":"other-uid","object":{"kind":"KindClusterPool","namespace":"default","name":"pool","uid":"pool-uid"}}`,
+			},
+			want: map[string]*owner{
+				"foo": {
+					Manager: "manager-uid",
+					Object:  corev1.ObjectReference{Kind: "KindCluster", Namespace: "default", Name: "foo", UID: "foo-uid"},
+				},
+				"bar": {
+					Manager: "other-uid",
+					Object:  corev1.ObjectReference{Kind: "KindClusterPool", Namespace: "default", Name: "pool", UID: "pool-uid"},
+				},
+			},
+		},
+		{
+			name:  "reads a released owner",
+			lines: []string{`foo {"object":{}}`},
+			want:  map[string]*owner{"foo": {}},
+		},
+		{
+			name: "skips volumes without a kind cluster name",
+			lines: []string{
+				` {"manager":"manager-uid","object":{"uid":"foo-uid"}}`,
+				`foo`,
+				``,
+			},
+			want: map[string]*owner{},
+		},
+		{
+			name:    "fails on an owner that can not be parsed",
+			lines:   []string{`foo {"manager":`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			owners, err := parseCreationOwners(tt.lines)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(owners).To(Equal(tt.want))
+		})
+	}
+}
@@ -1,74 +1,223 @@
 package infrastructure
 
 import (
+	"fmt"
 	"net"
 	"net/url"
-	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 )
 
 const defaultWaitTime = 10 * time.Minute
 
+// kindClusterExistsMessage is part of the error kind returns when the nodes
+// of a cluster with the name already exist, e.g. if another manager sharing
+// the docker daemon created it in the meantime.
+const kindClusterExistsMessage = "already exist for a cluster with the name"
+
+// kindClusters are the operations of kind on the kind clusters of a docker
+// daemon.
+type kindClusters interface {
+	ClusterLister
+	ListNodes(name string) ([]nodes.Node, error)
+	// Create creates the kind cluster, exporting its kubeconfig to the file
+	// and retaining its nodes on failure.
+	Create(name string, config *v1alpha4.Cluster, kubeconfigPath string) error
+	// Delete deletes the kind cluster, removing it from the kubeconfig file.
+	Delete(name, kubeconfigPath string) error
+	// KubeConfig returns the kubeconfig of the kind cluster, pointing at the
+	// API server port published on the docker host.
+	KubeConfig(name string) (string, error)
+	CollectLogs(name, dir string) error
+}
+
 type KindProvider struct {
-	kubeconfigPath  string
-	clusterProvider *cluster.Provider
+	kubeconfigDir string
+	managerID     string
+	clusters      kindClusters
+	clusterCache  *ClusterCache
+	hosts         *Hosts
+
+	// bound is set on the providers returned by onHost, which operate on
+	// the host of a single kind cluster.
+	bound bool
+	// docker runs the docker CLI against the docker daemon of the host.
+	docker dockerCLI
+	// apiServerAddress is the address the API servers of the kind clusters
+	// are published on, if the host is remote.
+	apiServerAddress string
 }
 
-func NewKindProvider(kubeconfigPath string, clusterProvider *cluster.Provider) *KindProvider {
+// NewKindProvider creates a KindProvider using the cluster provider and
+// cache for the local docker daemon. If hosts is nil kind clusters can not
+// reference a KindHost. The managerID identifies the management cluster in
+// the owners recorded in the kind clusters, so that managers sharing a
+// docker daemon tell their kind clusters apart. The kubeconfig of every
+// kind cluster is written to its own file in kubeconfigDir, or nowhere if it
+// is empty.
+func NewKindProvider(kubeconfigDir, managerID string, clusterProvider *cluster.Provider, clusterCache *ClusterCache, hosts *Hosts) *KindProvider {
 	return &KindProvider{
-		kubeconfigPath:  kubeconfigPath,
-		clusterProvider: clusterProvider,
+		kubeconfigDir: kubeconfigDir,
+		managerID:     managerID,
+		clusters:      localClusters{provider: clusterProvider},
+		clusterCache:  clusterCache,
+		hosts:         hosts,
 	}
 }
 
-func (p *KindProvider) Create(kindCluster *kclusterv1.KindCluster) error {
-	return p.clusterProvider.Create(
-		kindCluster.Spec.Name,
-		cluster.CreateWithV1Alpha4Config(toConfig(kindCluster)),
-		cluster.CreateWithKubeconfigPath(p.kubeconfigPath),
-		cluster.CreateWithWaitForReady(defaultWaitTime))
+// onHost returns the provider for the host of the kind cluster. A bound
+// provider is returned as it is, so that operations can call each other.
+func (p *KindProvider) onHost(kindCluster *kclusterv1.KindCluster) (bound *KindProvider, err error) {
+	if p.bound {
+		return p, nil
+	}
+
+	return p.bind(kindCluster.GetHostName())
 }
 
-func (p *KindProvider) Exists(kindCluster *kclusterv1.KindCluster) (bool, error) {
-	clusters, err := p.clusterProvider.List()
+// bind returns a provider for the KindHost with the given name, or the
+// provider itself for the local docker daemon if the name is empty.
+func (p *KindProvider) bind(hostName string) (*KindProvider, error) {
+	if hostName == "" {
+		return p, nil
+	}
+
+	if p.hosts == nil {
+		return nil, fmt.Errorf("host %q can not be used, no hosts are configured", hostName)
+	}
+
+	host, err := p.hosts.get(hostName)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("failed to get host %q: %w", hostName, err)
 	}
 
-	for _, cluster := range clusters {
-		if cluster == kindCluster.Spec.Name {
-			return true, nil
-		}
+	return &KindProvider{
+		kubeconfigDir:    p.kubeconfigDir,
+		managerID:        p.managerID,
+		clusters:         host.clusters,
+		clusterCache:     host.clusterCache,
+		hosts:            p.hosts,
+		bound:            true,
+		docker:           host.docker,
+		apiServerAddress: host.apiServerAddress,
+	}, nil
+}
+
+// Create creates the kind cluster and attaches its nodes to the networks of
+// their failure domains. The object is recorded as the owner of the kind
+// cluster until SetOwner records it in the nodes.
+func (p *KindProvider) Create(kindCluster *kclusterv1.KindCluster, object corev1.ObjectReference) error {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return err
+	}
+
+	defer p.clusterCache.Invalidate()
+
+	config, placement := toConfig(kindCluster)
+	if p.apiServerAddress != "" {
+		// The port is picked by the remote docker daemon, as kind would
+		// look for a free port on the local machine otherwise.
+		config.Networking.APIServerAddress = p.apiServerAddress
+		config.Networking.APIServerPort = -1
+	}
+
+	kubeconfigPath, cleanup, err := p.kubeconfigFile(kindCluster.Spec.Name)
+	if err != nil {
+		return err
 	}
+	defer cleanup()
 
-	return false, nil
+	existingNodes, err := p.clusters.ListNodes(kindCluster.Spec.Name)
+	if err != nil {
+		return err
+	}
+	if len(existingNodes) > 0 {
+		return fmt.Errorf("%w: %q", kclusterv1.ErrKindClusterExists, kindCluster.Spec.Name)
+	}
+
+	// The owner is recorded before the nodes exist, so that the kind cluster
+	// is known to be owned even if the manager stops while creating it.
+	err = p.recordCreationOwner(kindCluster.Spec.Name, object)
+	if err != nil {
+		return fmt.Errorf("failed to record owner: %w", err)
+	}
+
+	// The nodes are retained on failure so that diagnostics can be collected
+	// from them. The caller is responsible for deleting the cluster
+	// afterwards, unless kind found that it already exists.
+	err = p.clusters.Create(kindCluster.Spec.Name, config, kubeconfigPath)
+	if isKindClusterExists(err) {
+		return fmt.Errorf("%w: %v", kclusterv1.ErrKindClusterExists, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.placeNodes(kindCluster, placement)
 }
 
-func (p *KindProvider) Delete(kindCluster *kclusterv1.KindCluster) error {
-	return p.clusterProvider.Delete(kindCluster.Spec.Name, p.kubeconfigPath)
+// isKindClusterExists returns true if the error of kind is about the nodes
+// of the kind cluster already existing.
+func isKindClusterExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), kindClusterExistsMessage)
 }
 
-func (p *KindProvider) GetControlPlaneEndpoint(kindCluster *kclusterv1.KindCluster) (host string, port int, err error) {
-	kubeconfigFile, err := os.CreateTemp("", "")
+func (p *KindProvider) Exists(kindCluster *kclusterv1.KindCluster) (bool, error) {
+	p, err := p.onHost(kindCluster)
 	if err != nil {
-		return "", 0, err
+		return false, err
 	}
-	defer kubeconfigFile.Close()
-	defer os.RemoveAll(kubeconfigFile.Name())
 
-	err = p.clusterProvider.ExportKubeConfig(kindCluster.Spec.Name, kubeconfigFile.Name(), false)
+	return p.clusterCache.Contains(kindCluster.Spec.Name)
+}
+
+func (p *KindProvider) Delete(kindCluster *kclusterv1.KindCluster) error {
+	p, err := p.onHost(kindCluster)
 	if err != nil {
-		return "", 0, err
+		return err
 	}
 
-	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigFile.Name())
+	defer p.clusterCache.Invalidate()
+
+	// Kind removes the kind cluster from the kubeconfig it is given, so it
+	// is given a scratch file to keep it off the KUBECONFIG of the manager.
+	kubeconfigPath, cleanup, err := scratchKubeconfigFile()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if err := p.clusters.Delete(kindCluster.Spec.Name, kubeconfigPath); err != nil {
+		return err
+	}
+
+	if err := p.removeKubeconfigFile(kindCluster.Spec.Name); err != nil {
+		return err
+	}
+
+	if err := p.removeCreationOwner(kindCluster.Spec.Name); err != nil {
+		return err
+	}
+
+	return p.deleteNetworks(kindCluster)
+}
+
+// GetControlPlaneEndpoint returns the host and port of the API server in the
+// kubeconfig of a kind cluster.
+func (p *KindProvider) GetControlPlaneEndpoint(kubeconfig []byte) (host string, port int32, err error) {
+	kubeConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
 	if err != nil {
 		return "", 0, err
 	}
@@ -83,23 +232,115 @@ func (p *KindProvider) GetControlPlaneEndpoint(kindCluster *kclusterv1.KindClust
 		return "", 0, err
 	}
 
-	port, err = strconv.Atoi(portStr)
+	parsedPort, err := strconv.ParseInt(portStr, 10, 32)
 	if err != nil {
 		return "", 0, err
 	}
 
-	return host, port, nil
+	return host, int32(parsedPort), nil
 }
 
-func toConfig(kindCluster *kclusterv1.KindCluster) *v1alpha4.Cluster {
+// GetKubeconfig returns the kubeconfig of the kind cluster, pointing at the
+// API server port published on the docker host.
+func (p *KindProvider) GetKubeconfig(kindCluster *kclusterv1.KindCluster) ([]byte, error) {
+	p, err := p.onHost(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, err := p.clusters.KubeConfig(kindCluster.Spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(kubeconfig), nil
+}
+
+func (p *KindProvider) restConfig(kindCluster *kclusterv1.KindCluster) (*rest.Config, error) {
+	kubeconfig, err := p.GetKubeconfig(kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+}
+
+// toConfig returns the kind configuration of the kind cluster and the
+// failure domain of each of its nodes, keyed by node name.
+func toConfig(kindCluster *kclusterv1.KindCluster) (*v1alpha4.Cluster, map[string]string) {
+	controlPlaneNodes := int(kindCluster.Spec.ControlPlaneNodes)
+	workerNodes := int(kindCluster.Spec.WorkerNodes)
+	// kind creates a single control plane node when no nodes are given,
+	// which needs to be listed to set its image or failure domain.
+	if controlPlaneNodes == 0 && workerNodes == 0 &&
+		(kindCluster.Spec.KubernetesVersion != "" || len(kindCluster.Spec.FailureDomains) > 0) {
+		controlPlaneNodes = 1
+	}
+	placement := initialPlacement(kindCluster, controlPlaneNodes, workerNodes)
+
 	nodes := []v1alpha4.Node{}
-	for i := 0; i < kindCluster.Spec.ControlPlaneNodes; i++ {
-		nodes = append(nodes, v1alpha4.Node{Role: v1alpha4.ControlPlaneRole})
+	for i := 0; i < controlPlaneNodes; i++ {
+		nodes = append(nodes, v1alpha4.Node{
+			Role:   v1alpha4.ControlPlaneRole,
+			Labels: zoneLabels(placement[nodeName(kindCluster, constants.ControlPlaneNodeRoleValue, i)]),
+		})
 	}
-	for i := 0; i < kindCluster.Spec.WorkerNodes; i++ {
-		nodes = append(nodes, v1alpha4.Node{Role: v1alpha4.WorkerRole})
+	for i := 0; i < workerNodes; i++ {
+		nodes = append(nodes, v1alpha4.Node{
+			Role:   v1alpha4.WorkerRole,
+			Labels: zoneLabels(placement[nodeName(kindCluster, constants.WorkerNodeRoleValue, i)]),
+		})
+	}
+	if kindCluster.Spec.KubernetesVersion != "" {
+		for i := range nodes {
+			nodes[i].Image = kclusterv1.NodeImage(kindCluster.Spec.KubernetesVersion)
+		}
 	}
 	return &v1alpha4.Cluster{
 		Nodes: nodes,
-	}
+	}, placement
+}
+
+// localClusters runs kind against the local docker daemon.
+type localClusters struct {
+	provider *cluster.Provider
+}
+
+var _ kindClusters = localClusters{}
+
+func (c localClusters) List() ([]string, error) {
+	return c.provider.List()
+}
+
+func (c localClusters) ListNodes(name string) ([]nodes.Node, error) {
+	return c.provider.ListNodes(name)
+}
+
+func (c localClusters) Create(name string, config *v1alpha4.Cluster, kubeconfigPath string) error {
+	return c.create(name, cluster.CreateWithV1Alpha4Config(config), kubeconfigPath)
+}
+
+func (c localClusters) createWithRawConfig(name string, config []byte, kubeconfigPath string) error {
+	return c.create(name, cluster.CreateWithRawConfig(config), kubeconfigPath)
+}
+
+func (c localClusters) create(name string, config cluster.CreateOption, kubeconfigPath string) error {
+	return c.provider.Create(
+		name,
+		config,
+		cluster.CreateWithKubeconfigPath(kubeconfigPath),
+		cluster.CreateWithWaitForReady(defaultWaitTime),
+		cluster.CreateWithRetain(true))
+}
+
+func (c localClusters) Delete(name, kubeconfigPath string) error {
+	return c.provider.Delete(name, kubeconfigPath)
+}
+
+func (c localClusters) KubeConfig(name string) (string, error) {
+	return c.provider.KubeConfig(name, false)
+}
+
+func (c localClusters) CollectLogs(name, dir string) error {
+	return c.provider.CollectLogs(name, dir)
 }
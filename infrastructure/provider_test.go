@@ -0,0 +1,43 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIsKindClusterExists(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "matches the error of kind",
+			err:  errors.New(`node(s) already exist for a cluster with the name "foo"`),
+			want: true,
+		},
+		{
+			name: "matches the wrapped error of kind",
+			err:  fmt.Errorf("failed to create cluster: %w", errors.New(`node(s) already exist for a cluster with the name "foo"`)),
+			want: true,
+		},
+		{
+			name: "does not match other errors",
+			err:  errors.New(`failed to create cluster: command "docker run" failed with error: exit status 125`),
+		},
+		{
+			name: "does not match no error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(isKindClusterExists(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
@@ -3,8 +3,11 @@ package k8s
 import (
 	"context"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -27,3 +30,59 @@ func (c *Clusters) Get(ctx context.Context, kindCluster *kclusterv1.KindCluster)
 
 	return cluster, nil
 }
+
+// Delete deletes the Cluster and with it all of its objects.
+func (c *Clusters) Delete(ctx context.Context, cluster *clusterv1.Cluster) error {
+	return c.runtimeClient.Delete(ctx, cluster)
+}
+
+// GetForControlPlane returns the Cluster owning the KindControlPlane, or nil
+// if it is not owned by a Cluster yet.
+func (c *Clusters) GetForControlPlane(ctx context.Context, controlPlane *kclusterv1.KindControlPlane) (*clusterv1.Cluster, error) {
+	return util.GetOwnerCluster(ctx, c.runtimeClient, controlPlane.ObjectMeta)
+}
+
+// GetMachinePool returns the MachinePool owning the KindMachinePool, or nil
+// if it is not owned by a MachinePool yet.
+func (c *Clusters) GetMachinePool(ctx context.Context, pool *kclusterv1.KindMachinePool) (*expv1.MachinePool, error) {
+	return exputil.GetOwnerMachinePool(ctx, c.runtimeClient, pool.ObjectMeta)
+}
+
+// GetForMachinePool returns the Cluster the MachinePool belongs to.
+func (c *Clusters) GetForMachinePool(ctx context.Context, machinePool *expv1.MachinePool) (*clusterv1.Cluster, error) {
+	return util.GetClusterByName(ctx, c.runtimeClient, machinePool.Namespace, machinePool.Spec.ClusterName)
+}
+
+// GetKindCluster returns the KindCluster the Cluster references as its
+// infrastructure, or nil if it does not reference one.
+func (c *Clusters) GetKindCluster(ctx context.Context, cluster *clusterv1.Cluster) (*kclusterv1.KindCluster, error) {
+	ref := cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "KindCluster" {
+		return nil, nil
+	}
+
+	kindCluster := &kclusterv1.KindCluster{}
+	err := c.runtimeClient.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: ref.Name}, kindCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return kindCluster, nil
+}
+
+// GetControlPlane returns the KindControlPlane the Cluster references as its
+// control plane, or nil if its control plane is of another kind.
+func (c *Clusters) GetControlPlane(ctx context.Context, cluster *clusterv1.Cluster) (*kclusterv1.KindControlPlane, error) {
+	ref := cluster.Spec.ControlPlaneRef
+	if ref == nil || ref.Kind != "KindControlPlane" {
+		return nil, nil
+	}
+
+	controlPlane := &kclusterv1.KindControlPlane{}
+	err := c.runtimeClient.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: ref.Name}, controlPlane)
+	if err != nil {
+		return nil, err
+	}
+
+	return controlPlane, nil
+}
@@ -7,11 +7,14 @@ import (
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/k8s"
 )
 
@@ -110,4 +113,184 @@ var _ = Describe("Clusters", func() {
 			})
 		})
 	})
+
+	Describe("Delete", func() {
+		var deleted *clusterv1.Cluster
+
+		BeforeEach(func() {
+			deleted = &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deleted",
+					Namespace: namespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deleted)).To(Succeed())
+		})
+
+		It("deletes the cluster", func() {
+			Expect(clusters.Delete(ctx, deleted)).To(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: "deleted", Namespace: namespace}, &clusterv1.Cluster{})
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+	})
+
+	Describe("GetKindCluster", func() {
+		It("gets the kind cluster referenced as infrastructure", func() {
+			actualKindCluster, err := clusters.GetKindCluster(ctx, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualKindCluster.Name).To(Equal("potato"))
+			Expect(actualKindCluster.Spec.Name).To(Equal("the-kind-cluster-name"))
+		})
+
+		When("the infrastructure is not a KindCluster", func() {
+			BeforeEach(func() {
+				cluster.Spec.InfrastructureRef.Kind = "DockerCluster"
+			})
+
+			It("returns nil", func() {
+				actualKindCluster, err := clusters.GetKindCluster(ctx, cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualKindCluster).To(BeNil())
+			})
+		})
+	})
+
+	Describe("GetControlPlane", func() {
+		var controlPlane *kclusterv1.KindControlPlane
+
+		BeforeEach(func() {
+			controlPlane = &kclusterv1.KindControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "potato-control-plane",
+					Namespace: namespace,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: clusterv1.GroupVersion.String(),
+							Kind:       "Cluster",
+							Name:       cluster.Name,
+							UID:        cluster.UID,
+						},
+					},
+				},
+				Spec: kclusterv1.KindControlPlaneSpec{
+					Version: "v1.31.0",
+				},
+			}
+			Expect(k8sClient.Create(ctx, controlPlane)).To(Succeed())
+
+			cluster.Spec.ControlPlaneRef = &corev1.ObjectReference{
+				APIVersion: kclusterv1.GroupVersion.String(),
+				Kind:       "KindControlPlane",
+				Name:       controlPlane.Name,
+				Namespace:  namespace,
+			}
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, controlPlane)).To(Succeed())
+		})
+
+		It("gets the control plane referenced by the cluster", func() {
+			actualControlPlane, err := clusters.GetControlPlane(ctx, cluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualControlPlane.Name).To(Equal("potato-control-plane"))
+			Expect(actualControlPlane.Spec.Version).To(Equal("v1.31.0"))
+		})
+
+		It("gets the cluster owning the control plane", func() {
+			actualCluster, err := clusters.GetForControlPlane(ctx, controlPlane)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualCluster.Name).To(Equal("carrot"))
+		})
+
+		When("the control plane is not a KindControlPlane", func() {
+			BeforeEach(func() {
+				cluster.Spec.ControlPlaneRef.Kind = "KubeadmControlPlane"
+			})
+
+			It("returns nil", func() {
+				actualControlPlane, err := clusters.GetControlPlane(ctx, cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualControlPlane).To(BeNil())
+			})
+		})
+	})
+
+	Describe("GetMachinePool", func() {
+		var (
+			machinePool *expv1.MachinePool
+			pool        *kclusterv1.KindMachinePool
+		)
+
+		BeforeEach(func() {
+			machinePool = &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "potato-workers",
+					Namespace: namespace,
+				},
+				Spec: expv1.MachinePoolSpec{
+					ClusterName: cluster.Name,
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							ClusterName: cluster.Name,
+							Bootstrap: clusterv1.Bootstrap{
+								DataSecretName: ptr.To(""),
+							},
+							InfrastructureRef: corev1.ObjectReference{
+								APIVersion: kclusterv1.GroupVersion.String(),
+								Kind:       "KindMachinePool",
+								Name:       "potato-workers",
+								Namespace:  namespace,
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, machinePool)).To(Succeed())
+
+			pool = &kclusterv1.KindMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "potato-workers",
+					Namespace: namespace,
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: expv1.GroupVersion.String(),
+							Kind:       "MachinePool",
+							Name:       machinePool.Name,
+							UID:        machinePool.UID,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pool)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, pool)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, machinePool)).To(Succeed())
+		})
+
+		It("gets the machine pool owning the kind machine pool", func() {
+			actualMachinePool, err := clusters.GetMachinePool(ctx, pool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualMachinePool.Name).To(Equal("potato-workers"))
+		})
+
+		It("gets the cluster of the machine pool", func() {
+			actualCluster, err := clusters.GetForMachinePool(ctx, machinePool)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualCluster.Name).To(Equal("carrot"))
+		})
+
+		When("the kind machine pool is not owned by a machine pool", func() {
+			It("returns nil", func() {
+				actualMachinePool, err := clusters.GetMachinePool(ctx, &kclusterv1.KindMachinePool{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualMachinePool).To(BeNil())
+			})
+		})
+	})
 })
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const DiagnosticsKey = "diagnostics.tar.gz"
+
+// Diagnostics stores diagnostic archives in a Secret or ConfigMap owned by
+// the KindCluster, so they are garbage collected with it. The existing
+// Secret or ConfigMap is read with the reader, e.g. the API reader of the
+// manager, so that storing diagnostics does not cache every Secret or
+// ConfigMap of the management cluster.
+type Diagnostics struct {
+	runtimeClient client.Client
+	reader        client.Reader
+	storage       kclusterv1.DiagnosticsStorage
+}
+
+func NewDiagnostics(runtimeClient client.Client, reader client.Reader, storage kclusterv1.DiagnosticsStorage) *Diagnostics {
+	return &Diagnostics{
+		runtimeClient: runtimeClient,
+		reader:        reader,
+		storage:       storage,
+	}
+}
+
+func (d *Diagnostics) Store(ctx context.Context, kindCluster *kclusterv1.KindCluster, archive []byte) (*kclusterv1.DiagnosticsReference, error) {
+	var obj client.Object
+	switch d.storage {
+	case kclusterv1.DiagnosticsStorageSecret:
+		obj = &corev1.Secret{}
+	case kclusterv1.DiagnosticsStorageConfigMap:
+		obj = &corev1.ConfigMap{}
+	default:
+		return nil, fmt.Errorf("unsupported diagnostics storage %q", d.storage)
+	}
+
+	obj.SetName(DiagnosticsName(kindCluster))
+	obj.SetNamespace(kindCluster.Namespace)
+
+	err := d.reader.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	exists := err == nil
+
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		o.Data = map[string][]byte{DiagnosticsKey: archive}
+	case *corev1.ConfigMap:
+		o.BinaryData = map[string][]byte{DiagnosticsKey: archive}
+	}
+	err = controllerutil.SetOwnerReference(kindCluster, obj, d.runtimeClient.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		err = d.runtimeClient.Update(ctx, obj)
+	} else {
+		err = d.runtimeClient.Create(ctx, obj)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &kclusterv1.DiagnosticsReference{
+		Storage:     d.storage,
+		Name:        obj.GetName(),
+		Key:         DiagnosticsKey,
+		Size:        len(archive),
+		CollectedAt: metav1.Now(),
+	}, nil
+}
+
+// DiagnosticsName returns the name of the Secret or ConfigMap holding the
+// diagnostics of the KindCluster.
+func DiagnosticsName(kindCluster *kclusterv1.KindCluster) string {
+	return kindCluster.Name + "-diagnostics"
+}
@@ -0,0 +1,110 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("Diagnostics", func() {
+	var (
+		diagnostics *k8s.Diagnostics
+		storage     kclusterv1.DiagnosticsStorage
+		kindCluster *kclusterv1.KindCluster
+		reference   *kclusterv1.DiagnosticsReference
+		storeErr    error
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		storage = kclusterv1.DiagnosticsStorageSecret
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "potato",
+				Namespace: namespace,
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: "the-kind-cluster-name",
+			},
+		}
+		Expect(k8sClient.Create(ctx, kindCluster)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		diagnostics = k8s.NewDiagnostics(k8sClient, k8sClient, storage)
+		reference, storeErr = diagnostics.Store(ctx, kindCluster, []byte("logs"))
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, kindCluster)).To(Succeed())
+	})
+
+	It("stores the archive in a secret owned by the KindCluster", func() {
+		Expect(storeErr).NotTo(HaveOccurred())
+
+		secret := &corev1.Secret{}
+		namespacedName := types.NamespacedName{Name: "potato-diagnostics", Namespace: namespace}
+		Expect(k8sClient.Get(ctx, namespacedName, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue(k8s.DiagnosticsKey, []byte("logs")))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].UID).To(Equal(kindCluster.UID))
+	})
+
+	It("returns a reference to the secret", func() {
+		Expect(reference.Storage).To(Equal(kclusterv1.DiagnosticsStorageSecret))
+		Expect(reference.Name).To(Equal("potato-diagnostics"))
+		Expect(reference.Key).To(Equal(k8s.DiagnosticsKey))
+		Expect(reference.Size).To(Equal(4))
+	})
+
+	When("diagnostics were stored before", func() {
+		JustBeforeEach(func() {
+			Expect(storeErr).NotTo(HaveOccurred())
+			reference, storeErr = diagnostics.Store(ctx, kindCluster, []byte("newer logs"))
+		})
+
+		It("replaces the archive", func() {
+			Expect(storeErr).NotTo(HaveOccurred())
+
+			secret := &corev1.Secret{}
+			namespacedName := types.NamespacedName{Name: "potato-diagnostics", Namespace: namespace}
+			Expect(k8sClient.Get(ctx, namespacedName, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue(k8s.DiagnosticsKey, []byte("newer logs")))
+			Expect(secret.OwnerReferences).To(HaveLen(1))
+			Expect(reference.Size).To(Equal(10))
+		})
+	})
+
+	When("the diagnostics are stored in a ConfigMap", func() {
+		BeforeEach(func() {
+			storage = kclusterv1.DiagnosticsStorageConfigMap
+		})
+
+		It("stores the archive as binary data", func() {
+			Expect(storeErr).NotTo(HaveOccurred())
+
+			configMap := &corev1.ConfigMap{}
+			namespacedName := types.NamespacedName{Name: "potato-diagnostics", Namespace: namespace}
+			Expect(k8sClient.Get(ctx, namespacedName, configMap)).To(Succeed())
+			Expect(configMap.BinaryData).To(HaveKeyWithValue(k8s.DiagnosticsKey, []byte("logs")))
+		})
+	})
+
+	When("the storage is not supported", func() {
+		BeforeEach(func() {
+			storage = kclusterv1.DiagnosticsStorageHostPath
+		})
+
+		It("returns an error", func() {
+			Expect(storeErr).To(MatchError(ContainSubstring("unsupported diagnostics storage")))
+		})
+	})
+})
@@ -28,12 +28,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -47,6 +49,8 @@ func TestK8s(t *testing.T) {
 
 var (
 	k8sClient    client.Client
+	cachedClient client.Client
+	cancelCache  context.CancelFunc
 	testEnv      *envtest.Environment
 	namespace    string
 	namespaceObj *corev1.Namespace
@@ -74,15 +78,40 @@ var _ = BeforeSuite(func() {
 	err = clusterv1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = expv1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
+
 	//+kubebuilder:scaffold:scheme
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
+
+	// Listing by kind cluster name relies on a field index, which is only
+	// available when reading from the cache.
+	informerCache, err := cache.New(cfg, cache.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(kclusterv1.IndexKindClusterName(context.Background(), informerCache)).To(Succeed())
+	Expect(kclusterv1.IndexKindClusterHost(context.Background(), informerCache)).To(Succeed())
+
+	var cacheCtx context.Context
+	cacheCtx, cancelCache = context.WithCancel(context.Background())
+	go func() {
+		defer GinkgoRecover()
+		Expect(informerCache.Start(cacheCtx)).To(Succeed())
+	}()
+	Expect(informerCache.WaitForCacheSync(cacheCtx)).To(BeTrue())
+
+	cachedClient, err = client.New(cfg, client.Options{
+		Scheme: scheme.Scheme,
+		Cache:  &client.CacheOptions{Reader: informerCache},
+	})
+	Expect(err).NotTo(HaveOccurred())
 })
 
 var _ = AfterSuite(func() {
 	By("tearing down the test environment")
+	cancelCache()
 	err := testEnv.Stop()
 	Expect(err).NotTo(HaveOccurred())
 })
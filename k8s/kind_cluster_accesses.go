@@ -0,0 +1,104 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const ClusterAccessFinalizer = "kindclusteraccess.infrastructure.cluster.x-k8s.io"
+
+type KindClusterAccesses struct {
+	runtimeClient client.Client
+}
+
+func NewKindClusterAccesses(runtimeClient client.Client) *KindClusterAccesses {
+	return &KindClusterAccesses{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (c *KindClusterAccesses) Get(ctx context.Context, namespacedName types.NamespacedName) (*kclusterv1.KindClusterAccess, error) {
+	access := &kclusterv1.KindClusterAccess{}
+	err := c.runtimeClient.Get(ctx, namespacedName, access)
+	if err != nil {
+		return nil, err
+	}
+
+	return access, nil
+}
+
+// ListForKindCluster returns the KindClusterAccesses referencing the
+// KindCluster.
+func (c *KindClusterAccesses) ListForKindCluster(ctx context.Context, kindCluster *kclusterv1.KindCluster) ([]kclusterv1.KindClusterAccess, error) {
+	list := &kclusterv1.KindClusterAccessList{}
+	err := c.runtimeClient.List(ctx, list, client.InNamespace(kindCluster.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := []kclusterv1.KindClusterAccess{}
+	for _, access := range list.Items {
+		if access.Spec.KindClusterRef.Name == kindCluster.Name {
+			accesses = append(accesses, access)
+		}
+	}
+
+	return accesses, nil
+}
+
+func (c *KindClusterAccesses) AddFinalizer(ctx context.Context, access *kclusterv1.KindClusterAccess) error {
+	originalAccess := access.DeepCopy()
+	controllerutil.AddFinalizer(access, ClusterAccessFinalizer)
+	return c.runtimeClient.Patch(ctx, access, client.MergeFrom(originalAccess))
+}
+
+func (c *KindClusterAccesses) RemoveFinalizer(ctx context.Context, access *kclusterv1.KindClusterAccess) error {
+	originalAccess := access.DeepCopy()
+	controllerutil.RemoveFinalizer(access, ClusterAccessFinalizer)
+	return c.runtimeClient.Patch(ctx, access, client.MergeFrom(originalAccess))
+}
+
+func (c *KindClusterAccesses) UpdateStatus(ctx context.Context, status kclusterv1.KindClusterAccessStatus, access *kclusterv1.KindClusterAccess) error {
+	originalAccess := access.DeepCopy()
+	access.Status = status
+	return c.runtimeClient.Status().Patch(ctx, access, client.MergeFrom(originalAccess))
+}
+
+// StoreKubeconfig writes the kubeconfig to the Secret of the
+// KindClusterAccess. The Secret is owned by the KindClusterAccess, so that it
+// is garbage collected with it.
+func (c *KindClusterAccesses) StoreKubeconfig(ctx context.Context, access *kclusterv1.KindClusterAccess, kubeconfig []byte) error {
+	kubeconfigSecret := &corev1.Secret{}
+	kubeconfigSecret.Name = access.GetSecretName()
+	kubeconfigSecret.Namespace = access.Namespace
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c.runtimeClient, kubeconfigSecret, func() error {
+		kubeconfigSecret.Data = map[string][]byte{secret.KubeconfigDataName: kubeconfig}
+		return controllerutil.SetControllerReference(access, kubeconfigSecret, c.runtimeClient.Scheme())
+	})
+
+	return err
+}
+
+// KubeconfigExists returns whether the Secret of the KindClusterAccess holds
+// a kubeconfig.
+func (c *KindClusterAccesses) KubeconfigExists(ctx context.Context, access *kclusterv1.KindClusterAccess) (bool, error) {
+	kubeconfigSecret := &corev1.Secret{}
+	err := c.runtimeClient.Get(ctx, types.NamespacedName{Namespace: access.Namespace, Name: access.GetSecretName()}, kubeconfigSecret)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return len(kubeconfigSecret.Data[secret.KubeconfigDataName]) > 0, nil
+}
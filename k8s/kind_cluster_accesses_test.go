@@ -0,0 +1,197 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindClusterAccesses", func() {
+	var (
+		accesses       *k8s.KindClusterAccesses
+		access         *kclusterv1.KindClusterAccess
+		ctx            context.Context
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		accesses = k8s.NewKindClusterAccesses(k8sClient)
+
+		namespacedName = types.NamespacedName{
+			Name:      "potato-access",
+			Namespace: namespace,
+		}
+		access = &kclusterv1.KindClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+			Spec: kclusterv1.KindClusterAccessSpec{
+				KindClusterRef: corev1.LocalObjectReference{Name: "potato"},
+				User:           "jane",
+				ClusterRole:    "view",
+			},
+		}
+		Expect(k8sClient.Create(ctx, access)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Get(ctx, namespacedName, access)).To(Succeed())
+		controllerutil.RemoveFinalizer(access, k8s.ClusterAccessFinalizer)
+		Expect(k8sClient.Update(ctx, access)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, access)).To(Succeed())
+	})
+
+	It("defaults the expiry", func() {
+		Expect(access.Spec.Expiry).NotTo(BeNil())
+		Expect(access.Spec.Expiry.Duration).To(Equal(kclusterv1.DefaultAccessExpiry))
+	})
+
+	It("rejects reserved users", func() {
+		reserved := access.DeepCopy()
+		reserved.ObjectMeta = metav1.ObjectMeta{Name: "reserved", Namespace: namespace}
+		reserved.Spec.User = "system:kube-controller-manager"
+		Expect(k8sClient.Create(ctx, reserved)).To(MatchError(ContainSubstring("reserved")))
+	})
+
+	It("rejects reserved groups", func() {
+		reserved := access.DeepCopy()
+		reserved.ObjectMeta = metav1.ObjectMeta{Name: "reserved", Namespace: namespace}
+		reserved.Spec.Groups = []string{"developers", "system:masters"}
+		Expect(k8sClient.Create(ctx, reserved)).To(MatchError(ContainSubstring("reserved")))
+	})
+
+	Describe("Get", func() {
+		It("gets the existing access", func() {
+			actualAccess, err := accesses.Get(ctx, namespacedName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualAccess).To(Equal(access))
+		})
+
+		When("the access does not exist", func() {
+			It("returns a not found error", func() {
+				actualAccess, err := accesses.Get(ctx, types.NamespacedName{Name: "carrot", Namespace: namespace})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(actualAccess).To(BeNil())
+			})
+		})
+	})
+
+	Describe("ListForKindCluster", func() {
+		var otherAccess *kclusterv1.KindClusterAccess
+
+		BeforeEach(func() {
+			otherAccess = access.DeepCopy()
+			otherAccess.ObjectMeta = metav1.ObjectMeta{Name: "carrot-access", Namespace: namespace}
+			otherAccess.Spec.KindClusterRef.Name = "carrot"
+			Expect(k8sClient.Create(ctx, otherAccess)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, otherAccess)).To(Succeed())
+		})
+
+		It("lists the accesses referencing the KindCluster", func() {
+			kindCluster := &kclusterv1.KindCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "potato", Namespace: namespace},
+			}
+
+			actualAccesses, err := accesses.ListForKindCluster(ctx, kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualAccesses).To(HaveLen(1))
+			Expect(actualAccesses[0].Name).To(Equal("potato-access"))
+		})
+	})
+
+	Describe("AddFinalizer", func() {
+		It("adds the finalizer", func() {
+			Expect(accesses.AddFinalizer(ctx, access)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, access)).To(Succeed())
+			Expect(access.Finalizers).To(ContainElement(k8s.ClusterAccessFinalizer))
+		})
+	})
+
+	Describe("RemoveFinalizer", func() {
+		BeforeEach(func() {
+			Expect(accesses.AddFinalizer(ctx, access)).To(Succeed())
+		})
+
+		It("removes the finalizer", func() {
+			Expect(accesses.RemoveFinalizer(ctx, access)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, access)).To(Succeed())
+			Expect(access.Finalizers).NotTo(ContainElement(k8s.ClusterAccessFinalizer))
+		})
+	})
+
+	Describe("UpdateStatus", func() {
+		It("updates the status", func() {
+			expiresAt := metav1.Now().Rfc3339Copy()
+			status := kclusterv1.KindClusterAccessStatus{
+				Ready:      true,
+				SecretName: "potato-access-access-kubeconfig",
+				ExpiresAt:  &expiresAt,
+			}
+			Expect(accesses.UpdateStatus(ctx, status, access)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, access)).To(Succeed())
+			Expect(access.Status.Ready).To(BeTrue())
+			Expect(access.Status.SecretName).To(Equal("potato-access-access-kubeconfig"))
+			Expect(access.Status.ExpiresAt.Time).To(BeTemporally("==", expiresAt.Time))
+		})
+	})
+
+	Describe("StoreKubeconfig", func() {
+		var kubeconfigSecret *corev1.Secret
+
+		BeforeEach(func() {
+			kubeconfigSecret = &corev1.Secret{}
+			Expect(accesses.StoreKubeconfig(ctx, access, []byte("kubeconfig"))).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, kubeconfigSecret)).To(Succeed())
+		})
+
+		It("stores the kubeconfig in a Secret owned by the access", func() {
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "potato-access-access-kubeconfig"}, kubeconfigSecret)).To(Succeed())
+			Expect(kubeconfigSecret.Data).To(HaveKeyWithValue("value", []byte("kubeconfig")))
+			Expect(kubeconfigSecret.OwnerReferences).To(ConsistOf(HaveField("UID", access.UID)))
+		})
+
+		It("updates the existing Secret", func() {
+			Expect(accesses.StoreKubeconfig(ctx, access, []byte("rotated"))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: "potato-access-access-kubeconfig"}, kubeconfigSecret)).To(Succeed())
+			Expect(kubeconfigSecret.Data).To(HaveKeyWithValue("value", []byte("rotated")))
+		})
+
+		It("reports that the kubeconfig exists", func() {
+			exists, err := accesses.KubeconfigExists(ctx, access)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		})
+	})
+
+	Describe("KubeconfigExists", func() {
+		When("the Secret does not exist", func() {
+			It("returns false", func() {
+				exists, err := accesses.KubeconfigExists(ctx, access)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exists).To(BeFalse())
+			})
+		})
+	})
+})
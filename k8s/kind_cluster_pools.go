@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+const ClusterPoolFinalizer = "kindclusterpool.infrastructure.cluster.x-k8s.io"
+
+type KindClusterPools struct {
+	runtimeClient client.Client
+}
+
+func NewKindClusterPools(runtimeClient client.Client) *KindClusterPools {
+	return &KindClusterPools{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (c *KindClusterPools) Get(ctx context.Context, namespacedName types.NamespacedName) (*kclusterv1.KindClusterPool, error) {
+	pool := &kclusterv1.KindClusterPool{}
+	err := c.runtimeClient.Get(ctx, namespacedName, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+func (c *KindClusterPools) AddFinalizer(ctx context.Context, pool *kclusterv1.KindClusterPool) error {
+	originalPool := pool.DeepCopy()
+	controllerutil.AddFinalizer(pool, ClusterPoolFinalizer)
+	return c.runtimeClient.Patch(ctx, pool, client.MergeFrom(originalPool))
+}
+
+func (c *KindClusterPools) RemoveFinalizer(ctx context.Context, pool *kclusterv1.KindClusterPool) error {
+	originalPool := pool.DeepCopy()
+	controllerutil.RemoveFinalizer(pool, ClusterPoolFinalizer)
+	return c.runtimeClient.Patch(ctx, pool, client.MergeFrom(originalPool))
+}
+
+// UpdateStatus sets the status of the pool. The patch fails with a conflict
+// if the pool has changed since it was read, so that claims made in the
+// meantime are not lost.
+func (c *KindClusterPools) UpdateStatus(ctx context.Context, status kclusterv1.KindClusterPoolStatus, pool *kclusterv1.KindClusterPool) error {
+	originalPool := pool.DeepCopy()
+	pool.Status = status
+	return c.runtimeClient.Status().Patch(ctx, pool, client.MergeFromWithOptions(originalPool, client.MergeFromWithOptimisticLock{}))
+}
+
+// Claim marks the idle kind cluster with the given name as claimed by the
+// KindCluster. The patch fails with a conflict if the pool has changed since
+// it was read, so that a kind cluster is never claimed twice.
+func (c *KindClusterPools) Claim(ctx context.Context, clusterName string, kindCluster *kclusterv1.KindCluster, pool *kclusterv1.KindClusterPool) error {
+	originalPool := pool.DeepCopy()
+
+	var claimed *kclusterv1.PooledCluster
+	for i := range pool.Status.Clusters {
+		if pool.Status.Clusters[i].Name == clusterName {
+			claimed = &pool.Status.Clusters[i]
+			break
+		}
+	}
+	if claimed == nil || claimed.Phase != kclusterv1.PooledClusterPhaseIdle {
+		return fmt.Errorf("kind cluster %q is not idle in pool %s", clusterName, pool.Name)
+	}
+
+	claimed.Phase = kclusterv1.PooledClusterPhaseClaimed
+	claimed.ClaimedBy = &corev1.ObjectReference{
+		APIVersion: kclusterv1.GroupVersion.String(),
+		Kind:       "KindCluster",
+		Namespace:  kindCluster.Namespace,
+		Name:       kindCluster.Name,
+		UID:        kindCluster.UID,
+	}
+	now := metav1.Now()
+	claimed.ClaimedAt = &now
+	pool.Status.Ready--
+
+	return c.runtimeClient.Status().Patch(ctx, pool, client.MergeFromWithOptions(originalPool, client.MergeFromWithOptimisticLock{}))
+}
+
+// MarkDeleting marks the kind cluster with the given name as being deleted,
+// so that it can no longer be claimed. The patch fails with a conflict if
+// the pool has changed since it was read, so that a kind cluster claimed in
+// the meantime is never deleted.
+func (c *KindClusterPools) MarkDeleting(ctx context.Context, clusterName string, pool *kclusterv1.KindClusterPool) error {
+	originalPool := pool.DeepCopy()
+
+	var deleting *kclusterv1.PooledCluster
+	for i := range pool.Status.Clusters {
+		if pool.Status.Clusters[i].Name == clusterName {
+			deleting = &pool.Status.Clusters[i]
+			break
+		}
+	}
+	if deleting == nil || deleting.Phase == kclusterv1.PooledClusterPhaseClaimed {
+		return fmt.Errorf("kind cluster %q cannot be deleted from pool %s", clusterName, pool.Name)
+	}
+	if deleting.Phase == kclusterv1.PooledClusterPhaseDeleting {
+		return nil
+	}
+
+	if deleting.Phase == kclusterv1.PooledClusterPhaseIdle {
+		pool.Status.Ready--
+	}
+	deleting.Phase = kclusterv1.PooledClusterPhaseDeleting
+
+	return c.runtimeClient.Status().Patch(ctx, pool, client.MergeFromWithOptions(originalPool, client.MergeFromWithOptimisticLock{}))
+}
@@ -0,0 +1,229 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindClusterPools", func() {
+	var (
+		clusterPools   *k8s.KindClusterPools
+		pool           *kclusterv1.KindClusterPool
+		ctx            context.Context
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clusterPools = k8s.NewKindClusterPools(k8sClient)
+
+		namespacedName = types.NamespacedName{
+			Name:      "potato-pool",
+			Namespace: namespace,
+		}
+		pool = &kclusterv1.KindClusterPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+			Spec: kclusterv1.KindClusterPoolSpec{
+				Size: 2,
+			},
+		}
+		Expect(k8sClient.Create(ctx, pool)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+		controllerutil.RemoveFinalizer(pool, k8s.ClusterPoolFinalizer)
+		Expect(k8sClient.Update(ctx, pool)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pool)).To(Succeed())
+	})
+
+	Describe("Get", func() {
+		It("gets the existing pool", func() {
+			actualPool, err := clusterPools.Get(ctx, namespacedName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualPool).To(Equal(pool))
+		})
+
+		When("the pool does not exist", func() {
+			It("returns a not found error", func() {
+				actualPool, err := clusterPools.Get(ctx, types.NamespacedName{Name: "carrot", Namespace: namespace})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(actualPool).To(BeNil())
+			})
+		})
+	})
+
+	Describe("AddFinalizer", func() {
+		It("adds the finalizer", func() {
+			Expect(clusterPools.AddFinalizer(ctx, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Finalizers).To(ContainElement(k8s.ClusterPoolFinalizer))
+		})
+	})
+
+	Describe("RemoveFinalizer", func() {
+		BeforeEach(func() {
+			Expect(clusterPools.AddFinalizer(ctx, pool)).To(Succeed())
+		})
+
+		It("removes the finalizer", func() {
+			Expect(clusterPools.RemoveFinalizer(ctx, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Finalizers).NotTo(ContainElement(k8s.ClusterPoolFinalizer))
+		})
+	})
+
+	Describe("UpdateStatus", func() {
+		var status kclusterv1.KindClusterPoolStatus
+
+		BeforeEach(func() {
+			status = kclusterv1.KindClusterPoolStatus{
+				Ready: 1,
+				Clusters: []kclusterv1.PooledCluster{
+					{Name: "pool-abcde", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: "hash"},
+				},
+			}
+		})
+
+		It("updates the status", func() {
+			Expect(clusterPools.UpdateStatus(ctx, status, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Status).To(Equal(status))
+		})
+
+		When("the pool has changed since it was read", func() {
+			BeforeEach(func() {
+				changed := pool.DeepCopy()
+				changed.Spec.Size = 3
+				Expect(k8sClient.Update(ctx, changed)).To(Succeed())
+			})
+
+			It("returns a conflict error", func() {
+				err := clusterPools.UpdateStatus(ctx, status, pool)
+				Expect(errors.IsConflict(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("Claim", func() {
+		var kindCluster *kclusterv1.KindCluster
+
+		BeforeEach(func() {
+			Expect(clusterPools.UpdateStatus(ctx, kclusterv1.KindClusterPoolStatus{
+				Ready: 1,
+				Clusters: []kclusterv1.PooledCluster{
+					{Name: "pool-abcde", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: "hash"},
+					{Name: "pool-fghij", Phase: kclusterv1.PooledClusterPhaseProvisioning, TemplateHash: "hash"},
+				},
+			}, pool)).To(Succeed())
+
+			kindCluster = &kclusterv1.KindCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "potato",
+					Namespace: namespace,
+					UID:       "potato-uid",
+				},
+			}
+		})
+
+		It("marks the kind cluster as claimed by the KindCluster", func() {
+			Expect(clusterPools.Claim(ctx, "pool-abcde", kindCluster, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Status.Ready).To(BeZero())
+			claimed := pool.Status.Clusters[0]
+			Expect(claimed.Phase).To(Equal(kclusterv1.PooledClusterPhaseClaimed))
+			Expect(claimed.ClaimedBy.Kind).To(Equal("KindCluster"))
+			Expect(claimed.ClaimedBy.Namespace).To(Equal(namespace))
+			Expect(claimed.ClaimedBy.Name).To(Equal("potato"))
+			Expect(claimed.ClaimedBy.UID).To(BeEquivalentTo("potato-uid"))
+			Expect(claimed.ClaimedAt).NotTo(BeNil())
+		})
+
+		When("the kind cluster is not idle", func() {
+			It("returns an error", func() {
+				Expect(clusterPools.Claim(ctx, "pool-fghij", kindCluster, pool)).NotTo(Succeed())
+			})
+		})
+
+		When("the kind cluster is not in the pool", func() {
+			It("returns an error", func() {
+				Expect(clusterPools.Claim(ctx, "carrot", kindCluster, pool)).NotTo(Succeed())
+			})
+		})
+
+		When("the pool has changed since it was read", func() {
+			BeforeEach(func() {
+				changed := pool.DeepCopy()
+				changed.Spec.Size = 3
+				Expect(k8sClient.Update(ctx, changed)).To(Succeed())
+			})
+
+			It("returns a conflict error", func() {
+				err := clusterPools.Claim(ctx, "pool-abcde", kindCluster, pool)
+				Expect(errors.IsConflict(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("MarkDeleting", func() {
+		BeforeEach(func() {
+			Expect(clusterPools.UpdateStatus(ctx, kclusterv1.KindClusterPoolStatus{
+				Ready: 1,
+				Clusters: []kclusterv1.PooledCluster{
+					{Name: "pool-abcde", Phase: kclusterv1.PooledClusterPhaseIdle, TemplateHash: "hash"},
+					{Name: "pool-fghij", Phase: kclusterv1.PooledClusterPhaseClaimed, TemplateHash: "hash"},
+				},
+			}, pool)).To(Succeed())
+		})
+
+		It("marks the kind cluster as being deleted", func() {
+			Expect(clusterPools.MarkDeleting(ctx, "pool-abcde", pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Status.Ready).To(BeZero())
+			Expect(pool.Status.Clusters[0].Phase).To(Equal(kclusterv1.PooledClusterPhaseDeleting))
+		})
+
+		When("the kind cluster is claimed", func() {
+			It("returns an error", func() {
+				Expect(clusterPools.MarkDeleting(ctx, "pool-fghij", pool)).NotTo(Succeed())
+			})
+		})
+
+		When("the kind cluster is not in the pool", func() {
+			It("returns an error", func() {
+				Expect(clusterPools.MarkDeleting(ctx, "carrot", pool)).NotTo(Succeed())
+			})
+		})
+
+		When("the pool has changed since it was read", func() {
+			BeforeEach(func() {
+				changed := pool.DeepCopy()
+				changed.Spec.Size = 3
+				Expect(k8sClient.Update(ctx, changed)).To(Succeed())
+			})
+
+			It("returns a conflict error", func() {
+				err := clusterPools.MarkDeleting(ctx, "pool-abcde", pool)
+				Expect(errors.IsConflict(err)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+// KindClusterQuotas checks KindClusters against the limits and the
+// KindClusterQuotas of their namespace before their kind cluster is created.
+type KindClusterQuotas struct {
+	reader client.Reader
+	limits kclusterv1.QuotaLimits
+}
+
+// NewKindClusterQuotas creates KindClusterQuotas listing the KindClusters
+// with the reader. It has to read from the API server rather than the cache
+// of the manager, as the cache may not have seen the phase of a KindCluster
+// whose kind cluster was started to be created by the previous reconcile.
+func NewKindClusterQuotas(reader client.Reader, limits kclusterv1.QuotaLimits) *KindClusterQuotas {
+	return &KindClusterQuotas{
+		reader: reader,
+		limits: limits,
+	}
+}
+
+// Check returns an error wrapping kclusterv1.ErrQuotaExceeded if creating the
+// kind cluster exceeds a quota. Only KindClusters whose kind cluster is being
+// or has been created are counted, so that KindClusters kept Pending by a
+// quota do not hold each other back.
+func (q *KindClusterQuotas) Check(ctx context.Context, kindCluster *kclusterv1.KindCluster) error {
+	return kclusterv1.CheckQuota(ctx, q.reader, kindCluster, q.limits, func(other *kclusterv1.KindCluster) bool {
+		return other.Status.Phase != "" && other.Status.Phase != kclusterv1.ClusterPhasePending
+	})
+}
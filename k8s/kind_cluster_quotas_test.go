@@ -0,0 +1,125 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindClusterQuotas", func() {
+	var (
+		quotas      *k8s.KindClusterQuotas
+		limits      kclusterv1.QuotaLimits
+		quota       *kclusterv1.KindClusterQuota
+		existing    *kclusterv1.KindCluster
+		kindCluster *kclusterv1.KindCluster
+		ctx         context.Context
+		checkErr    error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		limits = kclusterv1.QuotaLimits{}
+
+		quota = &kclusterv1.KindClusterQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quota",
+				Namespace: namespace,
+			},
+			Spec: kclusterv1.KindClusterQuotaSpec{
+				MaxClusters: ptr.To[int32](2),
+				MaxNodes:    ptr.To[int32](4),
+			},
+		}
+		Expect(k8sClient.Create(ctx, quota)).To(Succeed())
+
+		existing = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing",
+				Namespace: namespace,
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name:        "existing-kind-cluster-name",
+				WorkerNodes: 1,
+			},
+		}
+		Expect(k8sClient.Create(ctx, existing)).To(Succeed())
+		existing.Status.Phase = kclusterv1.ClusterPhaseReady
+		Expect(k8sClient.Status().Update(ctx, existing)).To(Succeed())
+
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: namespace,
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: "the-kind-cluster-name",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, existing)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, quota)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		quotas = k8s.NewKindClusterQuotas(k8sClient, limits)
+		checkErr = quotas.Check(ctx, kindCluster)
+	})
+
+	It("allows a kind cluster within the quota", func() {
+		Expect(checkErr).NotTo(HaveOccurred())
+	})
+
+	When("the kind cluster exceeds the nodes of the quota", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.WorkerNodes = 2
+		})
+
+		It("returns ErrQuotaExceeded", func() {
+			Expect(checkErr).To(MatchError(kclusterv1.ErrQuotaExceeded))
+			Expect(checkErr).To(MatchError(ContainSubstring("5 nodes exceed the maximum of 4 of KindClusterQuota quota")))
+		})
+	})
+
+	When("the kind cluster exceeds the clusters of the quota", func() {
+		BeforeEach(func() {
+			quota.Spec.MaxClusters = ptr.To[int32](1)
+			Expect(k8sClient.Update(ctx, quota)).To(Succeed())
+		})
+
+		It("returns ErrQuotaExceeded", func() {
+			Expect(checkErr).To(MatchError(kclusterv1.ErrQuotaExceeded))
+		})
+
+		When("the existing kind cluster is still pending", func() {
+			BeforeEach(func() {
+				existing.Status.Phase = kclusterv1.ClusterPhasePending
+				Expect(k8sClient.Status().Update(ctx, existing)).To(Succeed())
+			})
+
+			It("does not count it", func() {
+				Expect(checkErr).NotTo(HaveOccurred())
+			})
+		})
+	})
+
+	When("the kind cluster exceeds the global limits", func() {
+		BeforeEach(func() {
+			limits = kclusterv1.QuotaLimits{MaxClusters: 1}
+		})
+
+		It("returns ErrQuotaExceeded", func() {
+			Expect(checkErr).To(MatchError(kclusterv1.ErrQuotaExceeded))
+			Expect(checkErr).To(MatchError(ContainSubstring("exceed the limit of 1")))
+		})
+	})
+})
@@ -2,9 +2,12 @@ package k8s
 
 import (
 	"context"
+	"time"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -31,6 +34,36 @@ func (c *KindClusters) Get(ctx context.Context, namespacedName types.NamespacedN
 	return cluster, nil
 }
 
+// ListByName returns the KindClusters whose spec.name matches the given kind
+// cluster name. The runtime client has to read from a cache with the
+// kclusterv1.KindClusterNameField index.
+func (c *KindClusters) ListByName(ctx context.Context, name string) ([]kclusterv1.KindCluster, error) {
+	list := &kclusterv1.KindClusterList{}
+	err := c.runtimeClient.List(ctx, list, client.MatchingFields{kclusterv1.KindClusterNameField: name})
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// ListByHost returns the KindClusters created on the KindHost with the given
+// name. The runtime client has to read from a cache with the
+// kclusterv1.KindClusterHostField index.
+func (c *KindClusters) ListByHost(ctx context.Context, host string) ([]kclusterv1.KindCluster, error) {
+	list := &kclusterv1.KindClusterList{}
+	err := c.runtimeClient.List(ctx, list, client.MatchingFields{kclusterv1.KindClusterHostField: host})
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+func (c *KindClusters) Delete(ctx context.Context, cluster *kclusterv1.KindCluster) error {
+	return c.runtimeClient.Delete(ctx, cluster)
+}
+
 func (c *KindClusters) AddFinalizer(ctx context.Context, cluster *kclusterv1.KindCluster) error {
 	originalCluster := cluster.DeepCopy()
 	controllerutil.AddFinalizer(cluster, ClusterFinalizer)
@@ -43,7 +76,13 @@ func (c *KindClusters) RemoveFinalizer(ctx context.Context, cluster *kclusterv1.
 	return c.runtimeClient.Patch(ctx, cluster, client.MergeFrom(originalCluster))
 }
 
-func (c *KindClusters) SetControlPlaneEndpoint(ctx context.Context, endpoint kclusterv1.APIEndpoint, cluster *kclusterv1.KindCluster) error {
+func (c *KindClusters) SetName(ctx context.Context, name string, cluster *kclusterv1.KindCluster) error {
+	originalCluster := cluster.DeepCopy()
+	cluster.Spec.Name = name
+	return c.runtimeClient.Patch(ctx, cluster, client.MergeFrom(originalCluster))
+}
+
+func (c *KindClusters) SetControlPlaneEndpoint(ctx context.Context, endpoint clusterv1.APIEndpoint, cluster *kclusterv1.KindCluster) error {
 	originalCluster := cluster.DeepCopy()
 	cluster.Spec.ControlPlaneEndpoint = endpoint
 	return c.runtimeClient.Patch(ctx, cluster, client.MergeFrom(originalCluster))
@@ -54,3 +93,26 @@ func (c *KindClusters) UpdateStatus(ctx context.Context, status kclusterv1.KindC
 	cluster.Status = status
 	return c.runtimeClient.Status().Patch(ctx, cluster, client.MergeFrom(originalCluster))
 }
+
+// ExtendLease sets the expiry of the KindCluster and removes the
+// kclusterv1.ExtendLeaseAnnotation requesting it.
+func (c *KindClusters) ExtendLease(ctx context.Context, expiresAt metav1.Time, cluster *kclusterv1.KindCluster) error {
+	originalCluster := cluster.DeepCopy()
+	cluster.Spec.ExpiresAt = &expiresAt
+	delete(cluster.Annotations, kclusterv1.ExtendLeaseAnnotation)
+	return c.runtimeClient.Patch(ctx, cluster, client.MergeFrom(originalCluster))
+}
+
+// SuspendIdle suspends the KindCluster and records when and why in the
+// kclusterv1.IdleSuspendedAtAnnotation and
+// kclusterv1.IdleSuspendReasonAnnotation.
+func (c *KindClusters) SuspendIdle(ctx context.Context, reason string, cluster *kclusterv1.KindCluster) error {
+	originalCluster := cluster.DeepCopy()
+	cluster.Spec.Suspended = true
+	if cluster.Annotations == nil {
+		cluster.Annotations = map[string]string{}
+	}
+	cluster.Annotations[kclusterv1.IdleSuspendedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	cluster.Annotations[kclusterv1.IdleSuspendReasonAnnotation] = reason
+	return c.runtimeClient.Patch(ctx, cluster, client.MergeFrom(originalCluster))
+}
@@ -2,15 +2,18 @@ package k8s_test
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/k8s"
 )
 
@@ -65,6 +68,146 @@ var _ = Describe("KindClusters", func() {
 		})
 	})
 
+	Describe("ListByName", func() {
+		var otherCluster *kclusterv1.KindCluster
+
+		BeforeEach(func() {
+			kindClusters = k8s.NewKindClusters(cachedClient)
+			otherCluster = &kclusterv1.KindCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "carrot",
+					Namespace: namespace,
+				},
+				Spec: kclusterv1.KindClusterSpec{
+					Name: "another-kind-cluster-name",
+				},
+			}
+			Expect(k8sClient.Create(ctx, otherCluster)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, otherCluster)).To(Succeed())
+		})
+
+		It("returns only the KindClusters with the kind cluster name", func() {
+			Eventually(func(g Gomega) {
+				actualClusters, err := kindClusters.ListByName(ctx, "the-kind-cluster-name")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(actualClusters).To(HaveLen(1))
+				g.Expect(actualClusters[0].Name).To(Equal("potato"))
+			}).Should(Succeed())
+		})
+
+		When("no KindCluster uses the name", func() {
+			It("returns an empty list", func() {
+				actualClusters, err := kindClusters.ListByName(ctx, "missing")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualClusters).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("ListByHost", func() {
+		var otherCluster *kclusterv1.KindCluster
+
+		BeforeEach(func() {
+			kindClusters = k8s.NewKindClusters(cachedClient)
+			otherCluster = &kclusterv1.KindCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "carrot",
+					Namespace: namespace,
+				},
+				Spec: kclusterv1.KindClusterSpec{
+					Name:    "another-kind-cluster-name",
+					HostRef: &corev1.LocalObjectReference{Name: "remote"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, otherCluster)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, otherCluster)).To(Succeed())
+		})
+
+		It("returns only the KindClusters on the host", func() {
+			Eventually(func(g Gomega) {
+				actualClusters, err := kindClusters.ListByHost(ctx, "remote")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(actualClusters).To(HaveLen(1))
+				g.Expect(actualClusters[0].Name).To(Equal("carrot"))
+			}).Should(Succeed())
+		})
+
+		When("no KindCluster is on the host", func() {
+			It("returns an empty list", func() {
+				actualClusters, err := kindClusters.ListByHost(ctx, "missing")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualClusters).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Delete", func() {
+		var deleted *kclusterv1.KindCluster
+
+		BeforeEach(func() {
+			deleted = &kclusterv1.KindCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "deleted",
+					Namespace: namespace,
+				},
+			}
+			Expect(k8sClient.Create(ctx, deleted)).To(Succeed())
+		})
+
+		It("deletes the kind cluster", func() {
+			Expect(kindClusters.Delete(ctx, deleted)).To(Succeed())
+
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: "deleted", Namespace: namespace}, &kclusterv1.KindCluster{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("ExtendLease", func() {
+		BeforeEach(func() {
+			kindCluster.Annotations = map[string]string{
+				kclusterv1.ExtendLeaseAnnotation: "2h",
+				"another":                        "annotation",
+			}
+		})
+
+		It("sets the expiry and removes the annotation", func() {
+			expiresAt := metav1.NewTime(time.Now().Add(2 * time.Hour).Truncate(time.Second))
+			Expect(kindClusters.ExtendLease(ctx, expiresAt, kindCluster)).To(Succeed())
+
+			actualCluster := &kclusterv1.KindCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, actualCluster)).To(Succeed())
+			Expect(actualCluster.Spec.ExpiresAt.Time).To(BeTemporally("==", expiresAt.Time))
+			Expect(actualCluster.Annotations).NotTo(HaveKey(kclusterv1.ExtendLeaseAnnotation))
+			Expect(actualCluster.Annotations).To(HaveKeyWithValue("another", "annotation"))
+		})
+	})
+
+	Describe("SuspendIdle", func() {
+		BeforeEach(func() {
+			kindCluster.Annotations = map[string]string{"another": "annotation"}
+		})
+
+		It("suspends the kind cluster and records when and why", func() {
+			Expect(kindClusters.SuspendIdle(ctx, "idle for 2h", kindCluster)).To(Succeed())
+
+			actualCluster := &kclusterv1.KindCluster{}
+			Expect(k8sClient.Get(ctx, namespacedName, actualCluster)).To(Succeed())
+			Expect(actualCluster.Spec.Suspended).To(BeTrue())
+			Expect(actualCluster.Annotations).To(HaveKeyWithValue(kclusterv1.IdleSuspendReasonAnnotation, "idle for 2h"))
+			Expect(actualCluster.Annotations).To(HaveKeyWithValue("another", "annotation"))
+
+			suspendedAt, err := time.Parse(time.RFC3339, actualCluster.Annotations[kclusterv1.IdleSuspendedAtAnnotation])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suspendedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+	})
+
 	Describe("Finalizers", func() {
 		It("adds and removes the finalizers", func() {
 			err := kindClusters.AddFinalizer(ctx, kindCluster)
@@ -108,10 +251,27 @@ var _ = Describe("KindClusters", func() {
 		})
 	})
 
+	Describe("SetName", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.Name = ""
+		})
+
+		JustBeforeEach(func() {
+			err := kindClusters.SetName(ctx, "bar-potato", kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("sets the kind cluster name", func() {
+			err := k8sClient.Get(ctx, namespacedName, kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kindCluster.Spec.Name).To(Equal("bar-potato"))
+		})
+	})
+
 	Describe("AddControlPlaneEndpoint", func() {
-		var endpoint kclusterv1.APIEndpoint
+		var endpoint clusterv1.APIEndpoint
 		BeforeEach(func() {
-			endpoint = kclusterv1.APIEndpoint{
+			endpoint = clusterv1.APIEndpoint{
 				Host: "127.0.0.1",
 				Port: 1337,
 			}
@@ -127,12 +287,12 @@ var _ = Describe("KindClusters", func() {
 			Expect(err).NotTo(HaveOccurred())
 			actualEndpoint := kindCluster.Spec.ControlPlaneEndpoint
 			Expect(actualEndpoint.Host).To(Equal("127.0.0.1"))
-			Expect(actualEndpoint.Port).To(Equal(1337))
+			Expect(actualEndpoint.Port).To(Equal(int32(1337)))
 		})
 
 		When("the endpoint is already set", func() {
 			BeforeEach(func() {
-				existingEndpoint := kclusterv1.APIEndpoint{
+				existingEndpoint := clusterv1.APIEndpoint{
 					Host: "127.0.0.1",
 					Port: 1337,
 				}
@@ -147,7 +307,7 @@ var _ = Describe("KindClusters", func() {
 				Expect(err).NotTo(HaveOccurred())
 				actualEndpoint := kindCluster.Spec.ControlPlaneEndpoint
 				Expect(actualEndpoint.Host).To(Equal("172.0.0.1"))
-				Expect(actualEndpoint.Port).To(Equal(8080))
+				Expect(actualEndpoint.Port).To(Equal(int32(8080)))
 			})
 		})
 	})
@@ -0,0 +1,35 @@
+package k8s
+
+import (
+	"context"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type KindControlPlanes struct {
+	runtimeClient client.Client
+}
+
+func NewKindControlPlanes(runtimeClient client.Client) *KindControlPlanes {
+	return &KindControlPlanes{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (c *KindControlPlanes) Get(ctx context.Context, namespacedName types.NamespacedName) (*kclusterv1.KindControlPlane, error) {
+	controlPlane := &kclusterv1.KindControlPlane{}
+	err := c.runtimeClient.Get(ctx, namespacedName, controlPlane)
+	if err != nil {
+		return nil, err
+	}
+
+	return controlPlane, nil
+}
+
+func (c *KindControlPlanes) UpdateStatus(ctx context.Context, status kclusterv1.KindControlPlaneStatus, controlPlane *kclusterv1.KindControlPlane) error {
+	originalControlPlane := controlPlane.DeepCopy()
+	controlPlane.Status = status
+	return c.runtimeClient.Status().Patch(ctx, controlPlane, client.MergeFrom(originalControlPlane))
+}
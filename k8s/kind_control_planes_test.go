@@ -0,0 +1,101 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindControlPlanes", func() {
+	var (
+		controlPlanes  *k8s.KindControlPlanes
+		controlPlane   *kclusterv1.KindControlPlane
+		ctx            context.Context
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		controlPlanes = k8s.NewKindControlPlanes(k8sClient)
+
+		namespacedName = types.NamespacedName{
+			Name:      "potato-control-plane",
+			Namespace: namespace,
+		}
+		controlPlane = &kclusterv1.KindControlPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+			Spec: kclusterv1.KindControlPlaneSpec{
+				Version: "v1.31.0",
+			},
+		}
+		Expect(k8sClient.Create(ctx, controlPlane)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, controlPlane)).To(Succeed())
+	})
+
+	Describe("Get", func() {
+		It("gets the existing control plane", func() {
+			actualControlPlane, err := controlPlanes.Get(ctx, namespacedName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualControlPlane).To(Equal(controlPlane))
+		})
+
+		It("defaults the replicas", func() {
+			actualControlPlane, err := controlPlanes.Get(ctx, namespacedName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*actualControlPlane.Spec.Replicas).To(Equal(int32(1)))
+		})
+
+		When("the control plane does not exist", func() {
+			It("returns a not found error", func() {
+				actualControlPlane, err := controlPlanes.Get(ctx, types.NamespacedName{Name: "carrot", Namespace: namespace})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(actualControlPlane).To(BeNil())
+			})
+		})
+	})
+
+	Describe("UpdateStatus", func() {
+		It("updates the status", func() {
+			status := kclusterv1.KindControlPlaneStatus{
+				Replicas:    3,
+				Initialized: true,
+				Ready:       true,
+			}
+			err := controlPlanes.UpdateStatus(ctx, status, controlPlane)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, namespacedName, controlPlane)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(controlPlane.Status.Replicas).To(Equal(int32(3)))
+			Expect(controlPlane.Status.Initialized).To(BeTrue())
+			Expect(controlPlane.Status.Ready).To(BeTrue())
+		})
+
+		When("the control plane does not exist", func() {
+			It("returns an error", func() {
+				missingControlPlane := &kclusterv1.KindControlPlane{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "carrot",
+						Namespace: namespace,
+					},
+				}
+				err := controlPlanes.UpdateStatus(ctx, kclusterv1.KindControlPlaneStatus{}, missingControlPlane)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+})
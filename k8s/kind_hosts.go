@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+)
+
+type KindHosts struct {
+	runtimeClient client.Client
+}
+
+func NewKindHosts(runtimeClient client.Client) *KindHosts {
+	return &KindHosts{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (h *KindHosts) Get(ctx context.Context, name string) (*kclusterv1.KindHost, error) {
+	host := &kclusterv1.KindHost{}
+	err := h.runtimeClient.Get(ctx, types.NamespacedName{Name: name}, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return host, nil
+}
+
+func (h *KindHosts) List(ctx context.Context) ([]kclusterv1.KindHost, error) {
+	list := &kclusterv1.KindHostList{}
+	err := h.runtimeClient.List(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+func (h *KindHosts) UpdateStatus(ctx context.Context, status kclusterv1.KindHostStatus, host *kclusterv1.KindHost) error {
+	originalHost := host.DeepCopy()
+	host.Status = status
+	return h.runtimeClient.Status().Patch(ctx, host, client.MergeFrom(originalHost))
+}
+
+// GetTLSSecret returns the Secret referenced by the TLS Secret reference of
+// the KindHost, or nil if it has none.
+func (h *KindHosts) GetTLSSecret(ctx context.Context, host *kclusterv1.KindHost) (*corev1.Secret, error) {
+	if host.Spec.TLSSecretRef == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := h.runtimeClient.Get(ctx, types.NamespacedName{
+		Name:      host.Spec.TLSSecretRef.Name,
+		Namespace: host.Spec.TLSSecretRef.Namespace,
+	}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,121 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindHosts", func() {
+	var (
+		hosts  *k8s.KindHosts
+		host   *kclusterv1.KindHost
+		secret *corev1.Secret
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		hosts = k8s.NewKindHosts(k8sClient)
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "remote-tls",
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				kclusterv1.HostTLSCAKey: []byte("ca"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+		host = &kclusterv1.KindHost{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "remote",
+			},
+			Spec: kclusterv1.KindHostSpec{
+				Endpoint: "tcp://10.0.0.5:2376",
+				TLSSecretRef: &corev1.SecretReference{
+					Name:      secret.Name,
+					Namespace: secret.Namespace,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, host)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, host)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+	})
+
+	Describe("Get", func() {
+		It("gets the existing host", func() {
+			actualHost, err := hosts.Get(ctx, "remote")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualHost).To(Equal(host))
+		})
+
+		When("the host does not exist", func() {
+			It("returns a not found error", func() {
+				actualHost, err := hosts.Get(ctx, "carrot")
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(actualHost).To(BeNil())
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the hosts", func() {
+			actualHosts, err := hosts.List(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualHosts).To(HaveLen(1))
+			Expect(actualHosts[0].Name).To(Equal("remote"))
+		})
+	})
+
+	Describe("UpdateStatus", func() {
+		It("updates the status", func() {
+			status := kclusterv1.KindHostStatus{
+				Ready:    true,
+				Clusters: 2,
+				Nodes:    5,
+			}
+			Expect(hosts.UpdateStatus(ctx, status, host)).To(Succeed())
+
+			actualHost, err := hosts.Get(ctx, "remote")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualHost.Status.Ready).To(BeTrue())
+			Expect(actualHost.Status.Clusters).To(Equal(int32(2)))
+			Expect(actualHost.Status.Nodes).To(Equal(int32(5)))
+		})
+	})
+
+	Describe("GetTLSSecret", func() {
+		It("gets the referenced secret", func() {
+			actualSecret, err := hosts.GetTLSSecret(ctx, host)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualSecret.Data).To(HaveKeyWithValue(kclusterv1.HostTLSCAKey, []byte("ca")))
+		})
+
+		When("the host has no TLS secret", func() {
+			BeforeEach(func() {
+				host.Spec.TLSSecretRef = nil
+			})
+
+			It("returns nil", func() {
+				actualSecret, err := hosts.GetTLSSecret(ctx, host)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualSecret).To(BeNil())
+			})
+		})
+	})
+})
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const MachinePoolFinalizer = "kindmachinepool.infrastructure.cluster.x-k8s.io"
+
+type KindMachinePools struct {
+	runtimeClient client.Client
+}
+
+func NewKindMachinePools(runtimeClient client.Client) *KindMachinePools {
+	return &KindMachinePools{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (c *KindMachinePools) Get(ctx context.Context, namespacedName types.NamespacedName) (*kclusterv1.KindMachinePool, error) {
+	pool := &kclusterv1.KindMachinePool{}
+	err := c.runtimeClient.Get(ctx, namespacedName, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+func (c *KindMachinePools) AddFinalizer(ctx context.Context, pool *kclusterv1.KindMachinePool) error {
+	originalPool := pool.DeepCopy()
+	controllerutil.AddFinalizer(pool, MachinePoolFinalizer)
+	return c.runtimeClient.Patch(ctx, pool, client.MergeFrom(originalPool))
+}
+
+func (c *KindMachinePools) RemoveFinalizer(ctx context.Context, pool *kclusterv1.KindMachinePool) error {
+	originalPool := pool.DeepCopy()
+	controllerutil.RemoveFinalizer(pool, MachinePoolFinalizer)
+	return c.runtimeClient.Patch(ctx, pool, client.MergeFrom(originalPool))
+}
+
+func (c *KindMachinePools) SetProviderIDList(ctx context.Context, providerIDs []string, pool *kclusterv1.KindMachinePool) error {
+	originalPool := pool.DeepCopy()
+	pool.Spec.ProviderIDList = providerIDs
+	return c.runtimeClient.Patch(ctx, pool, client.MergeFrom(originalPool))
+}
+
+func (c *KindMachinePools) UpdateStatus(ctx context.Context, status kclusterv1.KindMachinePoolStatus, pool *kclusterv1.KindMachinePool) error {
+	originalPool := pool.DeepCopy()
+	pool.Status = status
+	return c.runtimeClient.Status().Patch(ctx, pool, client.MergeFrom(originalPool))
+}
@@ -0,0 +1,111 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("KindMachinePools", func() {
+	var (
+		machinePools   *k8s.KindMachinePools
+		pool           *kclusterv1.KindMachinePool
+		ctx            context.Context
+		namespacedName types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		machinePools = k8s.NewKindMachinePools(k8sClient)
+
+		namespacedName = types.NamespacedName{
+			Name:      "potato-workers",
+			Namespace: namespace,
+		}
+		pool = &kclusterv1.KindMachinePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespacedName.Name,
+				Namespace: namespacedName.Namespace,
+			},
+		}
+		Expect(k8sClient.Create(ctx, pool)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+		controllerutil.RemoveFinalizer(pool, k8s.MachinePoolFinalizer)
+		Expect(k8sClient.Update(ctx, pool)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pool)).To(Succeed())
+	})
+
+	Describe("Get", func() {
+		It("gets the existing machine pool", func() {
+			actualPool, err := machinePools.Get(ctx, namespacedName)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualPool).To(Equal(pool))
+		})
+
+		When("the machine pool does not exist", func() {
+			It("returns a not found error", func() {
+				actualPool, err := machinePools.Get(ctx, types.NamespacedName{Name: "carrot", Namespace: namespace})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(actualPool).To(BeNil())
+			})
+		})
+	})
+
+	Describe("AddFinalizer", func() {
+		It("adds the finalizer", func() {
+			Expect(machinePools.AddFinalizer(ctx, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Finalizers).To(ContainElement(k8s.MachinePoolFinalizer))
+		})
+	})
+
+	Describe("RemoveFinalizer", func() {
+		BeforeEach(func() {
+			Expect(machinePools.AddFinalizer(ctx, pool)).To(Succeed())
+		})
+
+		It("removes the finalizer", func() {
+			Expect(machinePools.RemoveFinalizer(ctx, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Finalizers).NotTo(ContainElement(k8s.MachinePoolFinalizer))
+		})
+	})
+
+	Describe("SetProviderIDList", func() {
+		It("sets the provider IDs", func() {
+			providerIDs := []string{"kind://docker/potato/potato-worker"}
+			Expect(machinePools.SetProviderIDList(ctx, providerIDs, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Spec.ProviderIDList).To(Equal(providerIDs))
+		})
+	})
+
+	Describe("UpdateStatus", func() {
+		It("updates the status", func() {
+			status := kclusterv1.KindMachinePoolStatus{
+				Ready:    true,
+				Replicas: 2,
+			}
+			Expect(machinePools.UpdateStatus(ctx, status, pool)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, namespacedName, pool)).To(Succeed())
+			Expect(pool.Status.Ready).To(BeTrue())
+			Expect(pool.Status.Replicas).To(Equal(int32(2)))
+		})
+	})
+})
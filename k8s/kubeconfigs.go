@@ -0,0 +1,44 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Kubeconfigs stores the kubeconfig of a workload cluster in the
+// `<cluster>-kubeconfig` Secret Cluster API and clusterctl expect. The Secret
+// is owned by the Cluster, so that it is moved and garbage collected with it.
+type Kubeconfigs struct {
+	runtimeClient client.Client
+}
+
+func NewKubeconfigs(runtimeClient client.Client) *Kubeconfigs {
+	return &Kubeconfigs{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (k *Kubeconfigs) Store(ctx context.Context, cluster *clusterv1.Cluster, kubeconfig []byte) error {
+	kubeconfigSecret := &corev1.Secret{}
+	kubeconfigSecret.Name = secret.Name(cluster.Name, secret.Kubeconfig)
+	kubeconfigSecret.Namespace = cluster.Namespace
+
+	_, err := controllerutil.CreateOrUpdate(ctx, k.runtimeClient, kubeconfigSecret, func() error {
+		if kubeconfigSecret.Labels == nil {
+			kubeconfigSecret.Labels = map[string]string{}
+		}
+		kubeconfigSecret.Labels[clusterv1.ClusterNameLabel] = cluster.Name
+		kubeconfigSecret.Labels[clusterctlv1.ClusterctlMoveLabel] = ""
+		kubeconfigSecret.Type = clusterv1.ClusterSecretType
+		kubeconfigSecret.Data = map[string][]byte{secret.KubeconfigDataName: kubeconfig}
+		return controllerutil.SetOwnerReference(cluster, kubeconfigSecret, k.runtimeClient.Scheme())
+	})
+
+	return err
+}
@@ -0,0 +1,68 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("Kubeconfigs", func() {
+	var (
+		kubeconfigs *k8s.Kubeconfigs
+		cluster     *clusterv1.Cluster
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		kubeconfigs = k8s.NewKubeconfigs(k8sClient)
+		cluster = &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "potato",
+				Namespace: namespace,
+			},
+		}
+		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
+	})
+
+	getSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		namespacedName := types.NamespacedName{Name: "potato-kubeconfig", Namespace: namespace}
+		Expect(k8sClient.Get(ctx, namespacedName, secret)).To(Succeed())
+		return secret
+	}
+
+	It("stores the kubeconfig in a secret owned by the Cluster", func() {
+		Expect(kubeconfigs.Store(ctx, cluster, []byte("kubeconfig"))).To(Succeed())
+
+		secret := getSecret()
+		Expect(secret.Type).To(Equal(clusterv1.ClusterSecretType))
+		Expect(secret.Data).To(HaveKeyWithValue("value", []byte("kubeconfig")))
+		Expect(secret.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, "potato"))
+		Expect(secret.Labels).To(HaveKey("clusterctl.cluster.x-k8s.io/move"))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].UID).To(Equal(cluster.UID))
+	})
+
+	When("the secret already exists", func() {
+		BeforeEach(func() {
+			Expect(kubeconfigs.Store(ctx, cluster, []byte("old-kubeconfig"))).To(Succeed())
+		})
+
+		It("updates it", func() {
+			Expect(kubeconfigs.Store(ctx, cluster, []byte("kubeconfig"))).To(Succeed())
+			Expect(getSecret().Data).To(HaveKeyWithValue("value", []byte("kubeconfig")))
+		})
+	})
+})
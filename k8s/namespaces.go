@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Namespaces struct {
+	runtimeClient client.Client
+}
+
+func NewNamespaces(runtimeClient client.Client) *Namespaces {
+	return &Namespaces{
+		runtimeClient: runtimeClient,
+	}
+}
+
+func (n *Namespaces) Get(ctx context.Context, name string) (*corev1.Namespace, error) {
+	namespace := &corev1.Namespace{}
+	err := n.runtimeClient.Get(ctx, types.NamespacedName{Name: name}, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return namespace, nil
+}
@@ -0,0 +1,41 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("Namespaces", func() {
+	var (
+		namespaces *k8s.Namespaces
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespaces = k8s.NewNamespaces(k8sClient)
+	})
+
+	Describe("Get", func() {
+		It("gets the existing namespace", func() {
+			actualNamespace, err := namespaces.Get(ctx, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualNamespace.Name).To(Equal(namespace))
+			Expect(actualNamespace.UID).To(Equal(namespaceObj.UID))
+		})
+
+		When("the namespace does not exist", func() {
+			It("returns a not found error", func() {
+				actualNamespace, err := namespaces.Get(ctx, "carrot")
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+				Expect(actualNamespace).To(BeNil())
+			})
+		})
+	})
+})
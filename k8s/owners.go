@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Owners looks up the objects recorded as the owners of kind clusters.
+type Owners struct {
+	runtimeClient client.Client
+}
+
+func NewOwners(runtimeClient client.Client) *Owners {
+	return &Owners{
+		runtimeClient: runtimeClient,
+	}
+}
+
+// Exists returns whether the referenced object exists with the referenced
+// UID. An object recreated under the same name does not count.
+func (o *Owners) Exists(ctx context.Context, ref corev1.ObjectReference) (bool, error) {
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	obj, err := o.runtimeClient.Scheme().New(gvk)
+	if err != nil {
+		return false, err
+	}
+
+	clientObj, ok := obj.(client.Object)
+	if !ok {
+		return false, fmt.Errorf("%s is not an object", gvk)
+	}
+
+	err = o.runtimeClient.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, clientObj)
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return clientObj.GetUID() == ref.UID, nil
+}
@@ -0,0 +1,83 @@
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/k8s"
+)
+
+var _ = Describe("Owners", func() {
+	var (
+		owners      *k8s.Owners
+		kindCluster *kclusterv1.KindCluster
+		ref         corev1.ObjectReference
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		owners = k8s.NewOwners(k8sClient)
+
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "potato",
+				Namespace: namespace,
+			},
+		}
+		Expect(k8sClient.Create(ctx, kindCluster)).To(Succeed())
+
+		ref = corev1.ObjectReference{
+			APIVersion: kclusterv1.GroupVersion.String(),
+			Kind:       "KindCluster",
+			Namespace:  namespace,
+			Name:       "potato",
+			UID:        kindCluster.UID,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, kindCluster)).To(Succeed())
+	})
+
+	It("finds the existing owner", func() {
+		Expect(owners.Exists(ctx, ref)).To(BeTrue())
+	})
+
+	When("the owner has been recreated", func() {
+		BeforeEach(func() {
+			ref.UID = "another-uid"
+		})
+
+		It("does not find it", func() {
+			Expect(owners.Exists(ctx, ref)).To(BeFalse())
+		})
+	})
+
+	When("the owner does not exist", func() {
+		BeforeEach(func() {
+			ref.Name = "carrot"
+		})
+
+		It("does not find it", func() {
+			Expect(owners.Exists(ctx, ref)).To(BeFalse())
+		})
+	})
+
+	When("the kind of the owner is unknown", func() {
+		BeforeEach(func() {
+			ref.Kind = "Potato"
+		})
+
+		It("returns an error", func() {
+			_, err := owners.Exists(ctx, ref)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
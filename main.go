@@ -17,14 +17,19 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -35,8 +40,10 @@ import (
 	"sigs.k8s.io/kind/pkg/cluster"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1alpha3 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/controllers"
 	"github.com/mnitchev/cluster-api-provider-kind/infrastructure"
 	"github.com/mnitchev/cluster-api-provider-kind/k8s"
@@ -51,20 +58,81 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
+	utilruntime.Must(kclusterv1alpha3.AddToScheme(scheme))
 	utilruntime.Must(kclusterv1.AddToScheme(scheme))
 	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	utilruntime.Must(expv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 func main() {
+	// The manager runs itself to create and delete kind clusters on the
+	// docker daemons of KindHosts.
+	if len(os.Args) > 1 && os.Args[1] == infrastructure.HostCommand {
+		if err := infrastructure.RunHostCommand(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var healthCheckInterval time.Duration
+	var diagnosticsStorage string
+	var diagnosticsDir string
+	var diagnosticsMaxSize int
+	var hostCertDir string
+	var hostSchedulingPolicy string
+	var maxKindClusters int
+	var maxKindNodes int
+	var idleTimeout time.Duration
+	var orphanCollectionInterval time.Duration
+	var orphanGracePeriod time.Duration
+	var orphanDryRun bool
+	var kubeconfigDir string
+	var certificateRenewBefore time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(&healthCheckInterval, "health-check-interval", time.Minute,
+		"How often Ready kind clusters are checked for running nodes and a ready API server. "+
+			"Set to 0 to disable the periodic check.")
+	flag.StringVar(&diagnosticsStorage, "diagnostics-storage", string(kclusterv1.DiagnosticsStorageSecret),
+		"Where to store the logs collected when a kind cluster fails to create. "+
+			"One of Secret, ConfigMap, HostPath or None.")
+	flag.StringVar(&diagnosticsDir, "diagnostics-dir", "/tmp/kind-diagnostics",
+		"The directory diagnostics are written to when --diagnostics-storage is HostPath.")
+	flag.IntVar(&diagnosticsMaxSize, "diagnostics-max-size", 900*1024,
+		fmt.Sprintf("The maximum size in bytes of a diagnostics archive. At least %d.", infrastructure.MinDiagnosticsMaxSize))
+	flag.StringVar(&hostCertDir, "host-cert-dir", "/tmp/kind-hosts",
+		"The directory the TLS certificates of KindHosts are written to.")
+	flag.StringVar(&hostSchedulingPolicy, "host-scheduling-policy", "",
+		"How KindClusters without a host are scheduled across the ready KindHosts. "+
+			"One of LeastLoaded or BinPack. Empty creates them on the local docker daemon.")
+	flag.IntVar(&maxKindClusters, "max-kind-clusters", 0,
+		"The maximum number of KindClusters across all namespaces. Set to 0 for no limit.")
+	flag.IntVar(&maxKindNodes, "max-kind-nodes", 0,
+		"The maximum number of nodes of all KindClusters across all namespaces. Set to 0 for no limit.")
+	flag.DurationVar(&idleTimeout, "idle-timeout", 0,
+		"How long the API server of a Ready kind cluster may serve no mutating requests before it is suspended, "+
+			"unless the KindCluster or its namespace set their own. Set to 0 to disable.")
+	flag.DurationVar(&orphanCollectionInterval, "orphan-collection-interval", 10*time.Minute,
+		"How often kind clusters whose KindCluster or KindClusterPool no longer exists are looked for. "+
+			"Set to 0 to disable.")
+	flag.DurationVar(&orphanGracePeriod, "orphan-grace-period", time.Hour,
+		"How long a kind cluster has to be orphaned before it is deleted.")
+	flag.BoolVar(&orphanDryRun, "orphan-dry-run", false,
+		"Only log the orphaned kind clusters that would be deleted.")
+	flag.StringVar(&kubeconfigDir, "kubeconfig-dir", "",
+		"The directory the kubeconfig of every kind cluster is written to, as <name>.kubeconfig. "+
+			"Empty writes no kubeconfig files.")
+	flag.DurationVar(&certificateRenewBefore, "certificate-renew-before", 30*24*time.Hour,
+		"How long before they expire the control plane certificates of Ready kind clusters are renewed. "+
+			"Set to 0 to disable.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -92,15 +160,185 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := kclusterv1.IndexKindClusterName(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to index KindClusters by kind cluster name")
+		os.Exit(1)
+	}
+
+	if err := kclusterv1.IndexKindClusterHost(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to index KindClusters by host")
+		os.Exit(1)
+	}
+
+	// The UID of the kube-system namespace identifies the management cluster
+	// in the owners recorded in the kind clusters.
+	kubeSystem := &corev1.Namespace{}
+	if err := mgr.GetAPIReader().Get(context.Background(), types.NamespacedName{Name: "kube-system"}, kubeSystem); err != nil {
+		setupLog.Error(err, "unable to get kube-system namespace")
+		os.Exit(1)
+	}
+
+	kindProvider := cluster.NewProvider()
+	clusterCache := infrastructure.NewClusterCache(kindProvider)
+	containerEvents := infrastructure.NewContainerEvents(clusterCache)
+	if err := mgr.Add(containerEvents); err != nil {
+		setupLog.Error(err, "unable to add container event watcher")
+		os.Exit(1)
+	}
+
+	if diagnosticsMaxSize < infrastructure.MinDiagnosticsMaxSize {
+		setupLog.Error(nil, "diagnostics max size is too small", "size", diagnosticsMaxSize,
+			"minimum", infrastructure.MinDiagnosticsMaxSize)
+		os.Exit(1)
+	}
+
+	var diagnostics controllers.DiagnosticsStore
+	switch kclusterv1.DiagnosticsStorage(diagnosticsStorage) {
+	case kclusterv1.DiagnosticsStorageSecret, kclusterv1.DiagnosticsStorageConfigMap:
+		diagnostics = k8s.NewDiagnostics(mgr.GetClient(), mgr.GetAPIReader(), kclusterv1.DiagnosticsStorage(diagnosticsStorage))
+	case kclusterv1.DiagnosticsStorageHostPath:
+		diagnostics = infrastructure.NewHostDiagnostics(diagnosticsDir)
+	case "None":
+	default:
+		setupLog.Error(nil, "invalid diagnostics storage", "storage", diagnosticsStorage)
+		os.Exit(1)
+	}
+
+	quotaLimits := kclusterv1.QuotaLimits{
+		MaxClusters: int32(maxKindClusters), //nolint:gosec
+		MaxNodes:    int32(maxKindNodes),    //nolint:gosec
+	}
+
+	kindHosts := k8s.NewKindHosts(mgr.GetClient())
+	var hostScheduler controllers.HostScheduler
+	if hostSchedulingPolicy != "" {
+		policy, err := controllers.NewHostSchedulingPolicy(hostSchedulingPolicy)
+		if err != nil {
+			setupLog.Error(err, "invalid host scheduling policy")
+			os.Exit(1)
+		}
+		hostScheduler = controllers.NewKindHostScheduler(
+			kindHosts,
+			k8s.NewKindClusters(mgr.GetClient()),
+			policy,
+		)
+	}
+
+	clusters := k8s.NewClusters(mgr.GetClient())
+	clusterPools := k8s.NewKindClusterPools(mgr.GetClient())
+	hosts := infrastructure.NewHosts(kindHosts, hostCertDir, containerEvents)
+	provider := infrastructure.NewKindProvider(kubeconfigDir, string(kubeSystem.UID), kindProvider, clusterCache, hosts)
 	reconciler := controllers.NewKindClusterReconciler(
-		k8s.NewClusters(mgr.GetClient()),
+		clusters,
 		k8s.NewKindClusters(mgr.GetClient()),
-		infrastructure.NewKindProvider(os.Getenv("KUBECONFIG"), cluster.NewProvider()),
+		provider,
+		k8s.NewKubeconfigs(mgr.GetClient()),
+		diagnostics,
+		mgr.GetEventRecorderFor("kindcluster-controller"),
+		controllers.Options{
+			HealthCheckInterval:    healthCheckInterval,
+			ClusterEvents:          containerEvents.Events(),
+			DiagnosticsMaxSize:     diagnosticsMaxSize,
+			HostScheduler:          hostScheduler,
+			Quota:                  k8s.NewKindClusterQuotas(mgr.GetAPIReader(), quotaLimits),
+			Pools:                  clusterPools,
+			IdleTimeout:            idleTimeout,
+			Namespaces:             k8s.NewNamespaces(mgr.GetClient()),
+			CertificateRenewBefore: certificateRenewBefore,
+		},
 	)
 	if err := reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KindCluster")
 		os.Exit(1)
 	}
+	controlPlaneReconciler := controllers.NewKindControlPlaneReconciler(
+		clusters,
+		k8s.NewKindControlPlanes(mgr.GetClient()),
+		provider,
+		mgr.GetEventRecorderFor("kindcontrolplane-controller"),
+		controllers.Options{
+			HealthCheckInterval: healthCheckInterval,
+		},
+	)
+	if err := controlPlaneReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KindControlPlane")
+		os.Exit(1)
+	}
+	machinePoolReconciler := controllers.NewKindMachinePoolReconciler(
+		clusters,
+		k8s.NewKindMachinePools(mgr.GetClient()),
+		provider,
+		mgr.GetEventRecorderFor("kindmachinepool-controller"),
+		controllers.Options{
+			HealthCheckInterval: healthCheckInterval,
+		},
+	)
+	if err := machinePoolReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KindMachinePool")
+		os.Exit(1)
+	}
+	hostReconciler := controllers.NewKindHostReconciler(
+		kindHosts,
+		provider,
+		mgr.GetEventRecorderFor("kindhost-controller"),
+		controllers.Options{
+			HealthCheckInterval: healthCheckInterval,
+		},
+	)
+	if err := hostReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KindHost")
+		os.Exit(1)
+	}
+	poolReconciler := controllers.NewKindClusterPoolReconciler(
+		clusterPools,
+		k8s.NewKindClusters(mgr.GetClient()),
+		provider,
+		mgr.GetEventRecorderFor("kindclusterpool-controller"),
+		controllers.Options{
+			HealthCheckInterval: healthCheckInterval,
+		},
+	)
+	if err := poolReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KindClusterPool")
+		os.Exit(1)
+	}
+	accessReconciler := controllers.NewKindClusterAccessReconciler(
+		k8s.NewKindClusterAccesses(mgr.GetClient()),
+		k8s.NewKindClusters(mgr.GetClient()),
+		provider,
+		mgr.GetEventRecorderFor("kindclusteraccess-controller"),
+		controllers.Options{
+			HealthCheckInterval: healthCheckInterval,
+		},
+	)
+	if err := accessReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "KindClusterAccess")
+		os.Exit(1)
+	}
+	if orphanCollectionInterval > 0 {
+		orphanCollector := controllers.NewOrphanCollector(
+			provider,
+			k8s.NewOwners(mgr.GetClient()),
+			kindHosts,
+			controllers.Options{
+				OrphanCollectionInterval: orphanCollectionInterval,
+				OrphanGracePeriod:        orphanGracePeriod,
+				OrphanDryRun:             orphanDryRun,
+			},
+		)
+		if err := mgr.Add(orphanCollector); err != nil {
+			setupLog.Error(err, "unable to add orphan collector")
+			os.Exit(1)
+		}
+	}
+	if err := (&kclusterv1.KindCluster{}).SetupWebhookWithManager(mgr, quotaLimits); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "KindCluster")
+		os.Exit(1)
+	}
+	if err := (&kclusterv1.KindClusterTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "KindClusterTemplate")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,84 @@
+package kind_test
+
+import (
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kind/pkg/cluster"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/infrastructure"
+)
+
+var _ = Describe("Control plane nodes", func() {
+	var (
+		kindProvider    *infrastructure.KindProvider
+		clusterProvider *cluster.Provider
+		name            string
+		kindCluster     *kclusterv1.KindCluster
+	)
+
+	controlPlaneNodes := func() []string {
+		nodes, err := kindProvider.GetNodes(kindCluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		names := []string{}
+		for _, node := range nodes {
+			if node.Role == "control-plane" {
+				names = append(names, node.Name)
+			}
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		name = uuid.New().String()
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: name,
+			},
+		}
+		clusterProvider = cluster.NewProvider()
+		kindProvider = infrastructure.NewKindProvider(kubeconfigDir, "integration-tests", clusterProvider, infrastructure.NewClusterCache(clusterProvider), nil)
+		Expect(kindProvider.Create(kindCluster, corev1.ObjectReference{})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+	})
+
+	It("reports the components of the control plane node", func() {
+		components, err := kindProvider.GetControlPlaneComponents(kindCluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		names := []string{}
+		for _, component := range components {
+			Expect(component.Node).To(Equal(name + "-control-plane"))
+			names = append(names, component.Name)
+		}
+		Expect(names).To(ConsistOf(kclusterv1.ControlPlaneComponents))
+	})
+
+	It("adds and removes control plane nodes", func() {
+		added, err := kindProvider.AddControlPlaneNode(kindCluster, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(Equal(name + "-control-plane2"))
+		Expect(controlPlaneNodes()).To(ConsistOf(name+"-control-plane", added))
+
+		Expect(kindProvider.RemoveControlPlaneNode(kindCluster, added)).To(Succeed())
+		Expect(controlPlaneNodes()).To(ConsistOf(name + "-control-plane"))
+		_, err = kindProvider.CheckHealth(kindCluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("does not remove the first control plane node", func() {
+		err := kindProvider.RemoveControlPlaneNode(kindCluster, name+"-control-plane")
+		Expect(err).To(MatchError(ContainSubstring("can not be removed")))
+	})
+})
@@ -0,0 +1,65 @@
+package kind_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/kind/pkg/cluster"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/infrastructure"
+)
+
+var _ = Describe("ContainerEvents", func() {
+	var (
+		clusterProvider *cluster.Provider
+		clusterCache    *infrastructure.ClusterCache
+		containerEvents *infrastructure.ContainerEvents
+		name            string
+		cancel          context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		name = uuid.New().String()
+		clusterProvider = cluster.NewProvider()
+		clusterCache = infrastructure.NewClusterCache(clusterProvider)
+		containerEvents = infrastructure.NewContainerEvents(clusterCache)
+
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(containerEvents.Start(ctx)).To(Succeed())
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+		Expect(clusterProvider.Delete(name, kubeconfig)).To(Succeed())
+	})
+
+	It("emits an event with the cluster name when a cluster is created", func() {
+		exists, err := clusterCache.Contains(name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(clusterProvider.Create(name, cluster.CreateWithKubeconfigPath(kubeconfig))).To(Succeed())
+		}()
+
+		var evt event.GenericEvent
+		Eventually(containerEvents.Events()).WithTimeout(5 * time.Minute).Should(Receive(&evt))
+		Expect(evt.Object.(*kclusterv1.KindCluster).Spec.Name).To(Equal(name))
+
+		Eventually(func() bool {
+			exists, err := clusterCache.Contains(name)
+			Expect(err).NotTo(HaveOccurred())
+			return exists
+		}).WithTimeout(5 * time.Minute).Should(BeTrue())
+	})
+})
@@ -0,0 +1,86 @@
+package kind_test
+
+import (
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/exec"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/infrastructure"
+)
+
+var _ = Describe("Failure domains", func() {
+	var (
+		kindProvider    *infrastructure.KindProvider
+		clusterProvider *cluster.Provider
+		name            string
+		kindCluster     *kclusterv1.KindCluster
+	)
+
+	failureDomains := func() map[string]string {
+		nodes, err := kindProvider.GetNodes(kindCluster)
+		Expect(err).NotTo(HaveOccurred())
+
+		placement := map[string]string{}
+		for _, node := range nodes {
+			placement[node.Name] = node.FailureDomain
+		}
+		return placement
+	}
+
+	networkExists := func(network string) bool {
+		return exec.Command("docker", "network", "inspect", network).Run() == nil
+	}
+
+	BeforeEach(func() {
+		name = uuid.New().String()
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name:              name,
+				ControlPlaneNodes: 1,
+				WorkerNodes:       2,
+				FailureDomains: []kclusterv1.FailureDomain{
+					{Name: "zone-a", ControlPlane: true},
+					{Name: "zone-b"},
+				},
+			},
+		}
+		clusterProvider = cluster.NewProvider()
+		kindProvider = infrastructure.NewKindProvider(kubeconfigDir, "integration-tests", clusterProvider, infrastructure.NewClusterCache(clusterProvider), nil)
+		Expect(kindProvider.Create(kindCluster, corev1.ObjectReference{})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+	})
+
+	It("spreads the nodes across the failure domains", func() {
+		Expect(failureDomains()).To(Equal(map[string]string{
+			name + "-control-plane": "zone-a",
+			name + "-worker":        "zone-a",
+			name + "-worker2":       "zone-b",
+		}))
+		Expect(networkExists(name + "-zone-a")).To(BeTrue())
+		Expect(networkExists(name + "-zone-b")).To(BeTrue())
+	})
+
+	It("places added nodes in the least used failure domain", func() {
+		added, err := kindProvider.AddWorkerNode(kindCluster, "workers", "", []string{"zone-b"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failureDomains()).To(HaveKeyWithValue(added, "zone-b"))
+	})
+
+	It("removes the networks of the failure domains with the cluster", func() {
+		Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		Expect(networkExists(name + "-zone-a")).To(BeFalse())
+		Expect(networkExists(name + "-zone-b")).To(BeFalse())
+	})
+})
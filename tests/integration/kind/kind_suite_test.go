@@ -9,7 +9,10 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var kubeconfig string
+var (
+	kubeconfig    string
+	kubeconfigDir string
+)
 
 func TestIntegration(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -21,8 +24,12 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	kubeconfig = tempFile.Name()
 	os.Setenv("KUBECONFIG", kubeconfig)
+
+	kubeconfigDir, err = os.MkdirTemp("", "kubeconfigs")
+	Expect(err).NotTo(HaveOccurred())
 })
 
 var _ = AfterSuite(func() {
 	os.RemoveAll(kubeconfig)
+	os.RemoveAll(kubeconfigDir)
 })
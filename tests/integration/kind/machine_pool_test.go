@@ -0,0 +1,75 @@
+package kind_test
+
+import (
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kind/pkg/cluster"
+
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
+	"github.com/mnitchev/cluster-api-provider-kind/infrastructure"
+)
+
+var _ = Describe("Machine pool nodes", func() {
+	var (
+		kindProvider    *infrastructure.KindProvider
+		clusterProvider *cluster.Provider
+		name            string
+		kindCluster     *kclusterv1.KindCluster
+	)
+
+	poolNodes := func(pool string) []string {
+		nodes, err := kindProvider.GetMachinePoolNodes(kindCluster, pool)
+		Expect(err).NotTo(HaveOccurred())
+
+		names := []string{}
+		for _, node := range nodes {
+			names = append(names, node.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		name = uuid.New().String()
+		kindCluster = &kclusterv1.KindCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: kclusterv1.KindClusterSpec{
+				Name: name,
+			},
+		}
+		clusterProvider = cluster.NewProvider()
+		kindProvider = infrastructure.NewKindProvider(kubeconfigDir, "integration-tests", clusterProvider, infrastructure.NewClusterCache(clusterProvider), nil)
+		Expect(kindProvider.Create(kindCluster, corev1.ObjectReference{})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+	})
+
+	It("adds and removes worker nodes of the pool", func() {
+		first, err := kindProvider.AddWorkerNode(kindCluster, "workers", "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first).To(Equal(name + "-worker"))
+
+		second, err := kindProvider.AddWorkerNode(kindCluster, "workers", "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(Equal(name + "-worker2"))
+
+		nodes, err := kindProvider.GetMachinePoolNodes(kindCluster, "workers")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nodes).To(HaveLen(2))
+		Expect(nodes[0].Name).To(Equal(first))
+		Expect(nodes[0].ProviderID).To(Equal(infrastructure.ProviderID(name, first)))
+		Expect(poolNodes("other")).To(BeEmpty())
+
+		Expect(kindProvider.RemoveWorkerNode(kindCluster, first)).To(Succeed())
+		Expect(poolNodes("workers")).To(ConsistOf(second))
+		_, err = kindProvider.CheckHealth(kindCluster)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -1,16 +1,21 @@
 package kind_test
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/kind/pkg/cluster"
 	"sigs.k8s.io/kind/pkg/cluster/constants"
+	"sigs.k8s.io/kind/pkg/exec"
 
-	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1alpha3"
+	kclusterv1 "github.com/mnitchev/cluster-api-provider-kind/api/v1beta1"
 	"github.com/mnitchev/cluster-api-provider-kind/infrastructure"
 )
 
@@ -20,6 +25,7 @@ var _ = Describe("KindProvider", func() {
 		clusterProvider *cluster.Provider
 		name            string
 		kindCluster     *kclusterv1.KindCluster
+		owner           corev1.ObjectReference
 	)
 
 	BeforeEach(func() {
@@ -33,18 +39,25 @@ var _ = Describe("KindProvider", func() {
 				Name: name,
 			},
 		}
+		owner = corev1.ObjectReference{
+			APIVersion: kclusterv1.GroupVersion.String(),
+			Kind:       "KindCluster",
+			Namespace:  "bar",
+			Name:       "foo",
+			UID:        "foo-uid",
+		}
 		clusterProvider = cluster.NewProvider()
-		kindProvider = infrastructure.NewKindProvider(kubeconfig, clusterProvider)
+		kindProvider = infrastructure.NewKindProvider(kubeconfigDir, "integration-tests", clusterProvider, infrastructure.NewClusterCache(clusterProvider), nil)
 	})
 
 	Describe("Create", func() {
 		JustBeforeEach(func() {
-			err := kindProvider.Create(kindCluster)
+			err := kindProvider.Create(kindCluster, owner)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		AfterEach(func() {
-			Expect(clusterProvider.Delete(name, kubeconfig)).To(Succeed())
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
 		})
 
 		It("creates the kind cluster", func() {
@@ -53,9 +66,21 @@ var _ = Describe("KindProvider", func() {
 			Expect(clusters).To(ContainElement(name))
 		})
 
+		It("writes the kubeconfig of the cluster to its own file", func() {
+			actualKubeconfig, err := os.ReadFile(filepath.Join(kubeconfigDir, name+".kubeconfig"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actualKubeconfig)).To(ContainSubstring("kind-" + name))
+		})
+
+		It("does not change the KUBECONFIG of the manager", func() {
+			managerKubeconfig, err := os.ReadFile(kubeconfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(managerKubeconfig)).NotTo(ContainSubstring(name))
+		})
+
 		When("the cluster already exists", func() {
 			It("returns an error", func() {
-				err := kindProvider.Create(kindCluster)
+				err := kindProvider.Create(kindCluster, owner)
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -131,6 +156,14 @@ var _ = Describe("KindProvider", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(clusters).NotTo(ContainElement(name))
 			})
+
+			It("removes the kubeconfig file of the cluster", func() {
+				kubeconfigFile := filepath.Join(kubeconfigDir, name+".kubeconfig")
+				Expect(os.WriteFile(kubeconfigFile, []byte("kubeconfig"), 0o600)).To(Succeed())
+
+				Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+				Expect(kubeconfigFile).NotTo(BeAnExistingFile())
+			})
 		})
 
 		When("the cluster does not exist", func() {
@@ -143,22 +176,333 @@ var _ = Describe("KindProvider", func() {
 
 	Describe("GetControlPlaneEndpoint", func() {
 		BeforeEach(func() {
-			err := kindProvider.Create(kindCluster)
+			err := kindProvider.Create(kindCluster, owner)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
 		AfterEach(func() {
-			Expect(clusterProvider.Delete(name, kubeconfig)).To(Succeed())
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
 		})
 
 		It("gets the endpoint", func() {
-			host, port, err := kindProvider.GetControlPlaneEndpoint(kindCluster)
+			clusterKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			host, port, err := kindProvider.GetControlPlaneEndpoint(clusterKubeconfig)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(host).To(Equal("127.0.0.1"))
 			Expect(port).To(BeNumerically(">", 1024))
 		})
 	})
 
+	Describe("GetKubeconfig", func() {
+		BeforeEach(func() {
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("returns the kubeconfig of the cluster", func() {
+			actualKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			host, port, err := kindProvider.GetControlPlaneEndpoint(actualKubeconfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actualKubeconfig)).To(ContainSubstring(fmt.Sprintf("https://%s:%d", host, port)))
+		})
+	})
+
+	Describe("ExportKubeconfig", func() {
+		var kubeconfigFile string
+
+		BeforeEach(func() {
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+
+			// A stale file, e.g. exported before the port of the API server
+			// changed.
+			kubeconfigFile = filepath.Join(kubeconfigDir, name+".kubeconfig")
+			Expect(os.WriteFile(kubeconfigFile, []byte("stale"), 0o600)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("rewrites the file of the kubeconfig", func() {
+			actualKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kindProvider.ExportKubeconfig(kindCluster, actualKubeconfig)).To(Succeed())
+
+			fileKubeconfig, err := os.ReadFile(kubeconfigFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fileKubeconfig).To(Equal(actualKubeconfig))
+		})
+	})
+
+	Describe("CheckHealth", func() {
+		BeforeEach(func() {
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("reports the cluster as healthy and returns its kubeconfig", func() {
+			healthyKubeconfig, err := kindProvider.CheckHealth(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			actualKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(healthyKubeconfig).To(Equal(actualKubeconfig))
+		})
+
+		When("a node container is stopped", func() {
+			BeforeEach(func() {
+				nodes, err := clusterProvider.ListNodes(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exec.Command("docker", "stop", nodes[0].String()).Run()).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := kindProvider.CheckHealth(kindCluster)
+				Expect(err).To(MatchError(ContainSubstring("exited")))
+			})
+		})
+	})
+
+	Describe("Owner", func() {
+		BeforeEach(func() {
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kindProvider.SetOwner(kindCluster, owner)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("lists the kind cluster with its owner", func() {
+			owned, err := kindProvider.ListOwnedClusters("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owned).To(ContainElement(kclusterv1.OwnedKindCluster{
+				Name:  name,
+				Owner: owner,
+			}))
+		})
+
+		When("the owner was recorded by another manager", func() {
+			It("does not list the kind cluster", func() {
+				otherProvider := infrastructure.NewKindProvider(kubeconfigDir, "other-manager", clusterProvider, infrastructure.NewClusterCache(clusterProvider), nil)
+				owned, err := otherProvider.ListOwnedClusters("")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(owned).NotTo(ContainElement(HaveField("Name", name)))
+			})
+		})
+
+		When("the owner is released", func() {
+			BeforeEach(func() {
+				Expect(kindProvider.ReleaseOwner(kindCluster)).To(Succeed())
+			})
+
+			It("does not list the kind cluster", func() {
+				owned, err := kindProvider.ListOwnedClusters("")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(owned).NotTo(ContainElement(HaveField("Name", name)))
+			})
+		})
+	})
+
+	Describe("Owner recorded while creating", func() {
+		BeforeEach(func() {
+			// The manager stopping after the create, before the owner is
+			// recorded in the nodes, leaves the same nodes as stopping in
+			// the middle of the create.
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("lists the kind cluster with its owner", func() {
+			owned, err := kindProvider.ListOwnedClusters("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owned).To(ContainElement(kclusterv1.OwnedKindCluster{
+				Name:  name,
+				Owner: owner,
+			}))
+		})
+
+		When("another owner is recorded in the nodes", func() {
+			var newOwner corev1.ObjectReference
+
+			BeforeEach(func() {
+				newOwner = owner
+				newOwner.Name = "new"
+				newOwner.UID = "new-uid"
+				Expect(kindProvider.SetOwner(kindCluster, newOwner)).To(Succeed())
+			})
+
+			It("lists the kind cluster with the owner of the nodes", func() {
+				owned, err := kindProvider.ListOwnedClusters("")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(owned).To(ContainElement(kclusterv1.OwnedKindCluster{
+					Name:  name,
+					Owner: newOwner,
+				}))
+			})
+		})
+
+		When("the kind cluster is deleted", func() {
+			BeforeEach(func() {
+				Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+			})
+
+			It("no longer lists the kind cluster", func() {
+				owned, err := kindProvider.ListOwnedClusters("")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(owned).NotTo(ContainElement(HaveField("Name", name)))
+			})
+		})
+	})
+
+	Describe("GetNodes", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.WorkerNodes = 1
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("returns the node containers", func() {
+			nodes, err := kindProvider.GetNodes(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(HaveLen(2))
+			for _, node := range nodes {
+				Expect(node.ContainerID).NotTo(BeEmpty())
+				Expect(node.Image).To(HavePrefix("kindest/node"))
+				Expect(node.State).To(Equal("running"))
+				Expect(node.InternalIPs).NotTo(BeEmpty())
+			}
+		})
+
+		It("returns the kubernetes version", func() {
+			clusterKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			version, err := kindProvider.GetKubernetesVersion(clusterKubeconfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(HavePrefix("v1."))
+		})
+	})
+
+	Describe("CollectDiagnostics", func() {
+		When("the cluster exists", func() {
+			BeforeEach(func() {
+				err := clusterProvider.Create(name, cluster.CreateWithKubeconfigPath(kubeconfig))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				Expect(clusterProvider.Delete(name, kubeconfig)).To(Succeed())
+			})
+
+			It("returns a size limited archive of the logs", func() {
+				archive, err := kindProvider.CollectDiagnostics(kindCluster, 512*1024)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(archive).NotTo(BeEmpty())
+				Expect(len(archive)).To(BeNumerically("<=", 512*1024))
+			})
+		})
+
+		When("the cluster does not exist", func() {
+			It("returns no archive", func() {
+				archive, err := kindProvider.CollectDiagnostics(kindCluster, 512*1024)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(archive).To(BeNil())
+			})
+		})
+	})
+
+	Describe("RestartNodes", func() {
+		BeforeEach(func() {
+			kindCluster.Spec.WorkerNodes = 1
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+
+			nodes, err := clusterProvider.ListNodes(name)
+			Expect(err).NotTo(HaveOccurred())
+			for _, node := range nodes {
+				Expect(exec.Command("docker", "stop", node.String()).Run()).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("starts the stopped node containers", func() {
+			Expect(kindProvider.RestartNodes(kindCluster)).To(Succeed())
+			Eventually(func() error {
+				_, err := kindProvider.CheckHealth(kindCluster)
+				return err
+			}).WithTimeout(2 * time.Minute).Should(Succeed())
+		})
+	})
+
+	Describe("Certificates", func() {
+		BeforeEach(func() {
+			err := kindProvider.Create(kindCluster, owner)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(kindProvider.Delete(kindCluster)).To(Succeed())
+		})
+
+		It("returns when the certificates expire", func() {
+			clusterKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+
+			expiry, err := kindProvider.GetCertificateExpiry(kindCluster, clusterKubeconfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(expiry).To(BeTemporally("~", time.Now().Add(365*24*time.Hour), 24*time.Hour))
+		})
+
+		It("renews the certificates", func() {
+			oldKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+			expiry, err := kindProvider.GetCertificateExpiry(kindCluster, oldKubeconfig)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(kindProvider.RenewCertificates(kindCluster)).To(Succeed())
+
+			renewedKubeconfig, err := kindProvider.GetKubeconfig(kindCluster)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewedKubeconfig).NotTo(Equal(oldKubeconfig))
+
+			renewedExpiry, err := kindProvider.GetCertificateExpiry(kindCluster, renewedKubeconfig)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(renewedExpiry).To(BeTemporally(">", expiry))
+
+			Eventually(func() error {
+				_, err := kindProvider.CheckHealth(kindCluster)
+				return err
+			}).WithTimeout(2 * time.Minute).Should(Succeed())
+		})
+	})
+
 	When("the docker binary is missing from the PATH", func() {
 		DescribeTable("operations return an error",
 			func(operation func() error) {
@@ -175,7 +519,7 @@ var _ = Describe("KindProvider", func() {
 			},
 
 			Entry("create", func() error {
-				return kindProvider.Create(kindCluster)
+				return kindProvider.Create(kindCluster, owner)
 			}),
 			Entry("exists", func() error {
 				_, err := kindProvider.Exists(kindCluster)
@@ -188,6 +532,10 @@ var _ = Describe("KindProvider", func() {
 				_, err := kindProvider.Exists(kindCluster)
 				return err
 			}),
+			Entry("check health", func() error {
+				_, err := kindProvider.CheckHealth(kindCluster)
+				return err
+			}),
 		)
 	})
 })